@@ -71,6 +71,13 @@ func main() {
 
 	enableEnsUpdater := flag.Bool("ens.enabled", false, "Enable ens update process")
 
+	enableNftUpdater := flag.Bool("nft.enabled", false, "Enable nft ownership update process")
+	nftUpdaterBatchSize := flag.Int64("nft.batch", 10000, "Maximum number of nft token ids to update per run")
+
+	tokenHoldersBackfill := flag.String("token.holders.backfill", "", "Seed the holder index of an ERC-20 token with the current balances of all addresses that ever transferred it and exit")
+	enableTokenHolderUpdater := flag.Bool("token.holders.enabled", false, "Enable the update process of the token holder index from the indexed ERC-20 transfers")
+	tokenHolderUpdaterBatchSize := flag.Int64("token.holders.batch", 10000, "Maximum number of token holder updates to process per run")
+
 	flag.Parse()
 
 	if *versionFlag {
@@ -160,6 +167,17 @@ func main() {
 	// 	logrus.Fatal(err)
 	// }
 	// return
+	if *tokenHoldersBackfill != "" {
+		if !utils.IsEth1Address(*tokenHoldersBackfill) {
+			logrus.Fatalf("invalid token address %v", *tokenHoldersBackfill)
+		}
+		err = BackfillTokenHolders(bt, client, common.FromHex(*tokenHoldersBackfill), *balanceUpdaterBatchSize)
+		if err != nil {
+			logrus.Fatalf("error backfilling token holders: %v", err)
+		}
+		return
+	}
+
 	if *enableFullBalanceUpdater {
 		ProcessMetadataUpdates(bt, client, balanceUpdaterPrefix, *balanceUpdaterBatchSize, -1)
 		return
@@ -205,7 +223,17 @@ func main() {
 		bt.TransformERC1155,
 		bt.TransformUncle,
 		bt.TransformWithdrawals,
-		bt.TransformEnsNameRegistered)
+		bt.TransformEnsNameRegistered,
+		bt.TransformBalanceDeltas)
+
+	eventIndexers, err := db.NewEventIndexers(utils.Config.Indexer.EventIndexers)
+	if err != nil {
+		logrus.Fatalf("error initializing event indexers: %v", err)
+	}
+	for _, indexer := range eventIndexers {
+		logrus.Infof("indexing events of contract %v as %v", indexer.Contract(), indexer.Name())
+		transforms = append(transforms, bt.TransformEvents(indexer))
+	}
 
 	cache := freecache.NewCache(100 * 1024 * 1024) // 100 MB limit
 
@@ -377,6 +405,22 @@ func main() {
 			}
 		}
 
+		if *enableNftUpdater {
+			err := bt.ImportNftUpdates(*nftUpdaterBatchSize)
+			if err != nil {
+				logrus.WithError(err).Errorf("error updating nft owners")
+				continue
+			}
+		}
+
+		if *enableTokenHolderUpdater {
+			err := ImportTokenHolderUpdates(bt, client, *tokenHolderUpdaterBatchSize)
+			if err != nil {
+				logrus.WithError(err).Errorf("error updating token holders")
+				continue
+			}
+		}
+
 		logrus.Infof("index run completed")
 		services.ReportStatus("eth1indexer", "Running", nil)
 	}
@@ -384,6 +428,88 @@ func main() {
 	// utils.WaitForCtrlC()
 }
 
+// ImportTokenHolderUpdates retrieves the balances of the holders that were marked by ERC-20 transfers at the block of their latest transfer and saves them to the
+// holder index, the blocks are processed in ascending order so that the holder counts are dated with the day of the transfers
+func ImportTokenHolderUpdates(bt *db.Bigtable, client *rpc.ErigonClient, batchSize int64) error {
+	updates, keys, err := bt.GetTokenHolderUpdates(batchSize)
+	if err != nil {
+		return fmt.Errorf("error retrieving token holder updates: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	logrus.Infof("importing %v token holder updates", len(updates))
+
+	for start := 0; start < len(updates); {
+		end := start
+		pairs := []*types.Eth1AddressBalance{}
+		for ; end < len(updates) && updates[end].BlockNumber == updates[start].BlockNumber; end++ {
+			pairs = append(pairs, &types.Eth1AddressBalance{Address: updates[end].Address, Token: updates[end].Token})
+		}
+		balances, err := client.GetBalancesAtBlock(pairs, updates[start].BlockNumber)
+		if err != nil {
+			return fmt.Errorf("error retrieving balances at block %v from node: %w", updates[start].BlockNumber, err)
+		}
+		err = db.SaveTokenHolderBalances(balances, updates[start].BlockNumber, updates[start].BlockTime)
+		if err != nil {
+			return err
+		}
+		start = end
+	}
+	return bt.DeleteTokenHolderUpdates(keys)
+}
+
+// BackfillTokenHolders retrieves the current balances of all addresses that sent or received an ERC-20 token and adds them to the holder index of the token
+func BackfillTokenHolders(bt *db.Bigtable, client *rpc.ErigonClient, token []byte, batchSize int) error {
+	addresses := make(map[string]bool)
+	prefix := fmt.Sprintf("%d:I:ERC20:%x:ALL:%s", utils.Config.Chain.Config.DepositChainID, token, db.FILTER_TIME)
+	for {
+		transfers, lastKey, err := bt.GetEth1TxForToken(prefix, 1000)
+		if err != nil {
+			return fmt.Errorf("error retrieving transfers of token %x: %w", token, err)
+		}
+		for _, transfer := range transfers {
+			addresses[string(transfer.From)] = true
+			addresses[string(transfer.To)] = true
+		}
+		if len(transfers) == 0 || lastKey == "" {
+			break
+		}
+		prefix = lastKey
+	}
+	logrus.Infof("retrieving balances of %v addresses of token %x", len(addresses), token)
+
+	pairs := make([]*types.Eth1AddressBalance, 0, len(addresses))
+	for address := range addresses {
+		pairs = append(pairs, &types.Eth1AddressBalance{Address: []byte(address), Token: token})
+	}
+	// all balances are retrieved at the same block, transfers after it are applied by the token holder updater
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	header, err := client.GetNativeClient().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error retrieving latest eth block: %w", err)
+	}
+	blockNumber := header.Number.Uint64()
+	blockTime := time.Unix(int64(header.Time), 0)
+	for start := 0; start < len(pairs); start += batchSize {
+		end := start + batchSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		balances, err := client.GetBalancesAtBlock(pairs[start:end], blockNumber)
+		if err != nil {
+			return fmt.Errorf("error retrieving balances from node: %w", err)
+		}
+		err = db.SaveTokenHolderBalances(balances, blockNumber, blockTime)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("saved %v of %v balances of token %x", end, len(pairs), token)
+	}
+	return nil
+}
+
 func UpdateTokenPrices(bt *db.Bigtable, client *rpc.ErigonClient, tokenListPath string) error {
 
 	tokenListContent, err := ioutil.ReadFile(tokenListPath)
@@ -623,6 +749,13 @@ func ProcessMetadataUpdates(bt *db.Bigtable, client *rpc.ErigonClient, prefix st
 		// 	logrus.Infof("retrieved balance %x for token %x of address %x", b.Balance, b.Token, b.Address)
 		// }
 
+		// all balances of a run are retrieved at the same block
+		blockNumber, err := client.GetLatestEth1BlockNumber()
+		if err != nil {
+			logrus.Errorf("error retrieving latest eth block number: %v", err)
+			return
+		}
+
 		balances := make([]*types.Eth1AddressBalance, 0, len(pairs))
 		for b := 0; b < len(pairs); b += batchSize {
 			start := b
@@ -633,7 +766,7 @@ func ProcessMetadataUpdates(bt *db.Bigtable, client *rpc.ErigonClient, prefix st
 
 			logrus.Infof("processing batch %v with start %v and end %v", b, start, end)
 
-			b, err := client.GetBalances(pairs[start:end], 2, 4)
+			b, err := client.GetBalancesAtBlock(pairs[start:end], blockNumber)
 
 			if err != nil {
 				logrus.Errorf("error retrieving balances from node: %v", err)
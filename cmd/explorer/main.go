@@ -262,7 +262,9 @@ func main() {
 			return
 		}
 
+		price.InitSources(utils.Config)
 		go services.StartHistoricPriceService()
+		go services.StartHourlyPriceService()
 		go exporter.Start(rpcClient)
 	}
 
@@ -304,13 +306,18 @@ func main() {
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}", handlers.ApiValidatorGet).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator", handlers.ApiValidatorPost).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawals", handlers.ApiValidatorWithdrawals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawals/next", handlers.ApiValidatorNextWithdrawal).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/queue", handlers.ApiValidatorQueueEstimate).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/blsChange", handlers.ApiValidatorBlsChange).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/balancehistory", handlers.ApiValidatorBalanceHistory).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/incomedetailhistory", handlers.ApiValidatorIncomeDetailsHistory).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/performance", handlers.ApiValidatorPerformance).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/execution/performance", handlers.ApiValidatorExecutionPerformance).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestations", handlers.ApiValidatorAttestations).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/syncduties", handlers.ApiValidatorSyncDuties).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/proposals", handlers.ApiValidatorProposals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/proposals/upcoming", handlers.ApiValidatorUpcomingProposals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationrewards", handlers.ApiValidatorAttestationRewards).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/deposits", handlers.ApiValidatorDeposits).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationefficiency", handlers.ApiValidatorAttestationEfficiency).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationeffectiveness", handlers.ApiValidatorAttestationEffectiveness).Methods("GET", "OPTIONS")
@@ -318,6 +325,9 @@ func main() {
 		apiV1Router.HandleFunc("/validator/eth1/{address}", handlers.ApiValidatorByEth1Address).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/withdrawalCredentials/{withdrawalCredentialsOrEth1address}", handlers.ApiWithdrawalCredentialsValidators).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validators/queue", handlers.ApiValidatorQueue).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators/queue/simulate", handlers.ApiValidatorQueueSimulation).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators/benchmark", handlers.ApiValidatorBenchmark).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators/ethstore", handlers.ApiValidatorEthStore).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/graffitiwall", handlers.ApiGraffitiwall).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/chart/{chart}", handlers.ApiChart).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/user/token", handlers.APIGetToken).Methods("POST", "OPTIONS")
@@ -330,9 +340,11 @@ func main() {
 		apiV1Router.HandleFunc("/stats/{apiKey}", handlers.ClientStatsPostOld).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/client/metrics", handlers.ClientStatsPostNew).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/app/dashboard", handlers.ApiDashboard).Methods("POST", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{shareId}", handlers.ApiSharedDashboard).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/rocketpool/stats", handlers.ApiRocketpoolStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/rocketpool/validator/{indexOrPubkey}", handlers.ApiRocketpoolValidators).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/ethstore/{day}", handlers.ApiEthStoreDay).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/statistics/status", handlers.ApiStatisticsStatus).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/execution/gasnow", handlers.ApiEth1GasNowData).Methods("GET", "OPTIONS")
 		// query params: token
@@ -345,6 +357,15 @@ func main() {
 		apiV1Router.HandleFunc("/execution/address/{address}/blocks", handlers.ApiEth1AddressBlocks).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/address/{address}/uncles", handlers.ApiEth1AddressUncles).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/address/{address}/tokens", handlers.ApiEth1AddressTokens).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/balance", handlers.ApiEth1AddressBalance).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/balance/history", handlers.ApiEth1AddressBalanceHistory).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/labels", handlers.ApiEth1AddressLabels).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/search", handlers.ApiSearch).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/mempool/{address}", handlers.ApiEth1MempoolSender).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/tx/{hash}/decoded", handlers.ApiEth1TxDecoded).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/events/{indexerName}", handlers.ApiEth1IndexedEvents).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/token/{token}/holders", handlers.ApiTokenHolders).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/token/{token}/holders/history", handlers.ApiTokenHolderCountHistory).Methods("GET", "OPTIONS")
 		// // query params: type={erc20,erc721,erc1155}, address
 
 		// apiV1Router.HandleFunc("/execution/transactions", handlers.ApiEth1Tx).Methods("GET", "OPTIONS")
@@ -365,6 +386,9 @@ func main() {
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/widget", handlers.GetMobileWidgetStatsGet).Methods("GET")
 		apiV1Router.HandleFunc("/dashboard/widget", handlers.GetMobileWidgetStatsPost).Methods("POST")
 		apiV1Router.HandleFunc("/ens/lookup/{domain}", handlers.ResolveEnsDomain).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/nodejobs", handlers.ApiNodeJobsCreate).Methods("POST", "OPTIONS")
+		apiV1Router.HandleFunc("/nodejobs", handlers.ApiNodeJobsList).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/nodejobs/{ids}", handlers.ApiNodeJobs).Methods("GET", "OPTIONS")
 		apiV1Router.Use(utils.CORSMiddleware)
 
 		apiV1AuthRouter := apiV1Router.PathPrefix("/user").Subrouter()
@@ -377,6 +401,31 @@ func main() {
 		apiV1AuthRouter.HandleFunc("/validator/{pubkey}/add", handlers.UserValidatorWatchlistAdd).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/validator/{pubkey}/remove", handlers.UserValidatorWatchlistRemove).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/dashboard/save", handlers.UserDashboardWatchlistAdd).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards", handlers.UserDashboards).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards", handlers.UserDashboardCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}", handlers.UserDashboard).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/update", handlers.UserDashboardUpdate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/delete", handlers.UserDashboardDelete).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/share", handlers.UserDashboardShare).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/groups", handlers.UserDashboardGroupCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/groups/{groupId}/update", handlers.UserDashboardGroupUpdate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/groups/{groupId}/delete", handlers.UserDashboardGroupDelete).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/validators", handlers.UserDashboardValidatorsAdd).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/validators/remove", handlers.UserDashboardValidatorsRemove).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validatorsets", handlers.UserValidatorSets).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validatorsets", handlers.UserValidatorSetCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validatorsets/{setId}", handlers.UserValidatorSet).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validatorsets/{setId}/delete", handlers.UserValidatorSetDelete).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/execution/contract/{address}/abi", handlers.ApiContractAbiSubmit).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/labelsets", handlers.UserAddressLabelSets).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/labelsets", handlers.UserAddressLabelSetCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/labelsets/{setId}", handlers.UserAddressLabelSet).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/labelsets/{setId}/import", handlers.UserAddressLabelSetImport).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/labelsets/{setId}/delete", handlers.UserAddressLabelSetDelete).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/organisations", handlers.UserOrganisations).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/organisations", handlers.UserOrganisationCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/organisations/{organisationId}/members", handlers.UserOrganisationMemberAdd).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/organisations/{organisationId}/members/remove", handlers.UserOrganisationMemberRemove).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/bundled/subscribe", handlers.MultipleUsersNotificationsSubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/bundled/unsubscribe", handlers.MultipleUsersNotificationsUnsubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/subscribe", handlers.UserNotificationsSubscribe).Methods("POST", "OPTIONS")
@@ -397,7 +446,7 @@ func main() {
 		// logrus.Infof("frontend services initiated")
 
 		logrus.Infof("initializing prices")
-		price.Init(utils.Config.Chain.Config.DepositChainID, utils.Config.Eth1ErigonEndpoint)
+		price.Init(utils.Config)
 		logrus.Infof("prices initialized")
 		if !utils.Config.Frontend.Debug {
 			logrus.Infof("initializing ethclients")
@@ -455,6 +504,8 @@ func main() {
 			router.HandleFunc("/address/{address}/erc1155", handlers.Eth1AddressErc1155Transactions).Methods("GET")
 			router.HandleFunc("/token/{token}", handlers.Eth1Token).Methods("GET")
 			router.HandleFunc("/token/{token}/transfers", handlers.Eth1TokenTransfers).Methods("GET")
+			router.HandleFunc("/token/{token}/nft", handlers.NftCollection).Methods("GET")
+			router.HandleFunc("/token/{token}/nft/{id}", handlers.NftToken).Methods("GET")
 			router.HandleFunc("/transactions", handlers.Eth1Transactions).Methods("GET")
 			router.HandleFunc("/transactions/data", handlers.Eth1TransactionsData).Methods("GET")
 			router.HandleFunc("/block/{block}", handlers.Eth1Block).Methods("GET")
@@ -518,10 +569,15 @@ func main() {
 			router.HandleFunc("/dashboard/data/allbalances", handlers.DashboardDataBalanceCombined).Methods("GET")
 			router.HandleFunc("/dashboard/data/proposals", handlers.DashboardDataProposals).Methods("GET")
 			router.HandleFunc("/dashboard/data/proposalshistory", handlers.DashboardDataProposalsHistory).Methods("GET")
+			router.HandleFunc("/dashboard/data/upcomingproposals", handlers.DashboardDataUpcomingProposals).Methods("GET")
+			router.HandleFunc("/dashboard/data/incomedetails", handlers.DashboardDataIncomeDetails).Methods("GET")
+			router.HandleFunc("/dashboard/data/missedrewards", handlers.DashboardDataMissedRewards).Methods("GET")
 			router.HandleFunc("/dashboard/data/validators", handlers.DashboardDataValidators).Methods("GET")
 			router.HandleFunc("/dashboard/data/withdrawal", handlers.DashboardDataWithdrawals).Methods("GET")
 			router.HandleFunc("/dashboard/data/effectiveness", handlers.DashboardDataEffectiveness).Methods("GET")
 			router.HandleFunc("/dashboard/data/earnings", handlers.DashboardDataEarnings).Methods("GET")
+			router.HandleFunc("/dashboard/data/ethstore", handlers.DashboardDataEthStore).Methods("GET")
+			router.HandleFunc("/dashboard/calendar.ics", handlers.DashboardCalendar).Methods("GET")
 			router.HandleFunc("/graffitiwall", handlers.Graffitiwall).Methods("GET")
 			router.HandleFunc("/calculator", handlers.StakingCalculator).Methods("GET")
 			router.HandleFunc("/search", handlers.Search).Methods("POST")
@@ -542,6 +598,7 @@ func main() {
 			router.HandleFunc("/ens/{search}", handlers.EnsSearch).Methods("GET")
 
 			router.HandleFunc("/ethstore", handlers.EthStore).Methods("GET")
+			router.HandleFunc("/benchmark", handlers.ValidatorBenchmark).Methods("GET")
 
 			router.HandleFunc("/stakingServices", handlers.StakingServices).Methods("GET")
 
@@ -612,6 +669,31 @@ func main() {
 			authRouter.HandleFunc("/watchlist/add", handlers.UsersModalAddValidator).Methods("POST")
 			authRouter.HandleFunc("/watchlist/remove", handlers.UserModalRemoveSelectedValidator).Methods("POST")
 			authRouter.HandleFunc("/watchlist/update", handlers.UserModalManageNotificationModal).Methods("POST")
+			authRouter.HandleFunc("/dashboards", handlers.UserDashboards).Methods("GET")
+			authRouter.HandleFunc("/dashboards", handlers.UserDashboardCreate).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}", handlers.UserDashboard).Methods("GET")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/update", handlers.UserDashboardUpdate).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/delete", handlers.UserDashboardDelete).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/share", handlers.UserDashboardShare).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/groups", handlers.UserDashboardGroupCreate).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/groups/{groupId}/update", handlers.UserDashboardGroupUpdate).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/groups/{groupId}/delete", handlers.UserDashboardGroupDelete).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/validators", handlers.UserDashboardValidatorsAdd).Methods("POST")
+			authRouter.HandleFunc("/dashboards/{dashboardId}/validators/remove", handlers.UserDashboardValidatorsRemove).Methods("POST")
+			authRouter.HandleFunc("/validatorsets", handlers.UserValidatorSets).Methods("GET")
+			authRouter.HandleFunc("/validatorsets", handlers.UserValidatorSetCreate).Methods("POST")
+			authRouter.HandleFunc("/validatorsets/{setId}", handlers.UserValidatorSet).Methods("GET")
+			authRouter.HandleFunc("/validatorsets/{setId}/delete", handlers.UserValidatorSetDelete).Methods("POST")
+			authRouter.HandleFunc("/execution/contract/{address}/abi", handlers.ApiContractAbiSubmit).Methods("POST")
+			authRouter.HandleFunc("/labelsets", handlers.UserAddressLabelSets).Methods("GET")
+			authRouter.HandleFunc("/labelsets", handlers.UserAddressLabelSetCreate).Methods("POST")
+			authRouter.HandleFunc("/labelsets/{setId}", handlers.UserAddressLabelSet).Methods("GET")
+			authRouter.HandleFunc("/labelsets/{setId}/import", handlers.UserAddressLabelSetImport).Methods("POST")
+			authRouter.HandleFunc("/labelsets/{setId}/delete", handlers.UserAddressLabelSetDelete).Methods("POST")
+			authRouter.HandleFunc("/organisations", handlers.UserOrganisations).Methods("GET")
+			authRouter.HandleFunc("/organisations", handlers.UserOrganisationCreate).Methods("POST")
+			authRouter.HandleFunc("/organisations/{organisationId}/members", handlers.UserOrganisationMemberAdd).Methods("POST")
+			authRouter.HandleFunc("/organisations/{organisationId}/members/remove", handlers.UserOrganisationMemberRemove).Methods("POST")
 			authRouter.HandleFunc("/notifications/unsubscribe", handlers.UserNotificationsUnsubscribe).Methods("POST")
 			authRouter.HandleFunc("/notifications/bundled/subscribe", handlers.MultipleUsersNotificationsSubscribeWeb).Methods("POST", "OPTIONS")
 			authRouter.HandleFunc("/global_notification", handlers.UserGlobalNotification).Methods("GET")
@@ -3,8 +3,10 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"eth2-exporter/db"
 	"eth2-exporter/exporter"
+	"eth2-exporter/price"
 	"eth2-exporter/rpc"
 	"eth2-exporter/services"
 	"eth2-exporter/types"
@@ -12,6 +14,9 @@ import (
 	"eth2-exporter/version"
 	"fmt"
 	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +45,11 @@ var opts = struct {
 	BatchSize       uint64
 	DataConcurrency uint64
 	Transformers    string
+	Samples         uint64
+	LabelsFile      string
+	LabelsSet       string
+	LabelsSource    string
+	LabelsReplace   bool
 	Family          string
 	Key             string
 	DryRun          bool
@@ -47,7 +57,7 @@ var opts = struct {
 
 func main() {
 	configPath := flag.String("config", "config/default.config.yml", "Path to the config file")
-	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, applyDbSchema, epoch-export, debug-rewards, clear-bigtable, index-old-eth1-blocks, update-aggregation-bits, historic-prices-export, index-missing-blocks, export-epoch-missed-slots, migrate-last-attestation-slot-bigtable")
+	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, applyDbSchema, epoch-export, debug-rewards, clear-bigtable, index-old-eth1-blocks, update-aggregation-bits, historic-prices-export, index-missing-blocks, export-epoch-missed-slots, migrate-last-attestation-slot-bigtable, validate-balance-history, import-address-labels, backfill-nft-transfers, backfill-ens-records")
 	flag.Uint64Var(&opts.StartEpoch, "start-epoch", 0, "start epoch")
 	flag.Uint64Var(&opts.EndEpoch, "end-epoch", 0, "end epoch")
 	flag.Uint64Var(&opts.User, "user", 0, "user id")
@@ -62,6 +72,11 @@ func main() {
 	flag.Uint64Var(&opts.DataConcurrency, "data.concurrency", 30, "Concurrency to use when indexing data from bigtable")
 	flag.Uint64Var(&opts.BatchSize, "data.batchSize", 1000, "Batch size")
 	flag.StringVar(&opts.Transformers, "transformers", "", "Comma separated list of transformers used by the eth1 indexer")
+	flag.Uint64Var(&opts.Samples, "samples", 100, "Number of randomly sampled blocks to validate")
+	flag.StringVar(&opts.LabelsFile, "labels.file", "", "Csv or json file with address labels to import")
+	flag.StringVar(&opts.LabelsSet, "labels.set", "", "Name of the community address label set the labels are imported into")
+	flag.StringVar(&opts.LabelsSource, "labels.source", "", "Source of the address labels, used when the community address label set is created")
+	flag.BoolVar(&opts.LabelsReplace, "labels.replace", false, "Remove all existing labels of the address label set before the import")
 	dryRun := flag.String("dry-run", "true", "if 'false' it deletes all rows starting with the key, per default it only logs the rows that would be deleted, but does not really delete them")
 	versionFlag := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
@@ -201,6 +216,9 @@ func main() {
 		ClearBigtable(opts.Family, opts.Key, opts.DryRun, bt)
 	case "index-old-eth1-blocks":
 		IndexOldEth1Blocks(opts.StartBlock, opts.EndBlock, opts.BatchSize, opts.DataConcurrency, opts.Transformers, bt, erigonClient)
+	case "backfill-nft-transfers":
+		// re-indexes the nft transfers of blocks that were indexed before the id index existed and before erc1155 batch transfers were split up by id
+		IndexOldEth1Blocks(opts.StartBlock, opts.EndBlock, opts.BatchSize, opts.DataConcurrency, "TransformERC721,TransformERC1155", bt, erigonClient)
 	case "update-aggregation-bits":
 		updateAggreationBits(rpcClient, opts.StartEpoch, opts.EndEpoch, opts.DataConcurrency)
 	case "historic-prices-export":
@@ -209,6 +227,12 @@ func main() {
 		indexMissingBlocks(opts.StartBlock, opts.EndBlock, bt, erigonClient)
 	case "migrate-last-attestation-slot-bigtable":
 		migrateLastAttestationSlotToBigtable()
+	case "validate-balance-history":
+		validateBalanceHistory(opts.StartBlock, opts.EndBlock, opts.Samples, bt, erigonClient)
+	case "import-address-labels":
+		importAddressLabels(opts.LabelsFile, opts.LabelsSet, opts.LabelsSource, opts.LabelsReplace)
+	case "backfill-ens-records":
+		backfillEnsRecords(bt)
 	default:
 		utils.LogFatal(nil, "unknown command", 0)
 	}
@@ -473,6 +497,114 @@ func ClearBigtable(family string, key string, dryRun bool, bt *db.Bigtable) {
 }
 
 // Let's find blocks that are missing in bt and index them.
+// importAddressLabels imports a csv or json list of address labels into a community address label set that is visible to everyone
+func importAddressLabels(file, setName, source string, replace bool) {
+	if file == "" || setName == "" {
+		utils.LogFatal(nil, "labels.file and labels.set are required", 0)
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+
+	f, err := os.Open(file)
+	if err != nil {
+		utils.LogFatal(err, "error opening address label file", 0)
+	}
+	defer f.Close()
+
+	labels, err := utils.ParseAddressLabels(f, format)
+	if err != nil {
+		utils.LogFatal(err, "error parsing address label file", 0)
+	}
+
+	set, err := db.GetOrCreateCommunityAddressLabelSet(setName, source)
+	if err != nil {
+		utils.LogFatal(err, "error retrieving address label set", 0)
+	}
+	err = db.ImportAddressLabels(set.ID, labels, replace)
+	if err != nil {
+		utils.LogFatal(err, "error importing address labels", 0)
+	}
+	logrus.Infof("imported %v address labels into the community address label set %v (%v)", len(labels), set.Name, set.ID)
+}
+
+// backfillEnsRecords marks the ens names that were validated before owners and resolver records were stored dirty,
+// the eth1 indexer then validates them again and stores their owner, records and expiry
+func backfillEnsRecords(bt *db.Bigtable) {
+	names, err := db.GetEnsNamesWithoutRecords()
+	if err != nil {
+		utils.LogFatal(err, "error retrieving ens names without records", 0)
+	}
+
+	batchSize := 10000
+	for b := 0; b < len(names); b += batchSize {
+		end := b + batchSize
+		if end > len(names) {
+			end = len(names)
+		}
+		err = bt.QueueEnsNameUpdates(names[b:end])
+		if err != nil {
+			utils.LogFatal(err, "error queuing ens names for an update", 0)
+		}
+		logrus.Infof("queued %v of %v ens names for an update", end, len(names))
+	}
+	logrus.Infof("queued %v ens names without records, they are updated by the next ens import of the eth1 indexer", len(names))
+}
+
+// validateBalanceHistory compares the balances calculated from the indexed balance deltas with the balances returned by eth_getBalance
+// for up to three touched addresses of randomly sampled blocks within [start, end]
+func validateBalanceHistory(start uint64, end uint64, samples uint64, bt *db.Bigtable, client *rpc.ErigonClient) {
+	if end == 0 {
+		lastBlockFromBlocksTable, err := bt.GetLastBlockInBlocksTable()
+		if err != nil {
+			utils.LogError(err, "error retrieving last blocks from blocks table", 0)
+			return
+		}
+		end = uint64(lastBlockFromBlocksTable)
+	}
+	if start > end {
+		utils.LogError(nil, fmt.Sprintf("invalid block range [%v]->[%v]", start, end), 0)
+		return
+	}
+
+	checked := 0
+	mismatches := 0
+	for i := uint64(0); i < samples; i++ {
+		number := start + uint64(rand.Int63n(int64(end-start+1)))
+		block, err := bt.GetBlockFromBlocksTable(number)
+		if err != nil {
+			utils.LogError(err, fmt.Sprintf("error retrieving block %v from blocks table", number), 0)
+			continue
+		}
+
+		addresses := 0
+		for address := range utils.Eth1NativeBalanceDeltas(block) {
+			if addresses == 3 {
+				break
+			}
+			addresses++
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			expected, err := client.GetNativeClient().BalanceAt(ctx, address, new(big.Int).SetUint64(number))
+			cancel()
+			if err != nil {
+				utils.LogError(err, fmt.Sprintf("error retrieving balance of %v at block %v from the node", address, number), 0)
+				continue
+			}
+			actual, err := bt.GetNativeBalanceAtBlock(address.Bytes(), number)
+			if err != nil {
+				utils.LogError(err, fmt.Sprintf("error retrieving balance of %v at block %v from bigtable", address, number), 0)
+				continue
+			}
+
+			checked++
+			if actual.Cmp(expected) != 0 {
+				mismatches++
+				logrus.Warnf("balance mismatch for %v at block %v: indexed %v, node %v, difference %v", address, number, actual, expected, new(big.Int).Sub(expected, actual))
+			}
+		}
+	}
+	logrus.Infof("validated %v balances of %v sampled blocks in [%v]->[%v], found %v mismatches", checked, samples, start, end, mismatches)
+}
+
 func indexMissingBlocks(start uint64, end uint64, bt *db.Bigtable, client *rpc.ErigonClient) {
 
 	if end == 0 {
@@ -549,7 +681,7 @@ func IndexOldEth1Blocks(startBlock uint64, endBlock uint64, batchSize uint64, co
 	logrus.Infof("transformerFlag: %v", transformerFlag)
 	transformerList := strings.Split(transformerFlag, ",")
 	if transformerFlag == "all" {
-		transformerList = []string{"TransformBlock", "TransformTx", "TransformItx", "TransformERC20", "TransformERC721", "TransformERC1155", "TransformWithdrawals", "TransformUncle", "TransformEnsNameRegistered"}
+		transformerList = []string{"TransformBlock", "TransformTx", "TransformItx", "TransformERC20", "TransformERC721", "TransformERC1155", "TransformWithdrawals", "TransformUncle", "TransformEnsNameRegistered", "TransformBalanceDeltas"}
 	} else if len(transformerList) == 0 {
 		utils.LogError(nil, "no transformer functions provided", 0)
 		return
@@ -580,6 +712,8 @@ func IndexOldEth1Blocks(startBlock uint64, endBlock uint64, batchSize uint64, co
 		case "TransformEnsNameRegistered":
 			transforms = append(transforms, bt.TransformEnsNameRegistered)
 			importENSChanges = true
+		case "TransformBalanceDeltas":
+			transforms = append(transforms, bt.TransformBalanceDeltas)
 		default:
 			utils.LogError(nil, "Invalid transformer flag %v", 0)
 			return
@@ -630,6 +764,7 @@ func IndexOldEth1Blocks(startBlock uint64, endBlock uint64, batchSize uint64, co
 
 func exportHistoricPrices(dayStart uint64, dayEnd uint64) {
 	logrus.Infof("exporting historic prices for days %v - %v", dayStart, dayEnd)
+	price.InitSources(utils.Config)
 	for day := dayStart; day <= dayEnd; day++ {
 		timeStart := time.Now()
 		ts := utils.DayToTime(int64(day)).UTC().Truncate(utils.Day)
@@ -639,6 +774,10 @@ func exportHistoricPrices(dayStart uint64, dayEnd uint64) {
 			utils.LogError(err, errMsg, 0)
 			return
 		}
+		err = services.WriteHourlyPricesForDay(ts)
+		if err != nil && !errors.Is(err, price.ErrHistoricPricesNotSupported) {
+			utils.LogError(err, fmt.Sprintf("error exporting hourly prices for day %v", day), 0)
+		}
 		logrus.Printf("finished export for day %v, took %v", day, time.Since(timeStart))
 
 		if day < dayEnd {
@@ -106,5 +106,9 @@ func (njp *NodeJobsProcessor) Process() error {
 	if err != nil {
 		return fmt.Errorf("error submitting job: %w", err)
 	}
+	err = db.SendNodeJobCallbacks()
+	if err != nil {
+		return fmt.Errorf("error sending job callbacks: %w", err)
+	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"eth2-exporter/db"
 	"eth2-exporter/services"
 	"eth2-exporter/types"
@@ -8,11 +9,14 @@ import (
 	"eth2-exporter/version"
 	"flag"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	eth_rewards "github.com/gobitfly/eth-rewards"
 	"github.com/gobitfly/eth-rewards/beacon"
+	itypes "github.com/gobitfly/eth-rewards/types"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -28,6 +32,7 @@ func main() {
 	epochStart := flag.Uint64("epoch-start", 0, "start epoch to export")
 	epochEnd := flag.Uint64("epoch-end", 0, "end epoch to export")
 	sleepDuration := flag.Duration("sleep", time.Minute, "duration to sleep between export runs")
+	idealRewardsBackfill := flag.Bool("ideal-rewards-backfill", false, "only export the missing ideal attestation rewards of the epochs from epoch-start to epoch-end")
 
 	versionFlag := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
@@ -73,6 +78,14 @@ func main() {
 	}
 	defer bt.Close()
 
+	if *idealRewardsBackfill {
+		if *epochEnd == 0 {
+			logrus.Fatal("epoch-end is required for the ideal rewards backfill")
+		}
+		backfillIdealAttestationRewards(*bnAddress, *epochStart, *epochEnd)
+		return
+	}
+
 	if *epochEnd != 0 {
 		g := errgroup.Group{}
 		g.SetLimit(*batchConcurrency)
@@ -112,7 +125,7 @@ func main() {
 
 				var err error
 				for i := 0; i < 10; i++ {
-					err = export(e, bt, client, enAddress)
+					err = export(e, bt, client, bnAddress, enAddress)
 
 					if err != nil {
 						logrus.Error(err)
@@ -148,7 +161,7 @@ func main() {
 				utils.LogFatal(err, "getting chain head from lighthouse error", 0)
 			}
 			for _, e := range notExportedEpochs {
-				err := export(e, bt, client, enAddress)
+				err := export(e, bt, client, bnAddress, enAddress)
 
 				if err != nil {
 					logrus.Error(err)
@@ -173,13 +186,13 @@ func main() {
 		}
 	}
 
-	err = export(uint64(*epoch), bt, client, enAddress)
+	err = export(uint64(*epoch), bt, client, bnAddress, enAddress)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 }
 
-func export(epoch uint64, bt *db.Bigtable, client *beacon.Client, elClient *string) error {
+func export(epoch uint64, bt *db.Bigtable, client *beacon.Client, bnAddress, elClient *string) error {
 	start := time.Now()
 	logrus.Infof("retrieving rewards details for epoch %v", epoch)
 
@@ -195,5 +208,73 @@ func export(epoch uint64, bt *db.Bigtable, client *beacon.Client, elClient *stri
 	if err != nil {
 		return fmt.Errorf("error saving reward details to bigtable: %v", err)
 	}
+
+	// the ideal rewards are not part of the reward details, they are needed to compare the attestation rewards of validators with perfect attestations
+	idealRewards, err := getIdealAttestationRewards(*bnAddress, epoch)
+	if err != nil {
+		return fmt.Errorf("error retrieving ideal attestation rewards for epoch %v: %v", epoch, err)
+	}
+	err = db.SaveIdealAttestationRewards(epoch, idealRewards)
+	if err != nil {
+		return fmt.Errorf("error saving ideal attestation rewards: %v", err)
+	}
+
+	if utils.Config.RollingValidatorStats.Enabled {
+		err = db.SaveRollingValidatorIncome(uint64(epoch), rewards)
+		if err != nil {
+			return fmt.Errorf("error saving rolling validator income: %v", err)
+		}
+	}
 	return nil
 }
+
+var idealRewardsClient = &http.Client{Timeout: time.Minute}
+
+// getIdealAttestationRewards returns the ideal attestation rewards of an epoch. They are part of every attestation rewards response,
+// so only the rewards of validator 0 are requested instead of the rewards of all validators that the reward details already contain.
+func getIdealAttestationRewards(bnAddress string, epoch uint64) ([]*itypes.IdealAttestationRewardContainer, error) {
+	resp, err := idealRewardsClient.Post(fmt.Sprintf("%s/eth/v1/beacon/rewards/attestations/%d", bnAddress, epoch), "application/json", strings.NewReader(`["0"]`))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http request error: %s", resp.Status)
+	}
+
+	r := &itypes.AttestationRewardsApiResponse{}
+	err = json.NewDecoder(resp.Body).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Data.IdealRewards, nil
+}
+
+// backfillIdealAttestationRewards exports the ideal attestation rewards of the finalized epochs from startEpoch to endEpoch (inclusive) that have none,
+// which are the epochs whose rewards were exported before the ideal rewards were stored
+func backfillIdealAttestationRewards(bnAddress string, startEpoch, endEpoch uint64) {
+	epochs := []uint64{}
+	err := db.WriterDb.Select(&epochs, `
+		SELECT epoch FROM epochs
+		WHERE finalized AND epoch BETWEEN $1 AND $2 AND NOT EXISTS (SELECT 1 FROM attestation_ideal_rewards WHERE attestation_ideal_rewards.epoch = epochs.epoch)
+		ORDER BY epoch DESC`, startEpoch, endEpoch)
+	if err != nil {
+		logrus.Fatalf("error retrieving epochs without ideal attestation rewards: %v", err)
+	}
+
+	logrus.Infof("backfilling ideal attestation rewards of %v epochs", len(epochs))
+	for i, epoch := range epochs {
+		idealRewards, err := getIdealAttestationRewards(bnAddress, epoch)
+		if err == nil {
+			err = db.SaveIdealAttestationRewards(epoch, idealRewards)
+		}
+		if err != nil {
+			logrus.Errorf("error backfilling ideal attestation rewards of epoch %v: %v", epoch, err)
+			continue
+		}
+		if (i+1)%100 == 0 {
+			logrus.Infof("backfilled ideal attestation rewards of %v of %v epochs", i+1, len(epochs))
+		}
+	}
+}
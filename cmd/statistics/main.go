@@ -28,6 +28,7 @@ type options struct {
 	statisticsGraffitiToggle  bool
 	concurrencyTotal          uint64
 	concurrencyCl             uint64
+	retries                   uint64
 }
 
 var opt = &options{}
@@ -37,11 +38,12 @@ func main() {
 	flag.Int64Var(&opt.statisticsDayToExport, "statistics.day", -1, "Day to export statistics (will export the day independent if it has been already exported or not")
 	flag.StringVar(&opt.statisticsDaysToExport, "statistics.days", "", "Days to export statistics (will export the day independent if it has been already exported or not")
 	flag.BoolVar(&opt.statisticsValidatorToggle, "validators.enabled", false, "Toggle exporting validator statistics")
-	flag.StringVar(&opt.statisticsResetColumns, "validators.reset", "", "validator_stats_status columns to reset. Comma separated. Use 'all' for complete resync.")
+	flag.StringVar(&opt.statisticsResetColumns, "validators.reset", "", "validator_stats_status columns to reset, the columns depending on them are reset as well. Comma separated. Use 'all' for complete resync.")
 	flag.BoolVar(&opt.statisticsChartToggle, "charts.enabled", false, "Toggle exporting chart series")
 	flag.BoolVar(&opt.statisticsGraffitiToggle, "graffiti.enabled", false, "Toggle exporting graffiti statistics")
 	flag.Uint64Var(&opt.concurrencyTotal, "concurrency.total", 10, "Concurrency to use when writing total rewards/performance postgres queries")
 	flag.Uint64Var(&opt.concurrencyCl, "concurrency.cl", 50, "Concurrency to use when writing cl postgres queries")
+	flag.Uint64Var(&opt.retries, "validators.retries", 2, "Number of times a failed validator statistics task is retried before the export of the day is aborted")
 
 	versionFlag := flag.Bool("version", false, "Show version and exit")
 	flag.Parse()
@@ -105,7 +107,7 @@ func main() {
 		logrus.Fatalf("error connecting to bigtable: %v", err)
 	}
 
-	price.Init(utils.Config.Chain.Config.DepositChainID, utils.Config.Eth1ErigonEndpoint)
+	price.Init(utils.Config)
 
 	if utils.Config.TieredCacheProvider == "redis" || len(utils.Config.RedisCacheEndpoint) != 0 {
 		cache.MustInitTieredCache(utils.Config.RedisCacheEndpoint)
@@ -137,7 +139,7 @@ func main() {
 
 				clearStatsStatusTable(d, opt.statisticsResetColumns)
 
-				err = db.WriteValidatorStatisticsForDay(uint64(d), opt.concurrencyTotal, opt.concurrencyCl)
+				err = db.WriteValidatorStatisticsForDay(uint64(d), opt.concurrencyTotal, opt.concurrencyCl, opt.retries)
 				if err != nil {
 					logrus.Errorf("error exporting stats for day %v: %v", d, err)
 					break
@@ -177,7 +179,7 @@ func main() {
 		if opt.statisticsValidatorToggle {
 			clearStatsStatusTable(uint64(opt.statisticsDayToExport), opt.statisticsResetColumns)
 
-			err = db.WriteValidatorStatisticsForDay(uint64(opt.statisticsDayToExport), opt.concurrencyTotal, opt.concurrencyCl)
+			err = db.WriteValidatorStatisticsForDay(uint64(opt.statisticsDayToExport), opt.concurrencyTotal, opt.concurrencyCl, opt.retries)
 			if err != nil {
 				logrus.Errorf("error exporting stats for day %v: %v", opt.statisticsDayToExport, err)
 			}
@@ -204,14 +206,14 @@ func main() {
 		return
 	}
 
-	go statisticsLoop(opt.concurrencyTotal, opt.concurrencyCl)
+	go statisticsLoop(opt.concurrencyTotal, opt.concurrencyCl, opt.retries)
 
 	utils.WaitForCtrlC()
 
 	logrus.Println("exiting...")
 }
 
-func statisticsLoop(concurrencyTotal uint64, concurrencyCl uint64) {
+func statisticsLoop(concurrencyTotal uint64, concurrencyCl uint64, retries uint64) {
 	for {
 
 		latestEpoch := services.LatestFinalizedEpoch()
@@ -241,15 +243,28 @@ func statisticsLoop(concurrencyTotal uint64, concurrencyCl uint64) {
 			}
 
 			logrus.Infof("Validator Statistics: Latest epoch is %v, previous day is %v, last exported day is %v", latestEpoch, previousDay, lastExportedDayValidator)
+
+			// recompute the days whose tasks have been reset before exporting new days as later days build on their results,
+			// a day whose tasks keep failing is skipped so that the tasks of later days that do not depend on it are still exported
+			incompleteDays, err := db.GetIncompleteValidatorStatisticsDays(lastExportedDayValidator)
+			if err != nil {
+				logrus.Errorf("error retreiving incomplete days from the db: %v", err)
+			}
+			for _, day := range incompleteDays {
+				err := db.WriteValidatorStatisticsForDay(day, concurrencyTotal, concurrencyCl, retries)
+				if err != nil {
+					logrus.Errorf("error exporting stats for day %v: %v", day, err)
+				}
+			}
+
 			if lastExportedDayValidator != 0 {
 				lastExportedDayValidator++
 			}
 			if lastExportedDayValidator <= previousDay || lastExportedDayValidator == 0 {
 				for day := lastExportedDayValidator; day <= previousDay; day++ {
-					err := db.WriteValidatorStatisticsForDay(day, concurrencyTotal, concurrencyCl)
+					err := db.WriteValidatorStatisticsForDay(day, concurrencyTotal, concurrencyCl, retries)
 					if err != nil {
 						logrus.Errorf("error exporting stats for day %v: %v", day, err)
-						break
 					}
 				}
 			}
@@ -298,22 +313,15 @@ func statisticsLoop(concurrencyTotal uint64, concurrencyCl uint64) {
 }
 
 func clearStatsStatusTable(day uint64, columns string) {
+	if len(columns) == 0 {
+		return
+	}
+	tasks := strings.Split(columns, ",")
 	if columns == "all" {
-		logrus.Infof("Delete validator_stats_status for day %v", day)
-		_, err := db.WriterDb.Exec("DELETE FROM validator_stats_status WHERE day = $1", day)
-		if err != nil {
-			logrus.Fatalf("error resetting status for day %v: %v", day, err)
-		}
-	} else if len(columns) > 0 {
-		logrus.Infof("Resetting columns %v of validator_stats_status for day %v ", columns, day)
-		cols := strings.Join(strings.Split(columns, ","), " = false,")
-		_, err := db.WriterDb.Exec(fmt.Sprintf(`
-			UPDATE validator_stats_status
-			SET %v = false
-			WHERE day = $1
-		`, cols), day)
-		if err != nil {
-			logrus.Fatalf("error resetting status for day %v: %v", day, err)
-		}
+		tasks = db.ValidatorStatisticsTaskNames()
+	}
+	err := db.ResetValidatorStatisticsTasks(day, tasks)
+	if err != nil {
+		logrus.Fatalf("error resetting status for day %v: %v", day, err)
 	}
 }
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"eth2-exporter/types"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ErrAddressLabelSetNotFound is returned if an address label set does not exist or can not be edited by the user
+var ErrAddressLabelSetNotFound = errors.New("address label set not found")
+
+// ErrOrganisationNotFound is returned if an organisation does not exist or is not owned by the user
+var ErrOrganisationNotFound = errors.New("organisation not found")
+
+const addressLabelSetColumns = `id, name, source, user_id, organisation_id, created_ts,
+	(SELECT COUNT(*) FROM address_labels WHERE set_id = address_label_sets.id) AS label_count`
+
+// editableAddressLabelSets matches the sets of the user $1 and of the organisations the user is a member of
+const editableAddressLabelSets = `(address_label_sets.user_id = $1 OR address_label_sets.organisation_id IN (SELECT organisation_id FROM organisations_members WHERE user_id = $1))`
+
+// visibleAddressLabelSets matches the community sets and the sets that can be edited by the user $1
+const visibleAddressLabelSets = `((address_label_sets.user_id IS NULL AND address_label_sets.organisation_id IS NULL) OR ` + editableAddressLabelSets + `)`
+
+const addressLabelColumns = `address_labels.set_id, address_labels.address, address_labels.name, address_labels.category, address_labels.tags, address_labels.confidence,
+	address_label_sets.source, (address_label_sets.user_id IS NOT NULL OR address_label_sets.organisation_id IS NOT NULL) AS private`
+
+// CreateOrganisation stores a new organisation with the owner as its first member
+func CreateOrganisation(ownerID uint64, name string) (*types.Organisation, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	organisation := &types.Organisation{}
+	err = tx.Get(organisation, `INSERT INTO organisations (name, owner_id) VALUES ($1, $2) RETURNING id, name, owner_id, created_ts, 1 AS members`, name, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`INSERT INTO organisations_members (organisation_id, user_id) VALUES ($1, $2)`, organisation.ID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return organisation, tx.Commit()
+}
+
+// GetOrganisations returns all organisations the user is a member of
+func GetOrganisations(userID uint64) ([]*types.Organisation, error) {
+	organisations := []*types.Organisation{}
+	err := FrontendWriterDB.Select(&organisations, `
+		SELECT id, name, owner_id, created_ts, (SELECT COUNT(*) FROM organisations_members WHERE organisation_id = organisations.id) AS members
+		FROM organisations
+		WHERE id IN (SELECT organisation_id FROM organisations_members WHERE user_id = $1)
+		ORDER BY id`, userID)
+	return organisations, err
+}
+
+// AddOrganisationMember adds the user with the given email to an organisation of the owner, nothing is added if no user has the email
+func AddOrganisationMember(ownerID, organisationID uint64, email string) error {
+	return updateOrganisationMember(ownerID, organisationID, email, `
+		INSERT INTO organisations_members (organisation_id, user_id)
+		SELECT $1, id FROM users WHERE email = $2
+		ON CONFLICT DO NOTHING`)
+}
+
+// RemoveOrganisationMember removes the user with the given email from an organisation of the owner, the owner can not be removed
+func RemoveOrganisationMember(ownerID, organisationID uint64, email string) error {
+	return updateOrganisationMember(ownerID, organisationID, email, `
+		DELETE FROM organisations_members
+		WHERE organisation_id = $1 AND user_id = (SELECT id FROM users WHERE email = $2) AND user_id != (SELECT owner_id FROM organisations WHERE id = $1)`)
+}
+
+func updateOrganisationMember(ownerID, organisationID uint64, email string, query string) error {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists := false
+	err = tx.Get(&exists, `SELECT EXISTS (SELECT 1 FROM organisations WHERE id = $1 AND owner_id = $2)`, organisationID, ownerID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrOrganisationNotFound
+	}
+	// an unknown email is not reported so that the members api can not be used to probe for registered email addresses
+	_, err = tx.Exec(query, organisationID, email)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateAddressLabelSet stores a new address label set, the set is a community set if it has neither a user nor an organisation
+func CreateAddressLabelSet(set *types.AddressLabelSet) error {
+	return FrontendWriterDB.Get(set, `
+		INSERT INTO address_label_sets (name, source, user_id, organisation_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+addressLabelSetColumns,
+		set.Name, set.Source, set.UserID, set.OrganisationID)
+}
+
+// GetOrCreateCommunityAddressLabelSet returns the community address label set with the given name, the set is created if it does not exist yet
+func GetOrCreateCommunityAddressLabelSet(name, source string) (*types.AddressLabelSet, error) {
+	set := &types.AddressLabelSet{}
+	err := FrontendWriterDB.Get(set, `SELECT `+addressLabelSetColumns+` FROM address_label_sets WHERE name = $1 AND user_id IS NULL AND organisation_id IS NULL ORDER BY id LIMIT 1`, name)
+	if err == sql.ErrNoRows {
+		set.Name = name
+		set.Source = source
+		return set, CreateAddressLabelSet(set)
+	}
+	return set, err
+}
+
+// GetAddressLabelSets returns the address label sets the user can edit, the sets of the user and of its organisations
+func GetAddressLabelSets(userID uint64) ([]*types.AddressLabelSet, error) {
+	sets := []*types.AddressLabelSet{}
+	err := FrontendWriterDB.Select(&sets, `SELECT `+addressLabelSetColumns+` FROM address_label_sets WHERE `+editableAddressLabelSets+` ORDER BY id`, userID)
+	return sets, err
+}
+
+// GetAddressLabelSet returns an address label set the user can edit, ErrAddressLabelSetNotFound is returned for community sets and sets of other users
+func GetAddressLabelSet(userID, setID uint64) (*types.AddressLabelSet, error) {
+	set := &types.AddressLabelSet{}
+	err := FrontendWriterDB.Get(set, `SELECT `+addressLabelSetColumns+` FROM address_label_sets WHERE id = $2 AND `+editableAddressLabelSets, userID, setID)
+	if err == sql.ErrNoRows {
+		return nil, ErrAddressLabelSetNotFound
+	}
+	return set, err
+}
+
+// DeleteAddressLabelSet deletes an address label set the user can edit including its labels
+func DeleteAddressLabelSet(userID, setID uint64) error {
+	res, err := FrontendWriterDB.Exec(`DELETE FROM address_label_sets WHERE id = $2 AND `+editableAddressLabelSets, userID, setID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAddressLabelSetNotFound
+	}
+	return nil
+}
+
+// ImportAddressLabels stores the labels in a set, existing labels of the same addresses are overwritten.
+// If replace is set all other labels of the set are removed.
+func ImportAddressLabels(setID uint64, labels []*types.AddressLabel, replace bool) error {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if replace {
+		_, err = tx.Exec(`DELETE FROM address_labels WHERE set_id = $1`, setID)
+		if err != nil {
+			return err
+		}
+	}
+
+	batchSize := 5000
+	for b := 0; b < len(labels); b += batchSize {
+		end := b + batchSize
+		if end > len(labels) {
+			end = len(labels)
+		}
+
+		addresses := make(pq.ByteaArray, 0, end-b)
+		names := make(pq.StringArray, 0, end-b)
+		categories := make(pq.StringArray, 0, end-b)
+		// the tags of a label are joined by commas as postgres does not support arrays of arrays with different lengths
+		tags := make(pq.StringArray, 0, end-b)
+		confidences := make(pq.Float64Array, 0, end-b)
+		for _, label := range labels[b:end] {
+			addresses = append(addresses, label.Address)
+			names = append(names, label.Name)
+			categories = append(categories, string(label.Category))
+			tags = append(tags, strings.Join(label.Tags, ","))
+			confidences = append(confidences, label.Confidence)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO address_labels (set_id, address, name, category, tags, confidence)
+			SELECT $1, address, name, category, COALESCE(string_to_array(NULLIF(tags, ''), ','), '{}'), confidence
+			FROM UNNEST($2::bytea[], $3::text[], $4::text[], $5::text[], $6::real[]) AS t(address, name, category, tags, confidence)
+			ON CONFLICT (set_id, address) DO UPDATE SET
+				name = excluded.name,
+				category = excluded.category,
+				tags = excluded.tags,
+				confidence = excluded.confidence`,
+			setID, addresses, names, categories, tags, confidences)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAddressLabelSetLabels returns all labels of a set
+func GetAddressLabelSetLabels(setID uint64) ([]*types.AddressLabel, error) {
+	labels := []*types.AddressLabel{}
+	err := FrontendWriterDB.Select(&labels, `
+		SELECT `+addressLabelColumns+`
+		FROM address_labels
+		INNER JOIN address_label_sets ON address_label_sets.id = address_labels.set_id
+		WHERE address_labels.set_id = $1
+		ORDER BY address_labels.name, address_labels.address`, setID)
+	return labels, err
+}
+
+// GetAddressLabels returns the labels of the addresses that are visible to the user, the community labels and the labels of the user and its organisations.
+// The user id 0 only returns community labels. The labels are mapped by the string of the address bytes, private labels come first.
+func GetAddressLabels(userID uint64, addresses [][]byte) (map[string][]*types.AddressLabel, error) {
+	res := make(map[string][]*types.AddressLabel, len(addresses))
+	if len(addresses) == 0 {
+		return res, nil
+	}
+
+	labels := []*types.AddressLabel{}
+	err := FrontendReaderDB.Select(&labels, `
+		SELECT `+addressLabelColumns+`
+		FROM address_labels
+		INNER JOIN address_label_sets ON address_label_sets.id = address_labels.set_id
+		WHERE address_labels.address = ANY($2) AND `+visibleAddressLabelSets+`
+		ORDER BY private DESC, address_labels.confidence DESC, address_labels.set_id`, userID, pq.ByteaArray(addresses))
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		res[string(label.Address)] = append(res[string(label.Address)], label)
+	}
+	return res, nil
+}
+
+// SearchAddressLabels returns the labels visible to the user whose name starts with the query or that have the query as tag or category
+func SearchAddressLabels(userID uint64, query string, limit uint64) ([]*types.AddressLabel, error) {
+	labels := []*types.AddressLabel{}
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return labels, nil
+	}
+	err := FrontendReaderDB.Select(&labels, `
+		SELECT `+addressLabelColumns+`
+		FROM address_labels
+		INNER JOIN address_label_sets ON address_label_sets.id = address_labels.set_id
+		WHERE (LOWER(address_labels.name) LIKE $2 || '%' OR address_labels.tags @> ARRAY[$3] OR address_labels.category = REPLACE($3, ' ', '_')) AND `+visibleAddressLabelSets+`
+		ORDER BY private DESC, address_labels.confidence DESC, address_labels.name
+		LIMIT $4`, userID, strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query), query, limit)
+	return labels, err
+}
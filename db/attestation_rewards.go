@@ -0,0 +1,57 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+
+	itypes "github.com/gobitfly/eth-rewards/types"
+	"github.com/lib/pq"
+)
+
+// SaveIdealAttestationRewards stores the ideal attestation rewards per effective balance of an epoch as returned by the beacon node
+func SaveIdealAttestationRewards(epoch uint64, ideal []*itypes.IdealAttestationRewardContainer) error {
+	effectiveBalances := make(pq.Int64Array, 0, len(ideal))
+	heads := make(pq.Int64Array, 0, len(ideal))
+	sources := make(pq.Int64Array, 0, len(ideal))
+	targets := make(pq.Int64Array, 0, len(ideal))
+	for _, i := range ideal {
+		effectiveBalances = append(effectiveBalances, i.EffectiveBalance)
+		heads = append(heads, i.Head)
+		sources = append(sources, i.Source)
+		targets = append(targets, i.Target)
+	}
+
+	_, err := WriterDb.Exec(`
+		INSERT INTO attestation_ideal_rewards (epoch, effective_balance, head, source, target)
+		SELECT $1, u.effective_balance, u.head, u.source, u.target
+		FROM UNNEST($2::bigint[], $3::bigint[], $4::bigint[], $5::bigint[]) AS u(effective_balance, head, source, target)
+		ON CONFLICT (epoch, effective_balance) DO UPDATE SET
+			head = excluded.head,
+			source = excluded.source,
+			target = excluded.target`, epoch, effectiveBalances, heads, sources, targets)
+	if err != nil {
+		return fmt.Errorf("error saving ideal attestation rewards of epoch %v: %w", epoch, err)
+	}
+	return nil
+}
+
+// GetIdealAttestationRewards returns the ideal attestation rewards of the epochs from startEpoch to endEpoch (inclusive) by epoch and effective balance
+func GetIdealAttestationRewards(startEpoch, endEpoch uint64) (map[uint64]map[uint64]*types.IdealAttestationReward, error) {
+	rows := []*types.IdealAttestationReward{}
+	err := ReaderDb.Select(&rows, `
+		SELECT epoch, effective_balance, head, source, target
+		FROM attestation_ideal_rewards
+		WHERE epoch BETWEEN $1 AND $2`, startEpoch, endEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ideal attestation rewards: %w", err)
+	}
+
+	res := make(map[uint64]map[uint64]*types.IdealAttestationReward)
+	for _, row := range rows {
+		if res[row.Epoch] == nil {
+			res[row.Epoch] = make(map[uint64]*types.IdealAttestationReward)
+		}
+		res[row.Epoch][row.EffectiveBalance] = row
+	}
+	return res, nil
+}
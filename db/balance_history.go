@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"time"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/coocood/freecache"
+)
+
+// maxNativeBalanceDeltas is the maximum number of balance changes of an address that are read to compute its balance or balance history
+const maxNativeBalanceDeltas = 100000
+
+var ErrTooManyNativeBalanceDeltas = fmt.Errorf("the address has more than %v balance changes", maxNativeBalanceDeltas)
+
+// TransformBalanceDeltas accepts an eth1 block and creates bigtable mutations for the native balance changes of the block, see utils.Eth1NativeBalanceDeltas
+// It writes the net change of the balance of every address whose balance is changed by the block to the table data:
+// Row:    <chainID>:BD:<address>:<reversePaddedBlockNumber>
+// Family: f
+// Column: data
+// Cell:   json encoded types.Eth1BalanceDelta
+// Example scan: "1:BD:ea674fdde714fd979de3edf0f56aa9716b898ec8:" returns the balance changes of the address in desc order
+//
+// Value transfers of reverted internal calls are only skipped for blocks whose internal transactions carry their error. Blocks that were
+// exported before have to be re-exported from the node (eth1indexer -blocks.start/-blocks.end) before their balance changes are indexed.
+func (bigtable *Bigtable) TransformBalanceDeltas(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+
+	for address, delta := range utils.Eth1NativeBalanceDeltas(blk) {
+		b, err := json.Marshal(&types.Eth1BalanceDelta{
+			BlockNumber: blk.GetNumber(),
+			Time:        blk.GetTime().GetSeconds(),
+			Delta:       delta.String(),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		mut := gcp_bigtable.NewMutation()
+		mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+
+		bulkData.Keys = append(bulkData.Keys, fmt.Sprintf("%s:BD:%x:%s", bigtable.chainId, address.Bytes(), reversedPaddedBlockNumber(blk.GetNumber())))
+		bulkData.Muts = append(bulkData.Muts, mut)
+	}
+
+	return bulkData, bulkMetadataUpdates, nil
+}
+
+// GetNativeBalanceDeltas returns all indexed native balance changes of an address up to and including block maxBlock, the latest change comes first.
+// ErrTooManyNativeBalanceDeltas is returned if the address has more than maxNativeBalanceDeltas changes.
+func (bigtable *Bigtable) GetNativeBalanceDeltas(address []byte, maxBlock uint64) ([]*types.Eth1BalanceDelta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	if maxBlock > max_block_number {
+		maxBlock = max_block_number
+	}
+	prefix := fmt.Sprintf("%s:BD:%x:", bigtable.chainId, address)
+	rowRange := gcp_bigtable.NewRange(prefix+reversedPaddedBlockNumber(maxBlock), prefixSuccessor(prefix, 3))
+
+	deltas := []*types.Eth1BalanceDelta{}
+	var parseErr error
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		delta := &types.Eth1BalanceDelta{}
+		parseErr = json.Unmarshal(row[DEFAULT_FAMILY][0].Value, delta)
+		if parseErr != nil {
+			parseErr = fmt.Errorf("error parsing balance delta %v: %w", row.Key(), parseErr)
+			return false
+		}
+		deltas = append(deltas, delta)
+		return true
+	}, gcp_bigtable.RowFilter(gcp_bigtable.ColumnFilter(DATA_COLUMN)), gcp_bigtable.LimitRows(maxNativeBalanceDeltas+1))
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if len(deltas) > maxNativeBalanceDeltas {
+		return nil, ErrTooManyNativeBalanceDeltas
+	}
+	return deltas, nil
+}
+
+// GetNativeBalanceAtBlock returns the native balance of an address at the end of a block as the sum of its indexed balance changes
+func (bigtable *Bigtable) GetNativeBalanceAtBlock(address []byte, block uint64) (*big.Int, error) {
+	deltas, err := bigtable.GetNativeBalanceDeltas(address, block)
+	if err != nil {
+		return nil, err
+	}
+	return sumBalanceDeltas(deltas, time.Time{})
+}
+
+// GetNativeBalanceAtTime returns the native balance of an address at a point in time as the sum of its indexed balance changes
+func (bigtable *Bigtable) GetNativeBalanceAtTime(address []byte, ts time.Time) (*big.Int, error) {
+	deltas, err := bigtable.GetNativeBalanceDeltas(address, max_block_number)
+	if err != nil {
+		return nil, err
+	}
+	return sumBalanceDeltas(deltas, ts)
+}
+
+// sumBalanceDeltas adds up balance changes, changes after ts are skipped unless ts is zero
+func sumBalanceDeltas(deltas []*types.Eth1BalanceDelta, ts time.Time) (*big.Int, error) {
+	balance := new(big.Int)
+	for _, delta := range deltas {
+		if !ts.IsZero() && delta.Time > ts.Unix() {
+			continue
+		}
+		value, ok := new(big.Int).SetString(delta.Delta, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance delta %v at block %v", delta.Delta, delta.BlockNumber)
+		}
+		balance.Add(balance, value)
+	}
+	return balance, nil
+}
+
+// GetNativeBalanceHistory returns the native balance of an address at the end of every utc day on which its balance changed, ordered by day
+func (bigtable *Bigtable) GetNativeBalanceHistory(address []byte) ([]*types.Eth1BalanceHistoryPoint, error) {
+	deltas, err := bigtable.GetNativeBalanceDeltas(address, max_block_number)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []*types.Eth1BalanceHistoryPoint{}
+	balance := new(big.Int)
+	for i := len(deltas) - 1; i >= 0; i-- {
+		value, ok := new(big.Int).SetString(deltas[i].Delta, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance delta %v at block %v", deltas[i].Delta, deltas[i].BlockNumber)
+		}
+		balance.Add(balance, value)
+
+		day := time.Unix(deltas[i].Time, 0).UTC().Truncate(utils.Day).Unix()
+		if len(history) > 0 && history[len(history)-1].Day == day {
+			history[len(history)-1].BlockNumber = deltas[i].BlockNumber
+			history[len(history)-1].Balance = balance.String()
+			continue
+		}
+		history = append(history, &types.Eth1BalanceHistoryPoint{
+			Day:         day,
+			BlockNumber: deltas[i].BlockNumber,
+			Balance:     balance.String(),
+		})
+	}
+	return history, nil
+}
@@ -0,0 +1,236 @@
+package db
+
+import (
+	"database/sql"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// BenchmarkSelector selects the validators of a set whose performance is benchmarked, the zero value selects all validators of the network
+type BenchmarkSelector struct {
+	// filter is a condition on a validatorindex column, %[1]s is replaced by the column and %[2]d by the placeholder of arg
+	filter string
+	arg    interface{}
+}
+
+// BenchmarkValidators selects the validators with the given indices
+func BenchmarkValidators(indices []uint64) BenchmarkSelector {
+	return BenchmarkSelector{filter: "%[1]s = ANY($%[2]d::int[])", arg: pq.Array(indices)}
+}
+
+// BenchmarkPool selects all validators that are tagged with the given pool
+func BenchmarkPool(pool string) BenchmarkSelector {
+	return BenchmarkSelector{filter: `%[1]s IN (
+		SELECT validators.validatorindex
+		FROM validator_pool
+		INNER JOIN validators ON validators.pubkey = validator_pool.publickey
+		WHERE validator_pool.pool = $%[2]d)`, arg: pool}
+}
+
+// BenchmarkWithdrawalCredentials selects all validators with the given withdrawal credentials
+func BenchmarkWithdrawalCredentials(credentials []byte) BenchmarkSelector {
+	return BenchmarkSelector{filter: "%[1]s IN (SELECT validatorindex FROM validators WHERE withdrawalcredentials = $%[2]d)", arg: credentials}
+}
+
+// condition returns the sql condition that selects the validators of the set from column, placeholder is the number of the argument of the selector
+func (s BenchmarkSelector) condition(column string, placeholder int) string {
+	if s.filter == "" {
+		return "TRUE"
+	}
+	return fmt.Sprintf(s.filter, column, placeholder)
+}
+
+// args appends the argument of the selector to args
+func (s BenchmarkSelector) args(args ...interface{}) []interface{} {
+	if s.filter == "" {
+		return args
+	}
+	return append(args, s.arg)
+}
+
+// GetValidatorBenchmarkDays returns the aggregated daily performance of the selected validators from fromDay to toDay (inclusive).
+// Only days for which validator statistics have been exported are returned.
+func GetValidatorBenchmarkDays(selector BenchmarkSelector, fromDay, toDay uint64) ([]*types.ValidatorBenchmarkDay, error) {
+	days := []*types.ValidatorBenchmarkDay{}
+	err := ReaderDb.Select(&days, `
+		SELECT
+			validator_stats.day,
+			COUNT(*) AS validators,
+			COALESCE(SUM(validator_stats.missed_attestations), 0) AS missed_attestations,
+			COALESCE(SUM(validator_stats.proposed_blocks), 0) AS proposed_blocks,
+			COALESCE(SUM(validator_stats.missed_blocks), 0) AS missed_blocks,
+			COALESCE(SUM(validator_stats.participated_sync), 0) AS participated_sync,
+			COALESCE(SUM(validator_stats.missed_sync), 0) AS missed_sync
+		FROM validator_stats
+		INNER JOIN validators ON validators.validatorindex = validator_stats.validatorindex
+		WHERE validator_stats.day BETWEEN $1 AND $2
+			AND validators.activationepoch < (validator_stats.day + 1) * $3
+			AND validators.exitepoch > validator_stats.day * $3
+			AND `+selector.condition("validator_stats.validatorindex", 4)+`
+		GROUP BY validator_stats.day
+		ORDER BY validator_stats.day`, selector.args(fromDay, toDay, utils.EpochsPerDay())...)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator stats of benchmark days %v to %v: %w", fromDay, toDay, err)
+	}
+
+	aprs := []struct {
+		Day uint64  `db:"day"`
+		Apr float64 `db:"apr"`
+	}{}
+	err = ReaderDb.Select(&aprs, `
+		SELECT
+			day,
+			COALESCE(SUM(total_rewards_wei) / NULLIF(SUM(effective_balances_sum_wei), 0) * 365, 0) AS apr
+		FROM eth_store_stats
+		WHERE day BETWEEN $1 AND $2 AND validator >= 0 AND `+selector.condition("validator", 3)+`
+		GROUP BY day`, selector.args(fromDay, toDay)...)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving apr of benchmark days %v to %v: %w", fromDay, toDay, err)
+	}
+	aprByDay := make(map[uint64]float64, len(aprs))
+	for _, apr := range aprs {
+		aprByDay[apr.Day] = apr.Apr
+	}
+
+	for _, day := range days {
+		day.Apr = aprByDay[day.Day]
+		if day.Validators > 0 {
+			day.AttestationParticipation = 1 - float64(day.MissedAttestations)/float64(day.Validators*utils.EpochsPerDay())
+		}
+		if day.ParticipatedSync+day.MissedSync > 0 {
+			day.SyncParticipation = float64(day.ParticipatedSync) / float64(day.ParticipatedSync+day.MissedSync)
+		}
+	}
+	return days, nil
+}
+
+// GetEthStoreAprs returns the apr of the ETH.STORE days from fromDay to toDay (inclusive)
+func GetEthStoreAprs(fromDay, toDay uint64) (map[uint64]float64, error) {
+	aprs := []struct {
+		Day uint64  `db:"day"`
+		Apr float64 `db:"apr"`
+	}{}
+	err := ReaderDb.Select(&aprs, `SELECT day, apr FROM eth_store_stats WHERE validator = -1 AND day BETWEEN $1 AND $2`, fromDay, toDay)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uint64]float64, len(aprs))
+	for _, apr := range aprs {
+		res[apr.Day] = apr.Apr
+	}
+	return res, nil
+}
+
+// GetValidatorBenchmarkApr returns the apr of the selected validators from fromDay to toDay (inclusive), the rewards of all days
+// are related to the summed up effective balances of all days
+func GetValidatorBenchmarkApr(selector BenchmarkSelector, fromDay, toDay uint64) (float64, error) {
+	apr := float64(0)
+	err := ReaderDb.Get(&apr, `
+		SELECT COALESCE(SUM(total_rewards_wei) / NULLIF(SUM(effective_balances_sum_wei), 0) * 365, 0)
+		FROM eth_store_stats
+		WHERE day BETWEEN $1 AND $2 AND validator >= 0 AND `+selector.condition("validator", 3),
+		selector.args(fromDay, toDay)...)
+	return apr, err
+}
+
+// GetValidatorAprPercentiles returns the 0th to 100th percentile of the apr of all validators from fromDay to toDay (inclusive)
+func GetValidatorAprPercentiles(fromDay, toDay uint64) ([]float64, error) {
+	fractions := make(pq.Float64Array, 101)
+	for i := range fractions {
+		fractions[i] = float64(i) / 100
+	}
+
+	percentiles := pq.Float64Array{}
+	err := ReaderDb.Get(&percentiles, `
+		SELECT COALESCE(PERCENTILE_CONT($3::float[]) WITHIN GROUP (ORDER BY apr), '{}')
+		FROM (
+			SELECT SUM(total_rewards_wei) / NULLIF(SUM(effective_balances_sum_wei), 0) * 365 AS apr
+			FROM eth_store_stats
+			WHERE day BETWEEN $1 AND $2 AND validator >= 0
+			GROUP BY validator
+		) validator_aprs
+		WHERE apr IS NOT NULL`, fromDay, toDay, fractions)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving apr percentiles of days %v to %v: %w", fromDay, toDay, err)
+	}
+	return percentiles, nil
+}
+
+// GetLatestEthStoreDay returns the latest day for which the ETH.STORE has been exported, ok is false if no day has been exported yet
+func GetLatestEthStoreDay() (day uint64, ok bool, err error) {
+	var latestDay sql.NullInt64
+	err = ReaderDb.Get(&latestDay, `SELECT MAX(day) FROM eth_store_stats WHERE validator = -1`)
+	if err != nil || !latestDay.Valid {
+		return 0, false, err
+	}
+	return uint64(latestDay.Int64), true, nil
+}
+
+// GetValidatorEthStoreDays returns the ETH.STORE style performance of the selected validators next to the ETH.STORE of every day from fromDay
+// to toDay (inclusive). The apr of a day is the total rewards of the validators relative to their summed up effective balances, annualized.
+// Days on which none of the selected validators was active are returned with zero rewards.
+func GetValidatorEthStoreDays(selector BenchmarkSelector, fromDay, toDay uint64) ([]*types.ValidatorEthStoreDay, error) {
+	days := []*types.ValidatorEthStoreDay{}
+	err := ReaderDb.Select(&days, `
+		SELECT
+			network.day,
+			COALESCE(validator_days.validators, 0) AS validators,
+			COALESCE(validator_days.effective_balances_sum_wei, 0) AS effective_balances_sum_wei,
+			COALESCE(validator_days.consensus_rewards_sum_wei, 0) AS consensus_rewards_sum_wei,
+			COALESCE(validator_days.tx_fees_sum_wei, 0) AS tx_fees_sum_wei,
+			COALESCE(validator_days.total_rewards_wei, 0) AS total_rewards_wei,
+			COALESCE(validator_days.total_rewards_wei / NULLIF(validator_days.effective_balances_sum_wei, 0) * 365, 0) AS apr,
+			network.apr AS ethstore_apr
+		FROM eth_store_stats network
+		LEFT JOIN (
+			SELECT
+				day,
+				COUNT(*) AS validators,
+				SUM(effective_balances_sum_wei) AS effective_balances_sum_wei,
+				SUM(consensus_rewards_sum_wei) AS consensus_rewards_sum_wei,
+				SUM(tx_fees_sum_wei) AS tx_fees_sum_wei,
+				SUM(total_rewards_wei) AS total_rewards_wei
+			FROM eth_store_stats
+			WHERE day BETWEEN $1 AND $2 AND validator >= 0 AND `+selector.condition("validator", 3)+`
+			GROUP BY day
+		) validator_days ON validator_days.day = network.day
+		WHERE network.validator = -1 AND network.day BETWEEN $1 AND $2
+		ORDER BY network.day`, selector.args(fromDay, toDay)...)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ETH.STORE days %v to %v of validators: %w", fromDay, toDay, err)
+	}
+	return days, nil
+}
+
+// GetEthStoreRangeApr returns the apr of the ETH.STORE from fromDay to toDay (inclusive), the rewards of all days are related to the summed up
+// effective balances of all days like the apr of a set of validators over a range
+func GetEthStoreRangeApr(fromDay, toDay uint64) (float64, error) {
+	days := []*types.ValidatorEthStoreDay{}
+	err := ReaderDb.Select(&days, `
+		SELECT day, effective_balances_sum_wei, total_rewards_wei
+		FROM eth_store_stats
+		WHERE validator = -1 AND day BETWEEN $1 AND $2`, fromDay, toDay)
+	if err != nil {
+		return 0, err
+	}
+	return EthStoreRangeApr(days), nil
+}
+
+// EthStoreRangeApr returns the apr over a range of days, the total rewards of all days relative to the summed up effective balances of all days, annualized.
+// Days with a higher effective balance weigh more than a plain mean of the daily aprs would weigh them.
+func EthStoreRangeApr(days []*types.ValidatorEthStoreDay) float64 {
+	rewards := decimal.Zero
+	effectiveBalances := decimal.Zero
+	for _, day := range days {
+		rewards = rewards.Add(day.TotalRewardsWei)
+		effectiveBalances = effectiveBalances.Add(day.EffectiveBalancesSumWei)
+	}
+	if !effectiveBalances.IsPositive() {
+		return 0
+	}
+	return rewards.Div(effectiveBalances).Mul(decimal.NewFromInt(365)).InexactFloat64()
+}
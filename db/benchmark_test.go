@@ -0,0 +1,37 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestEthStoreRangeApr(t *testing.T) {
+	day := func(effectiveBalances, rewards int64) *types.ValidatorEthStoreDay {
+		return &types.ValidatorEthStoreDay{EffectiveBalancesSumWei: decimal.NewFromInt(effectiveBalances), TotalRewardsWei: decimal.NewFromInt(rewards)}
+	}
+
+	tests := []struct {
+		name string
+		days []*types.ValidatorEthStoreDay
+		apr  float64
+	}{
+		{"no days", nil, 0},
+		{"no effective balance", []*types.ValidatorEthStoreDay{day(0, 0)}, 0},
+		{"single day", []*types.ValidatorEthStoreDay{day(1000, 1)}, 0.365},
+		{"equal balances", []*types.ValidatorEthStoreDay{day(1000, 1), day(1000, 3)}, 0.73},
+		// the daily aprs are 0.365 and 1.095, their plain mean would be 0.73
+		{"weighted by balance", []*types.ValidatorEthStoreDay{day(3000, 3), day(1000, 3)}, 0.5475},
+		{"negative rewards", []*types.ValidatorEthStoreDay{day(1000, -1), day(1000, 3)}, 0.365},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apr := EthStoreRangeApr(tt.days)
+			if math.Abs(apr-tt.apr) > 1e-12 {
+				t.Errorf("expected apr %v, got %v", tt.apr, apr)
+			}
+		})
+	}
+}
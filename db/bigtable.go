@@ -875,10 +875,12 @@ func (bigtable *Bigtable) GetValidatorAttestationHistory(validators []uint64, st
 				res[validator] = make([]*types.ValidatorAttestation, 0)
 			}
 
+			// the delay counts the slots after the earliest possible inclusion in the slot following the attester slot
+			delay := int64(inclusionSlot) - int64(attesterSlot) - 1
 			if len(res[validator]) > 0 && res[validator][len(res[validator])-1].AttesterSlot == attesterSlot {
 				res[validator][len(res[validator])-1].InclusionSlot = inclusionSlot
 				res[validator][len(res[validator])-1].Status = status
-				res[validator][len(res[validator])-1].Delay = int64(inclusionSlot - attesterSlot)
+				res[validator][len(res[validator])-1].Delay = delay
 			} else {
 				res[validator] = append(res[validator], &types.ValidatorAttestation{
 					Index:          validator,
@@ -887,7 +889,7 @@ func (bigtable *Bigtable) GetValidatorAttestationHistory(validators []uint64, st
 					CommitteeIndex: 0,
 					Status:         status,
 					InclusionSlot:  inclusionSlot,
-					Delay:          int64(inclusionSlot) - int64(attesterSlot) - 1,
+					Delay:          delay,
 				})
 			}
 
@@ -14,6 +14,7 @@ import (
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
+	"html/template"
 	"log"
 	"math/big"
 	"sort"
@@ -73,8 +74,10 @@ const (
 	ACCOUNT_COLUMN_NAME = "NAME"
 	ACCOUNT_IS_CONTRACT = "ISCONTRACT"
 
-	CONTRACT_NAME = "CONTRACTNAME"
-	CONTRACT_ABI  = "ABI"
+	CONTRACT_NAME      = "CONTRACTNAME"
+	CONTRACT_ABI       = "ABI"
+	CONTRACT_SOURCE    = "SOURCE"
+	CONTRACT_SUBMITTER = "SUBMITTER"
 
 	ERC20_COLUMN_DECIMALS    = "DECIMALS"
 	ERC20_COLUMN_TOTALSUPPLY = "TOTALSUPPLY"
@@ -1229,6 +1232,8 @@ func (bigtable *Bigtable) TransformItx(blk *types.Eth1Block, cache *freecache.Ca
 // Family: f
 // Column: <chainID>:ERC20:<txHash>:<paddedLogIndex>
 // Cell:   nil
+//
+// The sender and the receiver of every transfer are marked for the update of the token holder index, see markTokenHolderUpdate
 func (bigtable *Bigtable) TransformERC20(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
 	bulkData = &types.BulkMutations{}
 	bulkMetadataUpdates = &types.BulkMutations{}
@@ -1292,6 +1297,8 @@ func (bigtable *Bigtable) TransformERC20(blk *types.Eth1Block, cache *freecache.
 			}
 			bigtable.markBalanceUpdate(indexedLog.From, indexedLog.TokenAddress, bulkMetadataUpdates, cache)
 			bigtable.markBalanceUpdate(indexedLog.To, indexedLog.TokenAddress, bulkMetadataUpdates, cache)
+			bigtable.markTokenHolderUpdate(indexedLog.TokenAddress, indexedLog.From, blk.GetNumber(), blk.GetTime().AsTime(), bulkData)
+			bigtable.markTokenHolderUpdate(indexedLog.TokenAddress, indexedLog.To, blk.GetNumber(), blk.GetTime().AsTime(), bulkData)
 
 			b, err := proto.Marshal(indexedLog)
 			if err != nil {
@@ -1380,6 +1387,14 @@ func (bigtable *Bigtable) TransformERC20(blk *types.Eth1Block, cache *freecache.
 // Family: f
 // Column: <chainID>:ERC721:<txHash>:<paddedLogIndex>
 // Cell:   nil
+//
+// Row:    <chainID>:I:ERC721:<TOKEN_ADDRESS>:ID:<tokenId>:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:ERC721:<txHash>:<paddedLogIndex>
+// Cell:   nil
+//
+// It marks the token id for the ownership update of the nft updater, see markNftUpdate. Blocks that were indexed before the id index
+// was added have to be re-indexed with the backfill-nft-transfers command of misc.
 func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
 	bulkData = &types.BulkMutations{}
 	bulkMetadataUpdates = &types.BulkMutations{}
@@ -1466,7 +1481,9 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 				fmt.Sprintf("%s:I:ERC721:%x:FROM:%x:%s:%s:%s", bigtable.chainId, indexedLog.To, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
 				fmt.Sprintf("%s:I:ERC721:%x:TOKEN_SENT:%x:%s:%s:%s", bigtable.chainId, indexedLog.From, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
 				fmt.Sprintf("%s:I:ERC721:%x:TOKEN_RECEIVED:%x:%s:%s:%s", bigtable.chainId, indexedLog.To, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+				fmt.Sprintf("%s:I:ERC721:%x:ID:%s:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, tokenId.String(), reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
 			}
+			bigtable.markNftUpdate(types.NftStandardERC721, indexedLog.TokenAddress, tokenId, bulkData)
 
 			for _, idx := range indexes {
 				mut := gcp_bigtable.NewMutation()
@@ -1488,7 +1505,8 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 // TransformERC1155 accepts an eth1 block and creates bigtable mutations for erc1155 transfer events.
 // Example: https://etherscan.io/tx/0xcffdd4b44ba9361a769a559c360293333d09efffeab79c36125bb4b20bd04270#eventlog
 // It transforms the logs contained within a block and writes the transformed logs to bigtable
-// It writes erc1155 events to the table data:
+// It writes erc1155 events to the table data, a batch transfer is written as one row per id whose key is suffixed by :<paddedIdIndex>,
+// the keys of all index rows of its transfers are suffixed the same way:
 // Row:    <chainID>:ERC1155:<txHash>:<paddedLogIndex>
 // Family: f
 // Column: data
@@ -1530,6 +1548,14 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 // Family: f
 // Column: <chainID>:ERC1155:<txHash>:<paddedLogIndex>
 // Cell:   nil
+//
+// Row:    <chainID>:I:ERC1155:<TOKEN_ADDRESS>:ID:<tokenId>:<reversePaddedBigtableTimestamp>:<paddedTxIndex>:<PaddedLogIndex>
+// Family: f
+// Column: <chainID>:ERC1155:<txHash>:<paddedLogIndex>
+// Cell:   nil
+//
+// It marks the token id for the ownership update of the nft updater, see markNftUpdate. Blocks that were indexed before the id index
+// was added, or before batch transfers were split up by id, have to be re-indexed with the backfill-nft-transfers command of misc.
 func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
 	bulkData = &types.BulkMutations{}
 	bulkMetadataUpdates = &types.BulkMutations{}
@@ -1550,8 +1576,6 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 			}
 			jReversed := reversePaddedIndex(j, 100000)
 
-			key := fmt.Sprintf("%s:ERC1155:%x:%s", bigtable.chainId, tx.GetHash(), jReversed)
-
 			// no events emitted continue
 			if len(log.GetTopics()) != 4 || (!bytes.Equal(log.GetTopics()[0], erc1155.TransferBulkTopic) && !bytes.Equal(log.GetTopics()[0], erc1155.TransferSingleTopic)) {
 				continue
@@ -1575,13 +1599,16 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 				Removed:     log.GetRemoved(),
 			}
 
-			indexedLog := &types.ETh1ERC1155Indexed{}
 			transferBatch, _ := filterer.ParseTransferBatch(ethLog)
 			transferSingle, _ := filterer.ParseTransferSingle(ethLog)
 			if transferBatch == nil && transferSingle == nil {
 				continue
 			}
 
+			// a batch transfer is stored as one transfer per id, the keys of its transfers are suffixed by the padded index of the id
+			indexedLogs := []*types.ETh1ERC1155Indexed{}
+			suffixes := []string{}
+
 			// && len(transferBatch.Operator) == 20 && len(transferBatch.From) == 20 && len(transferBatch.To) == 20 && len(transferBatch.Ids) > 0 && len(transferBatch.Values) > 0
 			if transferBatch != nil {
 				ids := make([][]byte, 0, len(transferBatch.Ids))
@@ -1599,64 +1626,106 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 					continue
 				}
 				for ti := range ids {
-					indexedLog.BlockNumber = blk.GetNumber()
-					indexedLog.Time = blk.GetTime()
-					indexedLog.ParentHash = tx.GetHash()
-					indexedLog.From = transferBatch.From.Bytes()
-					indexedLog.To = transferBatch.To.Bytes()
-					indexedLog.Operator = transferBatch.Operator.Bytes()
-					indexedLog.TokenId = ids[ti]
-					indexedLog.Value = values[ti]
-					indexedLog.TokenAddress = log.GetAddress()
+					if ti > 99999 {
+						return nil, nil, fmt.Errorf("unexpected number of ids in erc1155 batch transfer expected at most 99999 but got: %v tx: %x", ti, tx.GetHash())
+					}
+					indexedLogs = append(indexedLogs, &types.ETh1ERC1155Indexed{
+						BlockNumber:  blk.GetNumber(),
+						Time:         blk.GetTime(),
+						ParentHash:   tx.GetHash(),
+						From:         transferBatch.From.Bytes(),
+						To:           transferBatch.To.Bytes(),
+						Operator:     transferBatch.Operator.Bytes(),
+						TokenId:      ids[ti],
+						Value:        values[ti],
+						TokenAddress: log.GetAddress(),
+					})
+					suffixes = append(suffixes, ":"+reversePaddedIndex(ti, 100000))
 				}
 			} else if transferSingle != nil {
-				indexedLog.BlockNumber = blk.GetNumber()
-				indexedLog.Time = blk.GetTime()
-				indexedLog.ParentHash = tx.GetHash()
-				indexedLog.From = transferSingle.From.Bytes()
-				indexedLog.To = transferSingle.To.Bytes()
-				indexedLog.Operator = transferSingle.Operator.Bytes()
-				indexedLog.TokenId = transferSingle.Id.Bytes()
-				indexedLog.Value = transferSingle.Value.Bytes()
-				indexedLog.TokenAddress = log.GetAddress()
+				indexedLogs = append(indexedLogs, &types.ETh1ERC1155Indexed{
+					BlockNumber:  blk.GetNumber(),
+					Time:         blk.GetTime(),
+					ParentHash:   tx.GetHash(),
+					From:         transferSingle.From.Bytes(),
+					To:           transferSingle.To.Bytes(),
+					Operator:     transferSingle.Operator.Bytes(),
+					TokenId:      transferSingle.Id.Bytes(),
+					Value:        transferSingle.Value.Bytes(),
+					TokenAddress: log.GetAddress(),
+				})
+				suffixes = append(suffixes, "")
 			}
 
-			b, err := proto.Marshal(indexedLog)
-			if err != nil {
-				return nil, nil, err
-			}
+			if transferBatch != nil && len(indexedLogs) > 0 {
+				// blocks indexed before batch transfers were split up stored only the last id of a batch transfer under the unsuffixed keys,
+				// these rows are removed so re-indexing the block replaces them
+				last := indexedLogs[len(indexedLogs)-1]
+				legacyKeys := []string{
+					fmt.Sprintf("%s:ERC1155:%x:%s", bigtable.chainId, tx.GetHash(), jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:TIME:%s:%s:%s", bigtable.chainId, last.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:TIME:%s:%s:%s", bigtable.chainId, last.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:ALL:TIME:%s:%s:%s", bigtable.chainId, last.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:%x:TIME:%s:%s:%s", bigtable.chainId, last.TokenAddress, last.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:%x:TIME:%s:%s:%s", bigtable.chainId, last.TokenAddress, last.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:TO:%x:%s:%s:%s", bigtable.chainId, last.From, last.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:FROM:%x:%s:%s:%s", bigtable.chainId, last.To, last.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:TOKEN_SENT:%x:%s:%s:%s", bigtable.chainId, last.From, last.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:TOKEN_RECEIVED:%x:%s:%s:%s", bigtable.chainId, last.To, last.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:ID:%s:%s:%s:%s", bigtable.chainId, last.TokenAddress, new(big.Int).SetBytes(last.TokenId).String(), reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+				}
+				for _, legacyKey := range legacyKeys {
+					mut := gcp_bigtable.NewMutation()
+					mut.DeleteRow()
 
-			mut := gcp_bigtable.NewMutation()
-			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+					bulkData.Keys = append(bulkData.Keys, legacyKey)
+					bulkData.Muts = append(bulkData.Muts, mut)
+				}
+			}
 
-			bulkData.Keys = append(bulkData.Keys, key)
-			bulkData.Muts = append(bulkData.Muts, mut)
+			for ti, indexedLog := range indexedLogs {
+				key := fmt.Sprintf("%s:ERC1155:%x:%s%s", bigtable.chainId, tx.GetHash(), jReversed, suffixes[ti])
+				suffix := jReversed + suffixes[ti]
 
-			indexes := []string{
-				// fmt.Sprintf("%s:I:ERC1155:%s:%s:%s", bigtable.chainId, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-
-				fmt.Sprintf("%s:I:ERC1155:%x:ALL:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, indexedLog.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-
-				fmt.Sprintf("%s:I:ERC1155:%x:TO:%x:%s:%s:%s", bigtable.chainId, indexedLog.From, indexedLog.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:FROM:%x:%s:%s:%s", bigtable.chainId, indexedLog.To, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:TOKEN_SENT:%x:%s:%s:%s", bigtable.chainId, indexedLog.From, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-				fmt.Sprintf("%s:I:ERC1155:%x:TOKEN_RECEIVED:%x:%s:%s:%s", bigtable.chainId, indexedLog.To, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
-			}
+				b, err := proto.Marshal(indexedLog)
+				if err != nil {
+					return nil, nil, err
+				}
 
-			for _, idx := range indexes {
 				mut := gcp_bigtable.NewMutation()
-				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
-
-				// if i == 3 || i == 4 {
-				// 	mut.DeleteRow()
-				// }
+				mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
-				bulkData.Keys = append(bulkData.Keys, idx)
+				bulkData.Keys = append(bulkData.Keys, key)
 				bulkData.Muts = append(bulkData.Muts, mut)
+
+				indexes := []string{
+					// fmt.Sprintf("%s:I:ERC1155:%s:%s:%s", bigtable.chainId, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed),
+					fmt.Sprintf("%s:I:ERC1155:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+
+					fmt.Sprintf("%s:I:ERC1155:%x:ALL:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:%x:TIME:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, indexedLog.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+
+					fmt.Sprintf("%s:I:ERC1155:%x:TO:%x:%s:%s:%s", bigtable.chainId, indexedLog.From, indexedLog.To, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:FROM:%x:%s:%s:%s", bigtable.chainId, indexedLog.To, indexedLog.From, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:TOKEN_SENT:%x:%s:%s:%s", bigtable.chainId, indexedLog.From, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:TOKEN_RECEIVED:%x:%s:%s:%s", bigtable.chainId, indexedLog.To, indexedLog.TokenAddress, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+					fmt.Sprintf("%s:I:ERC1155:%x:ID:%s:%s:%s:%s", bigtable.chainId, indexedLog.TokenAddress, new(big.Int).SetBytes(indexedLog.TokenId).String(), reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, suffix),
+				}
+				bigtable.markNftUpdate(types.NftStandardERC1155, indexedLog.TokenAddress, new(big.Int).SetBytes(indexedLog.TokenId), bulkData)
+
+				for _, idx := range indexes {
+					mut := gcp_bigtable.NewMutation()
+					mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+					// if i == 3 || i == 4 {
+					// 	mut.DeleteRow()
+					// }
+
+					bulkData.Keys = append(bulkData.Keys, idx)
+					bulkData.Muts = append(bulkData.Muts, mut)
+				}
 			}
 		}
 	}
@@ -2604,7 +2673,7 @@ func (bigtable *Bigtable) GetAddressErc721TableData(address string, search strin
 			from,
 			to,
 			utils.FormatAddressAsLink(t.TokenAddress, "", false, true),
-			new(big.Int).SetBytes(t.TokenId).String(),
+			template.HTML(fmt.Sprintf(`<a href="/token/0x%x/nft/%s">%[2]s</a>`, t.TokenAddress, new(big.Int).SetBytes(t.TokenId).String())),
 		}
 	}
 
@@ -2690,7 +2759,7 @@ func (bigtable *Bigtable) GetAddressErc1155TableData(address string, search stri
 			from,
 			to,
 			utils.FormatAddressAsLink(t.TokenAddress, "", false, true),
-			new(big.Int).SetBytes(t.TokenId).String(),
+			template.HTML(fmt.Sprintf(`<a href="/token/0x%x/nft/%s">%[2]s</a>`, t.TokenAddress, new(big.Int).SetBytes(t.TokenId).String())),
 			new(big.Int).SetBytes(t.Value).String(),
 		}
 	}
@@ -3157,6 +3226,10 @@ func (bigtable *Bigtable) GetContractMetadata(address []byte) (*types.ContractMe
 					logrus.Fatalf("error decoding abi for address 0x%x: %v", address, err)
 				}
 				ret.ABI = &val
+			} else if item.Column == CONTRACT_METADATA_FAMILY+":"+CONTRACT_SOURCE {
+				ret.Source = string(item.Value)
+			} else if item.Column == CONTRACT_METADATA_FAMILY+":"+CONTRACT_SUBMITTER {
+				ret.SubmittedBy, _ = strconv.ParseUint(string(item.Value), 10, 64)
 			}
 		}
 	}
@@ -3165,6 +3238,7 @@ func (bigtable *Bigtable) GetContractMetadata(address []byte) (*types.ContractMe
 	return ret, err
 }
 
+// SaveContractMetadata stores the name and ABI of a contract, a cached metadata entry of the contract is replaced
 func (bigtable *Bigtable) SaveContractMetadata(address []byte, metadata *types.ContractMetadata) error {
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
 	defer cancel()
@@ -3172,8 +3246,19 @@ func (bigtable *Bigtable) SaveContractMetadata(address []byte, metadata *types.C
 	mut := gcp_bigtable.NewMutation()
 	mut.Set(CONTRACT_METADATA_FAMILY, CONTRACT_NAME, gcp_bigtable.Timestamp(0), []byte(metadata.Name))
 	mut.Set(CONTRACT_METADATA_FAMILY, CONTRACT_ABI, gcp_bigtable.Timestamp(0), metadata.ABIJson)
+	mut.Set(CONTRACT_METADATA_FAMILY, CONTRACT_SOURCE, gcp_bigtable.Timestamp(0), []byte(metadata.Source))
+	if metadata.SubmittedBy != 0 {
+		mut.Set(CONTRACT_METADATA_FAMILY, CONTRACT_SUBMITTER, gcp_bigtable.Timestamp(0), []byte(strconv.FormatUint(metadata.SubmittedBy, 10)))
+	} else {
+		mut.DeleteCellsInColumn(CONTRACT_METADATA_FAMILY, CONTRACT_SUBMITTER)
+	}
 
-	return bigtable.tableMetadata.Apply(ctx, fmt.Sprintf("%s:%x", bigtable.chainId, address), mut)
+	rowKey := fmt.Sprintf("%s:%x", bigtable.chainId, address)
+	err := bigtable.tableMetadata.Apply(ctx, rowKey, mut)
+	if err != nil {
+		return err
+	}
+	return cache.TieredCache.Set(bigtable.chainId+":CONTRACT:"+rowKey, metadata, time.Hour*24)
 }
 
 func (bigtable *Bigtable) SaveBalances(balances []*types.Eth1AddressBalance, deleteKeys []string) error {
@@ -0,0 +1,282 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrUserDashboardNotFound is returned if a dashboard or group does not exist or is not owned by the user
+var ErrUserDashboardNotFound = errors.New("dashboard not found")
+
+const userDashboardDefaultGroupName = "Default"
+
+// CreateUserDashboard creates a named dashboard for a user on the given network, every dashboard is created with a default group
+func CreateUserDashboard(userID uint64, network, name string) (*types.UserDashboard, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	dashboard := &types.UserDashboard{}
+	err = tx.Get(dashboard, `INSERT INTO users_dashboards (user_id, network, name) VALUES ($1, $2, $3) RETURNING id, user_id, network, name, public_id, created_ts`, userID, network, name)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting dashboard: %w", err)
+	}
+
+	group := &types.UserDashboardGroup{Validators: []uint64{}}
+	err = tx.Get(group, `INSERT INTO users_dashboards_groups (dashboard_id, name) VALUES ($1, $2) RETURNING id, dashboard_id, name, validator_set_id`, dashboard.ID, userDashboardDefaultGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting default group of dashboard %v: %w", dashboard.ID, err)
+	}
+	dashboard.Groups = []*types.UserDashboardGroup{group}
+
+	return dashboard, tx.Commit()
+}
+
+// GetUserDashboards returns all dashboards of a user on the given network including their groups and validators
+func GetUserDashboards(userID uint64, network string) ([]*types.UserDashboard, error) {
+	dashboards := []*types.UserDashboard{}
+	err := FrontendWriterDB.Select(&dashboards, `SELECT id, user_id, network, name, public_id, created_ts FROM users_dashboards WHERE user_id = $1 AND network = $2 ORDER BY id`, userID, network)
+	if err != nil {
+		return nil, err
+	}
+	err = fillUserDashboards(FrontendWriterDB, dashboards)
+	if err != nil {
+		return nil, err
+	}
+	return dashboards, nil
+}
+
+// CountUserDashboards returns the number of dashboards of a user on the given network
+func CountUserDashboards(userID uint64, network string) (uint64, error) {
+	count := uint64(0)
+	err := FrontendWriterDB.Get(&count, `SELECT COUNT(*) FROM users_dashboards WHERE user_id = $1 AND network = $2`, userID, network)
+	return count, err
+}
+
+// GetUserDashboard returns a dashboard of a user including its groups and validators, ErrUserDashboardNotFound is returned if the
+// dashboard does not exist or belongs to another user
+func GetUserDashboard(userID, dashboardID uint64) (*types.UserDashboard, error) {
+	return getUserDashboard(`SELECT id, user_id, network, name, public_id, created_ts FROM users_dashboards WHERE id = $1 AND user_id = $2`, dashboardID, userID)
+}
+
+// GetUserDashboardByPublicID returns the dashboard that is shared with the given public id
+func GetUserDashboardByPublicID(publicID string) (*types.UserDashboard, error) {
+	return getUserDashboard(`SELECT id, user_id, network, name, public_id, created_ts FROM users_dashboards WHERE public_id = $1`, publicID)
+}
+
+func getUserDashboard(query string, args ...interface{}) (*types.UserDashboard, error) {
+	dashboard := &types.UserDashboard{}
+	err := FrontendWriterDB.Get(dashboard, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserDashboardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = fillUserDashboards(FrontendWriterDB, []*types.UserDashboard{dashboard})
+	if err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+// fillUserDashboards loads the groups and validators of the dashboards
+func fillUserDashboards(q sqlx.Queryer, dashboards []*types.UserDashboard) error {
+	if len(dashboards) == 0 {
+		return nil
+	}
+	ids := make([]uint64, 0, len(dashboards))
+	dashboardsByID := make(map[uint64]*types.UserDashboard, len(dashboards))
+	for _, d := range dashboards {
+		d.Groups = []*types.UserDashboardGroup{}
+		ids = append(ids, d.ID)
+		dashboardsByID[d.ID] = d
+	}
+
+	groups := []*types.UserDashboardGroup{}
+	err := sqlx.Select(q, &groups, `SELECT id, dashboard_id, name, validator_set_id FROM users_dashboards_groups WHERE dashboard_id = ANY($1) ORDER BY id`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error retrieving dashboard groups: %w", err)
+	}
+	groupsByID := make(map[uint64]*types.UserDashboardGroup, len(groups))
+	for _, g := range groups {
+		g.Validators = []uint64{}
+		groupsByID[g.ID] = g
+		dashboardsByID[g.DashboardID].Groups = append(dashboardsByID[g.DashboardID].Groups, g)
+	}
+
+	validators := []struct {
+		GroupID        uint64 `db:"group_id"`
+		ValidatorIndex uint64 `db:"validatorindex"`
+	}{}
+	err = sqlx.Select(q, &validators, `SELECT group_id, validatorindex FROM users_dashboards_validators WHERE dashboard_id = ANY($1) ORDER BY validatorindex`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error retrieving dashboard validators: %w", err)
+	}
+	for _, v := range validators {
+		if g, ok := groupsByID[v.GroupID]; ok {
+			g.Validators = append(g.Validators, v.ValidatorIndex)
+		}
+	}
+
+	// groups that follow a validator set also contain the current members of the set
+	setValidators := []struct {
+		GroupID        uint64 `db:"group_id"`
+		ValidatorIndex uint64 `db:"validatorindex"`
+	}{}
+	err = sqlx.Select(q, &setValidators, `
+		SELECT g.id AS group_id, v.validatorindex
+		FROM users_dashboards_groups g
+		INNER JOIN users_validator_sets_validators v ON v.set_id = g.validator_set_id
+		WHERE g.dashboard_id = ANY($1)
+		ORDER BY v.validatorindex`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error retrieving dashboard validator set members: %w", err)
+	}
+	for _, v := range setValidators {
+		if g, ok := groupsByID[v.GroupID]; ok {
+			g.Validators = append(g.Validators, v.ValidatorIndex)
+		}
+	}
+	for _, g := range groups {
+		if g.ValidatorSetID != nil {
+			g.Validators = utils.SortedUniqueUint64(g.Validators)
+		}
+	}
+	return nil
+}
+
+// RenameUserDashboard renames a dashboard of a user
+func RenameUserDashboard(userID, dashboardID uint64, name string) error {
+	res, err := FrontendWriterDB.Exec(`UPDATE users_dashboards SET name = $3 WHERE id = $1 AND user_id = $2`, dashboardID, userID, name)
+	if err != nil {
+		return err
+	}
+	return checkUserDashboardRowsAffected(res)
+}
+
+// DeleteUserDashboard deletes a dashboard of a user including its groups and validators
+func DeleteUserDashboard(userID, dashboardID uint64) error {
+	res, err := FrontendWriterDB.Exec(`DELETE FROM users_dashboards WHERE id = $1 AND user_id = $2`, dashboardID, userID)
+	if err != nil {
+		return err
+	}
+	return checkUserDashboardRowsAffected(res)
+}
+
+// SetUserDashboardSharing enables or disables the read-only public link of a dashboard and returns the public id of the dashboard.
+// Enabling the sharing of an already shared dashboard keeps the existing public id.
+func SetUserDashboardSharing(userID, dashboardID uint64, enabled bool) (string, error) {
+	publicID := sql.NullString{}
+	var err error
+	if enabled {
+		err = FrontendWriterDB.Get(&publicID, `UPDATE users_dashboards SET public_id = COALESCE(public_id, $3) WHERE id = $1 AND user_id = $2 RETURNING public_id`, dashboardID, userID, utils.RandomString(32))
+	} else {
+		err = FrontendWriterDB.Get(&publicID, `UPDATE users_dashboards SET public_id = NULL WHERE id = $1 AND user_id = $2 RETURNING public_id`, dashboardID, userID)
+	}
+	if err == sql.ErrNoRows {
+		return "", ErrUserDashboardNotFound
+	}
+	return publicID.String, err
+}
+
+// CreateUserDashboardGroup adds a named group to a dashboard of a user, if validatorSetID is valid the group also contains all members of the validator set
+func CreateUserDashboardGroup(userID, dashboardID uint64, name string, validatorSetID sql.NullInt64) (*types.UserDashboardGroup, error) {
+	group := &types.UserDashboardGroup{Validators: []uint64{}}
+	err := FrontendWriterDB.Get(group, `
+		INSERT INTO users_dashboards_groups (dashboard_id, name, validator_set_id)
+		SELECT id, $3, $4 FROM users_dashboards WHERE id = $1 AND user_id = $2
+		RETURNING id, dashboard_id, name, validator_set_id`, dashboardID, userID, name, validatorSetID)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserDashboardNotFound
+	}
+	return group, err
+}
+
+// UpdateUserDashboardGroup renames a group of a dashboard of a user and sets the validator set the group follows
+func UpdateUserDashboardGroup(userID, dashboardID, groupID uint64, name string, validatorSetID sql.NullInt64) error {
+	res, err := FrontendWriterDB.Exec(`
+		UPDATE users_dashboards_groups SET name = $4, validator_set_id = $5
+		WHERE id = $3 AND dashboard_id = (SELECT id FROM users_dashboards WHERE id = $1 AND user_id = $2)`, dashboardID, userID, groupID, name, validatorSetID)
+	if err != nil {
+		return err
+	}
+	return checkUserDashboardRowsAffected(res)
+}
+
+// DeleteUserDashboardGroup deletes a group and all of its validators from a dashboard of a user
+func DeleteUserDashboardGroup(userID, dashboardID, groupID uint64) error {
+	res, err := FrontendWriterDB.Exec(`
+		DELETE FROM users_dashboards_groups
+		WHERE id = $3 AND dashboard_id = (SELECT id FROM users_dashboards WHERE id = $1 AND user_id = $2)`, dashboardID, userID, groupID)
+	if err != nil {
+		return err
+	}
+	return checkUserDashboardRowsAffected(res)
+}
+
+// AddValidatorsToUserDashboard adds validators to a group of a dashboard of a user, validators that are already part of another group
+// of the dashboard are moved to the group. If groupID is 0 the first group of the dashboard is used.
+func AddValidatorsToUserDashboard(userID, dashboardID, groupID uint64, validators []uint64) error {
+	if len(validators) == 0 {
+		return nil
+	}
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.Get(&groupID, `
+		SELECT g.id FROM users_dashboards_groups g
+		INNER JOIN users_dashboards d ON d.id = g.dashboard_id
+		WHERE d.id = $1 AND d.user_id = $2 AND ($3 = 0 OR g.id = $3)
+		ORDER BY g.id LIMIT 1`, dashboardID, userID, groupID)
+	if err == sql.ErrNoRows {
+		return ErrUserDashboardNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO users_dashboards_validators (dashboard_id, group_id, validatorindex)
+		SELECT $1, $2, UNNEST($3::int[])
+		ON CONFLICT (dashboard_id, validatorindex) DO UPDATE SET group_id = excluded.group_id`, dashboardID, groupID, pq.Array(validators))
+	if err != nil {
+		return fmt.Errorf("error inserting validators of dashboard %v: %w", dashboardID, err)
+	}
+	return tx.Commit()
+}
+
+// RemoveValidatorsFromUserDashboard removes validators from a dashboard of a user, ErrUserDashboardNotFound is returned if the user does not own
+// the dashboard or none of the validators is part of it
+func RemoveValidatorsFromUserDashboard(userID, dashboardID uint64, validators []uint64) error {
+	res, err := FrontendWriterDB.Exec(`
+		DELETE FROM users_dashboards_validators v
+		USING users_dashboards d
+		WHERE v.dashboard_id = d.id AND d.id = $1 AND d.user_id = $2 AND v.validatorindex = ANY($3)`, dashboardID, userID, pq.Array(validators))
+	if err != nil {
+		return err
+	}
+	return checkUserDashboardRowsAffected(res)
+}
+
+func checkUserDashboardRowsAffected(res sql.Result) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserDashboardNotFound
+	}
+	return nil
+}
@@ -1308,12 +1308,35 @@ func saveValidators(data *types.EpochData, tx *sqlx.Tx, client rpc.Client) error
 	return nil
 }
 
+// SaveUpcomingProposalAssignments persists the proposer duties of an epoch that has not started yet as scheduled proposal assignments,
+// duties that changed since they were last saved are replaced
+func SaveUpcomingProposalAssignments(epoch uint64, assignments map[uint64]uint64) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = saveValidatorProposalAssignments(epoch, assignments, tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func saveValidatorProposalAssignments(epoch uint64, assignments map[uint64]uint64, tx *sqlx.Tx) error {
 	start := time.Now()
 	defer func() {
 		metrics.TaskDuration.WithLabelValues("db_save_proposal_assignments").Observe(time.Since(start).Seconds())
 	}()
 
+	// drop the scheduled assignments of the epoch as they might have been persisted ahead of time and the duties changed since
+	_, err := tx.Exec(`DELETE FROM proposal_assignments WHERE epoch = $1 AND status = 0`, epoch)
+	if err != nil {
+		return fmt.Errorf("error deleting outdated proposal assignments of epoch %v: %w", epoch, err)
+	}
+
 	stmt, err := tx.Prepare(`
 		INSERT INTO proposal_assignments (epoch, validatorindex, proposerslot, status)
 		VALUES ($1, $2, $3, $4)
@@ -1858,6 +1881,32 @@ func updateQueueDeposits() error {
 	return nil
 }
 
+// GetChurnQueueState returns the state of the activation and exit queue at the given epoch
+func GetChurnQueueState(epoch uint64) (*types.ChurnQueueState, error) {
+	state := &types.ChurnQueueState{Epoch: epoch}
+	err := ReaderDb.Get(state, `
+		SELECT
+			COUNT(*) FILTER (WHERE activationepoch <= $1 AND exitepoch > $1) AS active_validator_count,
+			COUNT(*) FILTER (WHERE activationepoch > $1 AND activationeligibilityepoch < $2) AS activation_queue_length
+		FROM validators`, epoch, maxSqlNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator counts for churn queue state: %w", err)
+	}
+
+	err = ReaderDb.Get(state, `
+		SELECT exitepoch AS exit_queue_epoch, COUNT(*) AS exit_queue_churn
+		FROM validators
+		WHERE exitepoch > $1 AND exitepoch < $2
+		GROUP BY exitepoch
+		ORDER BY exitepoch DESC
+		LIMIT 1`, epoch, maxSqlNumber)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error getting exit queue for churn queue state: %w", err)
+	}
+
+	return state, nil
+}
+
 func GetQueueAheadOfValidator(validatorIndex uint64) (uint64, error) {
 	var res uint64
 	var selected struct {
@@ -2634,6 +2683,32 @@ func GetMostRecentWithdrawalValidator() (uint64, error) {
 	return validatorindex, nil
 }
 
+// GetWithdrawalSweepValidators returns all validators with execution withdrawal credentials ordered by index as well as the total number of validators
+func GetWithdrawalSweepValidators() ([]*types.WithdrawalSweepValidator, uint64, error) {
+	var validatorCount uint64
+	err := ReaderDb.Get(&validatorCount, `SELECT COALESCE(MAX(validatorindex) + 1, 0) FROM validators`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting validator count: %w", err)
+	}
+
+	validators := []*types.WithdrawalSweepValidator{}
+	err = ReaderDb.Select(&validators, `
+		SELECT
+			validatorindex,
+			withdrawalcredentials,
+			withdrawableepoch,
+			balance,
+			effectivebalance
+		FROM validators
+		WHERE withdrawalcredentials LIKE '\x01' || '%'::bytea
+		ORDER BY validatorindex ASC`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting withdrawal sweep validators: %w", err)
+	}
+
+	return validators, validatorCount, nil
+}
+
 // get all ad configurations
 func GetAdConfigurations() ([]*types.AdConfig, error) {
 	var adConfigs []*types.AdConfig
@@ -3023,6 +3098,26 @@ func GetValidatorsInitialWithdrawalCredentials(validators []uint64) ([][]byte, e
 	return withdrawalCredentials, nil
 }
 
+// GetValidatorsForEth1Address returns the validators that were deposited by an address or withdraw to it ordered by index, at most limit validators are returned
+func GetValidatorsForEth1Address(address []byte, limit int) ([]*types.ValidatorSetMember, error) {
+	credentials, err := utils.AddressToWithdrawalCredentials(address)
+	if err != nil {
+		return nil, err
+	}
+
+	validators := []*types.ValidatorSetMember{}
+	err = ReaderDb.Select(&validators, `
+		SELECT validatorindex, pubkey
+		FROM validators
+		WHERE withdrawalcredentials = $1 OR pubkey IN (SELECT publickey FROM eth1_deposits WHERE from_address = $2)
+		ORDER BY validatorindex
+		LIMIT $3`, credentials, address, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validators of address %x: %w", address, err)
+	}
+	return validators, nil
+}
+
 func GetWithdrawableValidatorCount(epoch uint64) (uint64, error) {
 	var count uint64
 	err := ReaderDb.Get(&count, `
@@ -6,6 +6,8 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"eth2-exporter/ens"
+	"eth2-exporter/erc1155"
+	"eth2-exporter/erc721"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
@@ -18,9 +20,11 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/coocood/freecache"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	eth_types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/lib/pq"
 
 	go_ens "github.com/wealdtech/go-ens/v3"
 )
@@ -97,6 +101,7 @@ func (bigtable *Bigtable) TransformEnsNameRegistered(blk *types.Eth1Block, cache
 		foundResolverIndex := -1
 		foundNameRenewedIndex := -1
 		foundAddressChangedIndices := []int{}
+		foundRecordChangedIndices := []int{}
 		foundNameChangedIndex := -1
 		foundNewOwnerIndex := -1
 		logs := tx.GetLogs()
@@ -119,6 +124,8 @@ func (bigtable *Bigtable) TransformEnsNameRegistered(blk *types.Eth1Block, cache
 					foundNameChangedIndex = j
 				} else if bytes.Equal(lTopic, ens.NewOwnerTopic) {
 					foundNewOwnerIndex = j
+				} else if bytes.Equal(lTopic, ens.TextChangedTopic) || bytes.Equal(lTopic, ens.TextChangedWithValueTopic) || bytes.Equal(lTopic, ens.ContenthashChangedTopic) {
+					foundRecordChangedIndices = append(foundRecordChangedIndices, j)
 				}
 			}
 		}
@@ -285,6 +292,15 @@ func (bigtable *Bigtable) TransformEnsNameRegistered(blk *types.Eth1Block, cache
 			keys[fmt.Sprintf("%s:ENS:V:H:%x", bigtable.chainId, addressChanged.Node)] = true
 
 		}
+		// We found changed text records or contenthashes, the node is always the first indexed topic so we do not need to parse the events
+		for _, recordChangeIndex := range foundRecordChangedIndices {
+			topics := logs[recordChangeIndex].GetTopics()
+			if len(topics) < 2 {
+				continue
+			}
+			keys[fmt.Sprintf("%s:ENS:I:H:%x:%x", bigtable.chainId, topics[1], tx.GetHash())] = true
+			keys[fmt.Sprintf("%s:ENS:V:H:%x", bigtable.chainId, topics[1])] = true
+		}
 	}
 	for key := range keys {
 		mut := gcp_bigtable.NewMutation()
@@ -297,6 +313,54 @@ func (bigtable *Bigtable) TransformEnsNameRegistered(blk *types.Eth1Block, cache
 	return bulkData, bulkMetadataUpdates, nil
 }
 
+// QueueEnsNameUpdate marks a name dirty so it is validated and indexed by the next ImportEnsUpdates, e.g. for names that were registered before the indexer started
+func (bigtable *Bigtable) QueueEnsNameUpdate(name string) error {
+	if err := verifyName(strings.TrimSuffix(name, ".eth")); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	key := fmt.Sprintf("%s:ENS:V:N:%s", bigtable.chainId, name)
+	mut := gcp_bigtable.NewMutation()
+	mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+	return bigtable.tableData.Apply(ctx, key, mut)
+}
+
+// QueueEnsNameUpdates marks names dirty in bulk so they are validated and indexed by the next ImportEnsUpdates, names that are too long are skipped
+func (bigtable *Bigtable) QueueEnsNameUpdates(names []string) error {
+	mutations := &types.BulkMutations{
+		Keys: make([]string, 0, len(names)),
+		Muts: make([]*gcp_bigtable.Mutation, 0, len(names)),
+	}
+	for _, name := range names {
+		if err := verifyName(strings.TrimSuffix(name, ".eth")); err != nil {
+			logger.Warnf("not queuing ens name %v for an update: %v", name, err)
+			continue
+		}
+		key := fmt.Sprintf("%s:ENS:V:N:%s", bigtable.chainId, name)
+		mut := gcp_bigtable.NewMutation()
+		mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+		mutations.Keys = append(mutations.Keys, key)
+		mutations.Muts = append(mutations.Muts, mut)
+	}
+	return bigtable.WriteBulk(mutations, bigtable.tableData)
+}
+
+// GetEnsNamesWithoutRecords returns the names that have neither an owner nor any resolver records, which are the names that were validated before owners and records were stored
+func GetEnsNamesWithoutRecords() ([]string, error) {
+	names := []string{}
+	err := ReaderDb.Select(&names, `
+		SELECT ens_name
+		FROM ens
+		WHERE owner IS NULL AND url IS NULL AND twitter IS NULL AND github IS NULL AND description IS NULL AND avatar IS NULL AND contenthash IS NULL
+		ORDER BY ens_name`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ens names without records: %w", err)
+	}
+	return names, nil
+}
+
 // Ens names are only supported to a length of 40
 // Source: https://github.com/wealdtech/go-ens/blob/5b323a4ef0472f06c515723b060b166843b9db08/resolver.go#L190
 func verifyName(name string) error {
@@ -498,6 +562,15 @@ func validateEnsName(client *ethclient.Client, name string, alreadyChecked *EnsC
 		logger.Warnf("could not get ens expire date [%v]: %v", name, err)
 		return removeEnsName(client, name)
 	}
+	// the registrant of the main domain is the owner that has to renew the name
+	var owner []byte
+	registrant, err := ensName.Registrant()
+	if err != nil {
+		logger.Warnf("could not get ens registrant [%v]: %v", name, err)
+	} else {
+		owner = registrant.Bytes()
+	}
+
 	isPrimary := false
 	if isPrimaryName == nil {
 		reverseName, err := go_ens.ReverseResolve(client, addr)
@@ -507,22 +580,41 @@ func validateEnsName(client *ethclient.Client, name string, alreadyChecked *EnsC
 	} else if *isPrimaryName {
 		isPrimary = true
 	}
+
+	records := getEnsResolverRecords(client, name)
 	_, err = WriterDb.Exec(`
 	INSERT INTO ens (
 		name_hash, 
 		ens_name, 
 		address,
 		is_primary_name, 
-		valid_to)
-	VALUES ($1, $2, $3, $4, $5) 
+		valid_to,
+		owner,
+		url,
+		twitter,
+		github,
+		description,
+		avatar,
+		avatar_url,
+		contenthash)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) 
 	ON CONFLICT 
 		(name_hash) 
 	DO UPDATE SET 
 		ens_name = excluded.ens_name,
 		address = excluded.address,
 		is_primary_name = excluded.is_primary_name,
-		valid_to = excluded.valid_to
-	`, nameHash[:], name, addr.Bytes(), isPrimary, expires)
+		valid_to = excluded.valid_to,
+		owner = excluded.owner,
+		url = excluded.url,
+		twitter = excluded.twitter,
+		github = excluded.github,
+		description = excluded.description,
+		avatar = excluded.avatar,
+		avatar_url = excluded.avatar_url,
+		contenthash = excluded.contenthash
+	`, nameHash[:], name, addr.Bytes(), isPrimary, expires, owner,
+		records.Url, records.Twitter, records.Github, records.Description, records.Avatar, records.AvatarUrl, records.Contenthash)
 	if err != nil {
 		utils.LogError(err, fmt.Errorf("error writing ens data for name [%v]", name), 0)
 		return err
@@ -531,6 +623,93 @@ func validateEnsName(client *ethclient.Client, name string, alreadyChecked *EnsC
 	return nil
 }
 
+// getEnsResolverRecords reads the text records and the contenthash of a name from its resolver.
+// Resolvers do not have to support all records, so records that can not be read are left empty.
+func getEnsResolverRecords(client *ethclient.Client, name string) *types.EnsRecords {
+	records := &types.EnsRecords{Name: name}
+	resolver, err := go_ens.NewResolver(client, name)
+	if err != nil {
+		logger.Warnf("could not get ens resolver [%v]: %v", name, err)
+		return records
+	}
+
+	texts := make(map[string]string, len(utils.EnsTextRecordKeys))
+	for _, key := range utils.EnsTextRecordKeys {
+		text, err := resolver.Text(key)
+		if err != nil {
+			logger.Warnf("could not get ens text record %v of [%v]: %v", key, name, err)
+			continue
+		}
+		// text records can be set to arbitrary values by the owner, huge values are ignored
+		if len(text) > 4096 {
+			logger.Warnf("ignoring ens text record %v of [%v] with a length of %v", key, name, len(text))
+			continue
+		}
+		texts[key] = strings.ToValidUTF8(text, "")
+	}
+	records.Url = texts["url"]
+	records.Twitter = texts["com.twitter"]
+	records.Github = texts["com.github"]
+	records.Description = texts["description"]
+	records.Avatar = texts["avatar"]
+	records.AvatarUrl = resolveEnsAvatar(client, records.Avatar)
+
+	contenthash, err := resolver.Contenthash()
+	if err != nil {
+		logger.Warnf("could not get ens contenthash of [%v]: %v", name, err)
+	} else if len(contenthash) > 0 {
+		records.Contenthash, err = go_ens.ContenthashToString(contenthash)
+		if err != nil {
+			logger.Warnf("could not decode ens contenthash %x of [%v]: %v", contenthash, name, err)
+		}
+	}
+	return records
+}
+
+// resolveEnsAvatar returns the image url of an avatar record, avatars that point to an nft are resolved to the image of the nft metadata
+func resolveEnsAvatar(client *ethclient.Client, avatar string) string {
+	if avatar == "" {
+		return ""
+	}
+	standard, token, tokenId, ok := utils.ParseEnsAvatarNft(avatar)
+	if !ok {
+		return utils.EnsAvatarUrl(avatar, utils.Config.Frontend.NftMetadata.IpfsGateway)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	var uri string
+	var err error
+	switch standard {
+	case types.NftStandardERC721:
+		var caller *erc721.Erc721Caller
+		caller, err = erc721.NewErc721Caller(token, client)
+		if err == nil {
+			uri, err = caller.TokenURI(&bind.CallOpts{Context: ctx}, tokenId)
+		}
+	case types.NftStandardERC1155:
+		var caller *erc1155.Erc1155Caller
+		caller, err = erc1155.NewErc1155Caller(token, client)
+		if err == nil {
+			uri, err = caller.Uri(&bind.CallOpts{Context: ctx}, tokenId)
+			uri = utils.NftTokenUri(uri, tokenId)
+		}
+	}
+	if err != nil || uri == "" {
+		logger.Warnf("could not get metadata uri of ens avatar nft %v: %v", avatar, err)
+		return ""
+	}
+
+	fetcher := utils.NewHttpNftMetadataFetcher(utils.Config.Frontend.NftMetadata.IpfsGateway, utils.Config.Frontend.NftMetadata.Timeout)
+	metadata, err := fetcher.FetchNftMetadata(ctx, uri)
+	if err != nil {
+		logger.Warnf("could not fetch metadata of ens avatar nft %v: %v", avatar, err)
+		return ""
+	}
+	return utils.EnsAvatarUrl(metadata.Image, utils.Config.Frontend.NftMetadata.IpfsGateway)
+}
+
 func removeEnsAddress(client *ethclient.Client, address common.Address, alreadyChecked *EnsCheckedDictionary) error {
 	name, err := GetEnsNameForAddress(address)
 	if err != nil && err != sql.ErrNoRows {
@@ -586,3 +765,56 @@ func GetEnsNameForAddress(address common.Address) (name *string, err error) {
 	;`, address.Bytes())
 	return name, err
 }
+
+// GetEnsRecords returns the indexed records of a name including its expiry status, expired names are returned as well
+func GetEnsRecords(name string) (*types.EnsRecords, error) {
+	records := &types.EnsRecords{}
+	err := ReaderDb.Get(records, `
+	SELECT 
+		ens_name,
+		address,
+		owner,
+		COALESCE(url, '') AS url,
+		COALESCE(twitter, '') AS twitter,
+		COALESCE(github, '') AS github,
+		COALESCE(description, '') AS description,
+		COALESCE(avatar, '') AS avatar,
+		COALESCE(avatar_url, '') AS avatar_url,
+		COALESCE(contenthash, '') AS contenthash,
+		valid_to
+	FROM ens
+	WHERE
+		ens_name = $1
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+	records.Status = utils.EnsNameStatus(records.ValidTo, time.Now())
+	return records, nil
+}
+
+// GetExpiringEnsNames returns the names owned by one of the owners that expire before before or are within their grace period
+func GetExpiringEnsNames(owners [][]byte, before time.Time) ([]*types.EnsRecords, error) {
+	names := []*types.EnsRecords{}
+	now := time.Now()
+	err := ReaderDb.Select(&names, `
+	SELECT 
+		ens_name,
+		address,
+		owner,
+		valid_to
+	FROM ens
+	WHERE
+		owner = ANY($1) AND
+		valid_to > $2 AND
+		valid_to < $3
+	ORDER BY valid_to
+	`, pq.ByteaArray(owners), now.Add(-utils.EnsGracePeriod).UTC(), before.UTC())
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range names {
+		n.Status = utils.EnsNameStatus(n.ValidTo, now)
+	}
+	return names, nil
+}
@@ -0,0 +1,305 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/coocood/freecache"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrEventIndexerNotFound = errors.New("event indexer not found")
+
+var eventIndexerNameRE = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// EventIndexer indexes the logs of a contract that match the events of an abi, it is configured by an entry of Indexer.EventIndexers
+type EventIndexer struct {
+	name     string
+	contract common.Address
+	events   map[common.Hash]abi.Event
+}
+
+// NewEventIndexer validates the config of an event indexer and parses its abi
+func NewEventIndexer(config types.EventIndexerConfig) (*EventIndexer, error) {
+	if !eventIndexerNameRE.MatchString(config.Name) {
+		return nil, fmt.Errorf("invalid event indexer name %q, it may only contain letters, digits, - and _", config.Name)
+	}
+	if !common.IsHexAddress(config.Address) {
+		return nil, fmt.Errorf("invalid contract address %q of event indexer %v", config.Address, config.Name)
+	}
+	contractAbi, err := abi.JSON(strings.NewReader(config.Abi))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi of event indexer %v: %w", config.Name, err)
+	}
+
+	indexer := &EventIndexer{
+		name:     config.Name,
+		contract: common.HexToAddress(config.Address),
+		events:   make(map[common.Hash]abi.Event, len(contractAbi.Events)),
+	}
+	for _, event := range contractAbi.Events {
+		if !event.Anonymous {
+			indexer.events[event.ID] = event
+		}
+	}
+	if len(indexer.events) == 0 {
+		return nil, fmt.Errorf("the abi of event indexer %v does not contain any non-anonymous event", config.Name)
+	}
+	return indexer, nil
+}
+
+// NewEventIndexers validates the configs of all event indexers and parses their abis, the name of every indexer has to be unique
+// as it is part of the row keys of the indexed events
+func NewEventIndexers(configs []types.EventIndexerConfig) ([]*EventIndexer, error) {
+	indexers := make([]*EventIndexer, 0, len(configs))
+	names := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		if names[config.Name] {
+			return nil, fmt.Errorf("duplicate event indexer name %q", config.Name)
+		}
+		names[config.Name] = true
+
+		indexer, err := NewEventIndexer(config)
+		if err != nil {
+			return nil, err
+		}
+		indexers = append(indexers, indexer)
+	}
+	return indexers, nil
+}
+
+var configuredEventIndexers struct {
+	once   sync.Once
+	byName map[string]*EventIndexer
+	err    error
+}
+
+// GetEventIndexer returns the configured event indexer with the given name, the configured indexers are only parsed once
+func GetEventIndexer(name string) (*EventIndexer, error) {
+	configuredEventIndexers.once.Do(func() {
+		indexers, err := NewEventIndexers(utils.Config.Indexer.EventIndexers)
+		if err != nil {
+			configuredEventIndexers.err = err
+			return
+		}
+		configuredEventIndexers.byName = make(map[string]*EventIndexer, len(indexers))
+		for _, indexer := range indexers {
+			configuredEventIndexers.byName[indexer.name] = indexer
+		}
+	})
+	if configuredEventIndexers.err != nil {
+		return nil, configuredEventIndexers.err
+	}
+	indexer, ok := configuredEventIndexers.byName[name]
+	if !ok {
+		return nil, ErrEventIndexerNotFound
+	}
+	return indexer, nil
+}
+
+func (indexer *EventIndexer) Name() string {
+	return indexer.name
+}
+
+func (indexer *EventIndexer) Contract() common.Address {
+	return indexer.contract
+}
+
+// Event returns the indexed event of a log, nil is returned if the log is not emitted by an event of the indexer
+func (indexer *EventIndexer) Event(topics [][]byte) *abi.Event {
+	if len(topics) == 0 {
+		return nil
+	}
+	event, ok := indexer.events[common.BytesToHash(topics[0])]
+	if !ok {
+		return nil
+	}
+	return &event
+}
+
+// addresses returns all addresses that are arguments of the event of a log
+func (indexer *EventIndexer) addresses(event *abi.Event, topics [][]byte, data []byte) []common.Address {
+	addresses := []common.Address{}
+	topic := 1
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			continue
+		}
+		if input.Type.T == abi.AddressTy && topic < len(topics) {
+			addresses = append(addresses, common.BytesToAddress(topics[topic]))
+		}
+		topic++
+	}
+
+	values, err := event.Inputs.NonIndexed().Unpack(data)
+	if err != nil {
+		return addresses
+	}
+	for _, value := range values {
+		if address, ok := value.(common.Address); ok {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// TransformEvents returns a transformer that indexes the logs of the contract of an event indexer
+// ==================================================
+//
+// It indexes logs by their tx hash and log index
+// Row:    <chainID>:EVT:<indexerName>:<txHash>:<paddedLogIndex>
+// Family: f
+// Column: data
+// Cell:   json encoded types.Eth1IndexedEvent
+// Example scan: "1:EVT:lido:e627ae94bd16eb1ed8774cd4003fc25625159f13f8a2612cc1c7f8d2ab11b1d7:99999"
+//
+// ==================================================
+//
+// It indexes the key of the log:
+//
+// - all logs by time
+// Row:    <chainID>:I:EVT:<indexerName>:ALL:TIME:<reversePaddedTimestamp>:<paddedTxIndex>:<paddedLogIndex>
+// Family: f
+// Column: key of the log
+// Cell:   nil
+//
+// - by every address that is an argument of the event
+// Row:    <chainID>:I:EVT:<indexerName>:<address>:TIME:<reversePaddedTimestamp>:<paddedTxIndex>:<paddedLogIndex>
+// Family: f
+// Column: key of the log
+// Cell:   nil
+//
+// ==================================================
+func (bigtable *Bigtable) TransformEvents(indexer *EventIndexer) func(blk *types.Eth1Block, cache *freecache.Cache) (*types.BulkMutations, *types.BulkMutations, error) {
+	return func(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+		bulkData = &types.BulkMutations{}
+		bulkMetadataUpdates = &types.BulkMutations{}
+
+		for i, tx := range blk.GetTransactions() {
+			if i > 9999 {
+				return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most 9999 but got: %v, tx: %x", i, tx.GetHash())
+			}
+			iReversed := reversePaddedIndex(i, 10000)
+			for j, log := range tx.GetLogs() {
+				if j > 99999 {
+					return nil, nil, fmt.Errorf("unexpected number of logs in block expected at most 99999 but got: %v tx: %x", j, tx.GetHash())
+				}
+				if !bytes.Equal(log.GetAddress(), indexer.contract.Bytes()) || log.GetRemoved() {
+					continue
+				}
+				event := indexer.Event(log.GetTopics())
+				if event == nil {
+					continue
+				}
+				jReversed := reversePaddedIndex(j, 100000)
+
+				indexedEvent := &types.Eth1IndexedEvent{
+					BlockNumber: blk.GetNumber(),
+					Time:        blk.GetTime().GetSeconds(),
+					TxHash:      tx.GetHash(),
+					TxIndex:     uint64(i),
+					LogIndex:    uint64(j),
+					Topics:      log.GetTopics(),
+					Data:        log.GetData(),
+				}
+				b, err := json.Marshal(indexedEvent)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				key := fmt.Sprintf("%s:EVT:%s:%x:%s", bigtable.chainId, indexer.name, tx.GetHash(), jReversed)
+				mut := gcp_bigtable.NewMutation()
+				mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+				bulkData.Keys = append(bulkData.Keys, key)
+				bulkData.Muts = append(bulkData.Muts, mut)
+
+				indexes := map[string]bool{
+					fmt.Sprintf("%s:I:EVT:%s:ALL:%s:%s:%s:%s", bigtable.chainId, indexer.name, FILTER_TIME, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed): true,
+				}
+				for _, address := range indexer.addresses(event, log.GetTopics(), log.GetData()) {
+					indexes[fmt.Sprintf("%s:I:EVT:%s:%x:%s:%s:%s:%s", bigtable.chainId, indexer.name, address, FILTER_TIME, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed)] = true
+				}
+				for idx := range indexes {
+					mut := gcp_bigtable.NewMutation()
+					mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+					bulkData.Keys = append(bulkData.Keys, idx)
+					bulkData.Muts = append(bulkData.Muts, mut)
+				}
+			}
+		}
+		return bulkData, bulkMetadataUpdates, nil
+	}
+}
+
+// GetIndexedEvents returns the latest logs of an event indexer starting after pageToken, if address is set only logs with the address as argument are returned.
+// The returned page token is empty if there are no more logs.
+func (bigtable *Bigtable) GetIndexedEvents(indexer *EventIndexer, address *common.Address, pageToken string, limit int64) ([]*types.Eth1IndexedEvent, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	prefix := fmt.Sprintf("%s:I:EVT:%s:ALL:%s:", bigtable.chainId, indexer.name, FILTER_TIME)
+	if address != nil {
+		prefix = fmt.Sprintf("%s:I:EVT:%s:%x:%s:", bigtable.chainId, indexer.name, address.Bytes(), FILTER_TIME)
+	}
+	if pageToken == "" {
+		pageToken = prefix
+	} else if !strings.HasPrefix(pageToken, prefix) {
+		return nil, "", fmt.Errorf("invalid page token")
+	}
+
+	// add \x00 to the row range such that we skip the previous value
+	rowRange := gcp_bigtable.NewRange(pageToken+"\x00", prefixSuccessor(prefix, 6))
+	keys := make([]string, 0, limit)
+	indexes := make([]string, 0, limit)
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		indexes = append(indexes, row.Key())
+		return true
+	}, gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(keys) == 0 {
+		return []*types.Eth1IndexedEvent{}, "", nil
+	}
+
+	keysMap := make(map[string]*types.Eth1IndexedEvent, len(keys))
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		event := &types.Eth1IndexedEvent{}
+		err := json.Unmarshal(row[DEFAULT_FAMILY][0].Value, event)
+		if err != nil {
+			logger.WithError(err).Errorf("error parsing indexed event %v", row.Key())
+			return true
+		}
+		keysMap[row.Key()] = event
+		return true
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	events := make([]*types.Eth1IndexedEvent, 0, len(keys))
+	for _, key := range keys {
+		if event := keysMap[key]; event != nil {
+			events = append(events, event)
+		}
+	}
+
+	nextPageToken := ""
+	if int64(len(indexes)) == limit {
+		nextPageToken = indexes[len(indexes)-1]
+	}
+	return events, nextPageToken, nil
+}
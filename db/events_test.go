@@ -0,0 +1,136 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testEventIndexerAbi = `[
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Submitted","inputs":[{"name":"amount","type":"uint256","indexed":true},{"name":"sender","type":"address","indexed":false},{"name":"referral","type":"address","indexed":false}]},
+	{"type":"event","name":"Marker","anonymous":true,"inputs":[{"name":"account","type":"address","indexed":true}]},
+	{"type":"function","name":"submit","inputs":[{"name":"referral","type":"address"}],"outputs":[]}
+]`
+
+const testEventIndexerAddress = "0xae7ab96520de3a18e5e111b5eaab095312d7fe84"
+
+func TestNewEventIndexer(t *testing.T) {
+	tests := []struct {
+		name   string
+		config types.EventIndexerConfig
+		events int
+		err    string
+	}{
+		{"valid", types.EventIndexerConfig{Name: "lido_steth-1", Address: testEventIndexerAddress, Abi: testEventIndexerAbi}, 2, ""},
+		{"empty name", types.EventIndexerConfig{Name: "", Address: testEventIndexerAddress, Abi: testEventIndexerAbi}, 0, "invalid event indexer name"},
+		{"name with separator", types.EventIndexerConfig{Name: "lido:steth", Address: testEventIndexerAddress, Abi: testEventIndexerAbi}, 0, "invalid event indexer name"},
+		{"name too long", types.EventIndexerConfig{Name: strings.Repeat("a", 65), Address: testEventIndexerAddress, Abi: testEventIndexerAbi}, 0, "invalid event indexer name"},
+		{"invalid address", types.EventIndexerConfig{Name: "lido", Address: "0xae7ab965", Abi: testEventIndexerAbi}, 0, "invalid contract address"},
+		{"invalid abi", types.EventIndexerConfig{Name: "lido", Address: testEventIndexerAddress, Abi: `{"type":`}, 0, "invalid abi"},
+		{"only anonymous events", types.EventIndexerConfig{Name: "lido", Address: testEventIndexerAddress, Abi: `[{"type":"event","name":"Marker","anonymous":true,"inputs":[]}]`}, 0, "does not contain any non-anonymous event"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer, err := NewEventIndexer(tt.config)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if indexer.Name() != tt.config.Name || indexer.Contract() != common.HexToAddress(tt.config.Address) {
+				t.Errorf("expected indexer %v of %v, got %v of %v", tt.config.Name, tt.config.Address, indexer.Name(), indexer.Contract())
+			}
+			if len(indexer.events) != tt.events {
+				t.Errorf("expected %v indexed events, got %v", tt.events, len(indexer.events))
+			}
+		})
+	}
+}
+
+func TestNewEventIndexers(t *testing.T) {
+	configs := []types.EventIndexerConfig{
+		{Name: "lido", Address: testEventIndexerAddress, Abi: testEventIndexerAbi},
+		{Name: "rocketpool", Address: testEventIndexerAddress, Abi: testEventIndexerAbi},
+	}
+	indexers, err := NewEventIndexers(configs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(indexers) != 2 || indexers[0].Name() != "lido" || indexers[1].Name() != "rocketpool" {
+		t.Errorf("expected the indexers lido and rocketpool, got %v", indexers)
+	}
+
+	_, err = NewEventIndexers(append(configs, types.EventIndexerConfig{Name: "lido", Address: testEventIndexerAddress, Abi: testEventIndexerAbi}))
+	if err == nil || !strings.Contains(err.Error(), "duplicate event indexer name") {
+		t.Errorf("expected an error for the duplicate name, got %v", err)
+	}
+}
+
+func TestEventIndexerAddresses(t *testing.T) {
+	indexer, err := NewEventIndexer(types.EventIndexerConfig{Name: "lido", Address: testEventIndexerAddress, Abi: testEventIndexerAbi})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transfer := indexer.events[eventID("Transfer(address,address,uint256)")]
+	submitted := indexer.events[eventID("Submitted(uint256,address,address)")]
+
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	referral := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	transferData, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	submittedData, err := submitted.Inputs.NonIndexed().Pack(from, referral)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		event     string
+		topics    [][]byte
+		data      []byte
+		addresses []common.Address
+	}{
+		{"indexed addresses", "Transfer", [][]byte{transfer.ID.Bytes(), common.LeftPadBytes(from.Bytes(), 32), common.LeftPadBytes(to.Bytes(), 32)}, transferData, []common.Address{from, to}},
+		{"missing topic", "Transfer", [][]byte{transfer.ID.Bytes(), common.LeftPadBytes(from.Bytes(), 32)}, transferData, []common.Address{from}},
+		{"non-indexed addresses", "Submitted", [][]byte{submitted.ID.Bytes(), common.LeftPadBytes(big.NewInt(5).Bytes(), 32)}, submittedData, []common.Address{from, referral}},
+		{"undecodable data", "Submitted", [][]byte{submitted.ID.Bytes(), common.LeftPadBytes(big.NewInt(5).Bytes(), 32)}, []byte{0x01}, []common.Address{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := indexer.Event(tt.topics)
+			if event == nil || event.RawName != tt.event {
+				t.Fatalf("expected event %v, got %v", tt.event, event)
+			}
+			addresses := indexer.addresses(event, tt.topics, tt.data)
+			if !reflect.DeepEqual(addresses, tt.addresses) {
+				t.Errorf("expected addresses %v, got %v", tt.addresses, addresses)
+			}
+		})
+	}
+
+	if event := indexer.Event([][]byte{eventID("Marker(address)").Bytes()}); event != nil {
+		t.Errorf("expected no event for an anonymous event, got %v", event.RawName)
+	}
+	if event := indexer.Event(nil); event != nil {
+		t.Errorf("expected no event without topics, got %v", event.RawName)
+	}
+}
+
+// eventID returns the id of an event, the keccak256 hash of its signature
+func eventID(signature string) common.Hash {
+	return crypto.Keccak256Hash([]byte(signature))
+}
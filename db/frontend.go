@@ -31,6 +31,13 @@ func GetUserEmailById(id uint64) (string, error) {
 	return mail, err
 }
 
+// GetUserGroupById returns the group of a user, it is empty for regular users.
+func GetUserGroupById(id uint64) (string, error) {
+	var group string = ""
+	err := FrontendWriterDB.Get(&group, "SELECT COALESCE(user_group, '') FROM users WHERE id = $1", id)
+	return group, err
+}
+
 // GetUserEmailsByIds returns the emails of users.
 func GetUserEmailsByIds(ids []uint64) (map[uint64]string, error) {
 	mailsByID := map[uint64]string{}
@@ -299,6 +306,18 @@ func DeleteSubscription(userID uint64, network string, eventName types.EventName
 	return err
 }
 
+// HasSubscription returns whether the user is subscribed to an event with an event filter
+func HasSubscription(userID uint64, network string, eventName types.EventName, eventFilter string) (bool, error) {
+	name := string(eventName)
+	if network != "" && !types.IsUserIndexed(eventName) {
+		name = strings.ToLower(network) + ":" + string(eventName)
+	}
+
+	exists := false
+	err := FrontendWriterDB.Get(&exists, "SELECT EXISTS (SELECT 1 FROM users_subscriptions WHERE user_id = $1 and event_name = $2 and event_filter = $3)", userID, name, eventFilter)
+	return exists, err
+}
+
 func DeleteAllSubscription(userID uint64, network string, eventName types.EventName) error {
 	name := string(eventName)
 	if network != "" && !types.IsUserIndexed(eventName) {
@@ -534,19 +553,12 @@ func getMachineStatsGap(resultCount uint64) int {
 	return 1
 }
 
-func GetHistoricalPrice(chainId uint64, currency string, day uint64) (float64, error) {
-	if chainId != 1 {
-		// Don't show a historical price for testnets
-		return 0.0, nil
-	}
+// GetHistoricalPrice returns the price of the native currency at the start of a day, ETH is treated as USD
+func GetHistoricalPrice(currency string, day uint64) (float64, error) {
 	if currency == "ETH" {
 		currency = "USD"
 	}
-	currency = strings.ToLower(currency)
-
-	if currency != "eur" && currency != "usd" && currency != "rub" && currency != "cny" && currency != "cad" && currency != "jpy" && currency != "gbp" && currency != "aud" {
-		return 0.0, fmt.Errorf("currency %v not supported", currency)
-	}
+	currency = strings.ToUpper(currency)
 
 	// Convert day to ts
 	genesisTime := time.Unix(int64(utils.Config.Chain.GenesisTimestamp), 0)
@@ -554,7 +566,7 @@ func GetHistoricalPrice(chainId uint64, currency string, day uint64) (float64, e
 	ts := dayStartGenesisTime.Add(utils.Day * time.Duration(day))
 
 	var value float64
-	err := ReaderDb.Get(&value, fmt.Sprintf("SELECT %s FROM price WHERE ts = $1", currency), ts)
+	err := ReaderDb.Get(&value, "SELECT price FROM historic_prices WHERE ts = $1 AND currency = $2", ts, currency)
 	if err != nil {
 		return 0.0, err
 	}
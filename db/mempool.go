@@ -0,0 +1,122 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SaveMempoolTransactions will insert newly seen mempool transactions and refresh the last seen timestamp and fees of known ones
+func SaveMempoolTransactions(txs []*types.MempoolTxLifecycle) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Preparex(`
+		INSERT INTO mempool_tx_lifecycle (hash, sender, nonce, gas_price, gas_fee_cap, gas_tip_cap, status, first_seen, first_seen_block, last_seen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (hash) DO UPDATE SET
+			gas_price = excluded.gas_price,
+			gas_fee_cap = excluded.gas_fee_cap,
+			gas_tip_cap = excluded.gas_tip_cap,
+			last_seen = excluded.last_seen`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, t := range txs {
+		_, err = stmt.Exec(t.Hash, t.Sender, t.Nonce, t.GasPrice, t.GasFeeCap, t.GasTipCap, types.MempoolTxStatusPending, t.FirstSeen, t.FirstSeenBlock, t.LastSeen)
+		if err != nil {
+			return fmt.Errorf("error saving mempool tx %x: %w", t.Hash, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// MarkMempoolTransactionIncluded will mark a tracked mempool transaction as included in the given block
+func MarkMempoolTransactionIncluded(hash []byte, blockNumber uint64, blockTime time.Time) error {
+	_, err := WriterDb.Exec(`
+		UPDATE mempool_tx_lifecycle SET status = $2, included_block = $3, included_time = $4
+		WHERE hash = $1`, hash, types.MempoolTxStatusIncluded, blockNumber, blockTime)
+	return err
+}
+
+// MarkMempoolTransactionReplaced will mark a tracked mempool transaction as replaced by a transaction with the same sender and nonce
+func MarkMempoolTransactionReplaced(hash []byte, replacedBy []byte, ts time.Time) error {
+	_, err := WriterDb.Exec(`
+		UPDATE mempool_tx_lifecycle SET status = $2, replaced_by = $3, dropped_time = $4
+		WHERE hash = $1`, hash, types.MempoolTxStatusReplaced, replacedBy, ts)
+	return err
+}
+
+// MarkMempoolTransactionDropped will mark a tracked mempool transaction as dropped from the mempool without being included
+func MarkMempoolTransactionDropped(hash []byte, ts time.Time) error {
+	_, err := WriterDb.Exec(`
+		UPDATE mempool_tx_lifecycle SET status = $2, dropped_time = $3
+		WHERE hash = $1`, hash, types.MempoolTxStatusDropped, ts)
+	return err
+}
+
+// GetPendingMempoolTransactions returns all tracked transactions that are still considered pending
+func GetPendingMempoolTransactions() ([]*types.MempoolTxLifecycle, error) {
+	txs := []*types.MempoolTxLifecycle{}
+	err := WriterDb.Select(&txs, `
+		SELECT hash, sender, nonce, gas_price, gas_fee_cap, gas_tip_cap, status, first_seen, first_seen_block, last_seen, replaced_by, included_block, included_time, dropped_time
+		FROM mempool_tx_lifecycle
+		WHERE status = $1`, types.MempoolTxStatusPending)
+	return txs, err
+}
+
+// GetMempoolTransactionLifecycle returns the tracked mempool lifecycle of a single transaction
+func GetMempoolTransactionLifecycle(hash []byte) (*types.MempoolTxLifecycle, error) {
+	t := &types.MempoolTxLifecycle{}
+	err := ReaderDb.Get(t, `
+		SELECT hash, sender, nonce, gas_price, gas_fee_cap, gas_tip_cap, status, first_seen, first_seen_block, last_seen, replaced_by, included_block, included_time, dropped_time
+		FROM mempool_tx_lifecycle
+		WHERE hash = $1`, hash)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetMempoolTransactionReplacements returns all tracked transactions of a sender using the given nonce ordered by the time they were first seen
+func GetMempoolTransactionReplacements(sender []byte, nonce uint64) ([]*types.MempoolTxLifecycle, error) {
+	txs := []*types.MempoolTxLifecycle{}
+	err := ReaderDb.Select(&txs, `
+		SELECT hash, sender, nonce, gas_price, gas_fee_cap, gas_tip_cap, status, first_seen, first_seen_block, last_seen, replaced_by, included_block, included_time, dropped_time
+		FROM mempool_tx_lifecycle
+		WHERE sender = $1 AND nonce = $2
+		ORDER BY first_seen ASC`, sender, nonce)
+	return txs, err
+}
+
+// GetMempoolTransactionsForSenders returns the tracked transactions of the given senders that are either pending or were last seen after the given time
+func GetMempoolTransactionsForSenders(senders [][]byte, since time.Time) ([]*types.MempoolTxLifecycle, error) {
+	txs := []*types.MempoolTxLifecycle{}
+	err := ReaderDb.Select(&txs, `
+		SELECT hash, sender, nonce, gas_price, gas_fee_cap, gas_tip_cap, status, first_seen, first_seen_block, last_seen, replaced_by, included_block, included_time, dropped_time
+		FROM mempool_tx_lifecycle
+		WHERE sender = ANY($1) AND (status = $2 OR last_seen >= $3)
+		ORDER BY nonce ASC, first_seen ASC`, pq.ByteaArray(senders), types.MempoolTxStatusPending, since)
+	return txs, err
+}
+
+// DeleteMempoolTransactionsBefore removes all tracked transactions that are no longer pending and were last seen before the given time
+func DeleteMempoolTransactionsBefore(ts time.Time) (int64, error) {
+	res, err := WriterDb.Exec(`DELETE FROM mempool_tx_lifecycle WHERE status != $1 AND last_seen < $2`, types.MempoolTxStatusPending, ts)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
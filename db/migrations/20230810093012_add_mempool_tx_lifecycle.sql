@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table mempool_tx_lifecycle';
+CREATE TABLE IF NOT EXISTS
+    mempool_tx_lifecycle (
+        hash bytea NOT NULL,
+        sender bytea NOT NULL,
+        nonce BIGINT NOT NULL,
+        gas_price NUMERIC NOT NULL DEFAULT 0,
+        gas_fee_cap NUMERIC NOT NULL DEFAULT 0,
+        gas_tip_cap NUMERIC NOT NULL DEFAULT 0,
+        status TEXT NOT NULL,
+        first_seen TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+        first_seen_block BIGINT NOT NULL,
+        last_seen TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+        replaced_by bytea,
+        included_block BIGINT,
+        included_time TIMESTAMP WITHOUT TIME ZONE,
+        dropped_time TIMESTAMP WITHOUT TIME ZONE,
+        PRIMARY KEY (hash)
+    );
+CREATE INDEX IF NOT EXISTS idx_mempool_tx_lifecycle_sender_nonce ON mempool_tx_lifecycle (sender, nonce);
+CREATE INDEX IF NOT EXISTS idx_mempool_tx_lifecycle_status_last_seen ON mempool_tx_lifecycle (status, last_seen);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table mempool_tx_lifecycle';
+DROP INDEX IF EXISTS idx_mempool_tx_lifecycle_sender_nonce;
+DROP INDEX IF EXISTS idx_mempool_tx_lifecycle_status_last_seen;
+DROP TABLE IF EXISTS mempool_tx_lifecycle CASCADE;
+-- +goose StatementEnd
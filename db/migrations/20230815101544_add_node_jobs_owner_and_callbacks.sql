@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add owner and callback columns to node_jobs';
+ALTER TABLE node_jobs ADD COLUMN IF NOT EXISTS owner_id INT;
+ALTER TABLE node_jobs ADD COLUMN IF NOT EXISTS callback_url TEXT;
+ALTER TABLE node_jobs ADD COLUMN IF NOT EXISTS callback_secret TEXT;
+CREATE INDEX IF NOT EXISTS idx_node_jobs_owner_id ON node_jobs (owner_id, created_time);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table node_jobs_callbacks';
+CREATE TABLE IF NOT EXISTS
+    node_jobs_callbacks (
+        id SERIAL,
+        node_job_id VARCHAR(40) NOT NULL,
+        status VARCHAR(40) NOT NULL,
+        created_time TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+        attempts INT NOT NULL DEFAULT 0,
+        last_attempt_time TIMESTAMP WITHOUT TIME ZONE,
+        delivered_time TIMESTAMP WITHOUT TIME ZONE,
+        PRIMARY KEY (id)
+    );
+CREATE INDEX IF NOT EXISTS idx_node_jobs_callbacks_pending ON node_jobs_callbacks (delivered_time, attempts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table node_jobs_callbacks';
+DROP INDEX IF EXISTS idx_node_jobs_callbacks_pending;
+DROP TABLE IF EXISTS node_jobs_callbacks CASCADE;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove owner and callback columns from node_jobs';
+DROP INDEX IF EXISTS idx_node_jobs_owner_id;
+ALTER TABLE node_jobs DROP COLUMN IF EXISTS callback_secret;
+ALTER TABLE node_jobs DROP COLUMN IF EXISTS callback_url;
+ALTER TABLE node_jobs DROP COLUMN IF EXISTS owner_id;
+-- +goose StatementEnd
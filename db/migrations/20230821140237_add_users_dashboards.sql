@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table users_dashboards';
+CREATE TABLE IF NOT EXISTS
+    users_dashboards (
+        id SERIAL,
+        user_id INT NOT NULL,
+        network VARCHAR(20) NOT NULL,
+        name VARCHAR(50) NOT NULL,
+        public_id VARCHAR(32),
+        created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (id),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS idx_users_dashboards_user_id ON users_dashboards (user_id, network);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_dashboards_public_id ON users_dashboards (public_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table users_dashboards_groups';
+CREATE TABLE IF NOT EXISTS
+    users_dashboards_groups (
+        id SERIAL,
+        dashboard_id INT NOT NULL,
+        name VARCHAR(50) NOT NULL,
+        PRIMARY KEY (id),
+        UNIQUE (dashboard_id, name),
+        FOREIGN KEY (dashboard_id) REFERENCES users_dashboards (id) ON DELETE CASCADE
+    );
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table users_dashboards_validators';
+CREATE TABLE IF NOT EXISTS
+    users_dashboards_validators (
+        dashboard_id INT NOT NULL,
+        group_id INT NOT NULL,
+        validatorindex INT NOT NULL,
+        PRIMARY KEY (dashboard_id, validatorindex),
+        FOREIGN KEY (dashboard_id) REFERENCES users_dashboards (id) ON DELETE CASCADE,
+        FOREIGN KEY (group_id) REFERENCES users_dashboards_groups (id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS idx_users_dashboards_validators_group_id ON users_dashboards_validators (group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table users_dashboards_validators';
+DROP TABLE IF EXISTS users_dashboards_validators;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table users_dashboards_groups';
+DROP TABLE IF EXISTS users_dashboards_groups;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table users_dashboards';
+DROP TABLE IF EXISTS users_dashboards;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table users_validator_sets';
+CREATE TABLE IF NOT EXISTS
+    users_validator_sets (
+        id SERIAL,
+        user_id INT NOT NULL,
+        network VARCHAR(20) NOT NULL,
+        name VARCHAR(50) NOT NULL,
+        rule_type VARCHAR(40) NOT NULL,
+        rule_value TEXT NOT NULL,
+        rule_node_address TEXT NOT NULL DEFAULT '',
+        max_validators INT NOT NULL,
+        event_names TEXT[] NOT NULL DEFAULT '{}',
+        event_threshold REAL NOT NULL DEFAULT 0,
+        last_evaluated_epoch INT,
+        created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (id),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS idx_users_validator_sets_user_id ON users_validator_sets (user_id, network);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table users_validator_sets_validators';
+CREATE TABLE IF NOT EXISTS
+    users_validator_sets_validators (
+        set_id INT NOT NULL,
+        validatorindex INT NOT NULL,
+        pubkey bytea NOT NULL,
+        added_epoch INT NOT NULL,
+        PRIMARY KEY (set_id, validatorindex),
+        FOREIGN KEY (set_id) REFERENCES users_validator_sets (id) ON DELETE CASCADE
+    );
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add validator_set_id column to users_dashboards_groups';
+ALTER TABLE users_dashboards_groups ADD COLUMN IF NOT EXISTS validator_set_id INT REFERENCES users_validator_sets (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove validator_set_id column from users_dashboards_groups';
+ALTER TABLE users_dashboards_groups DROP COLUMN IF EXISTS validator_set_id;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table users_validator_sets_validators';
+DROP TABLE IF EXISTS users_validator_sets_validators;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table users_validator_sets';
+DROP TABLE IF EXISTS users_validator_sets;
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table token_holders';
+CREATE TABLE IF NOT EXISTS
+    token_holders (
+        token bytea NOT NULL,
+        address bytea NOT NULL,
+        balance NUMERIC NOT NULL,
+        block_number BIGINT NOT NULL,
+        PRIMARY KEY (token, address)
+    );
+CREATE INDEX IF NOT EXISTS idx_token_holders_balance ON token_holders (token, balance DESC);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table token_holder_balances';
+CREATE TABLE IF NOT EXISTS
+    token_holder_balances (
+        token bytea NOT NULL,
+        address bytea NOT NULL,
+        block_number BIGINT NOT NULL,
+        balance NUMERIC NOT NULL,
+        PRIMARY KEY (token, address, block_number)
+    );
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table token_holder_counts';
+CREATE TABLE IF NOT EXISTS
+    token_holder_counts (
+        token bytea NOT NULL,
+        day DATE NOT NULL,
+        holders INT NOT NULL,
+        PRIMARY KEY (token, day)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table token_holder_counts';
+DROP TABLE IF EXISTS token_holder_counts;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table token_holder_balances';
+DROP TABLE IF EXISTS token_holder_balances;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table token_holders';
+DROP TABLE IF EXISTS token_holders;
+-- +goose StatementEnd
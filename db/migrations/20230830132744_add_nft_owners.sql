@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table nft_owners';
+CREATE TABLE IF NOT EXISTS
+    nft_owners (
+        token bytea NOT NULL,
+        token_id NUMERIC NOT NULL,
+        owner bytea NOT NULL,
+        standard TEXT NOT NULL,
+        amount NUMERIC NOT NULL,
+        block_number BIGINT NOT NULL,
+        PRIMARY KEY (token, token_id, owner)
+    );
+CREATE INDEX IF NOT EXISTS idx_nft_owners_owner ON nft_owners (owner);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table nft_owners';
+DROP TABLE IF EXISTS nft_owners;
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table organisations';
+CREATE TABLE IF NOT EXISTS
+    organisations (
+        id SERIAL,
+        name VARCHAR(50) NOT NULL,
+        owner_id INT NOT NULL,
+        created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (id),
+        FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table organisations_members';
+CREATE TABLE IF NOT EXISTS
+    organisations_members (
+        organisation_id INT NOT NULL,
+        user_id INT NOT NULL,
+        PRIMARY KEY (organisation_id, user_id),
+        FOREIGN KEY (organisation_id) REFERENCES organisations (id) ON DELETE CASCADE,
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS idx_organisations_members_user_id ON organisations_members (user_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table address_label_sets';
+CREATE TABLE IF NOT EXISTS
+    address_label_sets (
+        id SERIAL,
+        name VARCHAR(50) NOT NULL,
+        source VARCHAR(100) NOT NULL DEFAULT '',
+        user_id INT,
+        organisation_id INT,
+        created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (id),
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+        FOREIGN KEY (organisation_id) REFERENCES organisations (id) ON DELETE CASCADE,
+        CHECK (user_id IS NULL OR organisation_id IS NULL)
+    );
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table address_labels';
+CREATE TABLE IF NOT EXISTS
+    address_labels (
+        set_id INT NOT NULL,
+        address bytea NOT NULL,
+        name VARCHAR(100) NOT NULL,
+        category VARCHAR(20) NOT NULL DEFAULT '',
+        tags TEXT[] NOT NULL DEFAULT '{}',
+        confidence REAL NOT NULL DEFAULT 1,
+        PRIMARY KEY (set_id, address),
+        FOREIGN KEY (set_id) REFERENCES address_label_sets (id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS idx_address_labels_address ON address_labels (address);
+CREATE INDEX IF NOT EXISTS idx_address_labels_name ON address_labels (LOWER(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_address_labels_tags ON address_labels USING GIN (tags);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table address_labels';
+DROP TABLE IF EXISTS address_labels;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table address_label_sets';
+DROP TABLE IF EXISTS address_label_sets;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table organisations_members';
+DROP TABLE IF EXISTS organisations_members;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table organisations';
+DROP TABLE IF EXISTS organisations;
+-- +goose StatementEnd
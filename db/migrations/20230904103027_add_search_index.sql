@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table search_index';
+CREATE TABLE IF NOT EXISTS
+    search_index (
+        type VARCHAR(20) NOT NULL,
+        key TEXT NOT NULL,
+        name TEXT NOT NULL,
+        count INT NOT NULL DEFAULT 1,
+        PRIMARY KEY (type, key, name)
+    );
+CREATE INDEX IF NOT EXISTS idx_search_index_name ON search_index USING gin (LOWER(name) gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table search_index';
+DROP TABLE IF EXISTS search_index;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table historic_prices';
+CREATE TABLE IF NOT EXISTS
+    historic_prices (
+        ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+        currency VARCHAR(10) NOT NULL,
+        price NUMERIC(20, 10) NOT NULL,
+        PRIMARY KEY (ts, currency)
+    );
+CREATE INDEX IF NOT EXISTS idx_historic_prices_currency ON historic_prices (currency, ts);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - copy the prices of table price to historic_prices';
+INSERT INTO historic_prices (ts, currency, price)
+SELECT ts, currency, price
+FROM price
+CROSS JOIN LATERAL (VALUES ('EUR', eur), ('USD', usd), ('RUB', rub), ('CNY', cny), ('CAD', cad), ('JPY', jpy), ('GBP', gbp), ('AUD', aud)) AS t(currency, price)
+ON CONFLICT (ts, currency) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table historic_prices';
+DROP TABLE IF EXISTS historic_prices;
+-- +goose StatementEnd
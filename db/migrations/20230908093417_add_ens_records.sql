@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add ens owner, text records and contenthash';
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS owner bytea;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS url TEXT;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS twitter TEXT;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS github TEXT;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS description TEXT;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS avatar TEXT;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS avatar_url TEXT;
+ALTER TABLE ens ADD COLUMN IF NOT EXISTS contenthash TEXT;
+CREATE INDEX IF NOT EXISTS idx_ens_owner_valid_to ON ens (owner, valid_to);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove ens owner, text records and contenthash';
+DROP INDEX IF EXISTS idx_ens_owner_valid_to;
+ALTER TABLE ens DROP COLUMN IF EXISTS contenthash;
+ALTER TABLE ens DROP COLUMN IF EXISTS avatar_url;
+ALTER TABLE ens DROP COLUMN IF EXISTS avatar;
+ALTER TABLE ens DROP COLUMN IF EXISTS description;
+ALTER TABLE ens DROP COLUMN IF EXISTS github;
+ALTER TABLE ens DROP COLUMN IF EXISTS twitter;
+ALTER TABLE ens DROP COLUMN IF EXISTS url;
+ALTER TABLE ens DROP COLUMN IF EXISTS owner;
+-- +goose StatementEnd
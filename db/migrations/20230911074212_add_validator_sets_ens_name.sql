@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add ens name of the rule address of validator sets';
+ALTER TABLE users_validator_sets ADD COLUMN IF NOT EXISTS rule_ens_name TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove ens name of the rule address of validator sets';
+ALTER TABLE users_validator_sets DROP COLUMN IF EXISTS rule_ens_name;
+-- +goose StatementEnd
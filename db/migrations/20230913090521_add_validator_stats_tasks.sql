@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table validator_stats_tasks';
+CREATE TABLE IF NOT EXISTS
+    validator_stats_tasks (
+        day INT NOT NULL,
+        task VARCHAR(50) NOT NULL,
+        status VARCHAR(20) NOT NULL,
+        attempts INT NOT NULL DEFAULT 0,
+        duration_ms BIGINT NOT NULL DEFAULT 0,
+        error TEXT NOT NULL DEFAULT '',
+        started_ts TIMESTAMP WITHOUT TIME ZONE,
+        finished_ts TIMESTAMP WITHOUT TIME ZONE,
+        PRIMARY KEY (day, task)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table validator_stats_tasks';
+DROP TABLE IF EXISTS validator_stats_tasks;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table validator_stats_epoch';
+CREATE TABLE IF NOT EXISTS
+    validator_stats_epoch (
+        validatorindex INT NOT NULL,
+        epoch INT NOT NULL,
+        ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+        attestation_source_reward BIGINT NOT NULL DEFAULT 0,
+        attestation_source_penalty BIGINT NOT NULL DEFAULT 0,
+        attestation_target_reward BIGINT NOT NULL DEFAULT 0,
+        attestation_target_penalty BIGINT NOT NULL DEFAULT 0,
+        attestation_head_reward BIGINT NOT NULL DEFAULT 0,
+        finality_delay_penalty BIGINT NOT NULL DEFAULT 0,
+        proposer_slashing_inclusion_reward BIGINT NOT NULL DEFAULT 0,
+        proposer_attestation_inclusion_reward BIGINT NOT NULL DEFAULT 0,
+        proposer_sync_inclusion_reward BIGINT NOT NULL DEFAULT 0,
+        sync_committee_reward BIGINT NOT NULL DEFAULT 0,
+        sync_committee_penalty BIGINT NOT NULL DEFAULT 0,
+        slashing_reward BIGINT NOT NULL DEFAULT 0,
+        slashing_penalty BIGINT NOT NULL DEFAULT 0,
+        proposals_missed INT NOT NULL DEFAULT 0,
+        attestations_missed INT NOT NULL DEFAULT 0,
+        PRIMARY KEY (validatorindex, epoch)
+    );
+CREATE INDEX IF NOT EXISTS idx_validator_stats_epoch_ts ON validator_stats_epoch (ts);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'up SQL query - add table validator_stats_hourly';
+CREATE TABLE IF NOT EXISTS
+    validator_stats_hourly (
+        validatorindex INT NOT NULL,
+        ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+        epochs INT NOT NULL DEFAULT 0,
+        attestation_source_reward BIGINT NOT NULL DEFAULT 0,
+        attestation_source_penalty BIGINT NOT NULL DEFAULT 0,
+        attestation_target_reward BIGINT NOT NULL DEFAULT 0,
+        attestation_target_penalty BIGINT NOT NULL DEFAULT 0,
+        attestation_head_reward BIGINT NOT NULL DEFAULT 0,
+        finality_delay_penalty BIGINT NOT NULL DEFAULT 0,
+        proposer_slashing_inclusion_reward BIGINT NOT NULL DEFAULT 0,
+        proposer_attestation_inclusion_reward BIGINT NOT NULL DEFAULT 0,
+        proposer_sync_inclusion_reward BIGINT NOT NULL DEFAULT 0,
+        sync_committee_reward BIGINT NOT NULL DEFAULT 0,
+        sync_committee_penalty BIGINT NOT NULL DEFAULT 0,
+        slashing_reward BIGINT NOT NULL DEFAULT 0,
+        slashing_penalty BIGINT NOT NULL DEFAULT 0,
+        proposals_missed INT NOT NULL DEFAULT 0,
+        attestations_missed INT NOT NULL DEFAULT 0,
+        PRIMARY KEY (validatorindex, ts)
+    );
+CREATE INDEX IF NOT EXISTS idx_validator_stats_hourly_ts ON validator_stats_hourly (ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table validator_stats_hourly';
+DROP TABLE IF EXISTS validator_stats_hourly;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table validator_stats_epoch';
+DROP TABLE IF EXISTS validator_stats_epoch;
+-- +goose StatementEnd
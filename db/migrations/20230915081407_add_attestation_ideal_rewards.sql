@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query - add table attestation_ideal_rewards';
+CREATE TABLE IF NOT EXISTS
+    attestation_ideal_rewards (
+        epoch INT NOT NULL,
+        effective_balance BIGINT NOT NULL,
+        head BIGINT NOT NULL,
+        source BIGINT NOT NULL,
+        target BIGINT NOT NULL,
+        PRIMARY KEY (epoch, effective_balance)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query - remove table attestation_ideal_rewards';
+DROP TABLE IF EXISTS attestation_ideal_rewards;
+-- +goose StatementEnd
@@ -0,0 +1,366 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"eth2-exporter/types"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrNftNotFound = errors.New("nft not found")
+
+var nftZeroAddress = make([]byte, 20)
+
+// markNftUpdate marks a token id of an nft collection for the ownership update of ImportNftUpdates
+// Row:    <chainID>:NFT:V:<standard>:<tokenAddress>:<tokenId>
+// Family: f
+// Column: nil
+// Cell:   nil
+// Example scan: "1:NFT:V:ERC721:bc4ca0eda7647a8ab7c2061c2e118a18a936f13d:1234"
+func (bigtable *Bigtable) markNftUpdate(standard string, token []byte, tokenId *big.Int, mutations *types.BulkMutations) {
+	key := fmt.Sprintf("%s:NFT:V:%s:%x:%s", bigtable.chainId, standard, token, tokenId.String())
+	mut := gcp_bigtable.NewMutation()
+	mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+	mutations.Keys = append(mutations.Keys, key)
+	mutations.Muts = append(mutations.Muts, mut)
+}
+
+// GetNftStandard returns the standard of an nft collection based on its indexed transfers, ErrNftNotFound is returned if no transfer of the collection has been indexed
+func (bigtable *Bigtable) GetNftStandard(token []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	for _, standard := range []string{types.NftStandardERC721, types.NftStandardERC1155} {
+		found := false
+		prefix := fmt.Sprintf("%s:I:%s:%x:ALL:%s:", bigtable.chainId, standard, token, FILTER_TIME)
+		err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(prefix), func(row gcp_bigtable.Row) bool {
+			found = true
+			return false
+		}, gcp_bigtable.LimitRows(1))
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return standard, nil
+		}
+	}
+	return "", ErrNftNotFound
+}
+
+// GetNftTransfers returns the latest transfers of a token id of an nft collection, the latest transfer comes first. A limit of 0 returns all transfers
+func (bigtable *Bigtable) GetNftTransfers(standard string, token []byte, tokenId *big.Int, limit int64) ([]*types.NftTransfer, error) {
+	prefix := fmt.Sprintf("%s:I:%s:%x:ID:%s:", bigtable.chainId, standard, token, tokenId.String())
+	return bigtable.getNftTransfers(standard, prefix, limit)
+}
+
+// GetNftMint returns the first transfer of a token id of an nft collection if it was minted by it, nil is returned otherwise.
+// All index rows of the token id are scanned as the first transfer is the last row of the id index.
+func (bigtable *Bigtable) GetNftMint(standard string, token []byte, tokenId *big.Int) (*types.NftTransfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	prefix := fmt.Sprintf("%s:I:%s:%x:ID:%s:", bigtable.chainId, standard, token, tokenId.String())
+	firstKey := ""
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(prefix), func(row gcp_bigtable.Row) bool {
+		firstKey = strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:")
+		return true
+	}, gcp_bigtable.RowFilter(gcp_bigtable.StripValueFilter()))
+	if err != nil {
+		return nil, err
+	}
+	if firstKey == "" {
+		return nil, nil
+	}
+
+	row, err := bigtable.tableData.ReadRow(ctx, firstKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(row[DEFAULT_FAMILY]) == 0 {
+		return nil, nil
+	}
+	transfer, err := parseNftTransfer(standard, row[DEFAULT_FAMILY][0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nft transfer %v: %w", firstKey, err)
+	}
+	if !bytes.Equal(transfer.From, nftZeroAddress) {
+		return nil, nil
+	}
+	return transfer, nil
+}
+
+// GetNftCollectionTransfers returns the latest transfers of an nft collection, the latest transfer comes first
+func (bigtable *Bigtable) GetNftCollectionTransfers(standard string, token []byte, limit int64) ([]*types.NftTransfer, error) {
+	prefix := fmt.Sprintf("%s:I:%s:%x:ALL:%s:", bigtable.chainId, standard, token, FILTER_TIME)
+	return bigtable.getNftTransfers(standard, prefix, limit)
+}
+
+func (bigtable *Bigtable) getNftTransfers(standard string, prefix string, limit int64) ([]*types.NftTransfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	// a limit of 0 reads all transfers
+	opts := []gcp_bigtable.ReadOption{}
+	if limit > 0 {
+		opts = append(opts, gcp_bigtable.LimitRows(limit))
+	}
+	keys := []string{}
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(prefix), func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		return true
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []*types.NftTransfer{}, nil
+	}
+
+	keysMap := make(map[string]*types.NftTransfer, len(keys))
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		transfer, err := parseNftTransfer(standard, row[DEFAULT_FAMILY][0].Value)
+		if err != nil {
+			logger.WithError(err).Errorf("error parsing nft transfer %v", row.Key())
+			return true
+		}
+		keysMap[row.Key()] = transfer
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make([]*types.NftTransfer, 0, len(keys))
+	for _, key := range keys {
+		if transfer := keysMap[key]; transfer != nil {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+func parseNftTransfer(standard string, value []byte) (*types.NftTransfer, error) {
+	switch standard {
+	case types.NftStandardERC721:
+		indexed := &types.Eth1ERC721Indexed{}
+		err := proto.Unmarshal(value, indexed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.NftTransfer{
+			TokenId:     indexed.TokenId,
+			TxHash:      indexed.ParentHash,
+			BlockNumber: indexed.BlockNumber,
+			Time:        indexed.Time.AsTime(),
+			From:        indexed.From,
+			To:          indexed.To,
+			Value:       []byte{0x1},
+		}, nil
+	case types.NftStandardERC1155:
+		indexed := &types.ETh1ERC1155Indexed{}
+		err := proto.Unmarshal(value, indexed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.NftTransfer{
+			TokenId:     indexed.TokenId,
+			TxHash:      indexed.ParentHash,
+			BlockNumber: indexed.BlockNumber,
+			Time:        indexed.Time.AsTime(),
+			From:        indexed.From,
+			To:          indexed.To,
+			Value:       indexed.Value,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown nft standard %v", standard)
+}
+
+// nftOwnersFromTransfers replays the transfers of a token id, ordered by the latest transfer first, and returns the current owners of the token id
+func nftOwnersFromTransfers(standard string, transfers []*types.NftTransfer) []*types.NftOwner {
+	owners := []*types.NftOwner{}
+	if len(transfers) == 0 {
+		return owners
+	}
+
+	if standard == types.NftStandardERC721 {
+		latest := transfers[0]
+		if !bytes.Equal(latest.To, nftZeroAddress) {
+			owners = append(owners, &types.NftOwner{
+				Owner:       latest.To,
+				Amount:      decimal.NewFromInt(1),
+				BlockNumber: latest.BlockNumber,
+			})
+		}
+		return owners
+	}
+
+	balances := make(map[string]*big.Int)
+	lastChange := make(map[string]uint64)
+	for i := len(transfers) - 1; i >= 0; i-- {
+		transfer := transfers[i]
+		value := new(big.Int).SetBytes(transfer.Value)
+		if !bytes.Equal(transfer.From, nftZeroAddress) {
+			if balances[string(transfer.From)] == nil {
+				balances[string(transfer.From)] = new(big.Int)
+			}
+			balances[string(transfer.From)].Sub(balances[string(transfer.From)], value)
+			lastChange[string(transfer.From)] = transfer.BlockNumber
+		}
+		if !bytes.Equal(transfer.To, nftZeroAddress) {
+			if balances[string(transfer.To)] == nil {
+				balances[string(transfer.To)] = new(big.Int)
+			}
+			balances[string(transfer.To)].Add(balances[string(transfer.To)], value)
+			lastChange[string(transfer.To)] = transfer.BlockNumber
+		}
+	}
+	for owner, balance := range balances {
+		if balance.Sign() <= 0 {
+			continue
+		}
+		owners = append(owners, &types.NftOwner{
+			Owner:       []byte(owner),
+			Amount:      decimal.NewFromBigInt(balance, 0),
+			BlockNumber: lastChange[owner],
+		})
+	}
+	return owners
+}
+
+// ImportNftUpdates recomputes the owners of all token ids that were marked by markNftUpdate from their indexed transfers and saves them to the nft_owners table
+func (bigtable *Bigtable) ImportNftUpdates(batchSize int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	keys := []string{}
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(fmt.Sprintf("%s:NFT:V:", bigtable.chainId)), func(row gcp_bigtable.Row) bool {
+		keys = append(keys, row.Key())
+		return true
+	}, gcp_bigtable.LimitRows(batchSize))
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		logger.Info("no nft ownership updates to import")
+		return nil
+	}
+	logger.Infof("importing %v nft ownership updates", len(keys))
+
+	mutsDelete := &types.BulkMutations{
+		Keys: make([]string, 0, len(keys)),
+		Muts: make([]*gcp_bigtable.Mutation, 0, len(keys)),
+	}
+	for _, key := range keys {
+		// <chainID>:NFT:V:<standard>:<tokenAddress>:<tokenId>
+		split := strings.Split(key, ":")
+		if len(split) != 6 {
+			return fmt.Errorf("invalid nft update key %v", key)
+		}
+		standard := split[3]
+		token, err := hex.DecodeString(split[4])
+		if err != nil {
+			return fmt.Errorf("invalid token address of nft update key %v: %w", key, err)
+		}
+		tokenId, ok := new(big.Int).SetString(split[5], 10)
+		if !ok {
+			return fmt.Errorf("invalid token id of nft update key %v", key)
+		}
+
+		// an erc721 token id only has a single owner so only its latest transfer is required
+		limit := int64(1)
+		if standard == types.NftStandardERC1155 {
+			limit = 0
+		}
+		transfers, err := bigtable.GetNftTransfers(standard, token, tokenId, limit)
+		if err != nil {
+			return err
+		}
+		err = SaveNftOwners(standard, token, tokenId, nftOwnersFromTransfers(standard, transfers))
+		if err != nil {
+			return err
+		}
+
+		mut := gcp_bigtable.NewMutation()
+		mut.DeleteRow()
+		mutsDelete.Keys = append(mutsDelete.Keys, key)
+		mutsDelete.Muts = append(mutsDelete.Muts, mut)
+	}
+	return bigtable.WriteBulk(mutsDelete, bigtable.tableData)
+}
+
+// SaveNftOwners replaces the owners of a token id of an nft collection
+func SaveNftOwners(standard string, token []byte, tokenId *big.Int, owners []*types.NftOwner) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM nft_owners WHERE token = $1 AND token_id = $2`, token, tokenId.String())
+	if err != nil {
+		return fmt.Errorf("error removing nft owners: %w", err)
+	}
+
+	if len(owners) > 0 {
+		addresses := make(pq.ByteaArray, 0, len(owners))
+		amounts := make(pq.StringArray, 0, len(owners))
+		blocks := make(pq.Int64Array, 0, len(owners))
+		for _, owner := range owners {
+			addresses = append(addresses, owner.Owner)
+			amounts = append(amounts, owner.Amount.String())
+			blocks = append(blocks, int64(owner.BlockNumber))
+		}
+		_, err = tx.Exec(`
+			INSERT INTO nft_owners (token, token_id, owner, standard, amount, block_number)
+			SELECT $1, $2, o.owner, $3, o.amount, o.block_number
+			FROM UNNEST($4::bytea[], $5::text[]::numeric[], $6::bigint[]) AS o(owner, amount, block_number)`,
+			token, tokenId.String(), standard, addresses, amounts, blocks)
+		if err != nil {
+			return fmt.Errorf("error saving nft owners: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetNftOwners returns the current owners of a token id of an nft collection ordered by their amount
+func GetNftOwners(token []byte, tokenId *big.Int) ([]*types.NftOwner, error) {
+	owners := []*types.NftOwner{}
+	err := ReaderDb.Select(&owners, `
+		SELECT owner, amount, block_number
+		FROM nft_owners
+		WHERE token = $1 AND token_id = $2
+		ORDER BY amount DESC, owner
+		LIMIT 100`, token, tokenId.String())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving nft owners: %w", err)
+	}
+	return owners, nil
+}
+
+// GetNftCollectionStats returns the supply, the number of token ids with an owner and the number of unique holders of an nft collection
+func GetNftCollectionStats(token []byte) (*types.NftCollectionStats, error) {
+	stats := &types.NftCollectionStats{}
+	err := ReaderDb.Get(stats, `
+		SELECT
+			COALESCE(SUM(amount), 0) AS supply,
+			COUNT(DISTINCT token_id) AS tokens,
+			COUNT(DISTINCT owner) AS holders
+		FROM nft_owners
+		WHERE token = $1`, token)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving nft collection stats: %w", err)
+	}
+	return stats, nil
+}
@@ -3,6 +3,8 @@ package db
 import (
 	"bytes"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
@@ -13,17 +15,20 @@ import (
 
 	"github.com/attestantio/go-eth2-client/spec/capella"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	ethutil "github.com/wealdtech/go-eth2-util"
 )
 
+const maxNodeJobCallbackAttempts = 10
+
 func GetNodeJob(id string) (*types.NodeJob, error) {
 	if len(id) > 40 {
 		return nil, fmt.Errorf("invalid id")
 	}
 	job := types.NodeJob{}
-	err := WriterDb.Get(&job, `select id, type, status, created_time, submitted_to_node_time, completed_time, data from node_jobs where id = $1`, id)
+	err := WriterDb.Get(&job, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where id = $1`, id)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +36,38 @@ func GetNodeJob(id string) (*types.NodeJob, error) {
 	return &job, err
 }
 
+// GetNodeJobsByIDs returns all jobs with the given ids, unknown ids are ignored
+func GetNodeJobsByIDs(ids []string) ([]*types.NodeJob, error) {
+	jobs := []*types.NodeJob{}
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where id = any($1) order by created_time`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		err = job.ParseData()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing data of job %v: %w", job.ID, err)
+		}
+	}
+	return jobs, nil
+}
+
+// GetNodeJobsByOwner returns the jobs of a user ordered by creation time, newest first
+func GetNodeJobsByOwner(ownerID uint64, status types.NodeJobStatus, limit, offset uint64) ([]*types.NodeJob, error) {
+	jobs := []*types.NodeJob{}
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where owner_id = $1 and ($2 = '' or status = $2) order by created_time desc limit $3 offset $4`, ownerID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		err = job.ParseData()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing data of job %v: %w", job.ID, err)
+		}
+	}
+	return jobs, nil
+}
+
 func GetNodeJobValidatorInfos(job *types.NodeJob) ([]types.NodeJobValidatorInfo, error) {
 	indicesArr := []uint64{}
 	if job.Type == types.BLSToExecutionChangesNodeJobType {
@@ -48,6 +85,13 @@ func GetNodeJobValidatorInfos(job *types.NodeJob) ([]types.NodeJobValidatorInfo,
 		}
 
 		indicesArr = append(indicesArr, uint64(jobData.Message.ValidatorIndex))
+	} else if job.Type == types.ScheduledExitsNodeJobType {
+		jobData, ok := job.GetScheduledExitNodeJobData()
+		if !ok {
+			return nil, fmt.Errorf("invalid scheduled exit job-data")
+		}
+
+		indicesArr = append(indicesArr, jobData.ValidatorIndex)
 	} else {
 		return []types.NodeJobValidatorInfo{}, nil
 	}
@@ -77,10 +121,24 @@ func GetNodeJobValidatorInfos(job *types.NodeJob) ([]types.NodeJobValidatorInfo,
 }
 
 func CreateNodeJob(data []byte) (*types.NodeJob, error) {
+	return CreateOwnedNodeJob(data, sql.NullInt64{}, "")
+}
+
+// CreateOwnedNodeJob creates a node job that belongs to the user with ownerID. If callbackURL is set every status transition of the job
+// will be POSTed to it, signed with a random secret that is generated for the job.
+func CreateOwnedNodeJob(data []byte, ownerID sql.NullInt64, callbackURL string) (*types.NodeJob, error) {
 	j, err := types.NewNodeJob(data)
 	if err != nil {
 		return nil, err
 	}
+	j.OwnerID = ownerID
+	if callbackURL != "" {
+		if !utils.IsValidUrl(callbackURL) || !strings.HasPrefix(callbackURL, "https://") {
+			return nil, types.CreateNodeJobUserError{Message: "invalid callback_url, must be a https url"}
+		}
+		j.CallbackURL = sql.NullString{String: callbackURL, Valid: true}
+		j.CallbackSecret = sql.NullString{String: utils.RandomString(32), Valid: true}
+	}
 	switch j.Type {
 	default:
 		return nil, fmt.Errorf("unknown job-type %v", j.Type)
@@ -88,6 +146,10 @@ func CreateNodeJob(data []byte) (*types.NodeJob, error) {
 		return CreateBLSToExecutionChangesNodeJob(j)
 	case types.VoluntaryExitsNodeJobType:
 		return CreateVoluntaryExitNodeJob(j)
+	case types.ScheduledExitsNodeJobType:
+		return CreateScheduledExitNodeJob(j)
+	case types.DepositDataValidationNodeJobType:
+		return CreateDepositDataValidationNodeJob(j)
 	}
 }
 
@@ -101,6 +163,10 @@ func UpdateNodeJobs() error {
 	if err != nil {
 		return fmt.Errorf("error updating voluntary-exit-job: %w", err)
 	}
+	err = UpdateScheduledExitNodeJobs()
+	if err != nil {
+		return fmt.Errorf("error updating scheduled-exit-job: %w", err)
+	}
 	return nil
 }
 
@@ -114,6 +180,49 @@ func SubmitNodeJobs() error {
 	if err != nil {
 		return err
 	}
+	err = SubmitScheduledExitNodeJobs()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// insertNodeJob stores a newly created job and queues a callback for its initial status
+func insertNodeJob(tx sqlx.Execer, nj *types.NodeJob) error {
+	_, err := tx.Exec(`insert into node_jobs (id, type, status, data, created_time, completed_time, owner_id, callback_url, callback_secret) values ($1, $2, $3, $4, now(), $5, $6, $7, $8)`, nj.ID, nj.Type, nj.Status, nj.RawData, nj.CompletedTime, nj.OwnerID, nj.CallbackURL, nj.CallbackSecret)
+	if err != nil {
+		return fmt.Errorf("error inserting into node_jobs: %w", err)
+	}
+	return queueNodeJobCallback(tx, nj)
+}
+
+// UpdateNodeJobStatus persists the status and timestamps of a job and queues a callback for the status transition
+func UpdateNodeJobStatus(job *types.NodeJob) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transactions: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`update node_jobs set status = $1, submitted_to_node_time = $2, completed_time = $3 where id = $4`, job.Status, job.SubmittedToNodeTime, job.CompletedTime, job.ID)
+	if err != nil {
+		return err
+	}
+	err = queueNodeJobCallback(tx, job)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func queueNodeJobCallback(tx sqlx.Execer, job *types.NodeJob) error {
+	if !job.CallbackURL.Valid {
+		return nil
+	}
+	_, err := tx.Exec(`insert into node_jobs_callbacks (node_job_id, status, created_time) values ($1, $2, now())`, job.ID, job.Status)
+	if err != nil {
+		return fmt.Errorf("error inserting into node_jobs_callbacks: %w", err)
+	}
 	return nil
 }
 
@@ -207,9 +316,9 @@ func CreateBLSToExecutionChangesNodeJob(nj *types.NodeJob) (*types.NodeJob, erro
 		}
 	}
 
-	_, err = tx.Exec(`insert into node_jobs (id, type, status, data, created_time) values ($1, $2, $3, $4, now())`, nj.ID, nj.Type, nj.Status, nj.RawData)
+	err = insertNodeJob(tx, nj)
 	if err != nil {
-		return nil, fmt.Errorf("error inserting into node_jobs: %w", err)
+		return nil, err
 	}
 
 	err = tx.Commit()
@@ -223,7 +332,7 @@ func CreateBLSToExecutionChangesNodeJob(nj *types.NodeJob) (*types.NodeJob, erro
 
 func UpdateBLSToExecutionChangesNodeJobs() error {
 	jobs := []*types.NodeJob{}
-	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data from node_jobs where type = $1 and status = $2`, types.BLSToExecutionChangesNodeJobType, types.SubmittedToNodeNodeJobStatus)
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where type = $1 and status = $2`, types.BLSToExecutionChangesNodeJobType, types.SubmittedToNodeNodeJobStatus)
 	if err != nil {
 		return err
 	}
@@ -270,9 +379,10 @@ func UpdateBLSToExecutionChangesNodeJob(job *types.NodeJob) error {
 		}
 		if len(toCheck) == 0 {
 			// all validatrors have been completed
+			job.Status = types.CompletedNodeJobStatus
 			job.CompletedTime.Time = time.Now()
 			job.CompletedTime.Valid = true
-			_, err = WriterDb.Exec(`update node_jobs set status = $1, completed_time = $2 where id = $3`, types.CompletedNodeJobStatus, job.CompletedTime.Time, job.ID)
+			err = UpdateNodeJobStatus(job)
 			if err != nil {
 				return err
 			}
@@ -285,7 +395,7 @@ func UpdateBLSToExecutionChangesNodeJob(job *types.NodeJob) error {
 func SubmitBLSToExecutionChangesNodeJobs() error {
 	maxSubmittedJobs := 1000
 	jobs := []*types.NodeJob{}
-	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data from node_jobs where type = $1 and status = $2 order by created_time limit $4-(select count(*) from node_jobs where type = $1 and status = $3)`, types.BLSToExecutionChangesNodeJobType, types.PendingNodeJobStatus, types.SubmittedToNodeNodeJobStatus, maxSubmittedJobs)
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where type = $1 and status = $2 order by created_time limit $4-(select count(*) from node_jobs where type = $1 and status = $3)`, types.BLSToExecutionChangesNodeJobType, types.PendingNodeJobStatus, types.SubmittedToNodeNodeJobStatus, maxSubmittedJobs)
 	if err != nil {
 		return err
 	}
@@ -321,7 +431,7 @@ func SubmitBLSToExecutionChangesNodeJob(job *types.NodeJob) error {
 	job.Status = jobStatus
 	job.SubmittedToNodeTime.Time = time.Now()
 	job.SubmittedToNodeTime.Valid = true
-	_, err = WriterDb.Exec(`update node_jobs set status = $1, submitted_to_node_time = $2 where id = $3`, job.Status, job.SubmittedToNodeTime.Time, job.ID)
+	err = UpdateNodeJobStatus(job)
 	if err != nil {
 		return err
 	}
@@ -364,7 +474,7 @@ func CreateVoluntaryExitNodeJob(nj *types.NodeJob) (*types.NodeJob, error) {
 		return nil, err
 	}
 
-	_, err = WriterDb.Exec(`insert into node_jobs (id, type, status, data, created_time) values ($1, $2, $3, $4, now())`, nj.ID, nj.Type, nj.Status, nj.RawData)
+	err = insertNodeJob(WriterDb, nj)
 	if err != nil {
 		return nil, err
 	}
@@ -374,7 +484,7 @@ func CreateVoluntaryExitNodeJob(nj *types.NodeJob) (*types.NodeJob, error) {
 
 func UpdateVoluntaryExitNodeJobs() error {
 	jobs := []*types.NodeJob{}
-	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data from node_jobs where type = $1 and status = $2`, types.VoluntaryExitsNodeJobType, types.SubmittedToNodeNodeJobStatus)
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where type = $1 and status = $2`, types.VoluntaryExitsNodeJobType, types.SubmittedToNodeNodeJobStatus)
 	if err != nil {
 		return err
 	}
@@ -410,7 +520,7 @@ func UpdateVoluntaryExitNodeJob(job *types.NodeJob) error {
 		job.Status = types.CompletedNodeJobStatus
 		job.CompletedTime.Time = time.Now()
 		job.CompletedTime.Valid = true
-		_, err = WriterDb.Exec(`update node_jobs set status = $1, completed_time = $2 where id = $3`, job.Status, job.CompletedTime.Time, job.ID)
+		err = UpdateNodeJobStatus(job)
 		if err != nil {
 			return err
 		}
@@ -421,7 +531,7 @@ func UpdateVoluntaryExitNodeJob(job *types.NodeJob) error {
 func SubmitVoluntaryExitNodeJobs() error {
 	maxSubmittedJobs := 100
 	jobs := []*types.NodeJob{}
-	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data from node_jobs where type = $1 and status = $2 order by created_time limit $4-(select count(*) from node_jobs where type = $1 and status = $3)`, types.VoluntaryExitsNodeJobType, types.PendingNodeJobStatus, types.SubmittedToNodeNodeJobStatus, maxSubmittedJobs)
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where type = $1 and status = $2 order by created_time limit $4-(select count(*) from node_jobs where type = $1 and status = $3)`, types.VoluntaryExitsNodeJobType, types.PendingNodeJobStatus, types.SubmittedToNodeNodeJobStatus, maxSubmittedJobs)
 	if err != nil {
 		return err
 	}
@@ -457,10 +567,381 @@ func SubmitVoluntaryExitNodeJob(job *types.NodeJob) error {
 	job.Status = jobStatus
 	job.SubmittedToNodeTime.Time = time.Now()
 	job.SubmittedToNodeTime.Valid = true
-	_, err = WriterDb.Exec(`update node_jobs set status = $1, submitted_to_node_time = $2 where id = $3`, job.Status, job.SubmittedToNodeTime.Time, job.ID)
+	err = UpdateNodeJobStatus(job)
 	if err != nil {
 		return err
 	}
 	logrus.WithFields(logrus.Fields{"id": job.ID, "type": job.Type}).Infof("submitted node_job")
 	return nil
 }
+
+func getExitEncryptionKey() ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(utils.Config.NodeJobsProcessor.ExitEncryptionKey, "0x"))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid exit encryption key, expected 32 hex encoded bytes")
+	}
+	return key, nil
+}
+
+func CreateScheduledExitNodeJob(nj *types.NodeJob) (*types.NodeJob, error) {
+	if len(nj.RawData) > 5e3 {
+		return nil, types.CreateNodeJobUserError{Message: "data-size exceeds maximum of 5KB"}
+	}
+	nj.ID = uuid.New().String()
+	nj.Status = types.PendingNodeJobStatus
+
+	njd, ok := nj.GetScheduledExitNodeJobData()
+	if !ok || njd.Exit == nil {
+		return nil, types.CreateNodeJobUserError{Message: "invalid data, exit is missing"}
+	}
+
+	vali := struct {
+		Pubkey []byte `db:"pubkey"`
+		Status string `db:"status"`
+	}{}
+	err := WriterDb.Get(&vali, `select pubkey, status from validators where validatorindex = $1`, njd.ValidatorIndex)
+	if err == sql.ErrNoRows {
+		return nil, types.CreateNodeJobUserError{Message: fmt.Sprintf("validator with index %v not found", njd.ValidatorIndex)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch vali.Status {
+	case "exited", "exiting_online", "exiting_offline":
+		return nil, types.CreateNodeJobUserError{Message: "validator has exited"}
+	case "slashed", "slashing_offline", "slashing_online":
+		return nil, types.CreateNodeJobUserError{Message: "validator has been slashed"}
+	default:
+	}
+
+	if njd.BroadcastEpoch != 0 && njd.BroadcastEpoch < uint64(njd.Exit.Message.Epoch) {
+		return nil, types.CreateNodeJobUserError{Message: fmt.Sprintf("broadcast_epoch must not be before the epoch of the exit (%v)", njd.Exit.Message.Epoch)}
+	}
+
+	forkVersion := utils.ForkVersionAtEpoch(uint64(njd.Exit.Message.Epoch))
+	err = utils.VerifyVoluntaryExitSignature(njd.Exit, forkVersion.CurrentVersion, vali.Pubkey)
+	if err != nil {
+		return nil, types.CreateNodeJobUserError{Message: fmt.Sprintf("can not verify signature: %v", err)}
+	}
+
+	// the pre-signed exit is only stored encrypted, otherwise anyone with read access to the db could exit the validator
+	key, err := getExitEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	exit, err := json.Marshal(njd.Exit)
+	if err != nil {
+		return nil, err
+	}
+	njd.EncryptedExit, err = utils.AesGcmEncrypt(key, exit)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting exit: %w", err)
+	}
+	njd.Exit = nil
+	err = nj.SanitizeRawData()
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertNodeJob(WriterDb, nj)
+	if err != nil {
+		return nil, err
+	}
+	logrus.WithFields(logrus.Fields{"id": nj.ID, "type": nj.Type, "broadcastEpoch": njd.BroadcastEpoch, "trigger": njd.Trigger}).Infof("created node_job")
+	return nj, nil
+}
+
+func UpdateScheduledExitNodeJobs() error {
+	jobs := []*types.NodeJob{}
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where type = $1 and status = $2`, types.ScheduledExitsNodeJobType, types.SubmittedToNodeNodeJobStatus)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		err := job.ParseData()
+		if err != nil {
+			return err
+		}
+		jobData, ok := job.GetScheduledExitNodeJobData()
+		if !ok {
+			return fmt.Errorf("invalid job-data")
+		}
+		dbResult := struct {
+			Status string `db:"status"`
+		}{}
+		err = WriterDb.Get(&dbResult, `select status from validators where validatorindex = $1`, jobData.ValidatorIndex)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("validator not found")
+		}
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(dbResult.Status, "exit") {
+			err = completeNodeJob(job)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func completeNodeJob(job *types.NodeJob) error {
+	job.Status = types.CompletedNodeJobStatus
+	job.CompletedTime.Time = time.Now()
+	job.CompletedTime.Valid = true
+	return UpdateNodeJobStatus(job)
+}
+
+// SubmitScheduledExitNodeJobs will broadcast the pre-signed exits of all pending scheduled exit jobs whose broadcast epoch has been reached or whose trigger fired
+func SubmitScheduledExitNodeJobs() error {
+	jobs := []*types.NodeJob{}
+	err := WriterDb.Select(&jobs, `select id, type, status, created_time, submitted_to_node_time, completed_time, data, owner_id, callback_url, callback_secret from node_jobs where type = $1 and status = $2 order by created_time`, types.ScheduledExitsNodeJobType, types.PendingNodeJobStatus)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	latestEpoch, err := GetLatestEpoch()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		err = job.ParseData()
+		if err != nil {
+			return err
+		}
+		jobData, ok := job.GetScheduledExitNodeJobData()
+		if !ok {
+			return fmt.Errorf("invalid job-data")
+		}
+
+		vali := struct {
+			Status                string        `db:"status"`
+			Slashed               bool          `db:"slashed"`
+			WithdrawalCredentials []byte        `db:"withdrawalcredentials"`
+			LastAttestationSlot   sql.NullInt64 `db:"lastattestationslot"`
+			ActivationEpoch       uint64        `db:"activationepoch"`
+		}{}
+		err = WriterDb.Get(&vali, `select status, slashed, withdrawalcredentials, lastattestationslot, activationepoch from validators where validatorindex = $1`, jobData.ValidatorIndex)
+		if err != nil {
+			return fmt.Errorf("error getting validator %v of scheduled exit job %v: %w", jobData.ValidatorIndex, job.ID, err)
+		}
+		if vali.Slashed || strings.HasPrefix(vali.Status, "exit") {
+			// the validator is already leaving the chain, nothing left to broadcast
+			err = completeNodeJob(job)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		broadcast := jobData.BroadcastEpoch != 0 && latestEpoch >= jobData.BroadcastEpoch
+		switch jobData.Trigger {
+		case types.ScheduledExitTriggerSlashed:
+			if !broadcast {
+				err = WriterDb.Get(&broadcast, `select exists(select 1 from validators where withdrawalcredentials = $1 and slashed)`, vali.WithdrawalCredentials)
+				if err != nil {
+					return err
+				}
+			}
+		case types.ScheduledExitTriggerOffline:
+			lastAttestationEpoch := vali.ActivationEpoch
+			if vali.LastAttestationSlot.Valid {
+				lastAttestationEpoch = uint64(vali.LastAttestationSlot.Int64) / utils.Config.Chain.Config.SlotsPerEpoch
+			}
+			if latestEpoch > lastAttestationEpoch && latestEpoch-lastAttestationEpoch >= jobData.OfflineEpochs {
+				broadcast = true
+			}
+		}
+		if !broadcast {
+			continue
+		}
+
+		err = SubmitScheduledExitNodeJob(job, jobData)
+		if err != nil {
+			return fmt.Errorf("error calling SubmitScheduledExitNodeJob for job %v: %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+func SubmitScheduledExitNodeJob(job *types.NodeJob, jobData *types.ScheduledExitNodeJobData) error {
+	key, err := getExitEncryptionKey()
+	if err != nil {
+		return err
+	}
+	exit, err := utils.AesGcmDecrypt(key, jobData.EncryptedExit)
+	if err != nil {
+		return fmt.Errorf("error decrypting exit: %w", err)
+	}
+
+	client := &http.Client{Timeout: time.Second * 10}
+	url := fmt.Sprintf("%s/eth/v1/beacon/pool/voluntary_exits", utils.Config.NodeJobsProcessor.ClEndpoint)
+	resp, err := client.Post(url, "application/json", bytes.NewReader(exit))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	jobStatus := types.SubmittedToNodeNodeJobStatus
+	if resp.StatusCode != 200 {
+		d, _ := ioutil.ReadAll(resp.Body)
+		if len(d) > 1000 {
+			d = d[:1000]
+		}
+		jobStatus = types.FailedNodeJobStatus
+		logrus.WithFields(logrus.Fields{"res": string(d), "status": resp.Status, "jobID": job.ID, "jobType": job.Type}).Warnf("failed submitting a job")
+	}
+	job.Status = jobStatus
+	job.SubmittedToNodeTime.Time = time.Now()
+	job.SubmittedToNodeTime.Valid = true
+	err = UpdateNodeJobStatus(job)
+	if err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{"id": job.ID, "type": job.Type, "trigger": jobData.Trigger}).Infof("submitted node_job")
+	return nil
+}
+
+// CreateDepositDataValidationNodeJob validates every entry of a deposit_data file and stores the results. The job is finished right away,
+// it is COMPLETED if all deposits are valid and FAILED otherwise.
+func CreateDepositDataValidationNodeJob(nj *types.NodeJob) (*types.NodeJob, error) {
+	if len(nj.RawData) > 1e6 {
+		return nil, types.CreateNodeJobUserError{Message: "data-size exceeds maximum of 1MB"}
+	}
+	nj.ID = uuid.New().String()
+
+	d, ok := nj.GetDepositDataValidationNodeJobData()
+	if !ok {
+		return nil, types.CreateNodeJobUserError{Message: "invalid data"}
+	}
+
+	pubkeys := make([][]byte, 0, len(d))
+	for _, dd := range d {
+		pubkey, err := hex.DecodeString(strings.TrimPrefix(dd.Pubkey, "0x"))
+		if err == nil {
+			pubkeys = append(pubkeys, pubkey)
+		}
+	}
+	existing := []struct {
+		Pubkey                []byte `db:"pubkey"`
+		WithdrawalCredentials []byte `db:"withdrawalcredentials"`
+	}{}
+	err := WriterDb.Select(&existing, `select pubkey, withdrawalcredentials from validators where pubkey = any($1)`, pq.ByteaArray(pubkeys))
+	if err != nil {
+		return nil, err
+	}
+	existingWithdrawalCredentials := make(map[string][]byte, len(existing))
+	for _, v := range existing {
+		existingWithdrawalCredentials[fmt.Sprintf("%x", v.Pubkey)] = v.WithdrawalCredentials
+	}
+
+	nj.Status = types.CompletedNodeJobStatus
+	seen := make(map[string]bool, len(d))
+	for _, dd := range d {
+		problems := utils.ValidateDepositData(dd)
+		pubkey := strings.ToLower(strings.TrimPrefix(dd.Pubkey, "0x"))
+		if seen[pubkey] {
+			problems = append(problems, "duplicate pubkey in deposit data")
+		}
+		seen[pubkey] = true
+		if wc, exists := existingWithdrawalCredentials[pubkey]; exists && !strings.EqualFold(strings.TrimPrefix(dd.WithdrawalCredentials, "0x"), fmt.Sprintf("%x", wc)) {
+			// the withdrawal credentials of the first deposit are used, all further deposits are credited to them
+			problems = append(problems, fmt.Sprintf("validator already exists with different withdrawal_credentials 0x%x", wc))
+		}
+		valid := len(problems) == 0
+		dd.Valid = &valid
+		dd.Errors = problems
+		if !valid {
+			nj.Status = types.FailedNodeJobStatus
+		}
+	}
+	err = nj.SanitizeRawData()
+	if err != nil {
+		return nil, err
+	}
+
+	nj.CompletedTime.Time = time.Now()
+	nj.CompletedTime.Valid = true
+	err = insertNodeJob(WriterDb, nj)
+	if err != nil {
+		return nil, err
+	}
+	logrus.WithFields(logrus.Fields{"id": nj.ID, "type": nj.Type, "status": nj.Status, "deposits": len(d)}).Infof("created node_job")
+	return nj, nil
+}
+
+// SendNodeJobCallbacks delivers all queued callbacks of node jobs. Failed deliveries are retried with a linear backoff up to maxNodeJobCallbackAttempts times.
+func SendNodeJobCallbacks() error {
+	callbacks := []struct {
+		types.NodeJobCallback
+		Type           types.NodeJobType `db:"type"`
+		CallbackURL    string            `db:"callback_url"`
+		CallbackSecret string            `db:"callback_secret"`
+	}{}
+	err := WriterDb.Select(&callbacks, `
+		select c.id, c.node_job_id, c.status, c.created_time, c.attempts, c.last_attempt_time, c.delivered_time, j.type, j.callback_url, j.callback_secret
+		from node_jobs_callbacks c
+		inner join node_jobs j on j.id = c.node_job_id
+		where c.delivered_time is null and c.attempts < $1 and (c.last_attempt_time is null or c.last_attempt_time < now() - c.attempts * interval '1 minute')
+		order by c.id
+		limit 1000`, maxNodeJobCallbackAttempts)
+	if err != nil {
+		return err
+	}
+
+	// callback urls are set by users, only deliver them over https to public addresses
+	client := utils.NewPublicHttpClient(time.Second * 10)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	for _, c := range callbacks {
+		payload, err := json.Marshal(types.NodeJobCallbackPayload{
+			ID:             c.NodeJobID,
+			Type:           c.Type,
+			Status:         c.Status,
+			TransitionTime: c.CreatedTime.Unix(),
+		})
+		if err != nil {
+			return err
+		}
+
+		delivered := false
+		if !strings.HasPrefix(c.CallbackURL, "https://") {
+			logrus.WithFields(logrus.Fields{"jobID": c.NodeJobID, "status": c.Status}).Warnf("skipping node_job callback to non https url")
+			_, err = WriterDb.Exec(`update node_jobs_callbacks set attempts = $2, last_attempt_time = now() where id = $1`, c.ID, maxNodeJobCallbackAttempts)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		req, err := http.NewRequest("POST", c.CallbackURL, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Signature-256", "sha256="+utils.HmacSha256Hex([]byte(c.CallbackSecret), payload))
+		resp, err := client.Do(req)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"jobID": c.NodeJobID, "status": c.Status, "attempts": c.Attempts + 1}).Warnf("failed sending node_job callback: %v", err)
+		} else {
+			resp.Body.Close()
+			delivered = resp.StatusCode >= 200 && resp.StatusCode < 300
+			if !delivered {
+				logrus.WithFields(logrus.Fields{"jobID": c.NodeJobID, "status": c.Status, "attempts": c.Attempts + 1, "res": resp.Status}).Warnf("failed sending node_job callback")
+			}
+		}
+
+		if delivered {
+			_, err = WriterDb.Exec(`update node_jobs_callbacks set attempts = attempts + 1, last_attempt_time = now(), delivered_time = now() where id = $1`, c.ID)
+		} else {
+			_, err = WriterDb.Exec(`update node_jobs_callbacks set attempts = attempts + 1, last_attempt_time = now() where id = $1`, c.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
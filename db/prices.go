@@ -0,0 +1,53 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PriceSeries holds the historic prices of the native currency in a currency ordered by time, see GetPriceSeries
+type PriceSeries struct {
+	Currency string
+	prices   []types.Price
+}
+
+// GetPriceSeries returns the hourly and daily historic prices of the native currency in a currency between from and to
+func GetPriceSeries(currency string, from, to time.Time) (*PriceSeries, error) {
+	series := &PriceSeries{Currency: strings.ToUpper(currency)}
+	err := ReaderDb.Select(&series.prices, `
+		SELECT ts, price
+		FROM historic_prices
+		WHERE currency = $1 AND ts >= $2 AND ts <= $3
+		ORDER BY ts`, series.Currency, from.UTC().Truncate(time.Hour*24), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// At returns the latest price at or before ts on the same utc day, which is the hourly price of ts or the daily price if the day has no hourly prices.
+// It returns 0 if there is no price of the day.
+func (s *PriceSeries) At(ts time.Time) float64 {
+	i := sort.Search(len(s.prices), func(i int) bool { return s.prices[i].TS.After(ts) }) - 1
+	if i < 0 || s.prices[i].TS.Before(ts.UTC().Truncate(time.Hour*24)) {
+		return 0
+	}
+	return s.prices[i].Price
+}
+
+// Average returns the average of the prices of the hours from from up to to, e.g. for rewards that accrue evenly over the period.
+// It returns At(from) if there is no price within the period.
+func (s *PriceSeries) Average(from, to time.Time) float64 {
+	sum := 0.0
+	count := 0
+	for i := sort.Search(len(s.prices), func(i int) bool { return !s.prices[i].TS.Before(from) }); i < len(s.prices) && s.prices[i].TS.Before(to); i++ {
+		sum += s.prices[i].Price
+		count++
+	}
+	if count == 0 {
+		return s.At(from)
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"testing"
+	"time"
+)
+
+func TestPriceSeries(t *testing.T) {
+	day := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	series := &PriceSeries{Currency: "USD", prices: []types.Price{
+		{TS: day, Price: 1000},
+		{TS: day.Add(time.Hour), Price: 1010},
+		{TS: day.Add(time.Hour * 2), Price: 1030},
+		// the next day only has a daily price
+		{TS: day.Add(time.Hour * 24), Price: 1100},
+	}}
+
+	atTests := []struct {
+		name     string
+		ts       time.Time
+		expected float64
+	}{
+		{"before the first price", day.Add(-time.Minute), 0},
+		{"exactly at an hourly price", day.Add(time.Hour), 1010},
+		{"within an hour", day.Add(time.Hour + time.Minute*30), 1010},
+		{"latest hour of the day", day.Add(time.Hour * 20), 1030},
+		{"daily price of a day without hourly prices", day.Add(time.Hour * 30), 1100},
+		{"day without prices", day.Add(time.Hour * 48), 0},
+	}
+	for _, tt := range atTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if price := series.At(tt.ts); price != tt.expected {
+				t.Errorf("expected price %v at %v, got %v", tt.expected, tt.ts, price)
+			}
+		})
+	}
+
+	averageTests := []struct {
+		name     string
+		from, to time.Time
+		expected float64
+	}{
+		{"all hours of a day", day, day.Add(time.Hour * 24), (1000 + 1010 + 1030) / 3.0},
+		{"end is exclusive", day, day.Add(time.Hour * 2), (1000 + 1010) / 2.0},
+		{"start is inclusive", day.Add(time.Hour), day.Add(time.Hour * 3), (1010 + 1030) / 2.0},
+		{"period without prices falls back to the price at the start", day.Add(time.Hour*2 + time.Minute), day.Add(time.Hour * 3), 1030},
+		{"day with only a daily price", day.Add(time.Hour * 24), day.Add(time.Hour * 48), 1100},
+		{"period without any price", day.Add(time.Hour * 48), day.Add(time.Hour * 72), 0},
+	}
+	for _, tt := range averageTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if price := series.Average(tt.from, tt.to); price != tt.expected {
+				t.Errorf("expected average price %v from %v to %v, got %v", tt.expected, tt.from, tt.to, price)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	itypes "github.com/gobitfly/eth-rewards/types"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// rollingValidatorIncomeColumns are the income columns shared by validator_stats_epoch and validator_stats_hourly
+const rollingValidatorIncomeColumns = `
+	attestation_source_reward,
+	attestation_source_penalty,
+	attestation_target_reward,
+	attestation_target_penalty,
+	attestation_head_reward,
+	finality_delay_penalty,
+	proposer_slashing_inclusion_reward,
+	proposer_attestation_inclusion_reward,
+	proposer_sync_inclusion_reward,
+	sync_committee_reward,
+	sync_committee_penalty,
+	slashing_reward,
+	slashing_penalty,
+	proposals_missed,
+	attestations_missed`
+
+// rollingValidatorIncomeSums sums up the income columns of rows of validator_stats_epoch or validator_stats_hourly
+const rollingValidatorIncomeSums = `
+	COALESCE(SUM(attestation_source_reward), 0) AS attestation_source_reward,
+	COALESCE(SUM(attestation_source_penalty), 0) AS attestation_source_penalty,
+	COALESCE(SUM(attestation_target_reward), 0) AS attestation_target_reward,
+	COALESCE(SUM(attestation_target_penalty), 0) AS attestation_target_penalty,
+	COALESCE(SUM(attestation_head_reward), 0) AS attestation_head_reward,
+	COALESCE(SUM(finality_delay_penalty), 0) AS finality_delay_penalty,
+	COALESCE(SUM(proposer_slashing_inclusion_reward), 0) AS proposer_slashing_inclusion_reward,
+	COALESCE(SUM(proposer_attestation_inclusion_reward), 0) AS proposer_attestation_inclusion_reward,
+	COALESCE(SUM(proposer_sync_inclusion_reward), 0) AS proposer_sync_inclusion_reward,
+	COALESCE(SUM(sync_committee_reward), 0) AS sync_committee_reward,
+	COALESCE(SUM(sync_committee_penalty), 0) AS sync_committee_penalty,
+	COALESCE(SUM(slashing_reward), 0) AS slashing_reward,
+	COALESCE(SUM(slashing_penalty), 0) AS slashing_penalty,
+	COALESCE(SUM(proposals_missed), 0) AS proposals_missed,
+	COALESCE(SUM(attestations_missed), 0) AS attestations_missed`
+
+// rollingValidatorIncomeUpdates returns an assignment of every income column, format references the name of the column as %[1]s
+func rollingValidatorIncomeUpdates(format string) string {
+	columns := strings.Split(rollingValidatorIncomeColumns, ",")
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		updates = append(updates, fmt.Sprintf(format, strings.TrimSpace(column)))
+	}
+	return strings.Join(updates, ",\n\t\t\t")
+}
+
+// SaveRollingValidatorIncome stores the income details of all validators for an epoch in the rolling validator stats and adds them to
+// the hourly aggregate of the hour the epoch started in, a re-exported epoch is subtracted from the aggregate before it is added again.
+// Epochs that are older than the hourly window are ignored, rows that fall out of the configured windows are pruned once the store has caught up with the chain.
+func SaveRollingValidatorIncome(epoch uint64, rewards map[uint64]*itypes.ValidatorEpochIncome) error {
+	cfg := utils.Config.RollingValidatorStats
+	ts := utils.EpochToTime(epoch).UTC()
+	if time.Since(ts) > cfg.HourlyWindow {
+		return nil
+	}
+
+	start := time.Now()
+	indices := make([]uint64, 0, len(rewards))
+	for index := range rewards {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the lock makes concurrent exports of epochs of the same hour update its aggregate one after another
+	hour := ts.Truncate(time.Hour)
+	_, err = tx.Exec(`SELECT pg_advisory_xact_lock(501, $1)`, hour.Unix()/3600)
+	if err != nil {
+		return fmt.Errorf("error acquiring lock for hourly rolling validator income of %v: %w", hour, err)
+	}
+
+	// the rows of a re-exported epoch are replaced, their income is subtracted from the hourly aggregate first
+	res, err := tx.Exec(`
+		WITH previous AS (
+			DELETE FROM validator_stats_epoch WHERE epoch = $1
+			RETURNING validatorindex, `+rollingValidatorIncomeColumns+`
+		)
+		UPDATE validator_stats_hourly h SET
+			epochs = h.epochs - 1,
+			`+rollingValidatorIncomeUpdates("%[1]s = h.%[1]s - previous.%[1]s")+`
+		FROM previous
+		WHERE h.validatorindex = previous.validatorindex AND h.ts = $2`, epoch, hour)
+	if err != nil {
+		return fmt.Errorf("error subtracting previous rolling validator income of epoch %v: %w", epoch, err)
+	}
+	subtracted, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error subtracting previous rolling validator income of epoch %v: %w", epoch, err)
+	}
+
+	// the epoch rows of hours that are older than the epoch window may already be pruned, an epoch without rows of such an hour
+	// can not be told apart from a pruned epoch that is already part of the aggregate and is only added if no epoch of the hour is pruned
+	if subtracted == 0 && hour.Before(time.Now().Add(-cfg.EpochWindow)) {
+		var aggregated, stored uint64
+		err = tx.Get(&aggregated, `SELECT COALESCE(MAX(epochs), 0) FROM validator_stats_hourly WHERE ts = $1`, hour)
+		if err != nil {
+			return fmt.Errorf("error getting epochs of hourly rolling validator income of %v: %w", hour, err)
+		}
+		err = tx.Get(&stored, `SELECT COUNT(DISTINCT epoch) FROM validator_stats_epoch WHERE ts >= $1 AND ts < $2`, hour, hour.Add(time.Hour))
+		if err != nil {
+			return fmt.Errorf("error getting stored epochs of hourly rolling validator income of %v: %w", hour, err)
+		}
+		if stored < aggregated {
+			logger.Warnf("not saving rolling validator income of epoch %v, %v of the %v epochs of hour %v are pruned", epoch, aggregated-stored, aggregated, hour)
+			return nil
+		}
+	}
+
+	batchSize := 20000
+	for b := 0; b < len(indices); b += batchSize {
+		end := b + batchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+
+		n := end - b
+		validators := make(pq.Int64Array, 0, n)
+		columns := make([]pq.Int64Array, 15)
+		for i := range columns {
+			columns[i] = make(pq.Int64Array, 0, n)
+		}
+		for _, index := range indices[b:end] {
+			income := rewards[index]
+			attestationsMissed := int64(0)
+			if income.AttestationSourcePenalty > 0 {
+				attestationsMissed = 1
+			}
+			validators = append(validators, int64(index))
+			for i, v := range []int64{
+				int64(income.AttestationSourceReward),
+				int64(income.AttestationSourcePenalty),
+				int64(income.AttestationTargetReward),
+				int64(income.AttestationTargetPenalty),
+				int64(income.AttestationHeadReward),
+				int64(income.FinalityDelayPenalty),
+				int64(income.ProposerSlashingInclusionReward),
+				int64(income.ProposerAttestationInclusionReward),
+				int64(income.ProposerSyncInclusionReward),
+				int64(income.SyncCommitteeReward),
+				int64(income.SyncCommitteePenalty),
+				int64(income.SlashingReward),
+				int64(income.SlashingPenalty),
+				int64(income.ProposalsMissed),
+				attestationsMissed,
+			} {
+				columns[i] = append(columns[i], v)
+			}
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO validator_stats_epoch (validatorindex, epoch, ts, `+rollingValidatorIncomeColumns+`)
+			SELECT u.validatorindex, $1, $2, u.attestation_source_reward, u.attestation_source_penalty, u.attestation_target_reward, u.attestation_target_penalty,
+				u.attestation_head_reward, u.finality_delay_penalty, u.proposer_slashing_inclusion_reward, u.proposer_attestation_inclusion_reward,
+				u.proposer_sync_inclusion_reward, u.sync_committee_reward, u.sync_committee_penalty, u.slashing_reward, u.slashing_penalty,
+				u.proposals_missed, u.attestations_missed
+			FROM UNNEST($3::int[], $4::bigint[], $5::bigint[], $6::bigint[], $7::bigint[], $8::bigint[], $9::bigint[], $10::bigint[], $11::bigint[],
+				$12::bigint[], $13::bigint[], $14::bigint[], $15::bigint[], $16::bigint[], $17::int[], $18::int[])
+				AS u(validatorindex, attestation_source_reward, attestation_source_penalty, attestation_target_reward, attestation_target_penalty,
+				attestation_head_reward, finality_delay_penalty, proposer_slashing_inclusion_reward, proposer_attestation_inclusion_reward,
+				proposer_sync_inclusion_reward, sync_committee_reward, sync_committee_penalty, slashing_reward, slashing_penalty,
+				proposals_missed, attestations_missed)`,
+			epoch, ts, validators, columns[0], columns[1], columns[2], columns[3], columns[4], columns[5], columns[6], columns[7],
+			columns[8], columns[9], columns[10], columns[11], columns[12], columns[13], columns[14])
+		if err != nil {
+			return fmt.Errorf("error saving rolling validator income of epoch %v: %w", epoch, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO validator_stats_hourly (validatorindex, ts, epochs, `+rollingValidatorIncomeColumns+`)
+		SELECT validatorindex, $2, 1, `+rollingValidatorIncomeColumns+`
+		FROM validator_stats_epoch
+		WHERE epoch = $1
+		ON CONFLICT (validatorindex, ts) DO UPDATE SET
+			epochs = validator_stats_hourly.epochs + 1,
+			`+rollingValidatorIncomeUpdates("%[1]s = validator_stats_hourly.%[1]s + excluded.%[1]s"), epoch, hour)
+	if err != nil {
+		return fmt.Errorf("error saving hourly rolling validator income of %v: %w", hour, err)
+	}
+
+	// only prune once the store is close to the head, a backfill would otherwise remove the epochs of hours that are still being aggregated
+	if time.Since(ts) < time.Hour {
+		now := time.Now().UTC()
+		_, err = tx.Exec(`DELETE FROM validator_stats_epoch WHERE ts < $1`, now.Add(-cfg.EpochWindow))
+		if err != nil {
+			return fmt.Errorf("error pruning rolling validator income of epochs: %w", err)
+		}
+		_, err = tx.Exec(`DELETE FROM validator_stats_hourly WHERE ts < $1`, now.Add(-cfg.HourlyWindow))
+		if err != nil {
+			return fmt.Errorf("error pruning hourly rolling validator income: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing rolling validator income of epoch %v: %w", epoch, err)
+	}
+
+	logger.WithFields(logrus.Fields{"epoch": epoch, "validators": len(indices), "duration": time.Since(start)}).Infof("saved rolling validator income")
+	return nil
+}
+
+// GetRollingValidatorIncomeHourly returns the hourly income details of the validators for the hours in [from, to) ordered by hour descending and validator index
+func GetRollingValidatorIncomeHourly(validators []uint64, from, to time.Time) ([]*types.RollingValidatorIncome, error) {
+	income := []*types.RollingValidatorIncome{}
+	err := ReaderDb.Select(&income, `
+		SELECT validatorindex, ts, epochs, `+rollingValidatorIncomeColumns+`
+		FROM validator_stats_hourly
+		WHERE validatorindex = ANY($1) AND ts >= $2 AND ts < $3
+		ORDER BY ts DESC, validatorindex`, pq.Array(validators), from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving hourly rolling validator income: %w", err)
+	}
+	return income, nil
+}
+
+// GetRollingValidatorIncomeSumsPerEpoch returns the summed income details of the validators per epoch since from ordered by epoch
+func GetRollingValidatorIncomeSumsPerEpoch(validators []uint64, from time.Time) ([]*types.RollingValidatorIncome, error) {
+	income := []*types.RollingValidatorIncome{}
+	err := ReaderDb.Select(&income, `
+		SELECT epoch, ts, 1 AS epochs, `+rollingValidatorIncomeSums+`
+		FROM validator_stats_epoch
+		WHERE validatorindex = ANY($1) AND ts >= $2
+		GROUP BY epoch, ts
+		ORDER BY epoch`, pq.Array(validators), from.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving rolling validator income per epoch: %w", err)
+	}
+	return income, nil
+}
+
+// GetRollingValidatorIncomeSumsPerHour returns the summed income details of the validators per hour since from ordered by hour
+func GetRollingValidatorIncomeSumsPerHour(validators []uint64, from time.Time) ([]*types.RollingValidatorIncome, error) {
+	income := []*types.RollingValidatorIncome{}
+	err := ReaderDb.Select(&income, `
+		SELECT ts, MAX(epochs) AS epochs, `+rollingValidatorIncomeSums+`
+		FROM validator_stats_hourly
+		WHERE validatorindex = ANY($1) AND ts >= $2
+		GROUP BY ts
+		ORDER BY ts`, pq.Array(validators), from.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving rolling validator income per hour: %w", err)
+	}
+	return income, nil
+}
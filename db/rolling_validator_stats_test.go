@@ -0,0 +1,20 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRollingValidatorIncomeUpdates(t *testing.T) {
+	updates := rollingValidatorIncomeUpdates("%[1]s = h.%[1]s - previous.%[1]s")
+	assignments := strings.Split(updates, ",")
+	if len(assignments) != 15 {
+		t.Fatalf("expected an assignment for each of the 15 income columns, got %v", len(assignments))
+	}
+	if first := strings.TrimSpace(assignments[0]); first != "attestation_source_reward = h.attestation_source_reward - previous.attestation_source_reward" {
+		t.Errorf("unexpected first assignment %q", first)
+	}
+	if last := strings.TrimSpace(assignments[14]); last != "attestations_missed = h.attestations_missed - previous.attestations_missed" {
+		t.Errorf("unexpected last assignment %q", last)
+	}
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// searchIndexQueries select the entries of the search index types that are sourced from the chain database
+var searchIndexQueries = map[types.SearchResultType]string{
+	types.ValidatorNameSearchResult: `
+		SELECT MIN(validators.validatorindex)::TEXT AS key, validator_names.name, COUNT(*) AS count
+		FROM validator_names
+		INNER JOIN validators ON validators.pubkey = validator_names.publickey
+		WHERE validator_names.name != ''
+		GROUP BY validator_names.name`,
+	types.GraffitiSearchResult: `
+		SELECT ENCODE(graffiti, 'hex') AS key, MAX(graffiti_text) AS name, SUM(count)::INT AS count
+		FROM graffiti_stats
+		WHERE graffiti_text != ''
+		GROUP BY graffiti`,
+	types.EnsSearchResult: `
+		SELECT DISTINCT ON (ens_name) ENCODE(address, 'hex') AS key, ens_name AS name, 1 AS count
+		FROM ens
+		WHERE valid_to >= NOW()
+		ORDER BY ens_name, is_primary_name DESC`,
+	types.PoolSearchResult: `
+		SELECT pool AS key, pool AS name, validators AS count
+		FROM historical_pool_performance
+		WHERE day = (SELECT MAX(day) FROM historical_pool_performance)`,
+}
+
+// RebuildSearchIndex replaces the search index entries of a type that is sourced from the chain database
+func RebuildSearchIndex(resultType types.SearchResultType) error {
+	query, ok := searchIndexQueries[resultType]
+	if !ok {
+		return fmt.Errorf("search index type %v is not sourced from the database", resultType)
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM search_index WHERE type = $1`, resultType)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO search_index (type, key, name, count)
+		SELECT $1, key, name, count FROM (`+query+`) AS entries
+		ON CONFLICT (type, key, name) DO NOTHING`, resultType)
+	if err != nil {
+		return fmt.Errorf("error rebuilding search index %v: %w", resultType, err)
+	}
+	return tx.Commit()
+}
+
+// ReplaceSearchIndex replaces the search index entries of a type with the given entries
+func ReplaceSearchIndex(resultType types.SearchResultType, entries []*types.SearchIndexEntry) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM search_index WHERE type = $1`, resultType)
+	if err != nil {
+		return err
+	}
+
+	batchSize := 5000
+	for b := 0; b < len(entries); b += batchSize {
+		end := b + batchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		keys := make(pq.StringArray, 0, end-b)
+		names := make(pq.StringArray, 0, end-b)
+		counts := make(pq.Int64Array, 0, end-b)
+		for _, entry := range entries[b:end] {
+			keys = append(keys, entry.Key)
+			names = append(names, entry.Name)
+			counts = append(counts, int64(entry.Count))
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO search_index (type, key, name, count)
+			SELECT $1, key, name, count
+			FROM UNNEST($2::text[], $3::text[], $4::int[]) AS t(key, name, count)
+			ON CONFLICT (type, key, name) DO UPDATE SET count = excluded.count`,
+			resultType, keys, names, counts)
+		if err != nil {
+			return fmt.Errorf("error replacing search index %v: %w", resultType, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCommunityAddressLabelSearchEntries returns the community address labels as search index entries, an address is only returned once per name
+func GetCommunityAddressLabelSearchEntries() ([]*types.SearchIndexEntry, error) {
+	entries := []*types.SearchIndexEntry{}
+	err := FrontendReaderDB.Select(&entries, `
+		SELECT ENCODE(address_labels.address, 'hex') AS key, address_labels.name, 1 AS count
+		FROM address_labels
+		INNER JOIN address_label_sets ON address_label_sets.id = address_labels.set_id
+		WHERE address_label_sets.user_id IS NULL AND address_label_sets.organisation_id IS NULL
+		GROUP BY address_labels.address, address_labels.name`)
+	return entries, err
+}
+
+// GetTokenSearchEntries returns a search index entry for the name and for the symbol of every token that has holders, the count is the number of holders
+func GetTokenSearchEntries() ([]*types.SearchIndexEntry, error) {
+	tokens := []struct {
+		Token   []byte `db:"token"`
+		Holders uint64 `db:"holders"`
+	}{}
+	err := ReaderDb.Select(&tokens, `SELECT token, COUNT(*) AS holders FROM token_holders GROUP BY token`)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*types.SearchIndexEntry, 0, len(tokens)*2)
+	for _, token := range tokens {
+		metadata, err := BigtableClient.GetERC20MetadataForAddress(token.Token)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving metadata of token %x: %w", token.Token, err)
+		}
+		seen := map[string]bool{}
+		for _, name := range []string{metadata.Name, metadata.Symbol} {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			entries = append(entries, &types.SearchIndexEntry{
+				Type:  types.TokenSearchResult,
+				Key:   fmt.Sprintf("%x", token.Token),
+				Name:  name,
+				Count: token.Holders,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// Search returns the search index entries that match the query ranked by relevance.
+// Exact matches rank before prefix matches which rank before similar names, entries with a higher count rank higher within each group.
+func Search(query string, resultTypes []types.SearchResultType, limit uint64) ([]*types.SearchIndexEntry, error) {
+	entries := []*types.SearchIndexEntry{}
+	sqlQuery, args := searchIndexQuery(query, resultTypes, limit)
+	if sqlQuery == "" {
+		return entries, nil
+	}
+	err := ReaderDb.Select(&entries, sqlQuery, args...)
+	return entries, err
+}
+
+// searchIndexQuery builds the ranked search index query and its arguments, it returns an empty query if there is nothing to search for
+func searchIndexQuery(query string, resultTypes []types.SearchResultType, limit uint64) (string, []interface{}) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return "", nil
+	}
+
+	filter := make(pq.StringArray, 0, len(resultTypes))
+	for _, t := range resultTypes {
+		filter = append(filter, string(t))
+	}
+
+	return `
+		SELECT type, key, name, count, score FROM (
+			SELECT type, key, name, count,
+				((CASE WHEN LOWER(name) = $1 THEN 2 WHEN LOWER(name) LIKE $2 || '%' THEN 1 ELSE 0 END) + word_similarity($1, LOWER(name))) * (1 + LN(1 + count) / 10) AS score
+			FROM search_index
+			WHERE (LOWER(name) LIKE $2 || '%' OR $1 <% LOWER(name)) AND (CARDINALITY($3::text[]) = 0 OR type = ANY($3))
+		) AS matches
+		ORDER BY score DESC, name, type
+		LIMIT $4`, []interface{}{query, strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query), filter, limit}
+}
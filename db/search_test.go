@@ -0,0 +1,41 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestSearchIndexQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		resultTypes []types.SearchResultType
+		limit       uint64
+		args        []interface{}
+	}{
+		{"empty query", "  ", nil, 10, nil},
+		{"query is trimmed and lower cased", " Lido ", nil, 10, []interface{}{"lido", "lido", pq.StringArray{}, uint64(10)}},
+		{"like wildcards are escaped", `50%_\x`, nil, 5, []interface{}{`50%_\x`, `50\%\_\\x`, pq.StringArray{}, uint64(5)}},
+		{"result types are filtered", "vitalik", []types.SearchResultType{types.EnsSearchResult, types.AddressLabelSearchResult}, 3, []interface{}{"vitalik", "vitalik", pq.StringArray{string(types.EnsSearchResult), string(types.AddressLabelSearchResult)}, uint64(3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := searchIndexQuery(tt.query, tt.resultTypes, tt.limit)
+			if tt.args == nil {
+				if query != "" || args != nil {
+					t.Errorf("expected no query, got %q with %v", query, args)
+				}
+				return
+			}
+			if query == "" {
+				t.Fatalf("expected a query")
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("expected arguments %#v, got %#v", tt.args, args)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"eth2-exporter/cache"
 	"eth2-exporter/metrics"
@@ -21,7 +22,10 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func WriteValidatorStatisticsForDay(day uint64, concurrencyTotal uint64, concurrencyCl uint64) error {
+// WriteValidatorStatisticsForDay runs the tasks of the validator statistics export of a day that have not been completed yet in dependency order.
+// Failing tasks are retried up to retries times, after that they are marked as failed and not run again for validatorStatisticsTaskBackoff. Tasks that fail or whose
+// dependencies of the same or the previous day have not been exported are skipped, the other tasks of the day are still run.
+func WriteValidatorStatisticsForDay(day uint64, concurrencyTotal uint64, concurrencyCl uint64, retries uint64) error {
 	exportStart := time.Now()
 	defer func() {
 		metrics.TaskDuration.WithLabelValues("db_update_validator_stats").Observe(time.Since(exportStart).Seconds())
@@ -38,90 +42,72 @@ func WriteValidatorStatisticsForDay(day uint64, concurrencyTotal uint64, concurr
 	logger.Infof("getting exported state for day %v", day)
 	start := time.Now()
 
-	type Exported struct {
-		Status              bool `db:"status"`
-		FailedAttestations  bool `db:"failed_attestations_exported"`
-		SyncDuties          bool `db:"sync_duties_exported"`
-		WithdrawalsDeposits bool `db:"withdrawals_deposits_exported"`
-		Balance             bool `db:"balance_exported"`
-		ClRewards           bool `db:"cl_rewards_exported"`
-		ElRewards           bool `db:"el_rewards_exported"`
-		TotalPerformance    bool `db:"total_performance_exported"`
-		BlockStats          bool `db:"block_stats_exported"`
-	}
-	exported := Exported{}
-
-	err := ReaderDb.Get(&exported, `
-		SELECT 
-			status,
-			failed_attestations_exported,
-			sync_duties_exported,
-			withdrawals_deposits_exported,
-			balance_exported,
-			cl_rewards_exported,
-			el_rewards_exported,
-			total_performance_exported,
-			block_stats_exported
-		FROM validator_stats_status 
-		WHERE day = $1;
-		`, day)
-
-	if err != nil && err != sql.ErrNoRows {
-		return fmt.Errorf("error retrieving exported state: %v", err)
-	}
-	logger.Infof("getting exported state took %v", time.Since(start))
-
-	if exported.FailedAttestations && exported.SyncDuties && exported.WithdrawalsDeposits && exported.Balance && exported.ClRewards && exported.ElRewards && exported.TotalPerformance && exported.BlockStats && exported.Status {
-		logger.Infof("Skipping day %v as it is already exported", day)
-		return nil
-	}
-
-	if exported.FailedAttestations {
-		logger.Infof("Skipping failed attestations")
-	} else if err := WriteValidatorFailedAttestationsStatisticsForDay(day); err != nil {
+	exported, status, err := getValidatorStatisticsExported(day)
+	if err != nil {
 		return err
 	}
+	logger.Infof("getting exported state took %v", time.Since(start))
 
-	if exported.SyncDuties {
-		logger.Infof("Skipping sync duties")
-	} else if err := WriteValidatorSyncDutiesForDay(day); err != nil {
+	failed, err := getFailedValidatorStatisticsTasks(day)
+	if err != nil {
 		return err
 	}
 
-	if exported.WithdrawalsDeposits {
-		logger.Infof("Skipping withdrawals / deposits")
-	} else if err := WriteValidatorDepositWithdrawals(day); err != nil {
-		return err
+	// the tasks of the first tracked day do not depend on the previous day
+	var previousExported map[string]bool
+	if day > 0 {
+		tracked := false
+		err = ReaderDb.Get(&tracked, `SELECT EXISTS (SELECT 1 FROM validator_stats_status WHERE day = $1)`, day-1)
+		if err != nil {
+			return fmt.Errorf("error retrieving exported state of the previous day: %w", err)
+		}
+		if tracked {
+			previousExported, _, err = getValidatorStatisticsExported(day - 1)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	if exported.BlockStats {
-		logger.Infof("Skipping block stats")
-	} else if err := WriteValidatorBlockStats(day); err != nil {
-		return err
+	allExported := true
+	for _, task := range validatorStatisticsTasks {
+		allExported = allExported && exported[task.Column]
 	}
-
-	if exported.Balance {
-		logger.Infof("Skipping balances")
-	} else if err := WriteValidatorBalances(day); err != nil {
-		return err
+	if allExported && status {
+		logger.Infof("Skipping day %v as it is already exported", day)
+		return nil
 	}
 
-	if exported.ClRewards {
-		logger.Infof("Skipping cl rewards")
-	} else if err := WriteValidatorClIcome(day, concurrencyCl); err != nil {
-		return err
+	_, err = WriterDb.Exec(`INSERT INTO validator_stats_status (day, status) VALUES ($1, false) ON CONFLICT (day) DO NOTHING`, day)
+	if err != nil {
+		return fmt.Errorf("error adding validator statistics status of day %v: %w", day, err)
 	}
 
-	if exported.ElRewards {
-		logger.Infof("Skipping el rewards")
-	} else if err := WriteValidatorElIcome(day); err != nil {
-		return err
+	skipped := []string{}
+	for _, task := range validatorStatisticsTasks {
+		if exported[task.Column] {
+			logger.Infof("Skipping %v", task.Column)
+			continue
+		}
+		if failed[task.Column] {
+			logger.Warnf("skipping validator statistics task %v of day %v, it has failed less than %v ago", task.Column, day, validatorStatisticsTaskBackoff)
+			skipped = append(skipped, task.Column)
+			continue
+		}
+		if dep := missingValidatorStatisticsDependency(task, exported, previousExported); dep != "" {
+			logger.Warnf("skipping validator statistics task %v of day %v, dependency %v has not been exported", task.Column, day, dep)
+			skipped = append(skipped, task.Column)
+			continue
+		}
+		if err := runValidatorStatisticsTask(task, day, retries, concurrencyTotal, concurrencyCl); err != nil {
+			logger.Error(err)
+			skipped = append(skipped, task.Column)
+			continue
+		}
+		exported[task.Column] = true
 	}
-
-	if exported.TotalPerformance {
-		logger.Infof("Skipping total performance")
-	} else if err := WriteValidatorTotalPerformance(day, concurrencyTotal); err != nil {
-		return err
+	if len(skipped) > 0 {
+		return fmt.Errorf("statistics export of day %v is incomplete, skipped tasks: %v", day, strings.Join(skipped, ", "))
 	}
 
 	if err := WriteValidatorStatsExported(day); err != nil {
@@ -1158,7 +1144,7 @@ func markColumnExported(day uint64, column string) error {
 }
 
 func GetValidatorIncomeHistoryChart(validatorIndices []uint64, currency string, lastFinalizedEpoch uint64) ([]*types.ChartDataPoint, error) {
-	incomeHistory, err := GetValidatorIncomeHistory(validatorIndices, 0, 0, lastFinalizedEpoch)
+	incomeHistory, err := GetValidatorIncomeHistory(validatorIndices, 0, 0, lastFinalizedEpoch, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -1170,12 +1156,19 @@ func GetValidatorIncomeHistoryChart(validatorIndices []uint64, currency string,
 			color = "#f7a35c"
 		}
 		balanceTs := utils.DayToTime(incomeHistory[i].Day)
-		clRewardsSeries[i] = &types.ChartDataPoint{X: float64(balanceTs.Unix() * 1000), Y: utils.ExchangeRateForCurrency(currency) * (float64(incomeHistory[i].ClRewards) / 1e9), Color: color}
+		// the income is valued at the historic prices of the day, the current price is only used for days without historic prices
+		value := incomeHistory[i].ClRewardsValue
+		if incomeHistory[i].Price == 0 {
+			value = utils.ExchangeRateForCurrency(currency) * (float64(incomeHistory[i].ClRewards) / 1e9)
+		}
+		clRewardsSeries[i] = &types.ChartDataPoint{X: float64(balanceTs.Unix() * 1000), Y: value, Color: color}
 	}
 	return clRewardsSeries, err
 }
 
-func GetValidatorIncomeHistory(validatorIndices []uint64, lowerBoundDay uint64, upperBoundDay uint64, lastFinalizedEpoch uint64) ([]types.ValidatorIncomeHistory, error) {
+// GetValidatorIncomeHistory returns the daily income of the validators. If currency is a fiat currency the income is also valued in it,
+// cl rewards at the average hourly price of the day and execution rewards and withdrawals at the hourly price of their block.
+func GetValidatorIncomeHistory(validatorIndices []uint64, lowerBoundDay uint64, upperBoundDay uint64, lastFinalizedEpoch uint64, currency string) ([]types.ValidatorIncomeHistory, error) {
 	if len(validatorIndices) == 0 {
 		return []types.ValidatorIncomeHistory{}, nil
 	}
@@ -1193,7 +1186,10 @@ func GetValidatorIncomeHistory(validatorIndices []uint64, lowerBoundDay uint64,
 	validatorIndicesPqArr := pq.Array(validatorIndices)
 
 	cacheDur := time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot*utils.Config.Chain.Config.SlotsPerEpoch+10) // updates every epoch, keep 10sec longer
-	cacheKey := fmt.Sprintf("%d:validatorIncomeHistory:%d:%d:%d:%s", utils.Config.Chain.Config.DepositChainID, lowerBoundDay, upperBoundDay, lastFinalizedEpoch, strings.Join(validatorIndicesStr, ","))
+	if currency == "ETH" {
+		currency = ""
+	}
+	cacheKey := fmt.Sprintf("%d:validatorIncomeHistory:%d:%d:%d:%s:%s", utils.Config.Chain.Config.DepositChainID, lowerBoundDay, upperBoundDay, lastFinalizedEpoch, currency, strings.Join(validatorIndicesStr, ","))
 	cached := []types.ValidatorIncomeHistory{}
 	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, cacheDur, &cached); err == nil {
 		return cached, nil
@@ -1276,6 +1272,13 @@ func GetValidatorIncomeHistory(validatorIndices []uint64, lowerBoundDay uint64,
 		})
 	}
 
+	if currency != "" && len(result) > 0 {
+		err = valueValidatorIncomeHistory(result, validatorIndices, currency, lastFinalizedEpoch)
+		if err != nil {
+			return nil, fmt.Errorf("error valuing validator income history in %v: %w", currency, err)
+		}
+	}
+
 	go func() {
 		err := cache.TieredCache.Set(cacheKey, &result, cacheDur)
 		if err != nil {
@@ -1286,6 +1289,165 @@ func GetValidatorIncomeHistory(validatorIndices []uint64, lowerBoundDay uint64,
 	return result, nil
 }
 
+// validatorIncomeDayValue is the valuation of a finalized day of the income history of a set of validators, see valueValidatorIncomeHistory
+type validatorIncomeDayValue struct {
+	Price            float64 `json:"price"`
+	ElRewards        int64   `json:"el_rewards"`
+	ElRewardsValue   float64 `json:"el_rewards_value"`
+	WithdrawalsValue float64 `json:"withdrawals_value"`
+}
+
+// valueValidatorIncomeHistory values the income of the validators at the historic prices in a currency, see GetValidatorIncomeHistory.
+// The valuation of finalized days with prices is cached per day so that only the days that are not cached yet are valued.
+func valueValidatorIncomeHistory(history []types.ValidatorIncomeHistory, validatorIndices []uint64, currency string, lastFinalizedEpoch uint64) error {
+	validatorIndicesStr := make([]string, len(validatorIndices))
+	for i, v := range validatorIndices {
+		validatorIndicesStr[i] = fmt.Sprintf("%d", v)
+	}
+	cacheKey := fmt.Sprintf("%d:validatorIncomeValues:%s:%x", utils.Config.Chain.Config.DepositChainID, currency, sha256.Sum256([]byte(strings.Join(validatorIndicesStr, ","))))
+	cached := map[int64]*validatorIncomeDayValue{}
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Hour, &cached); err != nil {
+		cached = map[int64]*validatorIncomeDayValue{}
+	}
+
+	missing := []*types.ValidatorIncomeHistory{}
+	for i := range history {
+		day := &history[i]
+		value, ok := cached[day.Day]
+		if !ok {
+			missing = append(missing, day)
+			continue
+		}
+		day.Price = value.Price
+		day.ClRewardsValue = float64(day.ClRewards) / 1e9 * day.Price
+		day.ElRewards = value.ElRewards
+		day.ElRewardsValue = value.ElRewardsValue
+		day.WithdrawalsValue = value.WithdrawalsValue
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	err := valueValidatorIncomeDays(missing, validatorIndices, currency)
+	if err != nil {
+		return err
+	}
+
+	finalized := utils.EpochToTime(lastFinalizedEpoch + 1)
+	added := false
+	for _, day := range missing {
+		if day.Price == 0 || utils.DayToTime(day.Day+1).After(finalized) {
+			continue
+		}
+		cached[day.Day] = &validatorIncomeDayValue{Price: day.Price, ElRewards: day.ElRewards, ElRewardsValue: day.ElRewardsValue, WithdrawalsValue: day.WithdrawalsValue}
+		added = true
+	}
+	if added {
+		go func() {
+			err := cache.TieredCache.Set(cacheKey, cached, time.Hour*24)
+			if err != nil {
+				utils.LogError(err, fmt.Errorf("error setting tieredCache for valueValidatorIncomeHistory with key %v", cacheKey), 0)
+			}
+		}()
+	}
+	return nil
+}
+
+// valueValidatorIncomeDays values the income of the validators of the given days, which have to be in ascending order
+func valueValidatorIncomeDays(history []*types.ValidatorIncomeHistory, validatorIndices []uint64, currency string) error {
+	firstDay := history[0].Day
+	if firstDay < 0 {
+		firstDay = 0
+	}
+	lastDay := history[len(history)-1].Day
+	slotsPerDay := utils.EpochsPerDay() * utils.Config.Chain.Config.SlotsPerEpoch
+	firstSlot := uint64(firstDay) * slotsPerDay
+	lastSlot := uint64(lastDay+1)*slotsPerDay - 1
+
+	series, err := GetPriceSeries(currency, utils.DayToTime(firstDay), utils.DayToTime(lastDay+1))
+	if err != nil {
+		return err
+	}
+
+	days := make(map[int64]*types.ValidatorIncomeHistory, len(history))
+	for _, day := range history {
+		day.Price = series.Average(utils.DayToTime(day.Day), utils.DayToTime(day.Day+1))
+		day.ClRewardsValue = float64(day.ClRewards) / 1e9 * day.Price
+		day.ElRewards = 0
+		day.ElRewardsValue = 0
+		day.WithdrawalsValue = 0
+		days[day.Day] = day
+	}
+
+	withdrawals := []struct {
+		Slot   uint64 `db:"block_slot"`
+		Amount int64  `db:"amount"`
+	}{}
+	err = ReaderDb.Select(&withdrawals, `
+		SELECT w.block_slot, SUM(w.amount) AS amount
+		FROM blocks_withdrawals w
+		INNER JOIN blocks b ON b.blockroot = w.block_root AND b.status = '1'
+		WHERE w.validatorindex = ANY($1) AND w.block_slot BETWEEN $2 AND $3
+		GROUP BY w.block_slot`, pq.Array(validatorIndices), firstSlot, lastSlot)
+	if err != nil {
+		return fmt.Errorf("error retrieving withdrawals: %w", err)
+	}
+	for _, w := range withdrawals {
+		if day, ok := days[int64(w.Slot/slotsPerDay)]; ok {
+			day.WithdrawalsValue += float64(w.Amount) / 1e9 * series.At(utils.SlotToTime(w.Slot))
+		}
+	}
+
+	proposals := []struct {
+		Slot            uint64 `db:"slot"`
+		ExecBlockNumber uint64 `db:"exec_block_number"`
+	}{}
+	err = ReaderDb.Select(&proposals, `
+		SELECT slot, exec_block_number
+		FROM blocks
+		WHERE proposer = ANY($1) AND slot BETWEEN $2 AND $3 AND status = '1' AND exec_block_number > 0`, pq.Array(validatorIndices), firstSlot, lastSlot)
+	if err != nil {
+		return fmt.Errorf("error retrieving proposed blocks: %w", err)
+	}
+	if len(proposals) == 0 {
+		return nil
+	}
+
+	numbers := make([]uint64, 0, len(proposals))
+	slots := make(map[uint64]uint64, len(proposals))
+	for _, p := range proposals {
+		numbers = append(numbers, p.ExecBlockNumber)
+		slots[p.ExecBlockNumber] = p.Slot
+	}
+	blocks, err := BigtableClient.GetBlocksIndexedMultiple(numbers, uint64(len(numbers)))
+	if err != nil {
+		return fmt.Errorf("error retrieving proposed execution blocks: %w", err)
+	}
+	relaysData, err := GetRelayDataForIndexedBlocks(blocks)
+	if err != nil {
+		return fmt.Errorf("error retrieving relay data of proposed execution blocks: %w", err)
+	}
+	for _, b := range blocks {
+		slot, ok := slots[b.Number]
+		if !ok {
+			continue
+		}
+		day, ok := days[int64(slot/slotsPerDay)]
+		if !ok {
+			continue
+		}
+		// the proposer receives the mev bribe of relayed blocks and the block reward otherwise
+		reward := utils.Eth1TotalReward(b)
+		if relayData, ok := relaysData[common.BytesToHash(b.Hash)]; ok {
+			reward = relayData.MevBribe.BigInt()
+		}
+		rewardGwei := new(big.Int).Div(reward, big.NewInt(1e9)).Int64()
+		day.ElRewards += rewardGwei
+		day.ElRewardsValue += float64(rewardGwei) / 1e9 * series.At(b.Time.AsTime())
+	}
+	return nil
+}
+
 func WriteChartSeriesForDay(day int64) error {
 	startTs := time.Now()
 
@@ -0,0 +1,393 @@
+package db
+
+import (
+	"database/sql"
+	"eth2-exporter/metrics"
+	"eth2-exporter/types"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	StatisticsTaskPending   = "pending"
+	StatisticsTaskRunning   = "running"
+	StatisticsTaskCompleted = "completed"
+	StatisticsTaskFailed    = "failed"
+)
+
+// validatorStatisticsTask is a step of the validator statistics export of a day,
+// whether it has been completed is tracked in the column of the same name of the validator_stats_status table
+type validatorStatisticsTask struct {
+	Column string
+	// DependsOn are the tasks of the same day that have to be completed before the task can run
+	DependsOn []string
+	// DependsOnPreviousDay are the tasks of the previous day that have to be completed before the task can run
+	DependsOnPreviousDay []string
+	Run                  func(day uint64, concurrencyTotal uint64, concurrencyCl uint64) error
+}
+
+// validatorStatisticsTasks is the dependency graph of the validator statistics export, the tasks are in topological order
+var validatorStatisticsTasks = []*validatorStatisticsTask{
+	{
+		Column: "failed_attestations_exported",
+		Run: func(day, _, _ uint64) error {
+			return WriteValidatorFailedAttestationsStatisticsForDay(day)
+		},
+	},
+	{
+		Column: "sync_duties_exported",
+		Run: func(day, _, _ uint64) error {
+			return WriteValidatorSyncDutiesForDay(day)
+		},
+	},
+	{
+		Column: "withdrawals_deposits_exported",
+		Run: func(day, _, _ uint64) error {
+			return WriteValidatorDepositWithdrawals(day)
+		},
+	},
+	{
+		Column: "block_stats_exported",
+		Run: func(day, _, _ uint64) error {
+			return WriteValidatorBlockStats(day)
+		},
+	},
+	{
+		Column: "balance_exported",
+		Run: func(day, _, _ uint64) error {
+			return WriteValidatorBalances(day)
+		},
+	},
+	{
+		Column:               "cl_rewards_exported",
+		DependsOn:            []string{"balance_exported", "withdrawals_deposits_exported"},
+		DependsOnPreviousDay: []string{"balance_exported"},
+		Run: func(day, _, concurrencyCl uint64) error {
+			return WriteValidatorClIcome(day, concurrencyCl)
+		},
+	},
+	{
+		Column: "el_rewards_exported",
+		Run: func(day, _, _ uint64) error {
+			return WriteValidatorElIcome(day)
+		},
+	},
+	{
+		Column:               "total_performance_exported",
+		DependsOn:            []string{"cl_rewards_exported", "el_rewards_exported", "sync_duties_exported", "failed_attestations_exported"},
+		DependsOnPreviousDay: []string{"total_performance_exported"},
+		Run: func(day, concurrencyTotal, _ uint64) error {
+			return WriteValidatorTotalPerformance(day, concurrencyTotal)
+		},
+	},
+}
+
+// ValidatorStatisticsTaskNames returns the names of the tasks of the validator statistics export in the order they are run
+func ValidatorStatisticsTaskNames() []string {
+	names := make([]string, 0, len(validatorStatisticsTasks))
+	for _, task := range validatorStatisticsTasks {
+		names = append(names, task.Column)
+	}
+	return names
+}
+
+// downstreamValidatorStatisticsTasks returns the given tasks and all tasks of the same day that depend on them directly or indirectly,
+// as well as the tasks of the following day that depend on any of those
+func downstreamValidatorStatisticsTasks(tasks map[string]bool) (sameDay map[string]bool, nextDay map[string]bool) {
+	sameDay = make(map[string]bool, len(validatorStatisticsTasks))
+	nextDay = make(map[string]bool)
+	for task := range tasks {
+		sameDay[task] = true
+	}
+	// the tasks are in topological order, so a single pass collects all transitive dependents
+	for _, task := range validatorStatisticsTasks {
+		for _, dep := range task.DependsOn {
+			if sameDay[dep] {
+				sameDay[task.Column] = true
+			}
+		}
+	}
+	for _, task := range validatorStatisticsTasks {
+		for _, dep := range task.DependsOnPreviousDay {
+			if sameDay[dep] {
+				nextDay[task.Column] = true
+			}
+		}
+	}
+	return sameDay, nextDay
+}
+
+// ResetValidatorStatisticsTasks marks the given tasks of a day as not exported so that they are recomputed on the next export,
+// all tasks that depend on them are reset as well, including the tasks of later days that build on the results of the day
+func ResetValidatorStatisticsTasks(day uint64, tasks []string) error {
+	reset := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if !isValidatorStatisticsTask(task) {
+			return fmt.Errorf("unknown validator statistics task %v", task)
+		}
+		reset[task] = true
+	}
+	if len(reset) == 0 {
+		return nil
+	}
+
+	var lastDay uint64
+	err := WriterDb.Get(&lastDay, `SELECT COALESCE(MAX(day), 0) FROM validator_stats_status`)
+	if err != nil {
+		return fmt.Errorf("error retrieving last day of the validator statistics: %w", err)
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for d := day; len(reset) > 0; d++ {
+		var nextDay map[string]bool
+		reset, nextDay = downstreamValidatorStatisticsTasks(reset)
+		err = resetValidatorStatisticsTasksOfDay(tx, d, reset)
+		if err != nil {
+			return err
+		}
+		if d >= lastDay {
+			break
+		}
+		reset = nextDay
+	}
+
+	return tx.Commit()
+}
+
+func resetValidatorStatisticsTasksOfDay(tx *sqlx.Tx, day uint64, tasks map[string]bool) error {
+	columns := make([]string, 0, len(tasks))
+	names := make([]string, 0, len(tasks))
+	for _, task := range validatorStatisticsTasks {
+		if tasks[task.Column] {
+			columns = append(columns, task.Column+" = false")
+			names = append(names, task.Column)
+		}
+	}
+	logger.Infof("resetting validator statistics tasks %v of day %v", strings.Join(names, ", "), day)
+
+	_, err := tx.Exec(fmt.Sprintf(`UPDATE validator_stats_status SET status = false, %s WHERE day = $1`, strings.Join(columns, ", ")), day)
+	if err != nil {
+		return fmt.Errorf("error resetting validator statistics status of day %v: %w", day, err)
+	}
+	for _, name := range names {
+		_, err = tx.Exec(`
+			INSERT INTO validator_stats_tasks (day, task, status)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (day, task) DO UPDATE SET status = excluded.status, attempts = 0, duration_ms = 0, error = '', started_ts = NULL, finished_ts = NULL`,
+			day, name, StatisticsTaskPending)
+		if err != nil {
+			return fmt.Errorf("error resetting validator statistics task %v of day %v: %w", name, day, err)
+		}
+	}
+	return nil
+}
+
+func isValidatorStatisticsTask(name string) bool {
+	for _, task := range validatorStatisticsTasks {
+		if task.Column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getValidatorStatisticsExported returns for every task whether it has been exported for the day and whether the export of the day is completed
+func getValidatorStatisticsExported(day uint64) (map[string]bool, bool, error) {
+	names := ValidatorStatisticsTaskNames()
+	exported := make([]bool, len(names))
+	dest := make([]interface{}, 0, len(names)+1)
+	status := false
+	dest = append(dest, &status)
+	for i := range exported {
+		dest = append(dest, &exported[i])
+	}
+
+	err := ReaderDb.QueryRow(fmt.Sprintf(`SELECT status, %s FROM validator_stats_status WHERE day = $1`, strings.Join(names, ", ")), day).Scan(dest...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("error retrieving exported state: %w", err)
+	}
+
+	res := make(map[string]bool, len(names))
+	for i, name := range names {
+		res[name] = exported[i]
+	}
+	return res, status, nil
+}
+
+// validatorStatisticsTaskBackoff is the time after which a task that has failed after all retries is run again
+const validatorStatisticsTaskBackoff = time.Minute * 10
+
+// getFailedValidatorStatisticsTasks returns the tasks of a day that have failed after all retries within the last validatorStatisticsTaskBackoff
+func getFailedValidatorStatisticsTasks(day uint64) (map[string]bool, error) {
+	tasks := []string{}
+	err := ReaderDb.Select(&tasks, `SELECT task FROM validator_stats_tasks WHERE day = $1 AND status = $2 AND finished_ts > $3`,
+		day, StatisticsTaskFailed, time.Now().Add(-validatorStatisticsTaskBackoff))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving failed validator statistics tasks of day %v: %w", day, err)
+	}
+
+	res := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		res[task] = true
+	}
+	return res, nil
+}
+
+// missingValidatorStatisticsDependency returns the first dependency of the task that has not been exported, previousExported is nil if the previous day is not tracked
+func missingValidatorStatisticsDependency(task *validatorStatisticsTask, exported map[string]bool, previousExported map[string]bool) string {
+	for _, dep := range task.DependsOn {
+		if !exported[dep] {
+			return dep
+		}
+	}
+	if previousExported == nil {
+		return ""
+	}
+	for _, dep := range task.DependsOnPreviousDay {
+		if !previousExported[dep] {
+			return dep + " of the previous day"
+		}
+	}
+	return ""
+}
+
+// runValidatorStatisticsTask runs a task of the validator statistics export of a day, a failing task is retried up to retries times
+func runValidatorStatisticsTask(task *validatorStatisticsTask, day uint64, retries uint64, concurrencyTotal uint64, concurrencyCl uint64) error {
+	var err error
+	for attempt := uint64(1); attempt <= retries+1; attempt++ {
+		start := time.Now()
+		_, dbErr := WriterDb.Exec(`
+			INSERT INTO validator_stats_tasks (day, task, status, attempts, started_ts)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (day, task) DO UPDATE SET status = excluded.status, attempts = excluded.attempts, error = '', started_ts = excluded.started_ts, finished_ts = NULL`,
+			day, task.Column, StatisticsTaskRunning, attempt, start)
+		if dbErr != nil {
+			logger.Errorf("error updating status of validator statistics task %v of day %v: %v", task.Column, day, dbErr)
+		}
+
+		err = task.Run(day, concurrencyTotal, concurrencyCl)
+
+		status, errMsg := StatisticsTaskCompleted, ""
+		if err != nil {
+			status, errMsg = StatisticsTaskFailed, err.Error()
+		}
+		_, dbErr = WriterDb.Exec(`
+			UPDATE validator_stats_tasks
+			SET status = $3, duration_ms = $4, error = $5, finished_ts = $6
+			WHERE day = $1 AND task = $2`,
+			day, task.Column, status, time.Since(start).Milliseconds(), errMsg, time.Now())
+		if dbErr != nil {
+			logger.Errorf("error updating status of validator statistics task %v of day %v: %v", task.Column, day, dbErr)
+		}
+
+		if err == nil {
+			return nil
+		}
+		metrics.Errors.WithLabelValues("statistics_task_" + task.Column).Inc()
+		if attempt <= retries {
+			logger.Warnf("validator statistics task %v of day %v failed in attempt %v, retrying: %v", task.Column, day, attempt, err)
+			time.Sleep(time.Second * 10 * time.Duration(attempt))
+		}
+	}
+	return fmt.Errorf("error running validator statistics task %v of day %v: %w", task.Column, day, err)
+}
+
+// GetIncompleteValidatorStatisticsDays returns the days up to lastDay whose validator statistics export has not been completed, e.g. because tasks of the day have been reset
+func GetIncompleteValidatorStatisticsDays(lastDay uint64) ([]uint64, error) {
+	days := []uint64{}
+	err := ReaderDb.Select(&days, `SELECT day FROM validator_stats_status WHERE NOT status AND day <= $1 ORDER BY day`, lastDay)
+	return days, err
+}
+
+// GetValidatorStatisticsPipelineStatus returns the progress of the validator statistics export of the days from fromDay to toDay (inclusive)
+func GetValidatorStatisticsPipelineStatus(fromDay, toDay uint64) ([]*types.ApiStatisticsDayStatus, error) {
+	names := ValidatorStatisticsTaskNames()
+	rows, err := ReaderDb.Query(fmt.Sprintf(`SELECT day, status, %s FROM validator_stats_status WHERE day BETWEEN $1 AND $2 ORDER BY day`, strings.Join(names, ", ")), fromDay, toDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator statistics status: %w", err)
+	}
+	defer rows.Close()
+
+	days := make(map[uint64]*types.ApiStatisticsDayStatus)
+	res := []*types.ApiStatisticsDayStatus{}
+	for rows.Next() {
+		day := &types.ApiStatisticsDayStatus{}
+		exported := make([]bool, len(names))
+		dest := []interface{}{&day.Day, &day.Completed}
+		for i := range exported {
+			dest = append(dest, &exported[i])
+		}
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning validator statistics status: %w", err)
+		}
+		for i, task := range validatorStatisticsTasks {
+			status := StatisticsTaskPending
+			if exported[i] {
+				status = StatisticsTaskCompleted
+			}
+			day.Tasks = append(day.Tasks, &types.ApiStatisticsTaskStatus{
+				Task:                 task.Column,
+				DependsOn:            task.DependsOn,
+				DependsOnPreviousDay: task.DependsOnPreviousDay,
+				Exported:             exported[i],
+				Status:               status,
+			})
+		}
+		days[day.Day] = day
+		res = append(res, day)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating validator statistics status: %w", err)
+	}
+
+	runs := []struct {
+		Day        uint64       `db:"day"`
+		Task       string       `db:"task"`
+		Status     string       `db:"status"`
+		Attempts   uint64       `db:"attempts"`
+		DurationMs uint64       `db:"duration_ms"`
+		StartedTs  sql.NullTime `db:"started_ts"`
+		FinishedTs sql.NullTime `db:"finished_ts"`
+	}{}
+	err = ReaderDb.Select(&runs, `
+		SELECT day, task, status, attempts, duration_ms, started_ts, finished_ts
+		FROM validator_stats_tasks
+		WHERE day BETWEEN $1 AND $2`, fromDay, toDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator statistics tasks: %w", err)
+	}
+	for _, run := range runs {
+		day, ok := days[run.Day]
+		if !ok {
+			continue
+		}
+		for _, task := range day.Tasks {
+			if task.Task != run.Task {
+				continue
+			}
+			// a task that has been exported without being tracked or before it was reset keeps its exported status
+			if !(task.Exported && run.Status == StatisticsTaskPending) {
+				task.Status = run.Status
+			}
+			task.Attempts = run.Attempts
+			task.DurationMs = run.DurationMs
+			if run.StartedTs.Valid {
+				task.StartedTs = run.StartedTs.Time.Unix()
+			}
+			if run.FinishedTs.Valid {
+				task.FinishedTs = run.FinishedTs.Time.Unix()
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDownstreamValidatorStatisticsTasks(t *testing.T) {
+	tests := []struct {
+		reset   []string
+		sameDay []string
+		nextDay []string
+	}{
+		{
+			reset:   []string{"balance_exported"},
+			sameDay: []string{"balance_exported", "cl_rewards_exported", "total_performance_exported"},
+			nextDay: []string{"cl_rewards_exported", "total_performance_exported"},
+		},
+		{
+			reset:   []string{"el_rewards_exported"},
+			sameDay: []string{"el_rewards_exported", "total_performance_exported"},
+			nextDay: []string{"total_performance_exported"},
+		},
+		{
+			reset:   []string{"block_stats_exported"},
+			sameDay: []string{"block_stats_exported"},
+			nextDay: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		reset := make(map[string]bool)
+		for _, task := range tt.reset {
+			reset[task] = true
+		}
+		sameDay, nextDay := downstreamValidatorStatisticsTasks(reset)
+		if !reflect.DeepEqual(taskSet(tt.sameDay), sameDay) {
+			t.Errorf("resetting %v: expected same day tasks %v, got %v", tt.reset, tt.sameDay, sameDay)
+		}
+		if !reflect.DeepEqual(taskSet(tt.nextDay), nextDay) {
+			t.Errorf("resetting %v: expected next day tasks %v, got %v", tt.reset, tt.nextDay, nextDay)
+		}
+	}
+}
+
+func TestMissingValidatorStatisticsDependency(t *testing.T) {
+	task := getValidatorStatisticsTask(t, "cl_rewards_exported")
+	tests := []struct {
+		name             string
+		exported         []string
+		previousExported map[string]bool
+		missing          string
+	}{
+		{"same day dependency missing", []string{"balance_exported"}, taskSet([]string{"balance_exported"}), "withdrawals_deposits_exported"},
+		{"previous day dependency missing", []string{"balance_exported", "withdrawals_deposits_exported"}, taskSet([]string{}), "balance_exported of the previous day"},
+		{"previous day not tracked", []string{"balance_exported", "withdrawals_deposits_exported"}, nil, ""},
+		{"all dependencies exported", []string{"balance_exported", "withdrawals_deposits_exported"}, taskSet([]string{"balance_exported"}), ""},
+	}
+
+	for _, tt := range tests {
+		missing := missingValidatorStatisticsDependency(task, taskSet(tt.exported), tt.previousExported)
+		if missing != tt.missing {
+			t.Errorf("%v: expected missing dependency %q, got %q", tt.name, tt.missing, missing)
+		}
+	}
+}
+
+func taskSet(tasks []string) map[string]bool {
+	set := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		set[task] = true
+	}
+	return set
+}
+
+func getValidatorStatisticsTask(t *testing.T, name string) *validatorStatisticsTask {
+	for _, task := range validatorStatisticsTasks {
+		if task.Column == name {
+			return task
+		}
+	}
+	t.Fatalf("unknown validator statistics task %v", name)
+	return nil
+}
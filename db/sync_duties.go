@@ -0,0 +1,66 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+
+	"github.com/lib/pq"
+)
+
+// GetSyncCommitteeMemberships returns the sync committee periods from fromPeriod to toPeriod (inclusive) in which the validators are members of the
+// sync committee ordered by period and validator index
+func GetSyncCommitteeMemberships(validators []uint64, fromPeriod, toPeriod uint64) ([]*types.SyncCommitteeMembership, error) {
+	memberships := []*types.SyncCommitteeMembership{}
+	err := ReaderDb.Select(&memberships, `
+		SELECT DISTINCT period, validatorindex
+		FROM sync_committees
+		WHERE period BETWEEN $1 AND $2 AND validatorindex = ANY($3)
+		ORDER BY period, validatorindex`, fromPeriod, toPeriod, pq.Array(validators))
+	return memberships, err
+}
+
+// GetScheduledProposals returns the scheduled block proposals of the validators from fromSlot on ordered by slot,
+// this includes the proposer duties of the next epoch which are persisted ahead of time
+func GetScheduledProposals(validators []uint64, fromSlot uint64) ([]*types.ScheduledProposal, error) {
+	proposals := []*types.ScheduledProposal{}
+	err := ReaderDb.Select(&proposals, `
+		SELECT proposerslot AS slot, validatorindex AS proposer
+		FROM proposal_assignments
+		WHERE epoch >= $1 AND proposerslot >= $2 AND status = 0 AND validatorindex = ANY($3)
+		ORDER BY proposerslot`, utils.EpochOfSlot(fromSlot), fromSlot, pq.Array(validators))
+	return proposals, err
+}
+
+// GetAllScheduledProposals returns the scheduled block proposals of all validators from fromSlot on ordered by slot
+func GetAllScheduledProposals(fromSlot uint64) ([]*types.ScheduledProposal, error) {
+	proposals := []*types.ScheduledProposal{}
+	err := ReaderDb.Select(&proposals, `
+		SELECT proposerslot AS slot, validatorindex AS proposer
+		FROM proposal_assignments
+		WHERE epoch >= $1 AND proposerslot >= $2 AND status = 0
+		ORDER BY proposerslot`, utils.EpochOfSlot(fromSlot), fromSlot)
+	return proposals, err
+}
+
+// GetMissedAndOrphanedSlots returns the status of the slots from fromSlot to toSlot (inclusive) that have no canonical block,
+// the status is 3 if the block of the slot was orphaned and 2 if no block was proposed
+func GetMissedAndOrphanedSlots(fromSlot, toSlot uint64) (map[uint64]uint64, error) {
+	slots := []struct {
+		Slot   uint64 `db:"slot"`
+		Status uint64 `db:"status"`
+	}{}
+	err := ReaderDb.Select(&slots, `
+		SELECT slot, MAX(status)::int AS status
+		FROM blocks
+		WHERE slot BETWEEN $1 AND $2
+		GROUP BY slot
+		HAVING BOOL_AND(status IN ('2', '3'))`, fromSlot, toSlot)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uint64]uint64, len(slots))
+	for _, slot := range slots {
+		res[slot.Slot] = slot.Status
+	}
+	return res, nil
+}
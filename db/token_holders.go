@@ -0,0 +1,290 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"eth2-exporter/types"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/lib/pq"
+)
+
+var tokenHolderZeroAddress = make([]byte, 20)
+
+// markTokenHolderUpdate marks the balance of an address of an ERC-20 token for an update of the holder index after a transfer at a block, see GetTokenHolderUpdates
+// Row:    <chainID>:TH:V:<tokenAddress>:<address>:<blockNumber>:<blockTs>
+// Family: f
+// Column: nil
+// Cell:   nil
+// Example scan: "1:TH:V:dac17f958d2ee523a2206206994597c13d831ec7:28c6c06298d514db089934071355e5743bf21d60:18123456:1693906763"
+func (bigtable *Bigtable) markTokenHolderUpdate(token, address []byte, blockNumber uint64, blockTime time.Time, mutations *types.BulkMutations) {
+	if bytes.Equal(address, tokenHolderZeroAddress) {
+		return
+	}
+	key := fmt.Sprintf("%s:TH:V:%x:%x:%d:%d", bigtable.chainId, token, address, blockNumber, blockTime.Unix())
+	mut := gcp_bigtable.NewMutation()
+	mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+	mutations.Keys = append(mutations.Keys, key)
+	mutations.Muts = append(mutations.Muts, mut)
+}
+
+// GetTokenHolderUpdates returns up to limit token holder updates that were marked by markTokenHolderUpdate and their keys.
+// Only the update of the latest block is returned for every holder, the keys of all returned rows have to be deleted with DeleteTokenHolderUpdates once they are processed.
+func (bigtable *Bigtable) GetTokenHolderUpdates(limit int64) ([]*types.TokenHolderUpdate, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	keys := []string{}
+	latest := map[string]*types.TokenHolderUpdate{}
+	var parseErr error
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(fmt.Sprintf("%s:TH:V:", bigtable.chainId)), func(row gcp_bigtable.Row) bool {
+		key := row.Key()
+		update, err := parseTokenHolderUpdateKey(key)
+		if err != nil {
+			parseErr = err
+			return false
+		}
+		keys = append(keys, key)
+		holder := string(update.Token) + string(update.Address)
+		if latest[holder] == nil || latest[holder].BlockNumber < update.BlockNumber {
+			latest[holder] = update
+		}
+		return true
+	}, gcp_bigtable.LimitRows(limit), gcp_bigtable.RowFilter(gcp_bigtable.StripValueFilter()))
+	if err != nil {
+		return nil, nil, err
+	}
+	if parseErr != nil {
+		return nil, nil, parseErr
+	}
+
+	updates := make([]*types.TokenHolderUpdate, 0, len(latest))
+	for _, update := range latest {
+		updates = append(updates, update)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].BlockNumber < updates[j].BlockNumber
+	})
+	return updates, keys, nil
+}
+
+// parseTokenHolderUpdateKey parses a key of the format <chainID>:TH:V:<tokenAddress>:<address>:<blockNumber>:<blockTs>
+func parseTokenHolderUpdateKey(key string) (*types.TokenHolderUpdate, error) {
+	split := strings.Split(key, ":")
+	if len(split) != 7 {
+		return nil, fmt.Errorf("invalid token holder update key %v", key)
+	}
+	token, err := hex.DecodeString(split[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token address of token holder update key %v: %w", key, err)
+	}
+	address, err := hex.DecodeString(split[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid address of token holder update key %v: %w", key, err)
+	}
+	blockNumber, err := strconv.ParseUint(split[5], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number of token holder update key %v: %w", key, err)
+	}
+	blockTs, err := strconv.ParseInt(split[6], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block time of token holder update key %v: %w", key, err)
+	}
+	return &types.TokenHolderUpdate{Token: token, Address: address, BlockNumber: blockNumber, BlockTime: time.Unix(blockTs, 0)}, nil
+}
+
+// DeleteTokenHolderUpdates removes processed token holder updates
+func (bigtable *Bigtable) DeleteTokenHolderUpdates(keys []string) error {
+	mutsDelete := &types.BulkMutations{
+		Keys: make([]string, 0, len(keys)),
+		Muts: make([]*gcp_bigtable.Mutation, 0, len(keys)),
+	}
+	for _, key := range keys {
+		mut := gcp_bigtable.NewMutation()
+		mut.DeleteRow()
+		mutsDelete.Keys = append(mutsDelete.Keys, key)
+		mutsDelete.Muts = append(mutsDelete.Muts, mut)
+	}
+	return bigtable.WriteBulk(mutsDelete, bigtable.tableData)
+}
+
+// SaveTokenHolderBalances updates the holder index of ERC-20 tokens with balances that were retrieved at blockNumber, native balances are ignored.
+// A balance is only added to the balance history of a holder if it differs from the last known balance. The holder counts are updated
+// incrementally by the holders that were added and removed and are dated with the day of blockTime.
+func SaveTokenHolderBalances(balances []*types.Eth1AddressBalance, blockNumber uint64, blockTime time.Time) error {
+	type holder struct {
+		token   string
+		address string
+	}
+	latest := make(map[holder]*types.Eth1AddressBalance, len(balances))
+	for _, balance := range balances {
+		if len(balance.Token) != 20 {
+			continue
+		}
+		latest[holder{string(balance.Token), string(balance.Address)}] = balance
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+
+	tokens := make(pq.ByteaArray, 0, len(latest))
+	addresses := make(pq.ByteaArray, 0, len(latest))
+	values := make(pq.StringArray, 0, len(latest))
+	distinctTokens := make(pq.ByteaArray, 0)
+	seenTokens := make(map[string]bool)
+	for _, balance := range latest {
+		tokens = append(tokens, balance.Token)
+		addresses = append(addresses, balance.Address)
+		values = append(values, new(big.Int).SetBytes(balance.Balance).String())
+		if !seenTokens[string(balance.Token)] {
+			seenTokens[string(balance.Token)] = true
+			distinctTokens = append(distinctTokens, balance.Token)
+		}
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO token_holder_balances (token, address, block_number, balance)
+		SELECT b.token, b.address, $4, b.balance
+		FROM UNNEST($1::bytea[], $2::bytea[], $3::text[]::numeric[]) AS b(token, address, balance)
+		LEFT JOIN token_holders h ON h.token = b.token AND h.address = b.address
+		WHERE COALESCE(h.balance, 0) <> b.balance
+		ON CONFLICT (token, address, block_number) DO UPDATE SET balance = excluded.balance`,
+		tokens, addresses, values, blockNumber)
+	if err != nil {
+		return fmt.Errorf("error saving token holder balance history: %w", err)
+	}
+
+	// xmax is 0 for inserted rows and set for updated rows
+	added := [][]byte{}
+	err = tx.Select(&added, `
+		INSERT INTO token_holders (token, address, balance, block_number)
+		SELECT b.token, b.address, b.balance, $4
+		FROM UNNEST($1::bytea[], $2::bytea[], $3::text[]::numeric[]) AS b(token, address, balance)
+		WHERE b.balance > 0
+		ON CONFLICT (token, address) DO UPDATE SET balance = excluded.balance, block_number = excluded.block_number
+		WHERE token_holders.block_number <= excluded.block_number
+		RETURNING CASE WHEN xmax = 0 THEN token END`,
+		tokens, addresses, values, blockNumber)
+	if err != nil {
+		return fmt.Errorf("error saving token holders: %w", err)
+	}
+
+	removed := [][]byte{}
+	err = tx.Select(&removed, `
+		DELETE FROM token_holders h
+		USING UNNEST($1::bytea[], $2::bytea[], $3::text[]::numeric[]) AS b(token, address, balance)
+		WHERE h.token = b.token AND h.address = b.address AND b.balance = 0 AND h.block_number <= $4
+		RETURNING h.token`,
+		tokens, addresses, values, blockNumber)
+	if err != nil {
+		return fmt.Errorf("error removing token holders without balance: %w", err)
+	}
+
+	deltas := make(map[string]int64, len(distinctTokens))
+	for _, token := range added {
+		if token != nil {
+			deltas[string(token)]++
+		}
+	}
+	for _, token := range removed {
+		deltas[string(token)]--
+	}
+	changes := make(pq.Int64Array, 0, len(distinctTokens))
+	for _, token := range distinctTokens {
+		changes = append(changes, deltas[string(token)])
+	}
+
+	// the count of the day continues from the latest count before it
+	_, err = tx.Exec(`
+		INSERT INTO token_holder_counts (token, day, holders)
+		SELECT t.token, $3::date, GREATEST(COALESCE((SELECT c.holders FROM token_holder_counts c WHERE c.token = t.token AND c.day <= $3::date ORDER BY c.day DESC LIMIT 1), 0) + t.change, 0)
+		FROM UNNEST($1::bytea[], $2::bigint[]) AS t(token, change)
+		ON CONFLICT (token, day) DO UPDATE SET holders = excluded.holders`,
+		distinctTokens, changes, blockTime.UTC().Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("error saving token holder counts: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetTokenHolders returns the holders of an ERC-20 token ranked by their latest balance
+func GetTokenHolders(token []byte, limit, offset uint64) ([]*types.TokenHolder, error) {
+	holders := []*types.TokenHolder{}
+	err := ReaderDb.Select(&holders, `
+		SELECT address, balance, block_number
+		FROM token_holders
+		WHERE token = $1
+		ORDER BY balance DESC, address
+		LIMIT $2 OFFSET $3`, token, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving holders of token %x: %w", token, err)
+	}
+	return holders, nil
+}
+
+// GetTokenHolderCount returns the number of addresses that currently hold an ERC-20 token
+func GetTokenHolderCount(token []byte) (uint64, error) {
+	count := uint64(0)
+	err := ReaderDb.Get(&count, `SELECT COALESCE((SELECT holders FROM token_holder_counts WHERE token = $1 ORDER BY day DESC LIMIT 1), 0)`, token)
+	return count, err
+}
+
+// GetTokenHoldersAtBlock returns the holders of an ERC-20 token ranked by their balance at blockNumber and the number of holders at blockNumber.
+// Balances are only known from the first transfer of the holder that was indexed by the token holder updater on.
+func GetTokenHoldersAtBlock(token []byte, blockNumber, limit, offset uint64) ([]*types.TokenHolder, uint64, error) {
+	count := uint64(0)
+	err := ReaderDb.Get(&count, `
+		SELECT COUNT(*)
+		FROM (
+			SELECT DISTINCT ON (address) balance
+			FROM token_holder_balances
+			WHERE token = $1 AND block_number <= $2
+			ORDER BY address, block_number DESC
+		) snapshot
+		WHERE balance > 0`, token, blockNumber)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving holder count of token %x at block %v: %w", token, blockNumber, err)
+	}
+
+	holders := []*types.TokenHolder{}
+	err = ReaderDb.Select(&holders, `
+		SELECT address, balance, block_number
+		FROM (
+			SELECT DISTINCT ON (address) address, balance, block_number
+			FROM token_holder_balances
+			WHERE token = $1 AND block_number <= $2
+			ORDER BY address, block_number DESC
+		) snapshot
+		WHERE balance > 0
+		ORDER BY balance DESC, address
+		LIMIT $3 OFFSET $4`, token, blockNumber, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving holders of token %x at block %v: %w", token, blockNumber, err)
+	}
+	return holders, count, nil
+}
+
+// GetTokenHolderCountHistory returns the daily number of holders of an ERC-20 token
+func GetTokenHolderCountHistory(token []byte) ([]*types.TokenHolderCount, error) {
+	counts := []*types.TokenHolderCount{}
+	err := ReaderDb.Select(&counts, `SELECT day, holders FROM token_holder_counts WHERE token = $1 ORDER BY day`, token)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving holder count history of token %x: %w", token, err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseTokenHolderUpdateKey(t *testing.T) {
+	update, err := parseTokenHolderUpdateKey("1:TH:V:dac17f958d2ee523a2206206994597c13d831ec7:28c6c06298d514db089934071355e5743bf21d60:18123456:1693906763")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(update.Token, []byte{0xda, 0xc1, 0x7f, 0x95, 0x8d, 0x2e, 0xe5, 0x23, 0xa2, 0x20, 0x62, 0x06, 0x99, 0x45, 0x97, 0xc1, 0x3d, 0x83, 0x1e, 0xc7}) {
+		t.Errorf("unexpected token %x", update.Token)
+	}
+	if len(update.Address) != 20 || update.Address[0] != 0x28 {
+		t.Errorf("unexpected address %x", update.Address)
+	}
+	if update.BlockNumber != 18123456 {
+		t.Errorf("unexpected block number %v", update.BlockNumber)
+	}
+	if !update.BlockTime.Equal(time.Unix(1693906763, 0)) {
+		t.Errorf("unexpected block time %v", update.BlockTime)
+	}
+
+	for _, key := range []string{
+		"1:TH:V:dac17f958d2ee523a2206206994597c13d831ec7:28c6c06298d514db089934071355e5743bf21d60:18123456",
+		"1:TH:V:xyz:28c6c06298d514db089934071355e5743bf21d60:18123456:1693906763",
+		"1:TH:V:dac17f958d2ee523a2206206994597c13d831ec7:28c6c06298d514db089934071355e5743bf21d60:latest:1693906763",
+	} {
+		if _, err := parseTokenHolderUpdateKey(key); err == nil {
+			t.Errorf("expected an error for key %v", key)
+		}
+	}
+}
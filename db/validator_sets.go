@@ -0,0 +1,330 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrValidatorSetNotFound is returned if a validator set does not exist or is not owned by the user
+var ErrValidatorSetNotFound = errors.New("validator set not found")
+
+const validatorSetColumns = `id, user_id, network, name, rule_type, rule_value, rule_node_address, rule_ens_name, max_validators, event_names, event_threshold, last_evaluated_epoch, created_ts,
+	(SELECT COUNT(*) FROM users_validator_sets_validators WHERE set_id = users_validator_sets.id) AS validator_count`
+
+// CreateValidatorSet stores a new rule based validator set, the members of the set are evaluated by UpdateValidatorSet
+func CreateValidatorSet(set *types.ValidatorSet) error {
+	return FrontendWriterDB.Get(set, `
+		INSERT INTO users_validator_sets (user_id, network, name, rule_type, rule_value, rule_node_address, rule_ens_name, max_validators, event_names, event_threshold)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+validatorSetColumns,
+		set.UserID, set.Network, set.Name, set.RuleType, set.RuleValue, set.RuleNodeAddress, set.RuleEnsName, set.MaxValidators, set.EventNames, set.EventThreshold)
+}
+
+// GetValidatorSets returns all validator sets of a user on the given network
+func GetValidatorSets(userID uint64, network string) ([]*types.ValidatorSet, error) {
+	sets := []*types.ValidatorSet{}
+	err := FrontendWriterDB.Select(&sets, `SELECT `+validatorSetColumns+` FROM users_validator_sets WHERE user_id = $1 AND network = $2 ORDER BY id`, userID, network)
+	return sets, err
+}
+
+// CountValidatorSets returns the number of validator sets of a user on the given network
+func CountValidatorSets(userID uint64, network string) (uint64, error) {
+	count := uint64(0)
+	err := FrontendWriterDB.Get(&count, `SELECT COUNT(*) FROM users_validator_sets WHERE user_id = $1 AND network = $2`, userID, network)
+	return count, err
+}
+
+// GetValidatorSet returns a validator set of a user, ErrValidatorSetNotFound is returned if the set does not exist or belongs to another user
+func GetValidatorSet(userID, setID uint64) (*types.ValidatorSet, error) {
+	set := &types.ValidatorSet{}
+	err := FrontendWriterDB.Get(set, `SELECT `+validatorSetColumns+` FROM users_validator_sets WHERE id = $1 AND user_id = $2`, setID, userID)
+	if err == sql.ErrNoRows {
+		return nil, ErrValidatorSetNotFound
+	}
+	return set, err
+}
+
+// DeleteValidatorSet deletes a validator set of a user, subscriptions that were created for the members of the set are kept
+func DeleteValidatorSet(userID, setID uint64) error {
+	res, err := FrontendWriterDB.Exec(`DELETE FROM users_validator_sets WHERE id = $1 AND user_id = $2`, setID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrValidatorSetNotFound
+	}
+	return nil
+}
+
+// GetValidatorSetValidators returns the indices of the current members of a validator set
+func GetValidatorSetValidators(setID uint64) ([]uint64, error) {
+	validators := []uint64{}
+	err := FrontendWriterDB.Select(&validators, `SELECT validatorindex FROM users_validator_sets_validators WHERE set_id = $1 ORDER BY validatorindex`, setID)
+	return validators, err
+}
+
+// GetValidatorSetRuleMembers evaluates the rule of a validator set and returns the matching validators ordered by index,
+// at most set.MaxValidators validators are returned
+func GetValidatorSetRuleMembers(set *types.ValidatorSet) ([]*types.ValidatorSetMember, error) {
+	members := []*types.ValidatorSetMember{}
+	var err error
+	switch set.RuleType {
+	case types.WithdrawalCredentialsValidatorSetRule:
+		credentials, decodeErr := hex.DecodeString(set.RuleValue)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("invalid withdrawal credentials of validator set %v: %w", set.ID, decodeErr)
+		}
+		err = ReaderDb.Select(&members, `
+			SELECT validatorindex, pubkey
+			FROM validators
+			WHERE withdrawalcredentials = $1
+			ORDER BY validatorindex
+			LIMIT $2`, credentials, set.MaxValidators)
+	case types.DepositAddressValidatorSetRule:
+		address, decodeErr := hex.DecodeString(set.RuleValue)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("invalid deposit address of validator set %v: %w", set.ID, decodeErr)
+		}
+		err = ReaderDb.Select(&members, `
+			SELECT DISTINCT validators.validatorindex, validators.pubkey
+			FROM eth1_deposits
+			INNER JOIN validators ON validators.pubkey = eth1_deposits.publickey
+			WHERE eth1_deposits.from_address = $1
+			ORDER BY validators.validatorindex
+			LIMIT $2`, address, set.MaxValidators)
+	case types.PoolTagValidatorSetRule:
+		if set.RuleNodeAddress == "" {
+			err = ReaderDb.Select(&members, `
+				SELECT validators.validatorindex, validators.pubkey
+				FROM validator_tags
+				INNER JOIN validators ON validators.pubkey = validator_tags.publickey
+				WHERE validator_tags.tag = $1
+				ORDER BY validators.validatorindex
+				LIMIT $2`, set.RuleValue, set.MaxValidators)
+		} else {
+			nodeAddress, decodeErr := hex.DecodeString(set.RuleNodeAddress)
+			if decodeErr != nil {
+				return nil, fmt.Errorf("invalid node address of validator set %v: %w", set.ID, decodeErr)
+			}
+			err = ReaderDb.Select(&members, `
+				SELECT validators.validatorindex, validators.pubkey
+				FROM validator_tags
+				INNER JOIN validators ON validators.pubkey = validator_tags.publickey
+				INNER JOIN rocketpool_minipools ON rocketpool_minipools.pubkey = validators.pubkey
+				WHERE validator_tags.tag = $1 AND rocketpool_minipools.node_address = $2
+				ORDER BY validators.validatorindex
+				LIMIT $3`, set.RuleValue, nodeAddress, set.MaxValidators)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule type %v of validator set %v", set.RuleType, set.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating rule of validator set %v: %w", set.ID, err)
+	}
+	return members, nil
+}
+
+// updateValidatorSetEnsRule re-resolves the ens name of the rule of a validator set via the ens index and stores the address of the rule if
+// the address record of the name changed. Names that are not indexed or expired keep the last resolved address.
+func updateValidatorSetEnsRule(set *types.ValidatorSet) error {
+	records, err := GetEnsRecords(set.RuleEnsName)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error retrieving ens records of validator set %v: %w", set.ID, err)
+	}
+	if records.Status != types.EnsNameStatusActive || len(records.Address) == 0 {
+		return nil
+	}
+
+	address := hex.EncodeToString(records.Address)
+	switch set.RuleType {
+	case types.WithdrawalCredentialsValidatorSetRule:
+		credentials, err := utils.AddressToWithdrawalCredentials(records.Address)
+		if err != nil {
+			return fmt.Errorf("invalid ens address of validator set %v: %w", set.ID, err)
+		}
+		if hex.EncodeToString(credentials) == set.RuleValue {
+			return nil
+		}
+		set.RuleValue = hex.EncodeToString(credentials)
+	case types.DepositAddressValidatorSetRule:
+		if address == set.RuleValue {
+			return nil
+		}
+		set.RuleValue = address
+	case types.PoolTagValidatorSetRule:
+		if address == set.RuleNodeAddress {
+			return nil
+		}
+		set.RuleNodeAddress = address
+	default:
+		return nil
+	}
+
+	logger.Infof("address of ens name %v of validator set %v changed to 0x%v", set.RuleEnsName, set.ID, address)
+	_, err = FrontendWriterDB.Exec(`UPDATE users_validator_sets SET rule_value = $1, rule_node_address = $2 WHERE id = $3`, set.RuleValue, set.RuleNodeAddress, set.ID)
+	if err != nil {
+		return fmt.Errorf("error updating ens address of validator set %v: %w", set.ID, err)
+	}
+	return nil
+}
+
+// UpdateValidatorSet re-evaluates the rule of a validator set at the given epoch and updates its members. Validators that joined the
+// set are added to the watchlist of the owner and subscribed to the events of the set, validators that left the set are unsubscribed from them.
+func UpdateValidatorSet(set *types.ValidatorSet, epoch uint64) error {
+	if set.RuleEnsName != "" {
+		err := updateValidatorSetEnsRule(set)
+		if err != nil {
+			return err
+		}
+	}
+
+	members, err := GetValidatorSetRuleMembers(set)
+	if err != nil {
+		return err
+	}
+
+	current, err := GetValidatorSetValidators(set.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving members of validator set %v: %w", set.ID, err)
+	}
+	isCurrent := make(map[uint64]bool, len(current))
+	for _, v := range current {
+		isCurrent[v] = true
+	}
+
+	isMember := make(map[uint64]bool, len(members))
+	addedIndices := pq.Int64Array{}
+	addedPubkeys := pq.ByteaArray{}
+	for _, m := range members {
+		isMember[m.Index] = true
+		if !isCurrent[m.Index] {
+			addedIndices = append(addedIndices, int64(m.Index))
+			addedPubkeys = append(addedPubkeys, m.Pubkey)
+		}
+	}
+	removed := pq.Int64Array{}
+	for _, v := range current {
+		if !isMember[v] {
+			removed = append(removed, int64(v))
+		}
+	}
+
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(removed) > 0 {
+		removedPubkeys := [][]byte{}
+		err = tx.Select(&removedPubkeys, `DELETE FROM users_validator_sets_validators WHERE set_id = $1 AND validatorindex = ANY($2) RETURNING pubkey`, set.ID, removed)
+		if err != nil {
+			return fmt.Errorf("error removing members of validator set %v: %w", set.ID, err)
+		}
+
+		// the subscriptions of the set are removed unless another set of the user still subscribes the validator to the event
+		for _, eventName := range set.EventNames {
+			_, err = tx.Exec(`
+				DELETE FROM users_subscriptions s
+				USING UNNEST($3::bytea[]) AS removed(pubkey)
+				WHERE s.user_id = $1 AND s.event_name = $2 AND s.event_filter = ENCODE(removed.pubkey, 'hex') AND NOT EXISTS (
+					SELECT 1
+					FROM users_validator_sets_validators v
+					INNER JOIN users_validator_sets o ON o.id = v.set_id
+					WHERE o.user_id = $1 AND o.id != $4 AND v.pubkey = removed.pubkey AND $5 = ANY(o.event_names)
+				)`, set.UserID, strings.ToLower(set.Network)+":"+eventName, pq.ByteaArray(removedPubkeys), set.ID, eventName)
+			if err != nil {
+				return fmt.Errorf("error unsubscribing removed members of validator set %v from %v: %w", set.ID, eventName, err)
+			}
+		}
+	}
+
+	if len(addedIndices) > 0 {
+		_, err = tx.Exec(`
+			INSERT INTO users_validator_sets_validators (set_id, validatorindex, pubkey, added_epoch)
+			SELECT $1, UNNEST($2::int[]), UNNEST($3::bytea[]), $4
+			ON CONFLICT (set_id, validatorindex) DO NOTHING`, set.ID, addedIndices, addedPubkeys, epoch)
+		if err != nil {
+			return fmt.Errorf("error adding members of validator set %v: %w", set.ID, err)
+		}
+
+		if len(set.EventNames) > 0 {
+			_, err = tx.Exec(`
+				INSERT INTO users_validators_tags (user_id, validator_publickey, tag)
+				SELECT $1, UNNEST($2::bytea[]), $3
+				ON CONFLICT (user_id, validator_publickey, tag) DO NOTHING`, set.UserID, addedPubkeys, set.Network+":"+string(types.ValidatorTagsWatchlist))
+			if err != nil {
+				return fmt.Errorf("error adding members of validator set %v to the watchlist: %w", set.ID, err)
+			}
+		}
+		for _, eventName := range set.EventNames {
+			_, err = tx.Exec(`
+				INSERT INTO users_subscriptions (user_id, event_name, event_filter, created_ts, created_epoch, event_threshold)
+				SELECT $1, $2, ENCODE(pubkey, 'hex'), NOW(), $4, $5 FROM UNNEST($3::bytea[]) AS pubkey
+				ON CONFLICT (user_id, event_name, event_filter) DO NOTHING`, set.UserID, strings.ToLower(set.Network)+":"+eventName, addedPubkeys, epoch, set.EventThreshold)
+			if err != nil {
+				return fmt.Errorf("error subscribing members of validator set %v to %v: %w", set.ID, eventName, err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE users_validator_sets SET last_evaluated_epoch = $2 WHERE id = $1`, set.ID, epoch)
+	if err != nil {
+		return fmt.Errorf("error updating evaluation epoch of validator set %v: %w", set.ID, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	set.LastEvaluatedEpoch = sql.NullInt64{Int64: int64(epoch), Valid: true}
+	set.ValidatorCount = uint64(len(members))
+	return nil
+}
+
+// UpdateValidatorSets re-evaluates all validator sets of the current network that have not yet been evaluated at the given epoch
+func UpdateValidatorSets(epoch uint64) error {
+	start := time.Now()
+
+	sets := []*types.ValidatorSet{}
+	err := FrontendWriterDB.Select(&sets, `
+		SELECT `+validatorSetColumns+`
+		FROM users_validator_sets
+		WHERE network = $1 AND (last_evaluated_epoch IS NULL OR last_evaluated_epoch < $2)
+		ORDER BY id`, utils.GetNetwork(), epoch)
+	if err != nil {
+		return fmt.Errorf("error retrieving validator sets: %w", err)
+	}
+
+	failed := 0
+	for _, set := range sets {
+		err = UpdateValidatorSet(set, epoch)
+		if err != nil {
+			logger.WithError(err).Errorf("error updating validator set %v", set.ID)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("error updating %v of %v validator sets", failed, len(sets))
+	}
+
+	logger.WithFields(logrus.Fields{"epoch": epoch, "sets": len(sets), "duration": time.Since(start)}).Infof("updated validator sets")
+	return nil
+}
@@ -17,3 +17,12 @@ var NameChangedTopic []byte = []byte{0xb7, 0xd2, 0x9e, 0x91, 0x10, 0x41, 0xe8, 0
 
 // ce0457fe73731f824cc272376169235128c118b49d344817417c6d108d155e82
 var NewOwnerTopic []byte = []byte{0xce, 0x04, 0x57, 0xfe, 0x73, 0x73, 0x1f, 0x82, 0x4c, 0xc2, 0x72, 0x37, 0x61, 0x69, 0x23, 0x51, 0x28, 0xc1, 0x18, 0xb4, 0x9d, 0x34, 0x48, 0x17, 0x41, 0x7c, 0x6d, 0x10, 0x8d, 0x15, 0x5e, 0x82}
+
+// d8c9334b1a9c2f9da342a0a2b32629c1a229b6445dad78947f674b44444a7550
+var TextChangedTopic []byte = []byte{0xd8, 0xc9, 0x33, 0x4b, 0x1a, 0x9c, 0x2f, 0x9d, 0xa3, 0x42, 0xa0, 0xa2, 0xb3, 0x26, 0x29, 0xc1, 0xa2, 0x29, 0xb6, 0x44, 0x5d, 0xad, 0x78, 0x94, 0x7f, 0x67, 0x4b, 0x44, 0x44, 0x4a, 0x75, 0x50}
+
+// 448bc014f1536726cf8d54ff3d6481ed3cbc683c2591ca204274009afa09b1a1
+var TextChangedWithValueTopic []byte = []byte{0x44, 0x8b, 0xc0, 0x14, 0xf1, 0x53, 0x67, 0x26, 0xcf, 0x8d, 0x54, 0xff, 0x3d, 0x64, 0x81, 0xed, 0x3c, 0xbc, 0x68, 0x3c, 0x25, 0x91, 0xca, 0x20, 0x42, 0x74, 0x00, 0x9a, 0xfa, 0x09, 0xb1, 0xa1}
+
+// e379c1624ed7e714cc0937528a32359d69d5281337765313dba4e081b72d7578
+var ContenthashChangedTopic []byte = []byte{0xe3, 0x79, 0xc1, 0x62, 0x4e, 0xd7, 0xe7, 0x14, 0xcc, 0x09, 0x37, 0x52, 0x8a, 0x32, 0x35, 0x9d, 0x69, 0xd5, 0x28, 0x13, 0x37, 0x76, 0x53, 0x13, 0xdb, 0xa4, 0xe0, 0x81, 0xb7, 0x2d, 0x75, 0x78}
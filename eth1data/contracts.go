@@ -0,0 +1,339 @@
+package eth1data
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"eth2-exporter/cache"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// eip1967ImplementationSlot is the storage slot that holds the implementation address of EIP-1967 proxies
+var eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// ErrContractAbiMismatch is returned by VerifyContractAbi if the ABI does not belong to the contract
+var ErrContractAbiMismatch = errors.New("abi does not match the contract")
+
+// VerifyContractAbi checks that an ABI belongs to the contract at address: the selectors of all functions and the ids of all
+// non-anonymous events of the ABI have to be part of the deployed code of the contract and the ABI has to contain a function for
+// every selector of the function dispatcher of the code. If the contract is an EIP-1967 proxy the code of its implementation is
+// checked instead and the address of the implementation is returned.
+func VerifyContractAbi(ctx context.Context, address common.Address, contractAbi *abi.ABI) (*common.Address, error) {
+	return verifyContractAbi(ctx, rpc.CurrentErigonClient.GetNativeClient(), address, contractAbi)
+}
+
+// contractCodeReader reads the deployed code and storage of contracts at the latest block
+type contractCodeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+func verifyContractAbi(ctx context.Context, client contractCodeReader, address common.Address, contractAbi *abi.ABI) (*common.Address, error) {
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving code of contract %v: %w", address, err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%w: address %v is not a contract", ErrContractAbiMismatch, address)
+	}
+
+	mismatch := verifyAbiAgainstCode(code, contractAbi)
+	if mismatch == nil {
+		return nil, nil
+	}
+
+	slot, err := client.StorageAt(ctx, address, eip1967ImplementationSlot, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving implementation of contract %v: %w", address, err)
+	}
+	implementation := common.BytesToAddress(slot)
+	if implementation != (common.Address{}) {
+		implementationCode, err := client.CodeAt(ctx, implementation, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving code of implementation %v: %w", implementation, err)
+		}
+		mismatch = verifyAbiAgainstCode(implementationCode, contractAbi)
+		if mismatch == nil {
+			return &implementation, nil
+		}
+	}
+	return nil, mismatch
+}
+
+// verifyAbiAgainstCode returns an ErrContractAbiMismatch error if the ABI contains functions or events that are not part of the code,
+// or if the function dispatcher of the code contains selectors that are missing in the ABI
+func verifyAbiAgainstCode(code []byte, contractAbi *abi.ABI) error {
+	missing := missingAbiEntries(code, contractAbi)
+	if len(missing) > 0 {
+		return fmt.Errorf("%w, not found in the deployed code: %v", ErrContractAbiMismatch, truncateSignatures(missing))
+	}
+
+	undocumented := []string{}
+	for _, selector := range dispatcherSelectors(code) {
+		if _, err := contractAbi.MethodById(selector); err != nil {
+			undocumented = append(undocumented, fmt.Sprintf("0x%x", selector))
+		}
+	}
+	if len(undocumented) > 0 {
+		sort.Strings(undocumented)
+		return fmt.Errorf("%w, functions of the deployed code missing in the abi: %v", ErrContractAbiMismatch, truncateSignatures(undocumented))
+	}
+	return nil
+}
+
+// truncateSignatures joins the first five signatures of a list
+func truncateSignatures(signatures []string) string {
+	if len(signatures) > 5 {
+		signatures = append(signatures[:5], fmt.Sprintf("and %d more", len(signatures)-5))
+	}
+	return strings.Join(signatures, ", ")
+}
+
+// dispatcherSelectors returns the function selectors of the dispatcher of a contract compiled by solc. The dispatcher compares the
+// selector of the calldata against every function with "PUSH4 selector [DUPn] EQ PUSHn dest JUMPI", selectors with leading zero
+// bytes are pushed with a smaller PUSH opcode.
+func dispatcherSelectors(code []byte) [][]byte {
+	type instruction struct {
+		op   byte
+		data []byte
+	}
+	instructions := make([]instruction, 0, len(code))
+	for pc := 0; pc < len(code); pc++ {
+		op := code[pc]
+		ins := instruction{op: op}
+		if op >= 0x60 && op <= 0x7f {
+			size := int(op - 0x5f)
+			if pc+size >= len(code) {
+				break
+			}
+			ins.data = code[pc+1 : pc+1+size]
+			pc += size
+		}
+		instructions = append(instructions, ins)
+	}
+
+	isPush := func(op byte) bool { return op >= 0x60 && op <= 0x7f }
+	seen := map[[4]byte]bool{}
+	selectors := [][]byte{}
+	for i := 0; i+3 < len(instructions); i++ {
+		push := instructions[i]
+		if push.op < 0x60 || push.op > 0x63 {
+			continue
+		}
+		j := i + 1
+		if instructions[j].op >= 0x80 && instructions[j].op <= 0x8f {
+			j++
+		}
+		if j+2 >= len(instructions) || instructions[j].op != 0x14 || !isPush(instructions[j+1].op) || instructions[j+2].op != 0x57 {
+			continue
+		}
+		// small constants compared by regular code are not selectors
+		if push.op != 0x63 && len(bytes.TrimLeft(push.data, "\x00")) < 3 {
+			continue
+		}
+		var selector [4]byte
+		copy(selector[4-len(push.data):], push.data)
+		if !seen[selector] {
+			seen[selector] = true
+			selectors = append(selectors, selector[:])
+		}
+	}
+	return selectors
+}
+
+// missingAbiEntries returns the signatures of all functions and events of the ABI whose selector or id is not pushed by the code
+func missingAbiEntries(code []byte, contractAbi *abi.ABI) []string {
+	missing := []string{}
+	for _, method := range contractAbi.Methods {
+		// PUSH4 of the selector, the optimizer pushes selectors with leading zero bytes with a smaller PUSH opcode
+		push := append([]byte{0x63}, method.ID...)
+		selector := bytes.TrimLeft(method.ID, "\x00")
+		shortPush := append([]byte{0x5f + byte(len(selector))}, selector...)
+		if !bytes.Contains(code, push) && !bytes.Contains(code, shortPush) {
+			missing = append(missing, method.Sig)
+		}
+	}
+	for _, event := range contractAbi.Events {
+		if event.Anonymous {
+			continue
+		}
+		// PUSH32 of the event id
+		push := append([]byte{0x7f}, event.ID.Bytes()...)
+		if !bytes.Contains(code, push) {
+			missing = append(missing, event.Sig)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// DecodeCallData decodes the calldata of a call to a contract with the ABI of the contract, nil is returned if the ABI does not contain
+// the called method or the arguments can not be decoded
+func DecodeCallData(contractAbi *abi.ABI, data []byte) *types.Eth1DecodedCall {
+	if contractAbi == nil || len(data) < 4 {
+		return nil
+	}
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		logger.Warnf("error decoding arguments of method %v: %v", method.Sig, err)
+		return nil
+	}
+
+	call := &types.Eth1DecodedCall{
+		Name:      method.RawName,
+		Signature: strings.TrimPrefix(method.String(), "function "),
+		Args:      make([]*types.Eth1DecodedCallArg, 0, len(values)),
+	}
+	for i, value := range values {
+		call.Args = append(call.Args, decodedArg(method.Inputs[i], value))
+	}
+	return call
+}
+
+// DecodeEventArgs decodes the indexed arguments of an event from the topics and all other arguments from the data of a log.
+// Indexed arguments of dynamic types only contain the hash of the value.
+func DecodeEventArgs(event *abi.Event, topics [][]byte, data []byte) ([]*types.Eth1DecodedCallArg, error) {
+	values, err := event.Inputs.NonIndexed().Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding data of event %v: %w", event.Sig, err)
+	}
+
+	args := make([]*types.Eth1DecodedCallArg, 0, len(event.Inputs))
+	topic, value := 1, 0
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			args = append(args, decodedArg(input, values[value]))
+			value++
+			continue
+		}
+		if topic >= len(topics) {
+			return nil, fmt.Errorf("missing topic of argument %v of event %v", input.Name, event.Sig)
+		}
+		switch input.Type.T {
+		case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			args = append(args, decodedArg(abi.Argument{Name: input.Name, Type: abi.Type{T: abi.FixedBytesTy, Size: 32}}, common.BytesToHash(topics[topic])))
+		default:
+			decoded := map[string]interface{}{}
+			err := abi.ParseTopicsIntoMap(decoded, abi.Arguments{input}, []common.Hash{common.BytesToHash(topics[topic])})
+			if err != nil {
+				return nil, fmt.Errorf("error decoding topic of argument %v of event %v: %w", input.Name, event.Sig, err)
+			}
+			args = append(args, decodedArg(input, decoded[input.Name]))
+		}
+		topic++
+	}
+	return args, nil
+}
+
+// decodedArg formats a decoded value of an abi argument, addresses and integers are formatted as such, all byte types as hex
+func decodedArg(input abi.Argument, value interface{}) *types.Eth1DecodedCallArg {
+	arg := &types.Eth1DecodedCallArg{
+		Name:  input.Name,
+		Type:  input.Type.String(),
+		Raw:   fmt.Sprintf("0x%x", value),
+		Value: fmt.Sprintf("%v", value),
+	}
+	switch v := value.(type) {
+	case common.Address:
+		arg.Address = v
+		arg.Value = v.Hex()
+	case *big.Int:
+		arg.Value = v.String()
+	}
+	if strings.HasPrefix(arg.Type, "byte") {
+		arg.Value = arg.Raw
+	}
+	return arg
+}
+
+// newContractMetadataGetter returns a function that retrieves the metadata of the contracts involved in a tx, each contract is only
+// retrieved once and the metadata is nil for contracts without a known ABI
+func newContractMetadataGetter() func(common.Address) *types.ContractMetadata {
+	contractMetadataCache := make(map[common.Address]*types.ContractMetadata)
+	return func(address common.Address) *types.ContractMetadata {
+		meta, ok := contractMetadataCache[address]
+		if !ok {
+			var err error
+			meta, err = db.BigtableClient.GetContractMetadata(address.Bytes())
+			if err != nil || meta == nil || meta.ABI == nil {
+				meta = nil
+			}
+			contractMetadataCache[address] = meta
+		}
+		return meta
+	}
+}
+
+// GetEth1TransactionInternalCalls traces a tx and returns its internal calls, the calldata of calls to contracts with a known ABI is decoded.
+// The internal calls are cached like the tx itself so that a tx is only traced once.
+func GetEth1TransactionInternalCalls(hash common.Hash) ([]*types.Eth1InternalCall, error) {
+	cacheKey := fmt.Sprintf("%d:txInternalCalls:%s", utils.Config.Chain.Config.DepositChainID, hash.String())
+	if wanted, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Hour, new([]*types.Eth1InternalCall)); err == nil {
+		return *wanted.(*[]*types.Eth1InternalCall), nil
+	}
+
+	data, err := rpc.CurrentErigonClient.TraceParityTx(hash.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving parity trace of tx %v: %w", hash, err)
+	}
+	calls := getInternalCalls(data, newContractMetadataGetter())
+
+	err = cache.TieredCache.Set(cacheKey, calls, time.Hour*24)
+	if err != nil {
+		logger.Errorf("error caching internal calls of tx %v: %v", hash, err)
+	}
+	return calls, nil
+}
+
+// getInternalCalls returns all calls of the transaction except the top level call, the calldata of calls to contracts with a known ABI is decoded.
+// The method of all other calls is labeled by its 4-byte signature.
+func getInternalCalls(traces []*rpc.ParityTraceResult, getContractMetadata func(common.Address) *types.ContractMetadata) []*types.Eth1InternalCall {
+	calls := make([]*types.Eth1InternalCall, 0, len(traces))
+	for _, trace := range traces {
+		if trace.Type != "call" || len(trace.TraceAddress) == 0 {
+			continue
+		}
+
+		call := &types.Eth1InternalCall{
+			TraceAddress: trace.TraceAddress,
+			CallType:     trace.Action.CallType,
+			From:         common.HexToAddress(trace.Action.From),
+			To:           common.HexToAddress(trace.Action.To),
+			Input:        common.FromHex(trace.Action.Input),
+			Error:        trace.Error,
+		}
+		if value, err := hexutil.DecodeBig(trace.Action.Value); err == nil {
+			call.Value = value.Bytes()
+		}
+		if len(call.Input) >= 4 {
+			if meta := getContractMetadata(call.To); meta != nil {
+				call.DecodedCall = DecodeCallData(meta.ABI, call.Input)
+			}
+			if call.DecodedCall != nil {
+				call.Method = call.DecodedCall.Name
+			} else {
+				call.Method = db.BigtableClient.GetMethodLabel(call.Input[:4], true)
+			}
+		} else {
+			call.Method = db.BigtableClient.GetMethodLabel(call.Input, false)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
@@ -0,0 +1,225 @@
+package eth1data
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testContractAbi = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Marker","anonymous":true,"inputs":[]}
+]`
+
+func parseTestAbi(t *testing.T) *abi.ABI {
+	t.Helper()
+	contractAbi, err := abi.JSON(strings.NewReader(testContractAbi))
+	if err != nil {
+		t.Fatalf("error parsing abi: %v", err)
+	}
+	return &contractAbi
+}
+
+// dispatch returns the dispatcher comparison of a selector as emitted by solc: [DUP1] PUSHn selector EQ PUSH2 dest JUMPI
+func dispatch(selector []byte, dup bool) []byte {
+	code := []byte{}
+	if dup {
+		code = append(code, 0x80)
+	}
+	trimmed := bytes.TrimLeft(selector, "\x00")
+	code = append(code, 0x5f+byte(len(trimmed)))
+	code = append(code, trimmed...)
+	return append(code, 0x14, 0x61, 0x00, 0x40, 0x57)
+}
+
+// testContractCode returns code that dispatches the selectors and emits the events
+func testContractCode(selectors [][]byte, events []common.Hash) []byte {
+	// PUSH1 0 CALLDATALOAD PUSH1 0xe0 SHR, the selector of the calldata
+	code := []byte{0x60, 0x00, 0x35, 0x60, 0xe0, 0x1c}
+	for _, selector := range selectors {
+		code = append(code, dispatch(selector, true)...)
+	}
+	for _, event := range events {
+		code = append(code, 0x7f)
+		code = append(code, event.Bytes()...)
+		code = append(code, 0xa3)
+	}
+	return code
+}
+
+func TestDispatcherSelectors(t *testing.T) {
+	selector := []byte{0xa9, 0x05, 0x9c, 0xbb}
+	pushData := append([]byte{0x7f}, common.LeftPadBytes(dispatch(selector, false), 32)...)
+
+	tests := []struct {
+		name      string
+		code      []byte
+		selectors [][]byte
+	}{
+		{"with dup", dispatch(selector, true), [][]byte{selector}},
+		{"without dup", dispatch(selector, false), [][]byte{selector}},
+		{"leading zero byte", dispatch([]byte{0x00, 0x12, 0x34, 0x56}, true), [][]byte{{0x00, 0x12, 0x34, 0x56}}},
+		{"small constant", []byte{0x80, 0x60, 0x05, 0x14, 0x61, 0x00, 0x40, 0x57}, [][]byte{}},
+		{"duplicate", append(dispatch(selector, true), dispatch(selector, false)...), [][]byte{selector}},
+		{"multiple", append(dispatch(selector, true), dispatch([]byte{0x70, 0xa0, 0x82, 0x31}, true)...), [][]byte{selector, {0x70, 0xa0, 0x82, 0x31}}},
+		{"inside push data", pushData, [][]byte{}},
+		{"no jump", []byte{0x63, 0xa9, 0x05, 0x9c, 0xbb, 0x14, 0x61, 0x00, 0x40, 0x00}, [][]byte{}},
+		{"truncated push", []byte{0x63, 0xa9, 0x05}, [][]byte{}},
+		{"empty", []byte{}, [][]byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectors := dispatcherSelectors(tt.code)
+			if !reflect.DeepEqual(selectors, tt.selectors) {
+				t.Errorf("expected selectors %x, got %x", tt.selectors, selectors)
+			}
+		})
+	}
+}
+
+func TestMissingAbiEntries(t *testing.T) {
+	contractAbi := parseTestAbi(t)
+	transfer := contractAbi.Methods["transfer"].ID
+	balanceOf := contractAbi.Methods["balanceOf"].ID
+	transferEvent := contractAbi.Events["Transfer"].ID
+
+	zeroAbi := parseTestAbi(t)
+	zeroAbi.Methods["zero"] = abi.Method{Name: "zero", RawName: "zero", Sig: "zero()", ID: []byte{0x00, 0x12, 0x34, 0x56}}
+
+	tests := []struct {
+		name        string
+		contractAbi *abi.ABI
+		code        []byte
+		missing     []string
+	}{
+		{"complete", contractAbi, testContractCode([][]byte{transfer, balanceOf}, []common.Hash{transferEvent}), []string{}},
+		{"missing function", contractAbi, testContractCode([][]byte{transfer}, []common.Hash{transferEvent}), []string{"balanceOf(address)"}},
+		{"missing event", contractAbi, testContractCode([][]byte{transfer, balanceOf}, nil), []string{"Transfer(address,address,uint256)"}},
+		{"no code", contractAbi, []byte{}, []string{"Transfer(address,address,uint256)", "balanceOf(address)", "transfer(address,uint256)"}},
+		{"selector with leading zero byte", zeroAbi, testContractCode([][]byte{transfer, balanceOf, {0x00, 0x12, 0x34, 0x56}}, []common.Hash{transferEvent}), []string{}},
+		{"missing selector with leading zero byte", zeroAbi, testContractCode([][]byte{transfer, balanceOf}, []common.Hash{transferEvent}), []string{"zero()"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing := missingAbiEntries(tt.code, tt.contractAbi)
+			if !reflect.DeepEqual(missing, tt.missing) {
+				t.Errorf("expected missing entries %v, got %v", tt.missing, missing)
+			}
+		})
+	}
+}
+
+type testCodeReader struct {
+	code           map[common.Address][]byte
+	implementation map[common.Address]common.Address
+	err            error
+}
+
+func (reader *testCodeReader) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return reader.code[account], reader.err
+}
+
+func (reader *testCodeReader) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if key != eip1967ImplementationSlot {
+		return make([]byte, 32), nil
+	}
+	return common.LeftPadBytes(reader.implementation[account].Bytes(), 32), nil
+}
+
+func TestVerifyContractAbi(t *testing.T) {
+	contractAbi := parseTestAbi(t)
+	selectors := [][]byte{contractAbi.Methods["transfer"].ID, contractAbi.Methods["balanceOf"].ID}
+	events := []common.Hash{contractAbi.Events["Transfer"].ID}
+	undocumented := append(selectors, []byte{0x18, 0x16, 0x0d, 0xdd})
+
+	contract := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	proxy := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	implementation := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	proxyCode := []byte{0x36, 0x3d, 0x3d, 0x37, 0x3d, 0x3d, 0x3d, 0x36, 0x3d, 0xf4}
+
+	tests := []struct {
+		name           string
+		reader         *testCodeReader
+		address        common.Address
+		implementation *common.Address
+		mismatch       bool
+		err            bool
+	}{
+		{
+			name:    "matching contract",
+			reader:  &testCodeReader{code: map[common.Address][]byte{contract: testContractCode(selectors, events)}},
+			address: contract,
+		},
+		{
+			name:     "not a contract",
+			reader:   &testCodeReader{code: map[common.Address][]byte{}},
+			address:  contract,
+			mismatch: true,
+		},
+		{
+			name:     "function missing in the abi",
+			reader:   &testCodeReader{code: map[common.Address][]byte{contract: testContractCode(undocumented, events)}},
+			address:  contract,
+			mismatch: true,
+		},
+		{
+			name:     "event missing in the code",
+			reader:   &testCodeReader{code: map[common.Address][]byte{contract: testContractCode(selectors, nil)}},
+			address:  contract,
+			mismatch: true,
+		},
+		{
+			name: "proxy",
+			reader: &testCodeReader{
+				code:           map[common.Address][]byte{proxy: proxyCode, implementation: testContractCode(selectors, events)},
+				implementation: map[common.Address]common.Address{proxy: implementation},
+			},
+			address:        proxy,
+			implementation: &implementation,
+		},
+		{
+			name: "proxy with mismatching implementation",
+			reader: &testCodeReader{
+				code:           map[common.Address][]byte{proxy: proxyCode, implementation: testContractCode(undocumented, events)},
+				implementation: map[common.Address]common.Address{proxy: implementation},
+			},
+			address:  proxy,
+			mismatch: true,
+		},
+		{
+			name:    "node error",
+			reader:  &testCodeReader{err: errors.New("connection refused")},
+			address: contract,
+			err:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			implementation, err := verifyContractAbi(context.Background(), tt.reader, tt.address, contractAbi)
+			if tt.mismatch || tt.err {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				if errors.Is(err, ErrContractAbiMismatch) != tt.mismatch {
+					t.Errorf("expected mismatch %v, got error %v", tt.mismatch, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(implementation, tt.implementation) {
+				t.Errorf("expected implementation %v, got %v", tt.implementation, implementation)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package eth1data
+
+import (
+	"database/sql"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	go_ens "github.com/wealdtech/go-ens/v3"
+)
+
+// ErrEnsNameExpired is returned by ResolveEnsName for indexed names that are expired
+var ErrEnsNameExpired = errors.New("ens name is expired")
+
+// ResolveEnsName returns the address of an ens name. Names are resolved via the local ens index, which follows changes of the address records.
+// Names that are not indexed yet are resolved via the node and queued for indexing.
+func ResolveEnsName(name string) (common.Address, error) {
+	records, err := db.GetEnsRecords(name)
+	if err == nil {
+		if records.Status != types.EnsNameStatusActive {
+			return common.Address{}, fmt.Errorf("%w: %v", ErrEnsNameExpired, name)
+		}
+		return common.BytesToAddress(records.Address), nil
+	}
+	if err != sql.ErrNoRows {
+		return common.Address{}, fmt.Errorf("error retrieving ens records of %v: %w", name, err)
+	}
+
+	if rpc.CurrentErigonClient == nil {
+		return common.Address{}, fmt.Errorf("ens name %v is not indexed", name)
+	}
+	address, err := go_ens.Resolve(rpc.CurrentErigonClient.GetNativeClient(), name)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error resolving ens name %v: %w", name, err)
+	}
+
+	if db.BigtableClient != nil {
+		err = db.BigtableClient.QueueEnsNameUpdate(name)
+		if err != nil {
+			logger.WithError(err).Warnf("error queuing ens name %v for indexing", name)
+		}
+	}
+	return address, nil
+}
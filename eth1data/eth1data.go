@@ -122,6 +122,19 @@ func GetEth1Transaction(hash common.Hash) (*types.Eth1TxData, error) {
 		txPageData.Gas.TxFee = msg.GasFeeCap().Mul(msg.GasFeeCap(), big.NewInt(int64(receipt.GasUsed))).Bytes()
 	}
 
+	getContractMetadata := newContractMetadataGetter()
+
+	if txPageData.TargetIsContract && !txPageData.IsContractCreation && len(tx.Data()) >= 4 {
+		if meta := getContractMetadata(*txPageData.To); meta != nil {
+			txPageData.DecodedCallData = DecodeCallData(meta.ABI, tx.Data())
+		}
+		if txPageData.DecodedCallData != nil {
+			txPageData.Method = txPageData.DecodedCallData.Name
+		} else {
+			txPageData.Method = db.BigtableClient.GetMethodLabel(tx.Data()[:4], true)
+		}
+	}
+
 	if receipt.Status != 1 {
 		data, err := rpc.CurrentErigonClient.TraceParityTx(tx.Hash().Hex())
 		if err != nil {
@@ -131,6 +144,15 @@ func GetEth1Transaction(hash common.Hash) (*types.Eth1TxData, error) {
 		if err == nil {
 			txPageData.ErrorMsg = errorMsg
 		}
+		txPageData.InternalCalls = getInternalCalls(data, getContractMetadata)
+	} else if txPageData.DecodedCallData != nil {
+		// tracing is expensive, successful txs are only traced for their internal calls if the called contract has a known ABI
+		data, err := rpc.CurrentErigonClient.TraceParityTx(tx.Hash().Hex())
+		if err != nil {
+			logger.Warnf("error retrieving parity trace of tx %v for internal calls: %v", hash, err)
+		} else {
+			txPageData.InternalCalls = getInternalCalls(data, getContractMetadata)
+		}
 	}
 	if receipt.Status == 1 {
 		txPageData.Transfers, err = db.BigtableClient.GetArbitraryTokenTransfersForTransaction(tx.Hash().Bytes())
@@ -154,20 +176,9 @@ func GetEth1Transaction(hash common.Hash) (*types.Eth1TxData, error) {
 	}
 
 	if len(receipt.Logs) > 0 {
-		var wasContractMetadataCached bool
-		type contractMetadataMapEntry struct {
-			err  error
-			meta *types.ContractMetadata
-		}
-		var cmEntry contractMetadataMapEntry
-		contractMetadataCache := make(map[common.Address]contractMetadataMapEntry)
-
 		for _, log := range receipt.Logs {
-			if cmEntry, wasContractMetadataCached = contractMetadataCache[log.Address]; !wasContractMetadataCached {
-				cmEntry.meta, cmEntry.err = db.BigtableClient.GetContractMetadata(log.Address.Bytes())
-				contractMetadataCache[log.Address] = cmEntry
-			}
-			if cmEntry.err != nil || cmEntry.meta == nil || cmEntry.meta.ABI == nil {
+			meta := getContractMetadata(log.Address)
+			if meta == nil {
 				name := ""
 				if len(log.Topics) > 0 {
 					name = db.BigtableClient.GetEventLabel(log.Topics[0][:])
@@ -181,9 +192,9 @@ func GetEth1Transaction(hash common.Hash) (*types.Eth1TxData, error) {
 
 				txPageData.Events = append(txPageData.Events, eth1Event)
 			} else {
-				boundContract := bind.NewBoundContract(*txPageData.To, *cmEntry.meta.ABI, nil, nil, nil)
+				boundContract := bind.NewBoundContract(*txPageData.To, *meta.ABI, nil, nil, nil)
 
-				for name, event := range cmEntry.meta.ABI.Events {
+				for name, event := range meta.ABI.Events {
 					if log != nil && len(log.Topics) > 0 && bytes.Equal(event.ID.Bytes(), log.Topics[0].Bytes()) {
 						logData := make(map[string]interface{})
 						err := boundContract.UnpackLogIntoMap(logData, name, *log)
@@ -200,7 +211,7 @@ func GetEth1Transaction(hash common.Hash) (*types.Eth1TxData, error) {
 							DecodedData: map[string]types.Eth1DecodedEventData{},
 						}
 						typeMap := make(map[string]string)
-						for _, input := range cmEntry.meta.ABI.Events[name].Inputs {
+						for _, input := range meta.ABI.Events[name].Inputs {
 							typeMap[input.Name] = input.Type.String()
 						}
 
@@ -0,0 +1,88 @@
+package eth1data
+
+import (
+	"context"
+	"eth2-exporter/erc1155"
+	"eth2-exporter/erc721"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NftMetadataFetcher is used to retrieve the metadata of nfts, it defaults to an utils.HttpNftMetadataFetcher configured by Frontend.NftMetadata
+var NftMetadataFetcher utils.NftMetadataFetcher
+var nftMetadataFetcherOnce sync.Once
+
+func nftMetadataFetcher() utils.NftMetadataFetcher {
+	nftMetadataFetcherOnce.Do(func() {
+		if NftMetadataFetcher == nil {
+			NftMetadataFetcher = utils.NewHttpNftMetadataFetcher(utils.Config.Frontend.NftMetadata.IpfsGateway, utils.Config.Frontend.NftMetadata.Timeout)
+		}
+	})
+	return NftMetadataFetcher
+}
+
+// GetNftCollectionName returns the name and symbol of an ERC-721 collection, ERC-1155 collections do not have a name
+func GetNftCollectionName(ctx context.Context, standard string, token common.Address) (string, string, error) {
+	if standard != types.NftStandardERC721 {
+		return "", "", nil
+	}
+	caller, err := erc721.NewErc721Caller(token, rpc.CurrentErigonClient.GetNativeClient())
+	if err != nil {
+		return "", "", err
+	}
+	name, err := caller.Name(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return "", "", fmt.Errorf("error retrieving name of nft collection %v: %w", token, err)
+	}
+	symbol, err := caller.Symbol(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return "", "", fmt.Errorf("error retrieving symbol of nft collection %v: %w", token, err)
+	}
+	return name, symbol, nil
+}
+
+// GetNftMetadataUri returns the metadata uri of a token id, the tokenURI for ERC-721 and the uri with substituted id for ERC-1155 collections
+func GetNftMetadataUri(ctx context.Context, standard string, token common.Address, tokenId *big.Int) (string, error) {
+	switch standard {
+	case types.NftStandardERC721:
+		caller, err := erc721.NewErc721Caller(token, rpc.CurrentErigonClient.GetNativeClient())
+		if err != nil {
+			return "", err
+		}
+		return caller.TokenURI(&bind.CallOpts{Context: ctx}, tokenId)
+	case types.NftStandardERC1155:
+		caller, err := erc1155.NewErc1155Caller(token, rpc.CurrentErigonClient.GetNativeClient())
+		if err != nil {
+			return "", err
+		}
+		uri, err := caller.Uri(&bind.CallOpts{Context: ctx}, tokenId)
+		if err != nil {
+			return "", err
+		}
+		return utils.NftTokenUri(uri, tokenId), nil
+	}
+	return "", fmt.Errorf("unknown nft standard %v", standard)
+}
+
+// GetNftMetadata retrieves the metadata uri of a token id from the node and fetches the metadata it points to
+func GetNftMetadata(ctx context.Context, standard string, token common.Address, tokenId *big.Int) (string, *types.NftMetadata, error) {
+	uri, err := GetNftMetadataUri(ctx, standard, token, tokenId)
+	if err != nil {
+		return "", nil, fmt.Errorf("error retrieving metadata uri of nft %v %v: %w", token, tokenId, err)
+	}
+	if uri == "" {
+		return "", nil, nil
+	}
+	metadata, err := nftMetadataFetcher().FetchNftMetadata(ctx, uri)
+	if err != nil {
+		return uri, nil, err
+	}
+	return uri, metadata, nil
+}
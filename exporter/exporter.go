@@ -35,6 +35,7 @@ func Start(client rpc.Client) error {
 	go genesisDepositsExporter()
 	go checkSubscriptions()
 	go syncCommitteesExporter(client)
+	go upcomingProposerDutiesExporter(client)
 	go syncCommitteesCountExporter()
 	if utils.Config.SSVExporter.Enabled {
 		go ssvExporter()
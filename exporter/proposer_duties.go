@@ -0,0 +1,57 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// upcomingProposerDutiesExporter persists the proposer duties of the next epoch so that upcoming proposals can be shown and notified one epoch ahead
+func upcomingProposerDutiesExporter(client rpc.Client) {
+	lastExportedSlot := uint64(0)
+	for {
+		t0 := time.Now()
+		slot, err := exportUpcomingProposerDuties(client, lastExportedSlot)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting upcoming proposer duties")
+		} else {
+			lastExportedSlot = slot
+		}
+		time.Sleep(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot))
+	}
+}
+
+// exportUpcomingProposerDuties saves the proposer duties of the epoch following the head epoch and returns the head slot the duties were saved at.
+// The duties are saved once the head enters a new epoch and are refreshed at the last slot of the epoch as they can still change until the epoch transition.
+func exportUpcomingProposerDuties(client rpc.Client, lastExportedSlot uint64) (uint64, error) {
+	head, err := client.GetChainHead()
+	if err != nil {
+		return lastExportedSlot, fmt.Errorf("error retrieving chain head: %w", err)
+	}
+
+	isLastSlotOfEpoch := (head.HeadSlot+1)%utils.Config.Chain.Config.SlotsPerEpoch == 0
+	if head.HeadSlot == lastExportedSlot || (utils.EpochOfSlot(lastExportedSlot) == head.HeadEpoch && !isLastSlotOfEpoch && lastExportedSlot != 0) {
+		return lastExportedSlot, nil
+	}
+
+	epoch := head.HeadEpoch + 1
+	assignments, err := client.GetEpochAssignments(epoch)
+	if err != nil {
+		return lastExportedSlot, fmt.Errorf("error retrieving assignments for epoch %v: %w", epoch, err)
+	}
+	if len(assignments.ProposerAssignments) == 0 {
+		return lastExportedSlot, nil
+	}
+
+	err = db.SaveUpcomingProposalAssignments(epoch, assignments.ProposerAssignments)
+	if err != nil {
+		return lastExportedSlot, fmt.Errorf("error saving proposal assignments of epoch %v: %w", epoch, err)
+	}
+
+	logger.WithFields(logrus.Fields{"epoch": epoch, "slot": head.HeadSlot}).Infof("exported upcoming proposer duties")
+	return head.HeadSlot, nil
+}
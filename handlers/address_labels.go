@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+const maxAddressLabelSets = 20
+const maxAddressLabelsPerImport = 100000
+const maxAddressLabelImportSize = 10 << 20
+
+// UserAddressLabelSets godoc
+// @Summary Get the address label sets of the user and of the organisations the user is a member of
+// @Tags User
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiAddressLabelSet}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/labelsets [get]
+func UserAddressLabelSets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	sets, err := db.GetAddressLabelSets(user.UserID)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving address label sets of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiAddressLabelSet, 0, len(sets))
+	for _, set := range sets {
+		data = append(data, toApiAddressLabelSet(set))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserAddressLabelSet godoc
+// @Summary Get all labels of an address label set of the user or of one of its organisations
+// @Tags User
+// @Produce json
+// @Param setId path int true "Id of the address label set"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiAddressLabel}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/labelsets/{setId} [get]
+func UserAddressLabelSet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	set, ok := getUserAddressLabelSet(w, r, user.UserID)
+	if !ok {
+		return
+	}
+	labels, err := db.GetAddressLabelSetLabels(set.ID)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+
+	data := make([]*types.ApiAddressLabel, 0, len(labels))
+	for _, label := range labels {
+		data = append(data, toApiAddressLabel(label))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserAddressLabelSetCreate godoc
+// @Summary Create an address label set that is private to the user or shared with the members of an organisation
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param set body types.ApiAddressLabelSetCreateRequest true "The name and source of the set and optionally the organisation it is shared with"
+// @Success 200 {object} types.ApiResponse{data=types.ApiAddressLabelSet}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/labelsets [post]
+func UserAddressLabelSetCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	req := types.ApiAddressLabelSetCreateRequest{}
+	if !parseUserDashboardBody(w, r, &req) {
+		return
+	}
+	name, ok := validateUserDashboardName(w, r, req.Name)
+	if !ok {
+		return
+	}
+	source := strings.TrimSpace(req.Source)
+	if len(source) > 100 {
+		sendErrorResponse(w, r.URL.String(), "the source must be at most 100 characters long")
+		return
+	}
+
+	set := &types.AddressLabelSet{
+		Name:   name,
+		Source: source,
+		UserID: sql.NullInt64{Int64: int64(user.UserID), Valid: true},
+	}
+	if req.OrganisationID != 0 {
+		if !isOrganisationMember(w, r, user.UserID, req.OrganisationID) {
+			return
+		}
+		set.UserID = sql.NullInt64{}
+		set.OrganisationID = sql.NullInt64{Int64: int64(req.OrganisationID), Valid: true}
+	}
+
+	sets, err := db.GetAddressLabelSets(user.UserID)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+	if len(sets) >= maxAddressLabelSets {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("only a maximum of %d address label sets are allowed", maxAddressLabelSets))
+		return
+	}
+
+	err = db.CreateAddressLabelSet(set)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiAddressLabelSet(set)})
+}
+
+// UserAddressLabelSetImport godoc
+// @Summary Import address labels into an address label set from a csv or json list. Csv lists have the header address,name,category,tags,confidence where tags are separated by semicolons, json lists are an array of objects with these keys where tags is an array. Only the address and name are required, the category is one of exchange, bridge, mev_bot or staking_pool and the confidence is between 0 and 1 (default 1).
+// @Tags User
+// @Accept plain
+// @Produce json
+// @Param setId path int true "Id of the address label set"
+// @Param format query string false "csv (default) or json"
+// @Param replace query bool false "remove all existing labels of the set before the import"
+// @Param labels body string true "The address label list"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/labelsets/{setId}/import [post]
+func UserAddressLabelSetImport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	q := r.URL.Query()
+
+	set, ok := getUserAddressLabelSet(w, r, user.UserID)
+	if !ok {
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	labels, err := utils.ParseAddressLabels(io.LimitReader(r.Body, maxAddressLabelImportSize), format)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if len(labels) > maxAddressLabelsPerImport {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("only a maximum of %d labels can be imported at once", maxAddressLabelsPerImport))
+		return
+	}
+
+	err = db.ImportAddressLabels(set.ID, labels, q.Get("replace") == "true")
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{map[string]int{"imported": len(labels)}})
+}
+
+// UserAddressLabelSetDelete godoc
+// @Summary Delete an address label set including all of its labels
+// @Tags User
+// @Produce json
+// @Param setId path int true "Id of the address label set"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/labelsets/{setId}/delete [post]
+func UserAddressLabelSetDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	setID, err := strconv.ParseUint(mux.Vars(r)["setId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid address label set id")
+		return
+	}
+
+	err = db.DeleteAddressLabelSet(user.UserID, setID)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserOrganisations godoc
+// @Summary Get the organisations the user is a member of
+// @Tags User
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiOrganisation}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/organisations [get]
+func UserOrganisations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	organisations, err := db.GetOrganisations(user.UserID)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+
+	data := make([]*types.ApiOrganisation, 0, len(organisations))
+	for _, organisation := range organisations {
+		data = append(data, toApiOrganisation(organisation, user.UserID))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserOrganisationCreate godoc
+// @Summary Create an organisation, address label sets of the organisation are shared with all of its members
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param organisation body types.ApiUserDashboardRequest true "The name of the organisation"
+// @Success 200 {object} types.ApiResponse{data=types.ApiOrganisation}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/organisations [post]
+func UserOrganisationCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	name, ok := parseUserDashboardName(w, r, &types.ApiUserDashboardRequest{})
+	if !ok {
+		return
+	}
+
+	organisation, err := db.CreateOrganisation(user.UserID, name)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiOrganisation(organisation, user.UserID)})
+}
+
+// UserOrganisationMemberAdd godoc
+// @Summary Add a user to an organisation by its email address, only the owner of the organisation can add members
+// @Tags User
+// @Description The response is the same whether or not a user with the email address exists.
+// @Accept json
+// @Produce json
+// @Param organisationId path int true "Id of the organisation"
+// @Param member body types.ApiOrganisationMemberRequest true "The email address of the user"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/organisations/{organisationId}/members [post]
+func UserOrganisationMemberAdd(w http.ResponseWriter, r *http.Request) {
+	updateUserOrganisationMember(w, r, db.AddOrganisationMember)
+}
+
+// UserOrganisationMemberRemove godoc
+// @Summary Remove a user from an organisation by its email address, only the owner of the organisation can remove members
+// @Tags User
+// @Description The response is the same whether or not a user with the email address exists.
+// @Accept json
+// @Produce json
+// @Param organisationId path int true "Id of the organisation"
+// @Param member body types.ApiOrganisationMemberRequest true "The email address of the user"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/organisations/{organisationId}/members/remove [post]
+func UserOrganisationMemberRemove(w http.ResponseWriter, r *http.Request) {
+	updateUserOrganisationMember(w, r, db.RemoveOrganisationMember)
+}
+
+func updateUserOrganisationMember(w http.ResponseWriter, r *http.Request, update func(ownerID, organisationID uint64, email string) error) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	organisationID, err := strconv.ParseUint(mux.Vars(r)["organisationId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid organisation id")
+		return
+	}
+	req := types.ApiOrganisationMemberRequest{}
+	if !parseUserDashboardBody(w, r, &req) {
+		return
+	}
+
+	err = update(user.UserID, organisationID, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// ApiEth1AddressLabels godoc
+// @Summary Gets the labels of an ethereum address.
+// @Tags Execution
+// @Description Returns the community labels of an ethereum address, the private labels of the user and its organisations are included for authenticated users.
+// @Produce json
+// @Param address path string true "provide an ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiAddressLabel}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/labels [get]
+func ApiEth1AddressLabels(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	address := strings.ToLower(strings.Replace(ReplaceEnsNameWithAddress(mux.Vars(r)["address"]), "0x", "", -1))
+	if !utils.IsEth1Address(address) {
+		sendErrorResponse(w, r.URL.String(), "error invalid address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+	addressBytes := common.FromHex(address)
+
+	labels, err := db.GetAddressLabels(getUser(r).UserID, [][]byte{addressBytes})
+	if err != nil {
+		logger.Errorf("error retrieving labels for address: %v route: %v err: %v", address, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get labels for address")
+		return
+	}
+
+	data := make([]interface{}, 0, len(labels[string(addressBytes)]))
+	for _, label := range labels[string(addressBytes)] {
+		data = append(data, toApiAddressLabel(label))
+	}
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), data)
+}
+
+// getUserAddressLabels returns the labels of the addresses visible to the user of the request, errors are logged and result in no labels
+func getUserAddressLabels(r *http.Request, addresses ...[]byte) map[string][]*types.AddressLabel {
+	labels, err := db.GetAddressLabels(getUser(r).UserID, addresses)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving address labels")
+		return map[string][]*types.AddressLabel{}
+	}
+	return labels
+}
+
+func getUserAddressLabelSet(w http.ResponseWriter, r *http.Request, userID uint64) (*types.AddressLabelSet, bool) {
+	setID, err := strconv.ParseUint(mux.Vars(r)["setId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid address label set id")
+		return nil, false
+	}
+	set, err := db.GetAddressLabelSet(userID, setID)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return nil, false
+	}
+	return set, true
+}
+
+func isOrganisationMember(w http.ResponseWriter, r *http.Request, userID, organisationID uint64) bool {
+	organisations, err := db.GetOrganisations(userID)
+	if err != nil {
+		handleAddressLabelError(w, r, err)
+		return false
+	}
+	for _, organisation := range organisations {
+		if organisation.ID == organisationID {
+			return true
+		}
+	}
+	handleAddressLabelError(w, r, db.ErrOrganisationNotFound)
+	return false
+}
+
+func toApiAddressLabelSet(set *types.AddressLabelSet) *types.ApiAddressLabelSet {
+	res := &types.ApiAddressLabelSet{
+		ID:         set.ID,
+		Name:       set.Name,
+		Source:     set.Source,
+		LabelCount: set.LabelCount,
+		CreatedTs:  set.CreatedTs.Unix(),
+	}
+	if set.OrganisationID.Valid {
+		organisationID := uint64(set.OrganisationID.Int64)
+		res.OrganisationID = &organisationID
+	}
+	return res
+}
+
+func toApiAddressLabel(label *types.AddressLabel) *types.ApiAddressLabel {
+	return &types.ApiAddressLabel{
+		Address:    fmt.Sprintf("0x%x", label.Address),
+		Name:       label.Name,
+		Category:   string(label.Category),
+		Tags:       label.Tags,
+		Confidence: label.Confidence,
+		Source:     label.Source,
+		Private:    label.Private,
+	}
+}
+
+func toApiOrganisation(organisation *types.Organisation, userID uint64) *types.ApiOrganisation {
+	return &types.ApiOrganisation{
+		ID:        organisation.ID,
+		Name:      organisation.Name,
+		Owner:     organisation.OwnerID == userID,
+		Members:   organisation.Members,
+		CreatedTs: organisation.CreatedTs.Unix(),
+	}
+}
+
+func handleAddressLabelError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, db.ErrAddressLabelSetNotFound) || errors.Is(err, db.ErrOrganisationNotFound) {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	logger.WithError(err).Errorf("error accessing address labels")
+	sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+}
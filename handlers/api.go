@@ -753,6 +753,162 @@ func ApiValidatorQueue(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, w, r)
 }
 
+// ApiValidatorQueueSimulation godoc
+// @Summary Simulate the activation and exit queue
+// @Tags Validator
+// @Description Projects when the given number of validators would exit and become withdrawable if they initiated their exit now, and when the given number of new validators joining the end of the activation queue now would be activated. The projection uses the churn limit of the chain config and assumes that the number of active validators stays constant.
+// @Produce  json
+// @Param  exits query int false "Number of validators that initiate their exit now"
+// @Param  activations query int false "Number of validators that join the activation queue now"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorQueueSimulationResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validators/queue/simulate [get]
+func ApiValidatorQueueSimulation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	var exits, activations uint64
+	var err error
+	if q.Get("exits") != "" {
+		exits, err = strconv.ParseUint(q.Get("exits"), 10, 64)
+		if err != nil || exits > 1_000_000 {
+			sendErrorResponse(w, r.URL.String(), "invalid exits parameter")
+			return
+		}
+	}
+	if q.Get("activations") != "" {
+		activations, err = strconv.ParseUint(q.Get("activations"), 10, 64)
+		if err != nil || activations > 1_000_000 {
+			sendErrorResponse(w, r.URL.String(), "invalid activations parameter")
+			return
+		}
+	}
+
+	state := services.GetLatestStats().ChurnQueueState
+	if state == nil {
+		sendServerErrorResponse(w, r.URL.String(), "queue state is not available yet")
+		return
+	}
+
+	data := &types.ApiValidatorQueueSimulationResponse{
+		State:                state,
+		ChurnLimit:           utils.GetValidatorChurnLimit(state.ActiveValidatorCount),
+		ActivationChurnLimit: utils.GetValidatorActivationChurnLimit(state.ActiveValidatorCount, state.Epoch),
+		Exits:                exits,
+		Activations:          activations,
+	}
+	if exits > 0 {
+		data.FirstExitEpoch, data.LastExitEpoch = utils.ProjectExitEpochRange(state, exits)
+		data.LastExitTs = utils.EpochToTime(data.LastExitEpoch).Unix()
+		data.LastWithdrawableEpoch = data.LastExitEpoch + utils.Config.Chain.Config.MinValidatorWithdrawabilityDelay
+		data.LastWithdrawableTs = utils.EpochToTime(data.LastWithdrawableEpoch).Unix()
+	}
+	if activations > 0 {
+		data.LastActivationEpoch = utils.ProjectActivationEpoch(state, state.ActivationQueueLength+activations-1)
+		data.LastActivationTs = utils.EpochToTime(data.LastActivationEpoch).Unix()
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// ApiValidatorQueueEstimate godoc
+// @Summary Get the activation and exit estimates of up to 100 validators
+// @Tags Validator
+// @Description For pending validators the estimated activation epoch is returned. For exiting validators the scheduled exit and withdrawable epochs are returned. For active validators the exit and withdrawable epochs are projected as if all of the given active validators initiated their exit now, in the order of their index.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorQueueEstimateResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/queue [get]
+func ApiValidatorQueueEstimate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if len(queryIndices) == 0 {
+		sendErrorResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	state := services.GetLatestStats().ChurnQueueState
+	if state == nil {
+		sendServerErrorResponse(w, r.URL.String(), "queue state is not available yet")
+		return
+	}
+
+	validators := []struct {
+		Index                      uint64 `db:"validatorindex"`
+		Status                     string `db:"status"`
+		ActivationEligibilityEpoch uint64 `db:"activationeligibilityepoch"`
+		ActivationEpoch            uint64 `db:"activationepoch"`
+		ExitEpoch                  uint64 `db:"exitepoch"`
+		WithdrawableEpoch          uint64 `db:"withdrawableepoch"`
+	}{}
+	err = db.ReaderDb.Select(&validators, `
+		SELECT validatorindex, status, activationeligibilityepoch, activationepoch, exitepoch, withdrawableepoch
+		FROM validators
+		WHERE validatorindex = ANY($1)
+		ORDER BY validatorindex`, pq.Array(queryIndices))
+	if err != nil {
+		logger.Errorf("error retrieving validators for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	activeCount := uint64(0)
+	for _, v := range validators {
+		if v.ActivationEpoch <= state.Epoch && v.ExitEpoch > 100_000_000 {
+			activeCount++
+		}
+	}
+	exitEpochs := utils.ProjectExitEpochs(state, activeCount)
+
+	data := make([]*types.ApiValidatorQueueEstimateResponse, 0, len(validators))
+	for _, v := range validators {
+		e := &types.ApiValidatorQueueEstimateResponse{ValidatorIndex: v.Index, Status: v.Status}
+		if v.ExitEpoch < 100_000_000 {
+			e.ExitEpoch = v.ExitEpoch
+			e.WithdrawableEpoch = v.WithdrawableEpoch
+		} else if v.ActivationEpoch <= state.Epoch {
+			e.IsEstimate = true
+			e.ExitEpoch = exitEpochs[0]
+			e.WithdrawableEpoch = e.ExitEpoch + utils.Config.Chain.Config.MinValidatorWithdrawabilityDelay
+			exitEpochs = exitEpochs[1:]
+		} else if v.ActivationEpoch < 100_000_000 {
+			e.ActivationEpoch = v.ActivationEpoch
+		} else if v.ActivationEligibilityEpoch < 100_000_000 {
+			queueAhead, err := db.GetQueueAheadOfValidator(v.Index)
+			if err != nil {
+				logger.Errorf("error retrieving queue ahead of validator %v for %v route: %v", v.Index, r.URL.String(), err)
+				sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+				return
+			}
+			e.IsEstimate = true
+			e.QueuePosition = queueAhead + 1
+			e.ActivationEpoch = utils.ProjectActivationEpoch(state, queueAhead)
+		}
+
+		if e.ActivationEpoch > 0 {
+			e.ActivationTs = utils.EpochToTime(e.ActivationEpoch).Unix()
+		}
+		if e.ExitEpoch > 0 {
+			e.ExitTs = utils.EpochToTime(e.ExitEpoch).Unix()
+			e.WithdrawableTs = utils.EpochToTime(e.WithdrawableEpoch).Unix()
+		}
+		data = append(data, e)
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
 // ApiRocketpoolStats godoc
 // @Summary Get global rocketpool network statistics
 // @Tags Rocketpool
@@ -849,7 +1005,15 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 	var syncCommitteeStats *SyncCommitteesInfo
 
 	if getValidators {
-		queryIndices, err := parseApiValidatorParamToIndices(parsedBody.IndicesOrPubKey, maxValidators)
+		var queryIndices []uint64
+		if parsedBody.DashboardID != 0 || parsedBody.ShareID != "" {
+			queryIndices, err = getDashboardValidators(r, parsedBody.DashboardID, parsedBody.ShareID, parsedBody.GroupID)
+			if err == nil && len(queryIndices) > maxValidators {
+				err = fmt.Errorf("only a maximum of %d validators are allowed", maxValidators)
+			}
+		} else {
+			queryIndices, err = parseApiValidatorParamToIndices(parsedBody.IndicesOrPubKey, maxValidators)
+		}
 		if err != nil {
 			sendErrorResponse(w, r.URL.String(), err.Error())
 			return
@@ -1730,6 +1894,10 @@ func ApiValidatorByEth1Address(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	search := ReplaceEnsNameWithAddress(vars["address"])
+	if utils.IsValidEnsDomain(search) {
+		sendErrorResponse(w, r.URL.String(), "could not resolve ens name")
+		return
+	}
 	eth1Address, err := hex.DecodeString(strings.Replace(search, "0x", "", -1))
 	if err != nil {
 		sendErrorResponse(w, r.URL.String(), "invalid eth1 address provided")
@@ -1754,6 +1922,7 @@ func ApiValidatorByEth1Address(w http.ResponseWriter, r *http.Request) {
 // @Param  latest_epoch query int false "The latest epoch to consider in the query"
 // @Param  offset query int false "Number of items to skip"
 // @Param  limit query int false "Maximum number of items to return, up to 100"
+// @Param  granularity query string false "epoch (default) or hour, hourly income details are only available for the rolling window of the last days and limit and offset are counted in hours" Enums(epoch, hour)
 // @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorIncomeHistoryResponse}
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/validator/{indexOrPubkey}/incomedetailhistory [get]
@@ -1782,6 +1951,16 @@ func ApiValidatorIncomeDetailsHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	switch r.URL.Query().Get("granularity") {
+	case "", "epoch":
+	case "hour":
+		apiValidatorIncomeDetailsHourlyHistory(w, r, queryIndices, latestEpoch, limit)
+		return
+	default:
+		sendErrorResponse(w, r.URL.String(), "invalid granularity parameter")
+		return
+	}
+
 	history, err := db.BigtableClient.GetValidatorIncomeDetailsHistory(queryIndices, latestEpoch-(limit-1), latestEpoch)
 	if err != nil {
 		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
@@ -1848,6 +2027,85 @@ func ApiValidatorIncomeDetailsHistory(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// apiValidatorIncomeDetailsHourlyHistory responds with the hourly income details of the validators from the rolling validator stats,
+// latestEpoch and limit select the limit hours up to and including the hour of latestEpoch
+func apiValidatorIncomeDetailsHourlyHistory(w http.ResponseWriter, r *http.Request, validators []uint64, latestEpoch, limit uint64) {
+	j := json.NewEncoder(w)
+
+	if !utils.Config.RollingValidatorStats.Enabled {
+		sendErrorResponse(w, r.URL.String(), "hourly income details are not available")
+		return
+	}
+
+	// the offset has already been subtracted in epochs by getIncomeDetailsHistoryQueryParameters
+	offset, _ := strconv.ParseUint(r.URL.Query().Get("offset"), 10, 64)
+	from, to := getIncomeDetailsHourlyRange(latestEpoch, offset, limit)
+
+	history, err := db.GetRollingValidatorIncomeHourly(validators, from, to)
+	if err != nil {
+		logger.WithError(err).Error("error retrieving hourly validator income details")
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	responseData := make([]*types.ApiValidatorIncomeHistoryResponse, 0, len(history))
+
+	epochsPerWeek := utils.EpochsPerDay() * 7
+	for _, income := range history {
+		hour := income.Ts
+		epoch := uint64(0)
+		if e := utils.TimeToEpoch(hour); e > 0 {
+			epoch = uint64(e)
+		}
+		epochAtStartOfTheWeek := (epoch / epochsPerWeek) * epochsPerWeek
+
+		responseIncome := &types.ApiValidatorIncomeHistory{
+			AttestationSourceReward:            uint64(income.AttestationSourceReward),
+			AttestationSourcePenalty:           uint64(income.AttestationSourcePenalty),
+			AttestationTargetReward:            uint64(income.AttestationTargetReward),
+			AttestationTargetPenalty:           uint64(income.AttestationTargetPenalty),
+			AttestationHeadReward:              uint64(income.AttestationHeadReward),
+			FinalityDelayPenalty:               uint64(income.FinalityDelayPenalty),
+			ProposerSlashingInclusionReward:    uint64(income.ProposerSlashingInclusionReward),
+			ProposerAttestationInclusionReward: uint64(income.ProposerAttestationInclusionReward),
+			ProposerSyncInclusionReward:        uint64(income.ProposerSyncInclusionReward),
+			SyncCommitteeReward:                uint64(income.SyncCommitteeReward),
+			SyncCommitteePenalty:               uint64(income.SyncCommitteePenalty),
+			SlashingReward:                     uint64(income.SlashingReward),
+			SlashingPenalty:                    uint64(income.SlashingPenalty),
+			ProposalsMissed:                    income.ProposalsMissed}
+
+		responseData = append(responseData, &types.ApiValidatorIncomeHistoryResponse{
+			Income:         responseIncome,
+			Epoch:          epoch,
+			ValidatorIndex: income.ValidatorIndex,
+			Week:           epoch / epochsPerWeek,
+			WeekStart:      utils.EpochToTime(epochAtStartOfTheWeek),
+			WeekEnd:        utils.EpochToTime(epochAtStartOfTheWeek + epochsPerWeek),
+			Hour:           &hour,
+		})
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = responseData
+
+	err = j.Encode(response)
+
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// getIncomeDetailsHourlyRange returns the range [from, to) of the limit hours up to and including the hour of latestEpoch, the offset
+// is counted in hours instead of epochs: latestEpoch is the requested latest epoch minus the offset
+func getIncomeDetailsHourlyRange(latestEpoch, offset, limit uint64) (from, to time.Time) {
+	latestHour := utils.EpochToTime(latestEpoch + offset).Truncate(time.Hour).Add(-time.Hour * time.Duration(offset))
+	return latestHour.Add(-time.Hour * time.Duration(limit-1)), latestHour.Add(time.Hour)
+}
+
 func getIncomeDetailsHistoryQueryParameters(q url.Values) (uint64, uint64, error) {
 	onChainLatestEpoch := services.LatestFinalizedEpoch()
 	defaultLimit := uint64(100)
@@ -1881,6 +2139,57 @@ func getIncomeDetailsHistoryQueryParameters(q url.Values) (uint64, uint64, error
 	return latestEpoch, limit, nil
 }
 
+// ApiValidatorNextWithdrawal godoc
+// @Summary Get the predicted next withdrawal of up to 100 validators. The prediction simulates the withdrawal sweep from the current cursor and assumes that every slot contains a payload, the amount is the amount that would be withdrawn at the time of the request. Validators that are not eligible for a withdrawal are omitted.
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorNextWithdrawalResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/withdrawals/next [get]
+func ApiValidatorNextWithdrawal(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	if !services.WithdrawalPredictionsAvailable() {
+		sendServerErrorResponse(w, r.URL.String(), "withdrawal predictions are not available yet")
+		return
+	}
+
+	data := make([]*types.ApiValidatorNextWithdrawalResponse, 0, len(queryIndices))
+	for _, index := range queryIndices {
+		p, ok := services.GetPredictedWithdrawal(index)
+		if !ok {
+			continue
+		}
+		data = append(data, &types.ApiValidatorNextWithdrawalResponse{
+			ValidatorIndex: p.ValidatorIndex,
+			Epoch:          p.Slot / utils.Config.Chain.Config.SlotsPerEpoch,
+			Slot:           p.Slot,
+			Timestamp:      p.Time.Unix(),
+			Address:        fmt.Sprintf("0x%x", p.Address),
+			Amount:         p.Amount,
+			IsFull:         p.IsFull,
+		})
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
 // ApiValidatorWithdrawals godoc
 // @Summary Get the withdrawal history of up to 100 validators for the last 100 epochs. To receive older withdrawals modify the epoch paraum
 // @Tags Validator
@@ -3649,6 +3958,10 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 
 	credentialsOrAddressString := ReplaceEnsNameWithAddress(vars["withdrawalCredentialsOrEth1address"])
 	credentialsOrAddressString = strings.ToLower(credentialsOrAddressString)
+	if utils.IsValidEnsDomain(credentialsOrAddressString) {
+		sendErrorResponse(w, r.URL.String(), "could not resolve ens name")
+		return
+	}
 
 	if !utils.IsValidEth1Address(credentialsOrAddressString) &&
 		!utils.IsValidWithdrawalCredentials(credentialsOrAddressString) {
@@ -3731,6 +4044,12 @@ func APIDashboardDataBalance(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 
 	queryValidatorIndices, queryValidatorPubkeys, err := parseValidatorsFromQueryString(q.Get("validators"), 100)
+	if dashboardValidators, isDashboard, dashboardErr := getDashboardValidatorsFromQuery(r); isDashboard {
+		queryValidatorIndices, err = dashboardValidators, dashboardErr
+		if err == nil && len(queryValidatorIndices) > 100 {
+			err = ErrTooManyValidators
+		}
+	}
 	if err != nil || len(queryValidatorPubkeys) > 0 {
 		logger.WithError(err).WithField("route", r.URL.String()).Error("error parsing validators from query string")
 		http.Error(w, "Invalid query", 400)
@@ -3935,6 +4254,14 @@ func parseApiValidatorParamToIndices(origParam string, limit int) (indices []uin
 				return nil, fmt.Errorf("invalid validator-parameter")
 			}
 			pubkeys = append(pubkeys, pubkey)
+		} else if utils.IsValidEnsDomain(param) {
+			validators, err := getEnsValidators(param, limit+1)
+			if err != nil {
+				return nil, fmt.Errorf("invalid validator-parameter: %v", param)
+			}
+			for _, v := range validators {
+				indices = append(indices, v.Index)
+			}
 		} else {
 			index, err := strconv.ParseUint(param, 10, 64)
 			if err != nil {
@@ -3944,6 +4271,11 @@ func parseApiValidatorParamToIndices(origParam string, limit int) (indices []uin
 		}
 	}
 
+	// ens names expand to all validators of their address, so the limit is checked again after the expansion
+	if len(indices)+len(pubkeys) > limit {
+		return nil, fmt.Errorf("only a maximum of %d validators are allowed", limit)
+	}
+
 	var queryIndicesDeduped []uint64
 	queryIndicesDeduped = append(queryIndicesDeduped, indices...)
 	if len(pubkeys) != 0 {
@@ -3976,7 +4308,7 @@ func parseApiValidatorParamToPubkeys(origParam string, limit int) (pubkeys pq.By
 	var indices pq.Int64Array
 	params := strings.Split(origParam, ",")
 	if len(params) > limit {
-		return nil, fmt.Errorf("only a maximum of 100 query parameters are allowed")
+		return nil, fmt.Errorf("only a maximum of %d query parameters are allowed", limit)
 	}
 	for _, param := range params {
 		if strings.Contains(param, "0x") || len(param) == 96 {
@@ -3985,6 +4317,14 @@ func parseApiValidatorParamToPubkeys(origParam string, limit int) (pubkeys pq.By
 				return nil, fmt.Errorf("invalid validator-parameter")
 			}
 			pubkeys = append(pubkeys, pubkey)
+		} else if utils.IsValidEnsDomain(param) {
+			validators, err := getEnsValidators(param, limit+1)
+			if err != nil {
+				return nil, fmt.Errorf("invalid validator-parameter: %v", param)
+			}
+			for _, v := range validators {
+				pubkeys = append(pubkeys, v.Pubkey)
+			}
 		} else {
 			index, err := strconv.ParseUint(param, 10, 64)
 			if err != nil {
@@ -3994,6 +4334,11 @@ func parseApiValidatorParamToPubkeys(origParam string, limit int) (pubkeys pq.By
 		}
 	}
 
+	// ens names expand to all validators of their address, so the limit is checked again after the expansion
+	if len(indices)+len(pubkeys) > limit {
+		return nil, fmt.Errorf("only a maximum of %d validators are allowed", limit)
+	}
+
 	var queryIndicesDeduped pq.ByteaArray
 	queryIndicesDeduped = append(queryIndicesDeduped, pubkeys...)
 	if len(indices) != 0 {
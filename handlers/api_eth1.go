@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"eth2-exporter/db"
 	"eth2-exporter/price"
+	"eth2-exporter/rpc"
 	"eth2-exporter/services"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
@@ -808,6 +810,122 @@ func ApiEth1AddressTokens(w http.ResponseWriter, r *http.Request) {
 	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
 }
 
+// ApiEth1AddressBalance godoc
+// @Summary Gets the ether balance of an ethereum address at a block or point in time.
+// @Tags Execution
+// @Description Returns the ether balance of an ethereum address at the end of a block or at a unix timestamp, calculated from the indexed balance changes of the address. Without a block or timestamp the latest indexed balance is returned.
+// @Produce json
+// @Param address path string true "provide an ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Param block query integer false "block number"
+// @Param timestamp query integer false "unix timestamp"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/balance [get]
+func ApiEth1AddressBalance(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	q := r.URL.Query()
+
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		sendErrorResponse(w, r.URL.String(), "error invalid address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+	if q.Get("block") != "" && q.Get("timestamp") != "" {
+		sendErrorResponse(w, r.URL.String(), "error only one of block and timestamp can be provided")
+		return
+	}
+
+	response := struct {
+		Address   string `json:"address"`
+		Block     uint64 `json:"block,omitempty"`
+		Timestamp int64  `json:"timestamp,omitempty"`
+		Balance   string `json:"balance"`
+	}{
+		Address: fmt.Sprintf("0x%x", common.FromHex(address)),
+	}
+
+	var balance *big.Int
+	var err error
+	if q.Get("timestamp") != "" {
+		response.Timestamp, err = strconv.ParseInt(q.Get("timestamp"), 10, 64)
+		if err != nil || response.Timestamp < 0 {
+			sendErrorResponse(w, r.URL.String(), "error invalid timestamp provided")
+			return
+		}
+		balance, err = db.BigtableClient.GetNativeBalanceAtTime(common.FromHex(address), time.Unix(response.Timestamp, 0))
+	} else {
+		response.Block = services.LatestEth1BlockNumber()
+		if q.Get("block") != "" {
+			response.Block, err = strconv.ParseUint(q.Get("block"), 10, 64)
+			if err != nil {
+				sendErrorResponse(w, r.URL.String(), "error invalid block provided")
+				return
+			}
+		}
+		balance, err = db.BigtableClient.GetNativeBalanceAtBlock(common.FromHex(address), response.Block)
+	}
+	if err == db.ErrTooManyNativeBalanceDeltas {
+		sendErrorResponse(w, r.URL.String(), "error the balance of the address has changed too often to be computed")
+		return
+	}
+	if err != nil {
+		logger.Errorf("error retrieving balance for address: %v route: %v err: %v", address, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get balance for address")
+		return
+	}
+	response.Balance = balance.String()
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressBalanceHistory godoc
+// @Summary Gets the daily ether balance history of an ethereum address.
+// @Tags Execution
+// @Description Returns the ether balance in wei of an ethereum address at the end of every utc day on which its balance changed.
+// @Produce json
+// @Param address path string true "provide an ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=[]types.Eth1BalanceHistoryPoint}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/balance/history [get]
+func ApiEth1AddressBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		sendErrorResponse(w, r.URL.String(), "error invalid address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	history, err := db.BigtableClient.GetNativeBalanceHistory(common.FromHex(address))
+	if err == db.ErrTooManyNativeBalanceDeltas {
+		sendErrorResponse(w, r.URL.String(), "error the balance of the address has changed too often to compute its history")
+		return
+	}
+	if err != nil {
+		logger.Errorf("error retrieving balance history for address: %v route: %v err: %v", address, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get balance history for address")
+		return
+	}
+
+	data := make([]interface{}, 0, len(history))
+	for _, point := range history {
+		data = append(data, point)
+	}
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), data)
+}
+
 func formatBlocksForApiResponse(blocks []*types.Eth1BlockIndexed, relaysData map[common.Hash]types.RelaysData, beaconDataMap map[uint64]types.ExecBlockProposer, sortFunc func(i, j types.ExecutionBlockApiResponse) bool) []types.ExecutionBlockApiResponse {
 	results := []types.ExecutionBlockApiResponse{}
 
@@ -1200,3 +1318,147 @@ func parseFromAddressIndexOrPubkey(search string) (types.AddressIndexOrPubkey, e
 		}, nil
 	}
 }
+
+// ApiEth1MempoolSender godoc
+// @Summary Gets the tracked mempool transactions of an ethereum address with a diagnosis of why they are stuck
+// @Tags Execution
+// @Description Returns all pending transactions sent by the address as well as transactions that were included, replaced or dropped within the last 24 hours.
+// @Description Every pending transaction contains a list of reasons that keep it from being included, e.g. a nonce gap or a max fee below the current base fee.
+// @Produce json
+// @Param address path string true "provide an ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=types.ApiMempoolSenderResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/mempool/{address} [get]
+func ApiEth1MempoolSender(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !utils.Config.Frontend.MempoolTracker.Enabled {
+		sendErrorResponse(w, r.URL.String(), "mempool tracking is not enabled")
+		return
+	}
+
+	vars := mux.Vars(r)
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		sendErrorResponse(w, r.URL.String(), "error invalid address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+	sender := common.HexToAddress(address)
+
+	txs, err := db.GetMempoolTransactionsForSenders([][]byte{sender.Bytes()}, time.Now().Add(-time.Hour*24))
+	if err != nil {
+		logger.Errorf("error retrieving mempool transactions for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Second*10)
+	defer cancel()
+
+	accountNonce, err := rpc.CurrentErigonClient.GetNativeClient().NonceAt(ctx, sender, nil)
+	if err != nil {
+		logger.Errorf("error retrieving nonce of %v for %v route: %v", sender, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve account nonce")
+		return
+	}
+	header, err := rpc.CurrentErigonClient.GetNativeClient().HeaderByNumber(ctx, nil)
+	if err != nil {
+		logger.Errorf("error retrieving latest header for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve latest block")
+		return
+	}
+	baseFee := decimal.Zero
+	if header.BaseFee != nil {
+		baseFee = decimal.NewFromBigInt(header.BaseFee, 0)
+	}
+
+	response := &types.ApiMempoolSenderResponse{
+		Sender:         sender.Hex(),
+		AccountNonce:   accountNonce,
+		BaseFeePerGas:  baseFee.String(),
+		Pending:        []*types.ApiMempoolTxLifecycleResponse{},
+		RecentlyClosed: []*types.ApiMempoolTxLifecycleResponse{},
+	}
+
+	pendingNonces := make(map[uint64]bool)
+	for _, tx := range txs {
+		if tx.Status == types.MempoolTxStatusPending {
+			pendingNonces[tx.Nonce] = true
+		}
+	}
+
+	for _, tx := range txs {
+		formatted := formatMempoolTxLifecycle(tx)
+		if tx.Status != types.MempoolTxStatusPending {
+			response.RecentlyClosed = append(response.RecentlyClosed, formatted)
+			continue
+		}
+		formatted.Diagnosis = diagnoseMempoolTx(tx, accountNonce, pendingNonces, baseFee)
+		response.Pending = append(response.Pending, formatted)
+	}
+
+	j := json.NewEncoder(w)
+	sendOKResponse(j, r.URL.String(), []interface{}{response})
+}
+
+// diagnoseMempoolTx returns the reasons that keep a pending transaction from being included
+func diagnoseMempoolTx(tx *types.MempoolTxLifecycle, accountNonce uint64, pendingNonces map[uint64]bool, baseFee decimal.Decimal) []string {
+	diagnosis := []string{}
+
+	if tx.Nonce < accountNonce {
+		diagnosis = append(diagnosis, fmt.Sprintf("nonce %d has already been used, the transaction can not be included anymore", tx.Nonce))
+	}
+	for nonce := accountNonce; nonce < tx.Nonce; nonce++ {
+		if !pendingNonces[nonce] {
+			diagnosis = append(diagnosis, fmt.Sprintf("nonce gap: no pending transaction with nonce %d", nonce))
+			break
+		}
+	}
+	if tx.Nonce > accountNonce && len(diagnosis) == 0 {
+		diagnosis = append(diagnosis, fmt.Sprintf("waiting for %d pending transaction(s) with a lower nonce", tx.Nonce-accountNonce))
+	}
+
+	maxFee := tx.GasFeeCap
+	if maxFee.IsZero() {
+		maxFee = tx.GasPrice
+	}
+	if maxFee.LessThan(baseFee) {
+		diagnosis = append(diagnosis, fmt.Sprintf("max fee per gas of %v GWei is below the current base fee of %v GWei", maxFee.Div(decimal.NewFromInt(1e9)).StringFixed(2), baseFee.Div(decimal.NewFromInt(1e9)).StringFixed(2)))
+	}
+
+	if time.Since(tx.FirstSeen) > time.Minute*30 {
+		diagnosis = append(diagnosis, fmt.Sprintf("pending since %v", tx.FirstSeen.Format(time.RFC3339)))
+	}
+
+	return diagnosis
+}
+
+func formatMempoolTxLifecycle(tx *types.MempoolTxLifecycle) *types.ApiMempoolTxLifecycleResponse {
+	formatted := &types.ApiMempoolTxLifecycleResponse{
+		Hash:           fmt.Sprintf("%#x", tx.Hash),
+		Sender:         common.BytesToAddress(tx.Sender).Hex(),
+		Nonce:          tx.Nonce,
+		Status:         tx.Status,
+		GasPrice:       tx.GasPrice.String(),
+		GasFeeCap:      tx.GasFeeCap.String(),
+		GasTipCap:      tx.GasTipCap.String(),
+		FirstSeen:      tx.FirstSeen,
+		FirstSeenBlock: tx.FirstSeenBlock,
+		LastSeen:       tx.LastSeen,
+	}
+	if len(tx.ReplacedBy) > 0 {
+		formatted.ReplacedBy = fmt.Sprintf("%#x", tx.ReplacedBy)
+	}
+	if tx.IncludedBlock.Valid {
+		includedBlock := uint64(tx.IncludedBlock.Int64)
+		latencyBlocks := tx.InclusionLatencyBlocks()
+		latencySeconds := tx.InclusionLatency().Seconds()
+		formatted.IncludedBlock = &includedBlock
+		formatted.InclusionLatencyBlocks = &latencyBlocks
+		formatted.InclusionLatencySeconds = &latencySeconds
+	}
+	return formatted
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"eth2-exporter/types"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDiagnoseMempoolTx(t *testing.T) {
+	gwei := func(v int64) decimal.Decimal { return decimal.NewFromInt(v * 1e9) }
+	recent := time.Now().Add(-time.Minute)
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name          string
+		tx            *types.MempoolTxLifecycle
+		accountNonce  uint64
+		pendingNonces map[uint64]bool
+		baseFee       decimal.Decimal
+		diagnosis     []string
+	}{
+		{
+			name:         "includable",
+			tx:           &types.MempoolTxLifecycle{Nonce: 5, GasFeeCap: gwei(30), FirstSeen: recent},
+			accountNonce: 5,
+			baseFee:      gwei(20),
+			diagnosis:    []string{},
+		},
+		{
+			name:         "used nonce",
+			tx:           &types.MempoolTxLifecycle{Nonce: 3, GasFeeCap: gwei(30), FirstSeen: recent},
+			accountNonce: 5,
+			baseFee:      gwei(20),
+			diagnosis:    []string{"nonce 3 has already been used, the transaction can not be included anymore"},
+		},
+		{
+			name:          "nonce gap",
+			tx:            &types.MempoolTxLifecycle{Nonce: 8, GasFeeCap: gwei(30), FirstSeen: recent},
+			accountNonce:  5,
+			pendingNonces: map[uint64]bool{5: true, 6: true},
+			baseFee:       gwei(20),
+			diagnosis:     []string{"nonce gap: no pending transaction with nonce 7"},
+		},
+		{
+			name:          "waiting for lower nonces",
+			tx:            &types.MempoolTxLifecycle{Nonce: 7, GasFeeCap: gwei(30), FirstSeen: recent},
+			accountNonce:  5,
+			pendingNonces: map[uint64]bool{5: true, 6: true},
+			baseFee:       gwei(20),
+			diagnosis:     []string{"waiting for 2 pending transaction(s) with a lower nonce"},
+		},
+		{
+			name:         "max fee below base fee",
+			tx:           &types.MempoolTxLifecycle{Nonce: 5, GasFeeCap: gwei(10), GasTipCap: gwei(2), FirstSeen: recent},
+			accountNonce: 5,
+			baseFee:      gwei(20),
+			diagnosis:    []string{"max fee per gas of 10.00 GWei is below the current base fee of 20.00 GWei"},
+		},
+		{
+			name:         "legacy gas price above base fee",
+			tx:           &types.MempoolTxLifecycle{Nonce: 5, GasPrice: gwei(30), FirstSeen: recent},
+			accountNonce: 5,
+			baseFee:      gwei(20),
+			diagnosis:    []string{},
+		},
+		{
+			name:         "legacy gas price below base fee",
+			tx:           &types.MempoolTxLifecycle{Nonce: 5, GasPrice: gwei(10), FirstSeen: recent},
+			accountNonce: 5,
+			baseFee:      gwei(20),
+			diagnosis:    []string{"max fee per gas of 10.00 GWei is below the current base fee of 20.00 GWei"},
+		},
+		{
+			name:         "pending for long",
+			tx:           &types.MempoolTxLifecycle{Nonce: 5, GasFeeCap: gwei(30), FirstSeen: old},
+			accountNonce: 5,
+			baseFee:      gwei(20),
+			diagnosis:    []string{"pending since " + old.Format(time.RFC3339)},
+		},
+		{
+			name:          "multiple reasons",
+			tx:            &types.MempoolTxLifecycle{Nonce: 7, GasFeeCap: gwei(10), FirstSeen: old},
+			accountNonce:  5,
+			pendingNonces: map[uint64]bool{6: true},
+			baseFee:       gwei(20),
+			diagnosis: []string{
+				"nonce gap: no pending transaction with nonce 5",
+				"max fee per gas of 10.00 GWei is below the current base fee of 20.00 GWei",
+				"pending since " + old.Format(time.RFC3339),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnosis := diagnoseMempoolTx(tt.tx, tt.accountNonce, tt.pendingNonces, tt.baseFee)
+			if !reflect.DeepEqual(diagnosis, tt.diagnosis) {
+				t.Errorf("expected diagnosis %q, got %q", tt.diagnosis, diagnosis)
+			}
+		})
+	}
+}
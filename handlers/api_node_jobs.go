@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const maxNodeJobsPerBatch = 100
+
+// ApiNodeJobsCreate godoc
+// @Summary Create a node job that broadcasts signed BLS-to-execution changes or voluntary exits, schedules an exit or validates deposit data. If a https callback_url is provided every status transition of the job (PENDING, SUBMITTED_TO_NODE, COMPLETED, FAILED) will be POSTed to it. The callbacks are signed with the callback_secret that is only returned in this response, the hex encoded HMAC-SHA256 of the body is sent in the X-Signature-256 header.
+// @Tags Misc
+// @Accept json
+// @Produce json
+// @Param apikey query string false "User API key, if set the job will be listed at /api/v1/nodejobs"
+// @Param job body types.ApiNodeJobCreateRequest true "The job data and an optional callback url"
+// @Success 200 {object} types.ApiResponse{data=types.ApiNodeJob}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/nodejobs [post]
+func ApiNodeJobsCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	ownerID := sql.NullInt64{}
+	if apiKey := getApiKey(r); apiKey != "" {
+		user, err := db.GetUserIdByApiKey(apiKey)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "no user found with api key")
+			return
+		}
+		ownerID = sql.NullInt64{Int64: int64(user.ID), Valid: true}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 2e6))
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not read body")
+		return
+	}
+	req := types.ApiNodeJobCreateRequest{}
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid request body")
+		return
+	}
+	if req.CallbackURL != "" && !ownerID.Valid {
+		sendErrorResponse(w, r.URL.String(), "an api key is required to use callbacks")
+		return
+	}
+
+	job, err := db.CreateOwnedNodeJob(req.Data, ownerID, req.CallbackURL)
+	if err != nil {
+		var userErr types.CreateNodeJobUserError
+		if errors.As(err, &userErr) {
+			sendErrorResponse(w, r.URL.String(), userErr.Message)
+			return
+		}
+		logger.WithError(err).Errorf("failed creating a node-job")
+		sendServerErrorResponse(w, r.URL.String(), "could not create job")
+		return
+	}
+
+	data := toApiNodeJob(job, true)
+	data.CallbackSecret = job.CallbackSecret.String
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// ApiNodeJobsList godoc
+// @Summary Get the node jobs created with the given api key, newest first
+// @Tags Misc
+// @Produce json
+// @Param apikey query string true "User API key, can be found on https://beaconcha.in/user/settings"
+// @Param status query string false "Only return jobs with this status (PENDING, SUBMITTED_TO_NODE, COMPLETED, FAILED)"
+// @Param limit query int false "Limit the number of results (maximum: 100)"
+// @Param offset query int false "Offset the results"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiNodeJob}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/nodejobs [get]
+func ApiNodeJobsList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	apiKey := getApiKey(r)
+	if apiKey == "" {
+		sendErrorResponse(w, r.URL.String(), "api key required")
+		return
+	}
+	user, err := db.GetUserIdByApiKey(apiKey)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "no user found with api key")
+		return
+	}
+
+	status := types.NodeJobStatus(q.Get("status"))
+	switch status {
+	case "", types.PendingNodeJobStatus, types.SubmittedToNodeNodeJobStatus, types.CompletedNodeJobStatus, types.FailedNodeJobStatus:
+	default:
+		sendErrorResponse(w, r.URL.String(), "invalid status")
+		return
+	}
+
+	limit := uint64(maxNodeJobsPerBatch)
+	if q.Get("limit") != "" {
+		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil || limit > maxNodeJobsPerBatch {
+			sendErrorResponse(w, r.URL.String(), "invalid limit")
+			return
+		}
+	}
+	offset := uint64(0)
+	if q.Get("offset") != "" {
+		offset, err = strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid offset")
+			return
+		}
+	}
+
+	jobs, err := db.GetNodeJobsByOwner(user.ID, status, limit, offset)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving node-jobs of user %v", user.ID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiNodeJob, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, toApiNodeJob(job, true))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// ApiNodeJobs godoc
+// @Summary Get up to 100 node jobs by their ids
+// @Tags Misc
+// @Produce json
+// @Param ids path string true "Up to 100 job ids, comma separated"
+// @Param apikey query string false "User API key, the callback_url is only returned for jobs created with this key"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiNodeJob}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/nodejobs/{ids} [get]
+func ApiNodeJobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	ids := strings.Split(vars["ids"], ",")
+	if len(ids) > maxNodeJobsPerBatch {
+		sendErrorResponse(w, r.URL.String(), "only a maximum of 100 job ids are allowed per request")
+		return
+	}
+	for _, id := range ids {
+		if len(id) == 0 || len(id) > 40 {
+			sendErrorResponse(w, r.URL.String(), "invalid job id")
+			return
+		}
+	}
+
+	ownerID := sql.NullInt64{}
+	if apiKey := getApiKey(r); apiKey != "" {
+		user, err := db.GetUserIdByApiKey(apiKey)
+		if err == nil {
+			ownerID = sql.NullInt64{Int64: int64(user.ID), Valid: true}
+		}
+	}
+
+	jobs, err := db.GetNodeJobsByIDs(ids)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving node-jobs")
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiNodeJob, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, toApiNodeJob(job, ownerID.Valid && ownerID == job.OwnerID))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+func toApiNodeJob(job *types.NodeJob, isOwner bool) *types.ApiNodeJob {
+	res := &types.ApiNodeJob{
+		ID:          job.ID,
+		Type:        job.Type,
+		Status:      job.Status,
+		CreatedTime: job.CreatedTime.Unix(),
+		Data:        job.RawData,
+	}
+	if job.SubmittedToNodeTime.Valid {
+		ts := job.SubmittedToNodeTime.Time.Unix()
+		res.SubmittedToNodeTime = &ts
+	}
+	if job.CompletedTime.Valid {
+		ts := job.CompletedTime.Time.Unix()
+		res.CompletedTime = &ts
+	}
+	if isOwner {
+		res.CallbackURL = job.CallbackURL.String
+	}
+	return res
+}
+
+func getApiKey(r *http.Request) string {
+	apiKey := r.URL.Query().Get("apikey")
+	if apiKey == "" {
+		apiKey = r.Header.Get("apikey")
+	}
+	return apiKey
+}
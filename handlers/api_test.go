@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseApiValidatorParamEnsExpansionLimit(t *testing.T) {
+	ensValidators := map[string]int{"small.eth": 2, "large.eth": 4}
+	defer func(f func(string, int) ([]*types.ValidatorSetMember, error)) { getEnsValidators = f }(getEnsValidators)
+	getEnsValidators = func(name string, limit int) ([]*types.ValidatorSetMember, error) {
+		validators := []*types.ValidatorSetMember{}
+		for i := 0; i < ensValidators[name] && i < limit; i++ {
+			validators = append(validators, &types.ValidatorSetMember{Index: uint64(len(name)*10 + i), Pubkey: []byte(fmt.Sprintf("%s%d", name, i))})
+		}
+		return validators, nil
+	}
+
+	tests := []struct {
+		name  string
+		param string
+		limit int
+		count int
+		err   bool
+	}{
+		{"single name within limit", "small.eth", 5, 2, false},
+		{"names within limit", "small.eth,1", 3, 3, false},
+		{"name exceeding limit", "large.eth", 3, 0, true},
+		{"names exceeding limit together", "small.eth,large.eth", 5, 0, true},
+		{"name and indices exceeding limit", "large.eth,1,2", 5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indices, err := parseApiValidatorParamToIndices(tt.param, tt.limit)
+			checkEnsExpansion(t, "indices", len(indices), err, tt.count, tt.err, tt.limit)
+			if !tt.err && strings.Contains(tt.param, ",1") {
+				// plain indices are resolved to pubkeys through the db, the limit is checked before that
+				return
+			}
+			pubkeys, err := parseApiValidatorParamToPubkeys(tt.param, tt.limit)
+			checkEnsExpansion(t, "pubkeys", len(pubkeys), err, tt.count, tt.err, tt.limit)
+		})
+	}
+}
+
+func checkEnsExpansion(t *testing.T, kind string, count int, err error, expectedCount int, expectedErr bool, limit int) {
+	t.Helper()
+	if expectedErr {
+		expected := fmt.Sprintf("only a maximum of %d validators are allowed", limit)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%s: expected error %q, got %v", kind, expected, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("%s: unexpected error: %v", kind, err)
+		return
+	}
+	if count != expectedCount {
+		t.Errorf("%s: expected %d validators, got %d", kind, expectedCount, count)
+	}
+}
+
+func TestGetIncomeDetailsHourlyRange(t *testing.T) {
+	defer func(config *types.Config) { utils.Config = config }(utils.Config)
+	utils.Config = &types.Config{}
+	utils.Config.Chain.GenesisTimestamp = 1606824023
+	utils.Config.Chain.Config.SecondsPerSlot = 12
+	utils.Config.Chain.Config.SlotsPerEpoch = 32
+
+	tests := []struct {
+		name        string
+		latestEpoch uint64
+		offset      uint64
+		limit       uint64
+		from        int64
+		to          int64
+	}{
+		// epoch 100 starts at 2020-12-01 22:40:23
+		{"single hour", 100, 0, 1, 1606860000, 1606863600},
+		// latest epoch 200 with an offset of 10 ends 10 hours before the hour of epoch 200 instead of 10 epochs before it
+		{"offset in hours", 190, 10, 3, 1606856400, 1606867200},
+		{"hours before genesis", 0, 0, 24, 1606741200, 1606827600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := getIncomeDetailsHourlyRange(tt.latestEpoch, tt.offset, tt.limit)
+			if !from.Equal(time.Unix(tt.from, 0)) || !to.Equal(time.Unix(tt.to, 0)) {
+				t.Errorf("expected range %v to %v, got %v to %v", time.Unix(tt.from, 0).UTC(), time.Unix(tt.to, 0).UTC(), from.UTC(), to.UTC())
+			}
+		})
+	}
+}
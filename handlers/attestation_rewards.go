@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"sort"
+
+	itypes "github.com/gobitfly/eth-rewards/types"
+	"github.com/gorilla/mux"
+	"golang.org/x/sync/errgroup"
+)
+
+// ApiValidatorAttestationRewards godoc
+// @Summary Get the attestation rewards of up to 100 validators compared to the ideal attestation rewards
+// @Tags Validator
+// @Description Compares the attestation rewards of each epoch with the rewards a validator with the same effective balance would have received with a perfect attestation.
+// @Description The shortfall is attributed to a wrong head vote, a wrong target vote, a source vote that was included too late, the inclusion delay or a missed attestation, all amounts are in gwei.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param  latest_epoch query int false "The latest epoch to consider in the query"
+// @Param  offset query int false "Number of epochs to skip"
+// @Param  limit query int false "Maximum number of epochs to return, up to 100"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiAttestationRewardAnalysis}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/attestationrewards [get]
+func ApiValidatorAttestationRewards(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	latestEpoch, limit, err := getIncomeDetailsHistoryQueryParameters(r.URL.Query())
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		sendErrorResponse(w, r.URL.String(), "no validators provided")
+		return
+	}
+
+	startEpoch := uint64(0)
+	if latestEpoch >= limit {
+		startEpoch = latestEpoch - (limit - 1)
+	}
+
+	data, err := getAttestationRewardAnalysis(queryIndices, startEpoch, latestEpoch)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error retrieving attestation reward analysis")
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = data
+
+	err = j.Encode(response)
+
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// DashboardDataMissedRewards returns the attestation rewards the dashboard validators left on the table within the last day
+func DashboardDataMissedRewards(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	validators, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+
+	toEpoch := services.LatestFinalizedEpoch()
+	fromEpoch := uint64(0)
+	if toEpoch >= utils.EpochsPerDay() {
+		fromEpoch = toEpoch - utils.EpochsPerDay() + 1
+	}
+
+	analysis, err := getAttestationRewardAnalysis(validators, fromEpoch, toEpoch)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error retrieving attestation reward analysis")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(getDashboardMissedRewards(fromEpoch, toEpoch, analysis))
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getDashboardMissedRewards sums up the attestation reward analysis of the dashboard validators, the validators are ordered by the rewards they left on the table
+func getDashboardMissedRewards(fromEpoch, toEpoch uint64, analysis []*types.ApiAttestationRewardAnalysis) *types.DashboardMissedRewards {
+	data := &types.DashboardMissedRewards{
+		FromEpoch:  fromEpoch,
+		ToEpoch:    toEpoch,
+		Validators: []*types.DashboardMissedRewardsValidator{},
+	}
+
+	epochs := make(map[uint64]bool)
+	validators := make(map[uint64]*types.DashboardMissedRewardsValidator)
+	for _, a := range analysis {
+		epochs[a.Epoch] = true
+		utils.AddAttestationRewards(&data.Ideal, a.Ideal)
+		utils.AddAttestationRewards(&data.Actual, a.Actual)
+		utils.AddAttestationRewardShortfall(&data.Shortfall, a.Shortfall)
+
+		v := validators[a.ValidatorIndex]
+		if v == nil {
+			v = &types.DashboardMissedRewardsValidator{ValidatorIndex: a.ValidatorIndex}
+			validators[a.ValidatorIndex] = v
+			data.Validators = append(data.Validators, v)
+		}
+		utils.AddAttestationRewardShortfall(&v.Shortfall, a.Shortfall)
+	}
+	data.Epochs = uint64(len(epochs))
+
+	sort.Slice(data.Validators, func(i, j int) bool {
+		if data.Validators[i].Shortfall.Total != data.Validators[j].Shortfall.Total {
+			return data.Validators[i].Shortfall.Total > data.Validators[j].Shortfall.Total
+		}
+		return data.Validators[i].ValidatorIndex < data.Validators[j].ValidatorIndex
+	})
+	return data
+}
+
+// getAttestationRewardAnalysis compares the attestation rewards of the validators from startEpoch to endEpoch (inclusive) with the ideal rewards
+// of their effective balance, epochs whose rewards or ideal rewards have not been exported yet are omitted. The ideal rewards of epochs that were exported before
+// they were stored are filled in by the ideal-rewards-backfill of the rewards-exporter. The result is ordered by epoch descending and validator index.
+func getAttestationRewardAnalysis(validators []uint64, startEpoch, endEpoch uint64) ([]*types.ApiAttestationRewardAnalysis, error) {
+	var income map[uint64]map[uint64]*itypes.ValidatorEpochIncome
+	var balances map[uint64][]*types.ValidatorBalance
+	var attestations map[uint64][]*types.ValidatorAttestation
+	var ideal map[uint64]map[uint64]*types.IdealAttestationReward
+
+	g := errgroup.Group{}
+	g.Go(func() error {
+		var err error
+		income, err = db.BigtableClient.GetValidatorIncomeDetailsHistory(validators, startEpoch, endEpoch)
+		if err != nil {
+			return fmt.Errorf("error retrieving income details: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		// the rewards of an epoch are computed with the effective balances of the following epoch
+		var err error
+		balances, err = db.BigtableClient.GetValidatorBalanceHistory(validators, startEpoch, endEpoch+1)
+		if err != nil {
+			return fmt.Errorf("error retrieving balances: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		attestations, err = db.BigtableClient.GetValidatorAttestationHistory(validators, startEpoch, endEpoch)
+		if err != nil {
+			return fmt.Errorf("error retrieving attestations: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		ideal, err = db.GetIdealAttestationRewards(startEpoch, endEpoch)
+		return err
+	})
+	err := g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveBalances := make(map[uint64]map[uint64]uint64, len(balances))
+	for validator, history := range balances {
+		effectiveBalances[validator] = make(map[uint64]uint64, len(history))
+		for _, b := range history {
+			effectiveBalances[validator][b.Epoch] = b.EffectiveBalance
+		}
+	}
+	duties := make(map[uint64]map[uint64]*types.ValidatorAttestation, len(attestations))
+	for validator, history := range attestations {
+		duties[validator] = make(map[uint64]*types.ValidatorAttestation, len(history))
+		for _, a := range history {
+			duties[validator][a.Epoch] = a
+		}
+	}
+
+	res := make([]*types.ApiAttestationRewardAnalysis, 0, len(income)*int(endEpoch-startEpoch+1))
+	for validator, epochs := range income {
+		for epoch, i := range epochs {
+			duty := duties[validator][epoch]
+			if duty == nil || ideal[epoch] == nil {
+				continue
+			}
+			effectiveBalance, found := effectiveBalances[validator][epoch+1]
+			if !found {
+				effectiveBalance = effectiveBalances[validator][epoch]
+			}
+			idealReward := ideal[epoch][effectiveBalance]
+			if idealReward == nil {
+				continue
+			}
+
+			a := &types.ApiAttestationRewardAnalysis{
+				Epoch:            epoch,
+				ValidatorIndex:   validator,
+				EffectiveBalance: effectiveBalance,
+				Included:         duty.Status == 1,
+				Ideal: types.ApiAttestationRewards{
+					Head:   idealReward.Head,
+					Source: idealReward.Source,
+					Target: idealReward.Target,
+				},
+				Actual: types.ApiAttestationRewards{
+					Head:   int64(i.AttestationHeadReward),
+					Source: int64(i.AttestationSourceReward) - int64(i.AttestationSourcePenalty),
+					Target: int64(i.AttestationTargetReward) - int64(i.AttestationTargetPenalty),
+				},
+			}
+			if a.Included {
+				// a delay of 0 is an inclusion in the slot following the attester slot
+				a.InclusionDelay = duty.Delay
+			}
+			utils.AttributeAttestationRewardShortfall(a)
+			res = append(res, a)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Epoch != res[j].Epoch {
+			return res[i].Epoch > res[j].Epoch
+		}
+		return res[i].ValidatorIndex < res[j].ValidatorIndex
+	})
+	return res, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"eth2-exporter/types"
+	"testing"
+)
+
+func TestGetDashboardMissedRewards(t *testing.T) {
+	analysis := []*types.ApiAttestationRewardAnalysis{
+		{
+			Epoch:          11,
+			ValidatorIndex: 5,
+			Shortfall:      types.ApiAttestationRewardShortfall{WrongHead: 10, Total: 10},
+		},
+		{
+			Epoch:          11,
+			ValidatorIndex: 2,
+			Ideal:          types.ApiAttestationRewards{Head: 10, Source: 20, Target: 30, Total: 60},
+			Actual:         types.ApiAttestationRewards{Head: 0, Source: 20, Target: 30, Total: 50},
+			Shortfall:      types.ApiAttestationRewardShortfall{WrongHead: 10, Total: 10},
+		},
+		{
+			Epoch:          11,
+			ValidatorIndex: 1,
+			Ideal:          types.ApiAttestationRewards{Head: 10, Source: 20, Target: 30, Total: 60},
+			Actual:         types.ApiAttestationRewards{Head: 0, Source: -20, Target: -30, Total: -50},
+			Shortfall:      types.ApiAttestationRewardShortfall{Missed: 110, Total: 110},
+		},
+		{
+			Epoch:          10,
+			ValidatorIndex: 2,
+			Ideal:          types.ApiAttestationRewards{Head: 10, Source: 20, Target: 30, Total: 60},
+			Actual:         types.ApiAttestationRewards{Head: 10, Source: 15, Target: 30, Total: 55},
+			Shortfall:      types.ApiAttestationRewardShortfall{InclusionDelay: 5, Total: 5},
+		},
+		{
+			Epoch:          10,
+			ValidatorIndex: 3,
+			Ideal:          types.ApiAttestationRewards{Head: 10, Source: 20, Target: 30, Total: 60},
+			Actual:         types.ApiAttestationRewards{Head: 0, Source: 20, Target: 30, Total: 50},
+			Shortfall:      types.ApiAttestationRewardShortfall{WrongHead: 10, Total: 10},
+		},
+		{
+			Epoch:          10,
+			ValidatorIndex: 4,
+			Ideal:          types.ApiAttestationRewards{Head: 10, Source: 20, Target: 30, Total: 60},
+			Actual:         types.ApiAttestationRewards{Head: 10, Source: 20, Target: 30, Total: 60},
+		},
+	}
+
+	data := getDashboardMissedRewards(10, 12, analysis)
+	if data.FromEpoch != 10 || data.ToEpoch != 12 {
+		t.Errorf("expected epochs 10 to 12, got %v to %v", data.FromEpoch, data.ToEpoch)
+	}
+	if data.Epochs != 2 {
+		t.Errorf("expected 2 analysed epochs, got %v", data.Epochs)
+	}
+	if expected := (types.ApiAttestationRewards{Head: 50, Source: 100, Target: 150, Total: 300}); data.Ideal != expected {
+		t.Errorf("expected ideal rewards %+v, got %+v", expected, data.Ideal)
+	}
+	if expected := (types.ApiAttestationRewards{Head: 20, Source: 55, Target: 90, Total: 165}); data.Actual != expected {
+		t.Errorf("expected actual rewards %+v, got %+v", expected, data.Actual)
+	}
+	if expected := (types.ApiAttestationRewardShortfall{WrongHead: 30, InclusionDelay: 5, Missed: 110, Total: 145}); data.Shortfall != expected {
+		t.Errorf("expected shortfall %+v, got %+v", expected, data.Shortfall)
+	}
+
+	// ordered by shortfall descending, validators with the same shortfall by index
+	expected := []types.DashboardMissedRewardsValidator{
+		{ValidatorIndex: 1, Shortfall: types.ApiAttestationRewardShortfall{Missed: 110, Total: 110}},
+		{ValidatorIndex: 2, Shortfall: types.ApiAttestationRewardShortfall{WrongHead: 10, InclusionDelay: 5, Total: 15}},
+		{ValidatorIndex: 3, Shortfall: types.ApiAttestationRewardShortfall{WrongHead: 10, Total: 10}},
+		{ValidatorIndex: 5, Shortfall: types.ApiAttestationRewardShortfall{WrongHead: 10, Total: 10}},
+		{ValidatorIndex: 4},
+	}
+	if len(data.Validators) != len(expected) {
+		t.Fatalf("expected %v validators, got %v", len(expected), len(data.Validators))
+	}
+	for i, v := range data.Validators {
+		if *v != expected[i] {
+			t.Errorf("expected validator %v to be %+v, got %+v", i, expected[i], *v)
+		}
+	}
+
+	empty := getDashboardMissedRewards(10, 12, nil)
+	if empty.Epochs != 0 || len(empty.Validators) != 0 || empty.Shortfall.Total != 0 {
+		t.Errorf("expected no missed rewards without analysis, got %+v", empty)
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/templates"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const maxBenchmarkSets = 5
+
+var errInvalidBenchmarkSet = errors.New("invalid set")
+
+// ValidatorBenchmark will return the page that compares the performance of validator sets against the network and ETH.STORE
+func ValidatorBenchmark(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "benchmark.html")
+	benchmarkTemplate := templates.GetTemplate(templateFiles...)
+	w.Header().Set("Content-Type", "text/html")
+	data := InitPageData(w, r, "services", "/benchmark", "Validator Benchmark", templateFiles)
+
+	pageData := &types.ValidatorBenchmarkPageData{
+		Sets:       make([]string, maxBenchmarkSets),
+		Days:       31,
+		Windows:    services.BenchmarkWindows,
+		Disclaimer: services.EthStoreDisclaimer(),
+	}
+	copy(pageData.Sets, r.URL.Query()["set"])
+	if days, err := strconv.ParseUint(r.URL.Query().Get("days"), 10, 64); err == nil && isBenchmarkWindow(days) {
+		pageData.Days = days
+	}
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "benchmark.go", "ValidatorBenchmark", "", benchmarkTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// ApiValidatorBenchmark godoc
+// @Summary Compare the daily apr, attestation participation, missed duties and sync participation of up to 5 sets of validators against the network and ETH.STORE®
+// @Tags Validator
+// @Description A set is one of dashboard:{dashboardId}[:{groupId}], share:{shareId}[:{groupId}], validatorset:{setId}, pool:{pool}, address:{withdrawalAddressOrCredentials} or validators:{indicesOrPubkeys}.
+// @Description Dashboards and validator sets require an authenticated user. The apr percentile rank of a set is its rank within the apr of all validators over the same days.
+// @Produce json
+// @Param set query []string true "Sets of validators to compare" collectionFormat(multi)
+// @Param days query int false "Number of days to compare, one of 1, 7 or 31 (default)"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorBenchmarkResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/validators/benchmark [get]
+func ApiValidatorBenchmark(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	days := uint64(31)
+	if q.Get("days") != "" {
+		var err error
+		days, err = strconv.ParseUint(q.Get("days"), 10, 64)
+		if err != nil || !isBenchmarkWindow(days) {
+			sendErrorResponse(w, r.URL.String(), fmt.Sprintf("invalid days, must be one of %v", services.BenchmarkWindows))
+			return
+		}
+	}
+
+	sets := q["set"]
+	if len(sets) == 0 {
+		sendErrorResponse(w, r.URL.String(), "no set provided")
+		return
+	}
+	if len(sets) > maxBenchmarkSets {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("only a maximum of %d sets can be compared", maxBenchmarkSets))
+		return
+	}
+
+	benchmark := services.LatestValidatorBenchmark()
+	if len(benchmark.Days) == 0 {
+		sendErrorResponse(w, r.URL.String(), "benchmark data is not available yet")
+		return
+	}
+	lastDay := benchmark.Days[len(benchmark.Days)-1].Day
+	fromDay := uint64(0)
+	if lastDay+1 >= days {
+		fromDay = lastDay + 1 - days
+	}
+	percentiles := benchmark.AprPercentiles[days]
+
+	data := &types.ApiValidatorBenchmarkResponse{
+		Days: days,
+		Network: &types.ApiValidatorBenchmarkSet{
+			Set:              "network",
+			DailyPerformance: []*types.ValidatorBenchmarkDay{},
+		},
+		Sets: make([]*types.ApiValidatorBenchmarkSet, 0, len(sets)),
+	}
+	for _, day := range benchmark.Days {
+		if day.Day >= fromDay {
+			data.Network.DailyPerformance = append(data.Network.DailyPerformance, day)
+		}
+	}
+	summarizeValidatorBenchmarkSet(data.Network)
+	data.Network.Apr = benchmark.Apr[days]
+	data.Network.EthStoreApr = benchmark.EthStoreApr[days]
+	data.Network.AprPercentileRank = utils.PercentileRank(percentiles, data.Network.Apr)
+
+	for _, set := range sets {
+		selector, err := getBenchmarkSelector(r, set)
+		if err != nil {
+			if errors.Is(err, errInvalidBenchmarkSet) || errors.Is(err, db.ErrUserDashboardNotFound) || errors.Is(err, db.ErrValidatorSetNotFound) {
+				sendErrorResponse(w, r.URL.String(), fmt.Sprintf("%v: %v", set, err))
+				return
+			}
+			logger.WithError(err).Errorf("error retrieving validators of benchmark set %v", set)
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+
+		performance, err := db.GetValidatorBenchmarkDays(selector, fromDay, lastDay)
+		if err != nil {
+			logger.WithError(err).Errorf("error retrieving daily performance of benchmark set %v", set)
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+		apr, err := db.GetValidatorBenchmarkApr(selector, fromDay, lastDay)
+		if err != nil {
+			logger.WithError(err).Errorf("error retrieving apr of benchmark set %v", set)
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+
+		res := &types.ApiValidatorBenchmarkSet{
+			Set:               set,
+			Apr:               apr,
+			AprPercentileRank: utils.PercentileRank(percentiles, apr),
+			DailyPerformance:  performance,
+		}
+		summarizeValidatorBenchmarkSet(res)
+		data.Sets = append(data.Sets, res)
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// getBenchmarkSelector parses a set of the benchmark api and returns the selector of its validators
+func getBenchmarkSelector(r *http.Request, set string) (db.BenchmarkSelector, error) {
+	kind, value, _ := strings.Cut(set, ":")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return db.BenchmarkSelector{}, fmt.Errorf("%w, must be of the form type:value", errInvalidBenchmarkSet)
+	}
+
+	switch kind {
+	case "dashboard", "share":
+		id, group, _ := strings.Cut(value, ":")
+		groupID := uint64(0)
+		if group != "" {
+			var err error
+			groupID, err = strconv.ParseUint(group, 10, 64)
+			if err != nil {
+				return db.BenchmarkSelector{}, fmt.Errorf("%w group", errInvalidBenchmarkSet)
+			}
+		}
+		var validators []uint64
+		var err error
+		if kind == "share" {
+			validators, err = getDashboardValidators(r, 0, id, groupID)
+		} else {
+			dashboardID, parseErr := strconv.ParseUint(id, 10, 64)
+			if parseErr != nil {
+				return db.BenchmarkSelector{}, fmt.Errorf("%w dashboard id", errInvalidBenchmarkSet)
+			}
+			validators, err = getDashboardValidators(r, dashboardID, "", groupID)
+		}
+		if err != nil {
+			return db.BenchmarkSelector{}, err
+		}
+		return db.BenchmarkValidators(validators), nil
+	case "validatorset":
+		setID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return db.BenchmarkSelector{}, fmt.Errorf("%w validator set id", errInvalidBenchmarkSet)
+		}
+		userID, ok := getDashboardUserID(r)
+		if !ok {
+			return db.BenchmarkSelector{}, db.ErrValidatorSetNotFound
+		}
+		validatorSet, err := db.GetValidatorSet(userID, setID)
+		if err != nil {
+			return db.BenchmarkSelector{}, err
+		}
+		validators, err := db.GetValidatorSetValidators(validatorSet.ID)
+		if err != nil {
+			return db.BenchmarkSelector{}, err
+		}
+		return db.BenchmarkValidators(validators), nil
+	case "pool":
+		if len(value) > 40 {
+			return db.BenchmarkSelector{}, fmt.Errorf("%w pool", errInvalidBenchmarkSet)
+		}
+		return db.BenchmarkPool(value), nil
+	case "address":
+		value = strings.ToLower(ReplaceEnsNameWithAddress(value))
+		if utils.IsValidEth1Address(value) {
+			credentials, err := utils.AddressToWithdrawalCredentials(common.FromHex(value))
+			if err != nil {
+				return db.BenchmarkSelector{}, err
+			}
+			return db.BenchmarkWithdrawalCredentials(credentials), nil
+		}
+		if utils.IsValidWithdrawalCredentials(value) {
+			return db.BenchmarkWithdrawalCredentials(common.FromHex(value)), nil
+		}
+		return db.BenchmarkSelector{}, fmt.Errorf("%w withdrawal address or credentials", errInvalidBenchmarkSet)
+	case "validators":
+		validators, err := parseApiValidatorParamToIndices(value, getUserPremium(r).MaxValidators)
+		if err != nil {
+			return db.BenchmarkSelector{}, fmt.Errorf("%w validators: %v", errInvalidBenchmarkSet, err)
+		}
+		return db.BenchmarkValidators(validators), nil
+	default:
+		return db.BenchmarkSelector{}, fmt.Errorf("%w type, must be one of dashboard, share, validatorset, pool, address or validators", errInvalidBenchmarkSet)
+	}
+}
+
+// summarizeValidatorBenchmarkSet sums up the duties of the daily performance of a set
+func summarizeValidatorBenchmarkSet(set *types.ApiValidatorBenchmarkSet) {
+	attestations := uint64(0)
+	syncDuties := uint64(0)
+	for _, day := range set.DailyPerformance {
+		attestations += day.Validators * utils.EpochsPerDay()
+		syncDuties += day.ParticipatedSync + day.MissedSync
+		set.MissedAttestations += day.MissedAttestations
+		set.ProposedBlocks += day.ProposedBlocks
+		set.MissedBlocks += day.MissedBlocks
+		set.MissedSync += day.MissedSync
+	}
+	if attestations > 0 {
+		set.AttestationParticipation = 1 - float64(set.MissedAttestations)/float64(attestations)
+	}
+	if syncDuties > 0 {
+		set.SyncParticipation = 1 - float64(set.MissedSync)/float64(syncDuties)
+	}
+	if len(set.DailyPerformance) > 0 {
+		set.Validators = set.DailyPerformance[len(set.DailyPerformance)-1].Validators
+	}
+}
+
+func isBenchmarkWindow(days uint64) bool {
+	for _, window := range services.BenchmarkWindows {
+		if window == days {
+			return true
+		}
+	}
+	return false
+}
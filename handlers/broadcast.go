@@ -116,6 +116,8 @@ func BroadcastStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pageData.Validators = &validators
+	pageData.ScheduledExit, _ = job.GetScheduledExitNodeJobData()
+	pageData.Deposits, _ = job.GetDepositDataValidationNodeJobData()
 
 	data.Data = pageData
 	err = tpl.ExecuteTemplate(w, "layout", data)
@@ -131,6 +133,10 @@ func FormatNodeJobType(nodeJobType types.NodeJobType) string {
 		label = "Set withdrawal address"
 	case types.VoluntaryExitsNodeJobType:
 		label = "Voluntary exit"
+	case types.ScheduledExitsNodeJobType:
+		label = "Scheduled exit"
+	case types.DepositDataValidationNodeJobType:
+		label = "Deposit data validation"
 	}
 	return label
 }
@@ -142,6 +148,10 @@ func FormatNodeJobTitle(nodeJobType types.NodeJobType) string {
 		label = "Withdrawal Credentials Change Request Job"
 	case types.VoluntaryExitsNodeJobType:
 		label = "Voluntary Exit Request Job"
+	case types.ScheduledExitsNodeJobType:
+		label = "Scheduled Voluntary Exit Request Job"
+	case types.DepositDataValidationNodeJobType:
+		label = "Deposit Data Validation Job"
 	}
 	return label
 }
@@ -468,24 +468,10 @@ func GetCurrencySymbol(r *http.Request) string {
 		return "$"
 	}
 
-	switch cookie.Value {
-	case "AUD":
-		return "A$"
-	case "CAD":
-		return "C$"
-	case "CNY":
-		return "¥"
-	case "EUR":
-		return "€"
-	case "GBP":
-		return "£"
-	case "JPY":
-		return "¥"
-	case "RUB":
-		return "₽"
-	default:
-		return "$"
+	if symbol := price.GetSymbol(cookie.Value); symbol != "" {
+		return symbol
 	}
+	return "$"
 }
 
 func GetCurrentPrice(r *http.Request) uint64 {
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/eth1data"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+// maxContractAbiSize is the maximum size of a submitted ABI or metadata json
+const maxContractAbiSize = 1024 * 1024
+
+// ApiEth1TxDecoded godoc
+// @Summary Gets a transaction with its calldata, events and internal calls decoded by the ABIs of the involved contracts
+// @Tags Execution
+// @Description Calls and events of contracts without a known ABI are labeled by their 4-byte or event signature if it is known, their arguments are not decoded.
+// @Produce json
+// @Param hash path string true "Transaction hash"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1DecodedTxResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/execution/tx/{hash}/decoded [get]
+func ApiEth1TxDecoded(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	hash, err := hex.DecodeString(strings.TrimPrefix(vars["hash"], "0x"))
+	if err != nil || len(hash) != common.HashLength {
+		sendErrorResponse(w, r.URL.String(), "invalid transaction hash")
+		return
+	}
+
+	tx, err := eth1data.GetEth1Transaction(common.BytesToHash(hash))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			sendErrorResponse(w, r.URL.String(), "transaction not found")
+			return
+		}
+		logger.Errorf("error retrieving transaction for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve transaction")
+		return
+	}
+
+	// the tx page only traces successful txs to contracts with a known ABI, the api always returns the internal calls
+	internalCalls := tx.InternalCalls
+	if internalCalls == nil && tx.TargetIsContract && !tx.IsContractCreation {
+		internalCalls, err = eth1data.GetEth1TransactionInternalCalls(tx.Hash)
+		if err != nil {
+			logger.Errorf("error retrieving internal calls for %v route: %v", r.URL.String(), err)
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve internal calls")
+			return
+		}
+	}
+
+	response := &types.ApiEth1DecodedTxResponse{
+		Hash:          tx.Hash.Hex(),
+		From:          tx.From.Hex(),
+		Method:        tx.Method,
+		Call:          formatApiDecodedCall(tx.DecodedCallData),
+		Events:        make([]*types.ApiEth1DecodedEvent, 0, len(tx.Events)),
+		InternalCalls: make([]*types.ApiEth1DecodedInternal, 0, len(internalCalls)),
+	}
+	if tx.To != nil {
+		response.To = tx.To.Hex()
+	}
+
+	for _, event := range tx.Events {
+		formatted := &types.ApiEth1DecodedEvent{
+			Address: event.Address.Hex(),
+			Name:    event.Name,
+			Topics:  make([]string, 0, len(event.Topics)),
+			Data:    fmt.Sprintf("0x%x", event.Data),
+			Args:    make([]*types.ApiEth1DecodedArg, 0, len(event.DecodedData)),
+		}
+		for _, topic := range event.Topics {
+			formatted.Topics = append(formatted.Topics, topic.Hex())
+		}
+		for name, arg := range event.DecodedData {
+			formatted.Args = append(formatted.Args, &types.ApiEth1DecodedArg{Name: name, Type: arg.Type, Value: arg.Value})
+		}
+		sort.Slice(formatted.Args, func(i, j int) bool {
+			return formatted.Args[i].Name < formatted.Args[j].Name
+		})
+		response.Events = append(response.Events, formatted)
+	}
+
+	for _, call := range internalCalls {
+		response.InternalCalls = append(response.InternalCalls, &types.ApiEth1DecodedInternal{
+			TraceAddress: call.TraceAddress,
+			CallType:     call.CallType,
+			From:         call.From.Hex(),
+			To:           call.To.Hex(),
+			Value:        new(big.Int).SetBytes(call.Value).String(),
+			Input:        fmt.Sprintf("0x%x", call.Input),
+			Method:       call.Method,
+			Error:        call.Error,
+			Call:         formatApiDecodedCall(call.DecodedCall),
+		})
+	}
+
+	j := json.NewEncoder(w)
+	sendOKResponse(j, r.URL.String(), []interface{}{response})
+}
+
+// ApiContractAbiSubmit godoc
+// @Summary Submits the ABI of a contract that is used to decode its calls and events
+// @Tags Execution
+// @Description The ABI is either the json ABI of the contract or the metadata json of the Solidity compiler as published by Sourcify.
+// @Description It is only accepted if the selectors of all its functions and the ids of all its events are part of the deployed code of the contract, or of its implementation if the contract is an EIP-1967 proxy, and if it contains every function of the deployed code.
+// @Description The ABI of contracts whose ABI was retrieved from Etherscan can not be replaced, a submitted ABI can only be replaced by the user that submitted it.
+// @Accept json
+// @Produce json
+// @Param address path string true "Address of the contract"
+// @Param abi body types.ApiContractAbiRequest true "ABI of the contract"
+// @Success 200 {object} types.ApiResponse{data=types.ApiContractAbiResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/execution/contract/{address}/abi [post]
+func ApiContractAbiSubmit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	address := strings.ToLower(strings.TrimPrefix(vars["address"], "0x"))
+	if !utils.IsEth1Address(address) {
+		sendErrorResponse(w, r.URL.String(), "error invalid address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+	contract := common.HexToAddress(address)
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxContractAbiSize+1))
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "error reading body")
+		return
+	}
+	if len(body) > maxContractAbiSize {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("the abi must not be larger than %d bytes", maxContractAbiSize))
+		return
+	}
+	req := &types.ApiContractAbiRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid request body")
+		return
+	}
+
+	meta, err := utils.ParseContractMetadata(req.Abi)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Second*10)
+	defer cancel()
+	implementation, err := eth1data.VerifyContractAbi(ctx, contract, meta.ABI)
+	if err != nil {
+		if errors.Is(err, eth1data.ErrContractAbiMismatch) {
+			sendErrorResponse(w, r.URL.String(), err.Error())
+			return
+		}
+		logger.Errorf("error verifying abi of contract %v for %v route: %v", contract, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not verify abi")
+		return
+	}
+
+	// submitted abis never replace metadata of a verified source and the name of the contract is never taken from the submission
+	existing, err := db.BigtableClient.GetContractMetadata(contract.Bytes())
+	if err != nil && !errors.Is(err, utils.ErrRateLimit) {
+		logger.Errorf("error retrieving metadata of contract %v for %v route: %v", contract, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve contract metadata")
+		return
+	}
+	meta.Name = ""
+	meta.SubmittedBy = getUser(r).UserID
+	if existing != nil {
+		if existing.IsVerified() {
+			sendErrorResponse(w, r.URL.String(), "the contract already has a verified abi")
+			return
+		}
+		// a submitted abi can only be replaced by its submitter or an admin
+		if len(existing.ABIJson) > 0 && existing.SubmittedBy != meta.SubmittedBy {
+			userGroup, err := db.GetUserGroupById(meta.SubmittedBy)
+			if err != nil {
+				logger.Errorf("error retrieving group of user %v for %v route: %v", meta.SubmittedBy, r.URL.String(), err)
+				sendServerErrorResponse(w, r.URL.String(), "could not retrieve user")
+				return
+			}
+			if userGroup != "ADMIN" {
+				sendErrorResponse(w, r.URL.String(), "the abi of the contract was submitted by another user")
+				return
+			}
+		}
+		meta.Name = existing.Name
+	}
+
+	err = db.BigtableClient.SaveContractMetadata(contract.Bytes(), meta)
+	if err != nil {
+		logger.Errorf("error saving abi of contract %v for %v route: %v", contract, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not save abi")
+		return
+	}
+	logger.Infof("saved verified %v abi of contract %v submitted by user %v", meta.Source, contract, meta.SubmittedBy)
+
+	response := &types.ApiContractAbiResponse{
+		Address:   contract.Hex(),
+		Name:      meta.Name,
+		Source:    meta.Source,
+		Functions: len(meta.ABI.Methods),
+		Events:    len(meta.ABI.Events),
+	}
+	if implementation != nil {
+		response.Implementation = implementation.Hex()
+	}
+
+	j := json.NewEncoder(w)
+	sendOKResponse(j, r.URL.String(), []interface{}{response})
+}
+
+func formatApiDecodedCall(call *types.Eth1DecodedCall) *types.ApiEth1DecodedCall {
+	if call == nil {
+		return nil
+	}
+	formatted := &types.ApiEth1DecodedCall{
+		Name:      call.Name,
+		Signature: call.Signature,
+		Args:      make([]*types.ApiEth1DecodedArg, 0, len(call.Args)),
+	}
+	for _, arg := range call.Args {
+		formatted.Args = append(formatted.Args, &types.ApiEth1DecodedArg{Name: arg.Name, Type: arg.Type, Value: arg.Value})
+	}
+	return formatted
+}
+
+// ApiEth1IndexedEvents godoc
+// @Summary Gets the latest events of a contract that is tracked by a configured event indexer
+// @Tags Execution
+// @Description Event indexers are configured per explorer instance, each indexes the events of one contract.
+// @Description The arguments of the events are decoded with the ABI of the indexer, indexed arguments of dynamic types only contain their hash.
+// @Produce json
+// @Param indexerName path string true "Name of the event indexer"
+// @Param address query string false "Only return events that have the address as argument"
+// @Param limit query int false "Number of events to return, at most 100 (default 25)"
+// @Param pageToken query string false "Page token of the previous response"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1IndexedEventsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/execution/events/{indexerName} [get]
+func ApiEth1IndexedEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	indexer, err := db.GetEventIndexer(mux.Vars(r)["indexerName"])
+	if err != nil {
+		if errors.Is(err, db.ErrEventIndexerNotFound) {
+			sendErrorResponse(w, r.URL.String(), "event indexer not found")
+			return
+		}
+		logger.Errorf("error initializing event indexer for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "event indexer is not configured correctly")
+		return
+	}
+
+	var address *common.Address
+	if q.Get("address") != "" {
+		addressString := strings.ToLower(strings.TrimPrefix(ReplaceEnsNameWithAddress(q.Get("address")), "0x"))
+		if !utils.IsEth1Address(addressString) {
+			sendErrorResponse(w, r.URL.String(), "error invalid address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+			return
+		}
+		parsed := common.HexToAddress(addressString)
+		address = &parsed
+	}
+
+	limit := int64(25)
+	if q.Get("limit") != "" {
+		limit, err = strconv.ParseInt(q.Get("limit"), 10, 64)
+		if err != nil || limit < 1 || limit > 100 {
+			sendErrorResponse(w, r.URL.String(), "invalid limit, must be between 1 and 100")
+			return
+		}
+	}
+
+	events, nextPageToken, err := db.BigtableClient.GetIndexedEvents(indexer, address, q.Get("pageToken"), limit)
+	if err != nil {
+		logger.Errorf("error retrieving events of indexer %v for %v route: %v", indexer.Name(), r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve events")
+		return
+	}
+
+	response := &types.ApiEth1IndexedEventsResponse{
+		Indexer:       indexer.Name(),
+		Contract:      indexer.Contract().Hex(),
+		Events:        make([]*types.ApiEth1IndexedEvent, 0, len(events)),
+		NextPageToken: nextPageToken,
+	}
+	for _, event := range events {
+		formatted := &types.ApiEth1IndexedEvent{
+			BlockNumber: event.BlockNumber,
+			Timestamp:   event.Time,
+			TxHash:      fmt.Sprintf("0x%x", event.TxHash),
+			LogIndex:    event.LogIndex,
+			Args:        []*types.ApiEth1DecodedArg{},
+		}
+		if abiEvent := indexer.Event(event.Topics); abiEvent != nil {
+			formatted.Name = abiEvent.RawName
+			args, err := eth1data.DecodeEventArgs(abiEvent, event.Topics, event.Data)
+			if err != nil {
+				logger.Warnf("error decoding event of indexer %v in tx 0x%x: %v", indexer.Name(), event.TxHash, err)
+			}
+			for _, arg := range args {
+				formatted.Args = append(formatted.Args, &types.ApiEth1DecodedArg{Name: arg.Name, Type: arg.Type, Value: arg.Value})
+			}
+		}
+		response.Events = append(response.Events, formatted)
+	}
+
+	j := json.NewEncoder(w)
+	sendOKResponse(j, r.URL.String(), []interface{}{response})
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -37,6 +36,24 @@ func handleValidatorsQuery(w http.ResponseWriter, r *http.Request, checkValidato
 
 	fieldMap := map[string]interface{}{"route": r.URL.String()}
 
+	// Use the validators of a stored dashboard if the request references one
+	dashboardValidators, isDashboard, err := getDashboardValidatorsFromQuery(r)
+	if isDashboard {
+		if err != nil {
+			logger.Warnf("could not get validators of dashboard: %v; Route: %v", err, r.URL.String())
+			http.Error(w, "Not found", http.StatusNotFound)
+			return nil, nil, false, err
+		}
+		if len(dashboardValidators) > validatorLimit {
+			if checkValidatorLimit {
+				http.Error(w, "Invalid query", http.StatusBadRequest)
+				return nil, nil, false, ErrTooManyValidators
+			}
+			dashboardValidators = dashboardValidators[:validatorLimit]
+		}
+		return dashboardValidators, [][]byte{}, false, nil
+	}
+
 	// Parse all the validator indices and pubkeys from the query string
 	queryValidatorIndices, queryValidatorPubkeys, err := parseValidatorsFromQueryString(q.Get("validators"), validatorLimit)
 	if err != nil && (checkValidatorLimit || err != ErrTooManyValidators) {
@@ -102,10 +119,15 @@ func parseValidatorsFromQueryString(str string, validatorLimit int) ([]uint64, [
 	}
 
 	// Find all indices
+	var ensNames []string
 	for _, vStr := range strSplit {
 		if searchPubkeyExactRE.MatchString(vStr) {
 			continue
 		}
+		if utils.IsValidEnsDomain(vStr) {
+			ensNames = append(ensNames, vStr)
+			continue
+		}
 		v, err := strconv.ParseUint(vStr, 10, 64)
 		if err != nil {
 			return []uint64{}, [][]byte{}, err
@@ -118,6 +140,25 @@ func parseValidatorsFromQueryString(str string, validatorLimit int) ([]uint64, [
 		validatorIndices = append(validatorIndices, v)
 	}
 
+	// Ens names are expanded to the validators of the address they resolve to, the names stay in the query string so that a change of the address record is picked up on the next request
+	for _, name := range ensNames {
+		validators, err := getEnsValidators(name, validatorLimit+1)
+		if err != nil {
+			return []uint64{}, [][]byte{}, err
+		}
+		for _, v := range validators {
+			if exists := keys[v.Index]; exists {
+				continue
+			}
+			keys[v.Index] = true
+			validatorIndices = append(validatorIndices, v.Index)
+		}
+	}
+
+	if len(validatorIndices)+len(validatorPubkeys) > validatorLimit {
+		return []uint64{}, [][]byte{}, ErrTooManyValidators
+	}
+
 	return validatorIndices, validatorPubkeys, nil
 }
 
@@ -127,8 +168,7 @@ func updateValidatorsQueryString(w http.ResponseWriter, r *http.Request, validat
 		return false, nil
 	}
 
-	// Convert pubkeys to indices if possible
-	// validatorsCount stays the same after conversion
+	// Convert pubkeys to indices if possible, the other entries of the query string (indices and ens names) are kept as they are
 	redirect := false
 	if len(validatorPubkeys) > 0 {
 		validatorInfos := []struct {
@@ -140,33 +180,28 @@ func updateValidatorsQueryString(w http.ResponseWriter, r *http.Request, validat
 			return false, err
 		}
 
+		indexByPubkey := make(map[string]uint64, len(validatorInfos))
 		for _, info := range validatorInfos {
-			// Having duplicates of validator indices is not a problem so we don't need to check for that
-			validatorIndices = append(validatorIndices, info.Index)
+			indexByPubkey[fmt.Sprintf("%#x", info.Pubkey)] = info.Index
+		}
 
-			redirect = true
-			for idx, pubkey := range validatorPubkeys {
-				if bytes.Contains(pubkey, info.Pubkey) {
-					validatorPubkeys = append(validatorPubkeys[:idx], validatorPubkeys[idx+1:]...)
-					break
-				}
+		q := r.URL.Query()
+		strValidators := strings.Split(q.Get("validators"), ",")
+		for i, vStr := range strValidators {
+			// Having duplicates of validator indices is not a problem so we don't need to check for that
+			if index, exists := indexByPubkey[strings.ToLower(vStr)]; exists {
+				strValidators[i] = fmt.Sprintf("%v", index)
+				redirect = true
 			}
 		}
-	}
 
-	if redirect {
-		strValidators := make([]string, validatorsCount)
-		for i, n := range validatorIndices {
-			strValidators[i] = fmt.Sprintf("%v", n)
+		if redirect {
+			q.Set("validators", strings.Join(strValidators, ","))
+			r.URL.RawQuery = q.Encode()
 		}
-		for i, n := range validatorPubkeys {
-			strValidators[i+len(validatorIndices)] = fmt.Sprintf("%#x", n)
-		}
-
-		q := r.URL.Query()
-		q.Set("validators", strings.Join(strValidators, ","))
-		r.URL.RawQuery = q.Encode()
+	}
 
+	if redirect {
 		http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
 	}
 	return redirect, nil
@@ -292,16 +327,21 @@ func Dashboard(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html")
 
-	_, _, redirect, err := handleValidatorsQuery(w, r, false)
+	validators, _, redirect, err := handleValidatorsQuery(w, r, false)
 	if err != nil || redirect {
 		return
 	}
 
 	dashboardData := types.DashboardData{}
 	dashboardData.ValidatorLimit = getUserPremium(r).MaxValidators
+	if isDashboardQuery(r) {
+		dashboardData.StoredDashboard = true
+		dashboardData.StoredDashboardValidators = validators
+	}
 
 	epoch := services.LatestEpoch()
 	dashboardData.CappellaHasHappened = epoch >= (utils.Config.Chain.Config.CappellaForkEpoch)
+	dashboardData.IncomeDetailsEnabled = utils.Config.RollingValidatorStats.Enabled
 
 	data := InitPageData(w, r, "dashboard", "/dashboard", "Dashboard", templateFiles)
 	data.Data = dashboardData
@@ -316,6 +356,31 @@ func getNextWithdrawalRow(queryValidators []uint64) ([][]interface{}, error) {
 		return nil, nil
 	}
 
+	if services.WithdrawalPredictionsAvailable() {
+		// use the simulated withdrawal sweep to find the validator that will be withdrawn next
+		var next *types.PredictedWithdrawal
+		for _, index := range queryValidators {
+			p, ok := services.GetPredictedWithdrawal(index)
+			if ok && (next == nil || p.Slot < next.Slot) {
+				next = p
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+
+		// a validator that was already withdrawn in the current epoch has nothing left to withdraw in the current sweep
+		_, lastWithdrawnEpoch, err := db.GetValidatorWithdrawalsCount(next.ValidatorIndex)
+		if err != nil {
+			return nil, err
+		}
+		amount := next.Amount
+		if lastWithdrawnEpoch == services.LatestEpoch() {
+			amount = 0
+		}
+		return [][]interface{}{nextWithdrawalRowFromPrediction(next, amount)}, nil
+	}
+
 	stats := services.GetLatestStats()
 	if stats == nil || stats.LatestValidatorWithdrawalIndex == nil || stats.TotalValidatorCount == nil {
 		return nil, errors.New("stats not available")
@@ -443,6 +508,25 @@ func getNextWithdrawalRow(queryValidators []uint64) ([][]interface{}, error) {
 	return nextData, nil
 }
 
+// nextWithdrawalRowFromPrediction returns the row of the next withdrawal table for a predicted withdrawal of amount
+func nextWithdrawalRowFromPrediction(p *types.PredictedWithdrawal, amount uint64) []interface{} {
+	var withdrawalCredentialsTemplate template.HTML
+	if len(p.Address) > 0 {
+		withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="/address/0x%x"><span class="text-muted">%s</span></a>`, p.Address, utils.FormatAddress(p.Address, nil, "", false, false, true)))
+	} else {
+		withdrawalCredentialsTemplate = `<span class="text-muted">N/A</span>`
+	}
+
+	return []interface{}{
+		template.HTML(fmt.Sprintf("%v", utils.FormatValidator(p.ValidatorIndex))),
+		template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(p.Slot/utils.Config.Chain.Config.SlotsPerEpoch))),
+		template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(p.Slot))),
+		template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(p.Time.Unix()))),
+		withdrawalCredentialsTemplate,
+		template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="If the withdrawal were to be processed at this very moment, this amount would be withdrawn"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatAmount(new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(1e9)), "Ether", 6))),
+	}
+}
+
 // Dashboard Chart that combines balance data and
 func DashboardDataBalanceCombined(w http.ResponseWriter, r *http.Request) {
 	currency := GetCurrency(r)
@@ -515,6 +599,24 @@ func getExecutionChartData(indices []uint64, currency string) ([]*types.ChartDat
 	lastFinalizedEpoch := services.LatestFinalizedEpoch()
 	color := "#90ed7d"
 
+	// the rewards are valued at the historic price of the hour of each block, the current price is only used for blocks without historic prices
+	var prices *db.PriceSeries
+	if currency != "ETH" && len(blocks) > 0 {
+		from, to := blocks[0].Time.AsTime(), blocks[0].Time.AsTime()
+		for _, b := range blocks {
+			if b.Time.AsTime().Before(from) {
+				from = b.Time.AsTime()
+			}
+			if b.Time.AsTime().After(to) {
+				to = b.Time.AsTime()
+			}
+		}
+		prices, err = db.GetPriceSeries(currency, from, to)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for i := len(blocks) - 1; i >= 0; i-- {
 		blockEpoch := utils.TimeToEpoch(blocks[i].Time.AsTime())
 		consData := consMap[blocks[i].Number]
@@ -536,9 +638,16 @@ func getExecutionChartData(indices []uint64, currency string) ([]*types.ChartDat
 			totalReward = utils.WeiToEther(utils.Eth1TotalReward(blocks[i])).InexactFloat64()
 		}
 
+		rate := utils.ExchangeRateForCurrency(currency)
+		if prices != nil {
+			if price := prices.At(blocks[i].Time.AsTime()); price > 0 {
+				rate = price
+			}
+		}
+
 		chartData[len(blocks)-1-i] = &types.ChartDataPoint{
 			X:     ts,
-			Y:     utils.ExchangeRateForCurrency(currency) * totalReward,
+			Y:     rate * totalReward,
 			Color: color,
 		}
 	}
@@ -554,6 +663,12 @@ func DashboardDataBalance(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	validatorLimit := getUserPremium(r).MaxValidators
 	queryValidatorIndices, queryValidatorPubkeys, err := parseValidatorsFromQueryString(q.Get("validators"), validatorLimit)
+	if dashboardValidators, isDashboard, dashboardErr := getDashboardValidatorsFromQuery(r); isDashboard {
+		queryValidatorIndices, err = dashboardValidators, dashboardErr
+		if err == nil && len(queryValidatorIndices) > validatorLimit {
+			err = ErrTooManyValidators
+		}
+	}
 	if err != nil || len(queryValidatorPubkeys) > 0 {
 		utils.LogError(err, fmt.Errorf("error parsing validators from query string"), 0)
 		http.Error(w, "Invalid query", http.StatusBadRequest)
@@ -795,7 +910,7 @@ func DashboardDataValidators(w http.ResponseWriter, r *http.Request) {
 	latestEpoch := services.LatestEpoch()
 
 	stats := services.GetLatestStats()
-	churnRate := stats.ValidatorChurnLimit
+	churnRate := stats.ValidatorActivationChurnLimit
 
 	if len(validatorIndexArr) > 0 {
 		balances, err := db.BigtableClient.GetValidatorBalanceHistory(validatorIndexArr, latestEpoch, latestEpoch)
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"eth2-exporter/cache"
 	"eth2-exporter/db"
+	"eth2-exporter/eth1data"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
@@ -19,7 +21,7 @@ import (
 // ApiEnsLookup godoc
 // @Summary Get the address for an ens name and vice versa
 // @Tags Ens
-// @Description Returns and object with the ens name and address - if found.
+// @Description Returns and object with the ens name and address - if found. The records contain the text records, the contenthash and the expiry status of the name.
 // @Produce  json
 // @Param domain path string true "domain can either be an ens name or an etherum address"
 // @Success 200 {object} types.ApiResponse
@@ -37,6 +39,7 @@ func ResolveEnsDomain(w http.ResponseWriter, r *http.Request) {
 		sendErrorResponse(w, r.URL.String(), "failed to resolve ens")
 		return
 	}
+	data.Records = getEnsRecords(data.Domain)
 
 	j := json.NewEncoder(w)
 	sendOKResponse(j, r.URL.String(), []interface{}{data})
@@ -55,7 +58,7 @@ func GetEnsDomain(search string) (*types.EnsDomainResponse, error) {
 			data.Address = address
 			return data, nil
 		}
-		address, err := db.GetAddressForEnsName(search)
+		address, err := eth1data.ResolveEnsName(search)
 		if err != nil {
 			return data, err // We want to return the data if it was a valid domain even if there was an error getting the address from bigtable. A valid domain might be enough for the caller.
 		}
@@ -89,6 +92,30 @@ func GetEnsDomain(search string) (*types.EnsDomainResponse, error) {
 	return data, returnError //We always want to return the data if it was a valid address/domain even if there was an error getting data. A valid address might be enough for the caller.
 }
 
+// getEnsRecords returns the indexed records of an ens name or nil if the name is not indexed
+func getEnsRecords(name string) *types.EnsRecords {
+	if name == "" {
+		return nil
+	}
+	records, err := db.GetEnsRecords(name)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.Errorf("error retrieving ens records of %v: %v", name, err)
+		}
+		return nil
+	}
+	return records
+}
+
+// getEnsValidators resolves an ens name and returns the validators that were deposited by or withdraw to its address, at most limit validators are returned
+var getEnsValidators = func(name string, limit int) ([]*types.ValidatorSetMember, error) {
+	ensData, err := GetEnsDomain(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve ens name %v: %w", name, err)
+	}
+	return db.GetValidatorsForEth1Address(common.FromHex(ensData.Address), limit)
+}
+
 func ReplaceEnsNameWithAddress(search string) string {
 	if utils.IsValidEnsDomain(search) {
 		ensData, _ := GetEnsDomain(search)
@@ -27,6 +27,7 @@ func EnsSearch(w http.ResponseWriter, r *http.Request) {
 		pageData.Error = "No matching ENS registration found"
 	} else {
 		pageData.Result = result
+		pageData.Result.Records = getEnsRecords(result.Domain)
 	}
 	pageData.Search = search
 
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
 	"github.com/shopspring/decimal"
 	"golang.org/x/sync/errgroup"
@@ -74,6 +75,7 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 	unclesMined := &types.DataTableResponse{}
 	withdrawals := &types.DataTableResponse{}
 	withdrawalSummary := template.HTML("0")
+	var ensRecords *types.EnsRecords
 
 	g.Go(func() error {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
@@ -173,6 +175,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		withdrawalSummary = template.HTML(fmt.Sprintf("%v", utils.FormatAmount(new(big.Int).Mul(new(big.Int).SetUint64(sumWithdrawals), big.NewInt(1e9)), "Ether", 6)))
 		return nil
 	})
+	g.Go(func() error {
+		// addresses without a primary name are not an error
+		ensData, err := GetEnsDomain(fmt.Sprintf("0x%x", addressBytes))
+		if err == nil {
+			ensRecords = getEnsRecords(ensData.Domain)
+		}
+		return nil
+	})
 	// }
 
 	if err := g.Wait(); err != nil {
@@ -248,23 +258,58 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	tabs = append(tabs, types.Eth1AddressPageTabs{
+		Id:   "balanceHistory",
+		Href: "#balanceHistory",
+		Text: "Balance History",
+	})
+
+	var contract *types.ContractMetadata
+	if isContract {
+		contract, err = db.BigtableClient.GetContractMetadata(addressBytes)
+		if err != nil {
+			logger.WithError(err).Warnf("error retrieving contract metadata of address %v", address)
+		}
+		if contract != nil && contract.ABI == nil {
+			contract = nil
+		}
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "contract",
+			Href: "#contract",
+			Text: "Contract",
+		})
+	}
+
+	ensExpirySubscribed := false
+	if user := getUser(r); user.Authenticated {
+		ensExpirySubscribed, err = db.HasSubscription(user.UserID, utils.GetNetwork(), types.EnsNameExpiringEventName, address)
+		if err != nil {
+			logger.WithError(err).Errorf("error retrieving ens expiry subscription of user %v for address %v", user.UserID, address)
+		}
+	}
+
 	data.Data = types.Eth1AddressPageData{
-		Address:            address,
-		IsContract:         isContract,
-		QRCode:             pngStr,
-		QRCodeInverse:      pngStrInverse,
-		Metadata:           metadata,
-		WithdrawalsSummary: withdrawalSummary,
-		TransactionsTable:  txns,
-		InternalTxnsTable:  internal,
-		Erc20Table:         erc20,
-		Erc721Table:        erc721,
-		Erc1155Table:       erc1155,
-		WithdrawalsTable:   withdrawals,
-		BlocksMinedTable:   blocksMined,
-		UnclesMinedTable:   unclesMined,
-		EtherValue:         utils.FormatEtherValue(symbol, ethPrice, GetCurrentPriceFormatted(r)),
-		Tabs:               tabs,
+		Address:             address,
+		IsContract:          isContract,
+		QRCode:              pngStr,
+		QRCodeInverse:       pngStrInverse,
+		Metadata:            metadata,
+		WithdrawalsSummary:  withdrawalSummary,
+		TransactionsTable:   txns,
+		InternalTxnsTable:   internal,
+		Erc20Table:          erc20,
+		Erc721Table:         erc721,
+		Erc1155Table:        erc1155,
+		WithdrawalsTable:    withdrawals,
+		BlocksMinedTable:    blocksMined,
+		UnclesMinedTable:    unclesMined,
+		EtherValue:          utils.FormatEtherValue(symbol, ethPrice, GetCurrentPriceFormatted(r)),
+		Tabs:                tabs,
+		Contract:            contract,
+		Labels:              getUserAddressLabels(r, addressBytes)[string(addressBytes)],
+		Ens:                 ensRecords,
+		EnsExpirySubscribed: ensExpirySubscribed,
+		CsrfField:           csrf.TemplateField(r),
 	}
 
 	if handleTemplateError(w, r, "eth1Account.go", "Eth1Address", "Done", eth1AddressTemplate.ExecuteTemplate(w, "layout", data)) != nil {
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 )
 
@@ -75,6 +76,7 @@ func Eth1Block(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	eth1BlockPageData.MinerLabels = getUserAddressLabels(r, common.FromHex(eth1BlockPageData.MinerAddress))[string(common.FromHex(eth1BlockPageData.MinerAddress))]
 
 	// execute template based on whether block is pre or post merge
 	if eth1BlockPageData.Difficulty.Cmp(big.NewInt(0)) == 0 {
@@ -11,6 +11,7 @@ import (
 	"html/template"
 	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -33,12 +34,12 @@ func Eth1Token(w http.ResponseWriter, r *http.Request) {
 	// symbol := GetCurrencySymbol(r)
 
 	g := new(errgroup.Group)
-	g.SetLimit(3)
+	g.SetLimit(4)
 
 	var txns *types.DataTableResponse
 	var metadata *types.ERC20Metadata
 	var balance *types.Eth1AddressBalance
-	// var holders *types.DataTableResponse
+	var holders uint64
 
 	g.Go(func() error {
 		var err error
@@ -52,6 +53,12 @@ func Eth1Token(w http.ResponseWriter, r *http.Request) {
 		return err
 	})
 
+	g.Go(func() error {
+		var err error
+		holders, err = db.GetTokenHolderCount(token)
+		return err
+	})
+
 	if address != nil {
 		g.Go(func() error {
 			var err error
@@ -113,7 +120,7 @@ func Eth1Token(w http.ResponseWriter, r *http.Request) {
 		QRCodeInverse:    pngStrInverse,
 		MarketCap:        template.HTML("$" + utils.FormatThousandsEnglish(fmt.Sprintf("%.2f", marketCap))),
 		SocialProfiles:   template.HTML(``),
-		Holders:          template.HTML(`<span>500</span>`),
+		Holders:          template.HTML(fmt.Sprintf(`<span>%s</span>`, utils.FormatThousandsEnglish(fmt.Sprintf("%d", holders)))),
 		Transfers:        template.HTML(`<span>10,000</span>`),
 		DilutedMarketCap: template.HTML("$" + utils.FormatThousandsEnglish(fmt.Sprintf("%.2f", marketCap))),
 		Price:            template.HTML(fmt.Sprintf("<span>$%s</span><span>@ %.6f</span>", string(metadata.Price), ethExchangeRate)),
@@ -149,3 +156,136 @@ func Eth1TokenTransfers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// ApiTokenHolders godoc
+// @Summary Gets the holders of an ERC-20 token ranked by their balance, either the latest balances or a snapshot at a block
+// @Tags Execution
+// @Description Balances are retrieved by the token holder updater at the block of every transfer of the token, balances of a snapshot are only known from the first indexed transfer of a holder on.
+// @Description The share of a holder is relative to the current total supply of the token.
+// @Produce json
+// @Param token path string true "Address of the token"
+// @Param block query int false "Block number of the snapshot, the latest balances are returned if it is omitted"
+// @Param limit query int false "Number of holders to return, at most 1000 (default 100)"
+// @Param offset query int false "Number of holders to skip"
+// @Success 200 {object} types.ApiResponse{data=types.ApiTokenHoldersResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/execution/token/{token}/holders [get]
+func ApiTokenHolders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	tokenString := strings.ToLower(strings.TrimPrefix(mux.Vars(r)["token"], "0x"))
+	if !utils.IsEth1Address(tokenString) {
+		sendErrorResponse(w, r.URL.String(), "error invalid token address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+	token := common.FromHex(tokenString)
+
+	limit := uint64(100)
+	offset := uint64(0)
+	var err error
+	if q.Get("limit") != "" {
+		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil || limit == 0 || limit > 1000 {
+			sendErrorResponse(w, r.URL.String(), "invalid limit, must be between 1 and 1000")
+			return
+		}
+	}
+	if q.Get("offset") != "" {
+		offset, err = strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid offset")
+			return
+		}
+	}
+
+	response := &types.ApiTokenHoldersResponse{
+		Token: common.BytesToAddress(token).Hex(),
+	}
+	var holders []*types.TokenHolder
+	if q.Get("block") != "" {
+		block, err := strconv.ParseUint(q.Get("block"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid block")
+			return
+		}
+		response.Block = &block
+		holders, response.Holders, err = db.GetTokenHoldersAtBlock(token, block, limit, offset)
+		if err != nil {
+			logger.Errorf("error retrieving token holders for %v route: %v", r.URL.String(), err)
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+	} else {
+		holders, err = db.GetTokenHolders(token, limit, offset)
+		if err == nil {
+			response.Holders, err = db.GetTokenHolderCount(token)
+		}
+		if err != nil {
+			logger.Errorf("error retrieving token holders for %v route: %v", r.URL.String(), err)
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+	}
+
+	metadata, err := db.BigtableClient.GetERC20MetadataForAddress(token)
+	if err != nil {
+		logger.Errorf("error retrieving token metadata for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve token metadata")
+		return
+	}
+	totalSupply := decimal.NewFromBigInt(new(big.Int).SetBytes(metadata.TotalSupply), 0)
+
+	response.Top = make([]*types.ApiTokenHolder, 0, len(holders))
+	for i, holder := range holders {
+		formatted := &types.ApiTokenHolder{
+			Rank:        offset + uint64(i) + 1,
+			Address:     common.BytesToAddress(holder.Address).Hex(),
+			Balance:     holder.Balance.String(),
+			BlockNumber: holder.BlockNumber,
+		}
+		if totalSupply.IsPositive() {
+			formatted.Share, _ = holder.Balance.Div(totalSupply).Float64()
+		}
+		response.Top = append(response.Top, formatted)
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiTokenHolderCountHistory godoc
+// @Summary Gets the daily number of holders of an ERC-20 token
+// @Tags Execution
+// @Produce json
+// @Param token path string true "Address of the token"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiTokenHolderCountResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/execution/token/{token}/holders/history [get]
+func ApiTokenHolderCountHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	tokenString := strings.ToLower(strings.TrimPrefix(mux.Vars(r)["token"], "0x"))
+	if !utils.IsEth1Address(tokenString) {
+		sendErrorResponse(w, r.URL.String(), "error invalid token address. A ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	counts, err := db.GetTokenHolderCountHistory(common.FromHex(tokenString))
+	if err != nil {
+		logger.Errorf("error retrieving token holder counts for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	response := make([]interface{}, 0, len(counts))
+	for _, count := range counts {
+		response = append(response, &types.ApiTokenHolderCountResponse{
+			Day:     count.Day.Format("2006-01-02"),
+			Holders: count.Holders,
+		})
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), response)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -27,8 +29,8 @@ import (
 // Tx will show the tx using a go template
 func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 	txNotFoundTemplateFiles := append(layoutTemplateFiles, "eth1txnotfound.html")
-	txTemplateFiles := append(layoutTemplateFiles, "eth1tx.html")
-	mempoolTxTemplateFiles := append(layoutTemplateFiles, "mempoolTx.html")
+	txTemplateFiles := append(layoutTemplateFiles, "eth1tx.html", "components/mempoolReplacements.html")
+	mempoolTxTemplateFiles := append(layoutTemplateFiles, "mempoolTx.html", "components/mempoolReplacements.html")
 	var txNotFoundTemplate = templates.GetTemplate(txNotFoundTemplateFiles...)
 	var txTemplate = templates.GetTemplate(txTemplateFiles...)
 	var mempoolTxTemplate = templates.GetTemplate(mempoolTxTemplateFiles...)
@@ -61,6 +63,7 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 				if mempoolTx.Input != nil {
 					mempoolPageData.TargetIsContract = true
 				}
+				mempoolPageData.Lifecycle, mempoolPageData.Replacements = getMempoolTxHistory(txHash)
 
 				data.Data = mempoolPageData
 			} else {
@@ -93,7 +96,7 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 				if txDay < currentDay {
 					// Do not show the historical price if it is the current day
 					currency := GetCurrency(r)
-					price, err := db.GetHistoricalPrice(utils.Config.Chain.Config.DepositChainID, currency, txDay)
+					price, err := db.GetHistoricalPrice(currency, txDay)
 					if err != nil {
 						utils.LogError(err, "error retrieving historical prices", 0, map[string]interface{}{"txDay": txDay, "currency": currency})
 					} else {
@@ -103,6 +106,21 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
+			txData.MempoolLifecycle, txData.MempoolReplacements = getMempoolTxHistory(txHash)
+
+			// the tx data is shared through the cache, the labels depend on the user and are only set on a copy
+			txDataCopy := *txData
+			txData = &txDataCopy
+			addresses := [][]byte{txData.From.Bytes()}
+			if txData.To != nil {
+				addresses = append(addresses, txData.To.Bytes())
+			}
+			labels := getUserAddressLabels(r, addresses...)
+			txData.FromLabels = labels[string(txData.From.Bytes())]
+			if txData.To != nil {
+				txData.ToLabels = labels[string(txData.To.Bytes())]
+			}
+
 			data = InitPageData(w, r, "blockchain", path, title, txTemplateFiles)
 			data.Data = txData
 		}
@@ -119,3 +137,32 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 		return // an error has occurred and was processed
 	}
 }
+
+// getMempoolTxHistory returns the tracked mempool lifecycle of a tx as well as all other txs of the same sender that used the same nonce
+func getMempoolTxHistory(txHash []byte) (*types.MempoolTxLifecycle, []*types.MempoolTxLifecycle) {
+	if !utils.Config.Frontend.MempoolTracker.Enabled {
+		return nil, nil
+	}
+
+	lifecycle, err := db.GetMempoolTransactionLifecycle(txHash)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.Errorf("error retrieving mempool lifecycle of tx %x: %v", txHash, err)
+		}
+		return nil, nil
+	}
+
+	related, err := db.GetMempoolTransactionReplacements(lifecycle.Sender, lifecycle.Nonce)
+	if err != nil {
+		logger.Errorf("error retrieving mempool replacements of tx %x: %v", txHash, err)
+		return lifecycle, nil
+	}
+
+	replacements := make([]*types.MempoolTxLifecycle, 0, len(related))
+	for _, l := range related {
+		if !bytes.Equal(l.Hash, lifecycle.Hash) {
+			replacements = append(replacements, l)
+		}
+	}
+	return lifecycle, replacements
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"net/http"
+	"time"
+)
+
+// DashboardDataIncomeDetails returns the income components of the dashboard validators per epoch (granularity=epoch) of the
+// epoch window or per hour (granularity=hour) of the hourly window of the rolling validator stats
+func DashboardDataIncomeDetails(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !utils.Config.RollingValidatorStats.Enabled {
+		http.Error(w, "Income details are not available", http.StatusNotFound)
+		return
+	}
+
+	validators, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+
+	granularity := r.URL.Query().Get("granularity")
+	var income []*types.RollingValidatorIncome
+	switch granularity {
+	case "", "epoch":
+		granularity = "epoch"
+		income, err = db.GetRollingValidatorIncomeSumsPerEpoch(validators, time.Now().Add(-utils.Config.RollingValidatorStats.EpochWindow))
+	case "hour":
+		income, err = db.GetRollingValidatorIncomeSumsPerHour(validators, time.Now().Add(-utils.Config.RollingValidatorStats.HourlyWindow))
+	default:
+		http.Error(w, "Invalid granularity", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error retrieving income details")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(getDashboardIncomeDetails(granularity, income))
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getDashboardIncomeDetails groups the income columns into the net source, target, head, sync committee and proposer income,
+// slashings and the finality delay penalty are summed up as other income
+func getDashboardIncomeDetails(granularity string, income []*types.RollingValidatorIncome) *types.DashboardIncomeDetails {
+	n := len(income)
+	data := &types.DashboardIncomeDetails{
+		Granularity:        granularity,
+		Timestamps:         make([]int64, 0, n),
+		Source:             make([]int64, 0, n),
+		Target:             make([]int64, 0, n),
+		Head:               make([]int64, 0, n),
+		Sync:               make([]int64, 0, n),
+		Proposer:           make([]int64, 0, n),
+		Other:              make([]int64, 0, n),
+		AttestationsMissed: make([]uint64, 0, n),
+		ProposalsMissed:    make([]uint64, 0, n),
+	}
+	for _, i := range income {
+		data.Timestamps = append(data.Timestamps, i.Ts.UnixMilli())
+		data.Source = append(data.Source, i.AttestationSourceReward-i.AttestationSourcePenalty)
+		data.Target = append(data.Target, i.AttestationTargetReward-i.AttestationTargetPenalty)
+		data.Head = append(data.Head, i.AttestationHeadReward)
+		data.Sync = append(data.Sync, i.SyncCommitteeReward-i.SyncCommitteePenalty)
+		data.Proposer = append(data.Proposer, i.ProposerAttestationInclusionReward+i.ProposerSyncInclusionReward+i.ProposerSlashingInclusionReward)
+		data.Other = append(data.Other, i.SlashingReward-i.SlashingPenalty-i.FinalityDelayPenalty)
+		data.AttestationsMissed = append(data.AttestationsMissed, i.AttestationsMissed)
+		data.ProposalsMissed = append(data.ProposalsMissed, i.ProposalsMissed)
+	}
+	return data
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"eth2-exporter/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetDashboardIncomeDetails(t *testing.T) {
+	first := time.Date(2023, 9, 14, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	income := []*types.RollingValidatorIncome{
+		{
+			Ts:                                 first,
+			AttestationSourceReward:            100,
+			AttestationTargetReward:            200,
+			AttestationHeadReward:              50,
+			SyncCommitteeReward:                30,
+			ProposerAttestationInclusionReward: 1000,
+			ProposerSyncInclusionReward:        20,
+			ProposerSlashingInclusionReward:    5,
+		},
+		{
+			Ts:                       second,
+			AttestationSourcePenalty: 100,
+			AttestationTargetPenalty: 200,
+			SyncCommitteePenalty:     30,
+			FinalityDelayPenalty:     7,
+			SlashingReward:           3,
+			SlashingPenalty:          1000,
+			AttestationsMissed:       2,
+			ProposalsMissed:          1,
+		},
+	}
+
+	expected := &types.DashboardIncomeDetails{
+		Granularity:        "hour",
+		Timestamps:         []int64{first.UnixMilli(), second.UnixMilli()},
+		Source:             []int64{100, -100},
+		Target:             []int64{200, -200},
+		Head:               []int64{50, 0},
+		Sync:               []int64{30, -30},
+		Proposer:           []int64{1025, 0},
+		Other:              []int64{0, -1004},
+		AttestationsMissed: []uint64{0, 2},
+		ProposalsMissed:    []uint64{0, 1},
+	}
+	if data := getDashboardIncomeDetails("hour", income); !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected income details %+v, got %+v", expected, data)
+	}
+
+	empty := getDashboardIncomeDetails("epoch", nil)
+	if empty.Granularity != "epoch" || empty.Timestamps == nil || len(empty.Timestamps) != 0 || empty.Source == nil {
+		t.Errorf("expected empty series without income, got %+v", empty)
+	}
+}
@@ -96,7 +96,8 @@ func getSlotVizData(currentEpoch uint64) *types.SlotVizPageData {
 }
 
 func calculateChurn(page *types.IndexPageData) {
-	limit := services.GetLatestStats().ValidatorChurnLimit
+	// new deposits are processed at the activation churn, which is capped since deneb
+	limit := services.GetLatestStats().ValidatorActivationChurnLimit
 	pending_validators := services.GetLatestStats().PendingValidatorCount
 	// calculate daily new validators
 	limit_per_day := *limit * uint64(225)
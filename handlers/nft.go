@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/eth1data"
+	"eth2-exporter/templates"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"golang.org/x/sync/errgroup"
+)
+
+// NftCollection shows the supply, the unique holders and the latest transfers of an ERC-721 or ERC-1155 collection
+func NftCollection(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "execution/nftCollection.html")
+	var nftCollectionTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	tokenString := strings.ToLower(strings.TrimPrefix(mux.Vars(r)["token"], "0x"))
+	if !utils.IsEth1Address(tokenString) {
+		NotFound(w, r)
+		return
+	}
+	token := common.HexToAddress(tokenString)
+
+	standard, err := db.BigtableClient.GetNftStandard(token.Bytes())
+	if err != nil {
+		if errors.Is(err, db.ErrNftNotFound) {
+			NotFound(w, r)
+			return
+		}
+		if handleTemplateError(w, r, "nft.go", "NftCollection", "GetNftStandard", err) != nil {
+			return // an error has occurred and was processed
+		}
+	}
+
+	pageData := &types.NftCollectionPageData{
+		Token:    tokenString,
+		Standard: standard,
+	}
+
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		var err error
+		pageData.Stats, err = db.GetNftCollectionStats(token.Bytes())
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		pageData.Transfers, err = db.BigtableClient.GetNftCollectionTransfers(standard, token.Bytes(), 25)
+		return err
+	})
+	g.Go(func() error {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second*5)
+		defer cancel()
+		var err error
+		pageData.Name, pageData.Symbol, err = eth1data.GetNftCollectionName(ctx, standard, token)
+		if err != nil {
+			// name and symbol are optional for ERC-721 collections
+			logger.WithError(err).Warnf("error retrieving name of nft collection %v", token)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		if handleTemplateError(w, r, "nft.go", "NftCollection", "g.Wait()", err) != nil {
+			return // an error has occurred and was processed
+		}
+	}
+
+	title := fmt.Sprintf("NFT Collection %v", token)
+	if pageData.Name != "" {
+		title = fmt.Sprintf("NFT Collection %v", pageData.Name)
+	}
+	data := InitPageData(w, r, "blockchain", "/token", title, templateFiles)
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "nft.go", "NftCollection", "Done", nftCollectionTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// NftToken shows the current owners, the ownership chain, the mint transaction and the metadata of a token id of an ERC-721 or ERC-1155 collection
+func NftToken(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "execution/nftToken.html")
+	var nftTokenTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+	vars := mux.Vars(r)
+
+	tokenString := strings.ToLower(strings.TrimPrefix(vars["token"], "0x"))
+	if !utils.IsEth1Address(tokenString) {
+		NotFound(w, r)
+		return
+	}
+	token := common.HexToAddress(tokenString)
+	tokenId, ok := new(big.Int).SetString(vars["id"], 10)
+	if !ok || tokenId.Sign() < 0 {
+		NotFound(w, r)
+		return
+	}
+
+	standard, err := db.BigtableClient.GetNftStandard(token.Bytes())
+	if err != nil {
+		if errors.Is(err, db.ErrNftNotFound) {
+			NotFound(w, r)
+			return
+		}
+		if handleTemplateError(w, r, "nft.go", "NftToken", "GetNftStandard", err) != nil {
+			return // an error has occurred and was processed
+		}
+	}
+
+	pageData := &types.NftTokenPageData{
+		Token:    tokenString,
+		TokenId:  tokenId.String(),
+		Standard: standard,
+	}
+
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		var err error
+		pageData.Owners, err = db.GetNftOwners(token.Bytes(), tokenId)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		pageData.Transfers, err = db.BigtableClient.GetNftTransfers(standard, token.Bytes(), tokenId, 1000)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		pageData.Mint, err = db.BigtableClient.GetNftMint(standard, token.Bytes(), tokenId)
+		return err
+	})
+	g.Go(func() error {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second*5)
+		defer cancel()
+		var err error
+		pageData.CollectionName, _, err = eth1data.GetNftCollectionName(ctx, standard, token)
+		if err != nil {
+			logger.WithError(err).Warnf("error retrieving name of nft collection %v", token)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		ctx, cancel := context.WithTimeout(r.Context(), utils.Config.Frontend.NftMetadata.Timeout)
+		defer cancel()
+		var err error
+		pageData.MetadataUri, pageData.Metadata, err = eth1data.GetNftMetadata(ctx, standard, token, tokenId)
+		if err != nil {
+			// the metadata is hosted by third parties so it is shown as unavailable instead of failing the page
+			logger.WithError(err).Warnf("error retrieving metadata of nft %v %v", token, tokenId)
+			pageData.MetadataError = "The metadata of this token could not be retrieved."
+		}
+		if pageData.Metadata != nil && pageData.Metadata.Image != "" {
+			pageData.Image = utils.ResolveNftUri(pageData.Metadata.Image, utils.Config.Frontend.NftMetadata.IpfsGateway)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		if handleTemplateError(w, r, "nft.go", "NftToken", "g.Wait()", err) != nil {
+			return // an error has occurred and was processed
+		}
+	}
+
+	if len(pageData.Transfers) == 0 {
+		NotFound(w, r)
+		return
+	}
+
+	data := InitPageData(w, r, "blockchain", "/token", fmt.Sprintf("NFT %v #%v", token, tokenId), templateFiles)
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "nft.go", "NftToken", "Done", nftTokenTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
@@ -350,6 +350,11 @@ func createMenuItems(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/pools",
 							Icon:  "fa-chart-pie",
 						},
+						{
+							Label: "Validator Benchmark",
+							Path:  "/benchmark",
+							Icon:  "fa-balance-scale",
+						},
 						{
 							Label: "Rocket Pool Stats",
 							Path:  "/pools/rocketpool",
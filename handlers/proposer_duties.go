@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ApiValidatorUpcomingProposals godoc
+// @Summary Get the scheduled block proposals of up to 100 validators
+// @Tags Validator
+// @Description Returns the block proposals of the validators in the remainder of the current epoch and in the next epoch together with the estimated time until the proposal.
+// @Description The proposer duties of the next epoch are known one epoch ahead but can still change until the next epoch starts.
+// @Produce json
+// @Param indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiUpcomingProposal}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/proposals/upcoming [get]
+func ApiValidatorUpcomingProposals(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	validators, err := parseApiValidatorParamToIndices(mux.Vars(r)["indexOrPubkey"], 100)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	data, err := getUpcomingProposals(validators)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving upcoming proposals")
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve upcoming proposals")
+		return
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// DashboardDataUpcomingProposals returns the scheduled block proposals of the validators of a dashboard
+func DashboardDataUpcomingProposals(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	validators, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+
+	data, err := getUpcomingProposals(validators)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error retrieving upcoming proposals")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+func getUpcomingProposals(validators []uint64) ([]*types.ApiUpcomingProposal, error) {
+	headSlot := services.LatestSlot()
+	lastEpoch := utils.EpochOfSlot(headSlot) + 1
+	proposals, err := db.GetScheduledProposals(validators, headSlot+1)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	res := make([]*types.ApiUpcomingProposal, 0, len(proposals))
+	for _, p := range proposals {
+		epoch := utils.EpochOfSlot(p.Slot)
+		if epoch > lastEpoch {
+			continue
+		}
+		ts := utils.SlotToTime(p.Slot)
+		res = append(res, &types.ApiUpcomingProposal{
+			Slot:           p.Slot,
+			Epoch:          epoch,
+			ValidatorIndex: p.Proposer,
+			Timestamp:      ts.Unix(),
+			EtaSeconds:     int64(ts.Sub(now).Seconds()),
+		})
+	}
+	return res, nil
+}
@@ -9,11 +9,14 @@ import (
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
+	"html/template"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/lib/pq"
 )
@@ -24,6 +27,19 @@ var transactionLikeRE = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
 var searchLikeRE = regexp.MustCompile(`^[0-9a-fA-F]{0,96}$`)
 var thresholdHexLikeRE = regexp.MustCompile(`^[0-9a-fA-F]{5,96}$`)
 
+const searchAheadResultLimit = 10
+
+// searchAheadResultTypes are the result types of the search service that are returned for the search types of the navigation search box
+var searchAheadResultTypes = map[string][]types.SearchResultType{
+	"slots":          {types.SlotSearchResult},
+	"blocks":         {types.BlockSearchResult},
+	"transactions":   {types.TransactionSearchResult},
+	"epochs":         {types.EpochSearchResult},
+	"graffiti":       {types.GraffitiSearchResult},
+	"validators":     {types.ValidatorSearchResult, types.ValidatorNameSearchResult, types.PoolSearchResult},
+	"eth1_addresses": {types.AddressSearchResult, types.AddressLabelSearchResult, types.EnsSearchResult, types.TokenSearchResult},
+}
+
 // Search handles search requests
 func Search(w http.ResponseWriter, r *http.Request) {
 
@@ -75,130 +91,8 @@ func SearchAhead(w http.ResponseWriter, r *http.Request) {
 	var result interface{}
 
 	switch searchType {
-	case "slots":
-		if len(search) <= 1 || !searchLikeRE.MatchString(search) {
-			break
-		}
-		result = &types.SearchAheadSlotsResult{}
-		if _, parseErr := strconv.ParseInt(search, 10, 32); parseErr == nil {
-			err = db.ReaderDb.Select(result, `
-			SELECT slot, ENCODE(blockroot, 'hex') AS blockroot 
-			FROM blocks 
-			WHERE slot = $1
-			ORDER BY slot LIMIT 10`, search)
-		} else if len(search) == 64 {
-			var blockHash []byte
-			blockHash, err = hex.DecodeString(search)
-			if err != nil {
-				err = fmt.Errorf("error parsing blockHash to int: %v", err)
-				break
-			}
-			err = db.ReaderDb.Select(result, `
-			SELECT slot, ENCODE(blockroot, 'hex') AS blockroot 
-			FROM blocks 
-			WHERE blockroot = $1 OR
-				stateroot = $1
-			ORDER BY slot LIMIT 10`, blockHash)
-		}
-	case "blocks":
-		number, parseErr := strconv.ParseUint(search, 10, 64)
-		if parseErr != nil {
-			break
-		}
-		block, blockErr := db.BigtableClient.GetBlockFromBlocksTable(number)
-		if blockErr != nil {
-			if blockErr != db.ErrBlockNotFound {
-				err = blockErr
-			}
-			break
-		}
-		result = &types.SearchAheadBlocksResult{{
-			Block: block.Number,
-			Hash:  fmt.Sprintf("%#x", block.Hash),
-		}}
-	case "graffiti":
-		graffiti := &types.SearchAheadGraffitiResult{}
-		err = db.ReaderDb.Select(graffiti, `
-			SELECT graffiti, count(*)
-			FROM blocks
-			WHERE graffiti_text ILIKE LOWER($1)
-			GROUP BY graffiti
-			ORDER BY count desc
-			LIMIT 10`, "%"+search+"%")
-		if err != nil {
-			break
-		}
-		for i := range *graffiti {
-			(*graffiti)[i].Graffiti = utils.FormatGraffitiString((*graffiti)[i].Graffiti)
-		}
-		result = graffiti
-	case "transactions":
-		search = strings.ToLower(strings.Replace(search, "0x", "", -1))
-		if !transactionLikeRE.MatchString(search) {
-			break
-		}
-		result = &types.SearchAheadTransactionsResult{}
-		var txHash []byte
-		txHash, err = hex.DecodeString(search)
-		if err != nil {
-			err = fmt.Errorf("error parsing txHash %v: %v", search, err)
-			break
-		}
-		var tx *types.Eth1TransactionIndexed
-		tx, err = db.BigtableClient.GetIndexedEth1Transaction(txHash)
-		if err != nil || tx == nil {
-			break
-		}
-		result = &types.SearchAheadTransactionsResult{{TxHash: fmt.Sprintf("%x", tx.Hash)}}
-	case "epochs":
-		epochNumber, parseErr := strconv.ParseUint(search, 10, 32)
-		if parseErr != nil {
-			break
-		}
-		result = &types.SearchAheadEpochsResult{}
-		err = db.ReaderDb.Select(result, "SELECT epoch FROM epochs WHERE epoch = $1 ORDER BY epoch LIMIT 10", epochNumber)
-	case "validators":
-		// find all validators that have a index, publickey or name like the search-query
-		result = &types.SearchAheadValidatorsResult{}
-		indexNumeric, parseErr := strconv.ParseInt(search, 10, 32)
-		if parseErr == nil { // search the validator by its index
-			err = db.ReaderDb.Select(result, `SELECT validatorindex AS index, pubkeyhex as pubkey FROM validators WHERE validatorindex = $1`, indexNumeric)
-		} else if thresholdHexLikeRE.MatchString(search) {
-			err = db.ReaderDb.Select(result, `SELECT validatorindex AS index, pubkeyhex as pubkey FROM validators WHERE pubkeyhex LIKE LOWER($1 || '%')`, search)
-		} else {
-			err = db.ReaderDb.Select(result, `
-			SELECT validatorindex AS index, pubkeyhex AS pubkey
-			FROM validators
-			LEFT JOIN validator_names ON validators.pubkey = validator_names.publickey
-			WHERE LOWER(validator_names.name) LIKE LOWER($1)
-			ORDER BY index LIMIT 10`, search+"%")
-		}
-	case "eth1_addresses":
-		if utils.IsValidEnsDomain(search) {
-			ensData, _ := GetEnsDomain(search)
-			if len(ensData.Address) > 0 {
-				result = []*types.Eth1AddressSearchItem{{
-					Address: ensData.Address,
-					Name:    ensData.Domain,
-					Token:   "",
-				}}
-				break
-			}
-		}
-		if !searchLikeRE.MatchString(search) {
-			break
-		}
-		if len(search)%2 != 0 { // pad with 0 if uneven
-			search = search + "0"
-		}
-		eth1AddressHash, decodeErr := hex.DecodeString(search)
-		if decodeErr != nil {
-			break
-		}
-		result, err = db.BigtableClient.SearchForAddress(eth1AddressHash, 10)
-		if err != nil {
-			err = fmt.Errorf("error searching for eth1AddressHash: %v", err)
-		}
+	case "slots", "blocks", "transactions", "epochs", "graffiti", "validators", "eth1_addresses":
+		result, err = searchAheadIndex(r, vars["search"], searchAheadResultTypes[searchType])
 	case "indexed_validators":
 		// find all validators that have a publickey or index like the search-query
 		result = &types.SearchAheadValidatorsResult{}
@@ -352,6 +246,49 @@ func SearchAhead(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// searchAheadIndex returns the ranked results of the search service with html-escaped names.
+// Labels of private label sets are not part of the search index, address label searches therefore rank the matching private labels of the user first.
+func searchAheadIndex(r *http.Request, search string, resultTypes []types.SearchResultType) ([]*types.ApiSearchResult, error) {
+	results, err := searchAll(strings.TrimSpace(search), resultTypes, searchAheadResultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resultType := range resultTypes {
+		if resultType != types.AddressLabelSearchResult {
+			continue
+		}
+		labels, err := db.SearchAddressLabels(getUser(r).UserID, search, searchAheadResultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("error searching for address labels: %w", err)
+		}
+		private := []*types.ApiSearchResult{}
+		for _, label := range labels {
+			if !label.Private {
+				continue
+			}
+			entry := &types.SearchIndexEntry{Type: types.AddressLabelSearchResult, Key: fmt.Sprintf("%x", label.Address), Name: label.Name, Count: 1, Score: directSearchScore}
+			private = append(private, &types.ApiSearchResult{
+				Type:  string(entry.Type),
+				Name:  entry.Name,
+				Key:   entry.Key,
+				Url:   searchResultUrl(entry),
+				Count: entry.Count,
+				Score: entry.Score,
+			})
+		}
+		results = append(private, results...)
+	}
+	if len(results) > searchAheadResultLimit {
+		results = results[:searchAheadResultLimit]
+	}
+
+	for _, result := range results {
+		result.Name = template.HTMLEscapeString(result.Name)
+	}
+	return results, nil
+}
+
 // search can ether be a valid ETH address or an ENS name mapping to one
 func FindValidatorIndicesByEth1Address(search string) (types.SearchValidatorsByEth1Result, error) {
 	result := &[]struct {
@@ -428,3 +365,244 @@ func FindValidatorIndicesByEth1Address(search string) (types.SearchValidatorsByE
 	}
 	return *result, nil
 }
+
+// directSearchScore is the score of results that are looked up by number or hash, they always rank before results of the search index
+const directSearchScore = 100
+
+// ApiSearch godoc
+// @Summary Searches validators, slots, epochs, blocks, transactions, addresses and the names of the search index.
+// @Tags Search
+// @Description Returns relevance ranked results of all types. Numbers, hashes and prefixes of at least 5 hex characters of pubkeys and addresses are looked up directly and rank first, followed by matching
+// @Description validator names, graffiti, ENS names, address labels, token names and symbols and pool names.
+// @Produce json
+// @Param q query string true "Search query"
+// @Param limit query int false "Maximum number of results, defaults to 10 and must be at most 100"
+// @Param type query string false "Comma separated list of result types to include, defaults to all types"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiSearchResult}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/search [get]
+func ApiSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	q := r.URL.Query()
+	search := strings.TrimSpace(q.Get("q"))
+	if search == "" || len(search) > 200 {
+		sendErrorResponse(w, r.URL.String(), "invalid query, q must be between 1 and 200 characters long")
+		return
+	}
+
+	limit := uint64(10)
+	if q.Get("limit") != "" {
+		var err error
+		limit, err = strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil || limit == 0 || limit > 100 {
+			sendErrorResponse(w, r.URL.String(), "invalid limit, must be between 1 and 100")
+			return
+		}
+	}
+
+	resultTypes := []types.SearchResultType{}
+	if q.Get("type") != "" {
+		for _, t := range strings.Split(q.Get("type"), ",") {
+			resultTypes = append(resultTypes, types.SearchResultType(strings.TrimSpace(t)))
+		}
+	}
+
+	results, err := searchAll(search, resultTypes, limit)
+	if err != nil {
+		logger.Errorf("error searching for %q route: %v err: %v", search, r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "error could not search")
+		return
+	}
+
+	data := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		data = append(data, result)
+	}
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), data)
+}
+
+// searchAll looks up numbers, hashes and pubkey or address prefixes directly and appends the ranked matches of the search index, results are limited to resultTypes if it is not empty
+func searchAll(search string, resultTypes []types.SearchResultType, limit uint64) ([]*types.ApiSearchResult, error) {
+	included := func(t types.SearchResultType) bool {
+		if len(resultTypes) == 0 {
+			return true
+		}
+		for _, resultType := range resultTypes {
+			if resultType == t {
+				return true
+			}
+		}
+		return false
+	}
+
+	entries := []*types.SearchIndexEntry{}
+	add := func(t types.SearchResultType, key, name string) {
+		if included(t) {
+			entries = append(entries, &types.SearchIndexEntry{Type: t, Key: key, Name: name, Count: 1, Score: directSearchScore})
+		}
+	}
+
+	hexSearch := strings.ToLower(strings.TrimPrefix(search, "0x"))
+	if number, err := strconv.ParseUint(search, 10, 32); err == nil {
+		exists := false
+		err = db.ReaderDb.Get(&exists, `SELECT EXISTS (SELECT 1 FROM validators WHERE validatorindex = $1)`, number)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			add(types.ValidatorSearchResult, search, fmt.Sprintf("Validator %v", number))
+		}
+		err = db.ReaderDb.Get(&exists, `SELECT EXISTS (SELECT 1 FROM blocks WHERE slot = $1)`, number)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			add(types.SlotSearchResult, search, fmt.Sprintf("Slot %v", number))
+		}
+		err = db.ReaderDb.Get(&exists, `SELECT EXISTS (SELECT 1 FROM epochs WHERE epoch = $1)`, number)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			add(types.EpochSearchResult, search, fmt.Sprintf("Epoch %v", number))
+		}
+		if included(types.BlockSearchResult) {
+			_, err = db.BigtableClient.GetBlockFromBlocksTable(number)
+			if err == nil {
+				add(types.BlockSearchResult, search, fmt.Sprintf("Block %v", number))
+			} else if err != db.ErrBlockNotFound {
+				return nil, err
+			}
+		}
+	} else if transactionLikeRE.MatchString(hexSearch) {
+		hash, err := hex.DecodeString(hexSearch)
+		if err != nil {
+			return nil, err
+		}
+		slots := []uint64{}
+		err = db.ReaderDb.Select(&slots, `SELECT slot FROM blocks WHERE blockroot = $1 OR stateroot = $1 ORDER BY slot LIMIT 10`, hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range slots {
+			add(types.SlotSearchResult, fmt.Sprintf("%v", slot), fmt.Sprintf("Slot %v", slot))
+		}
+		blocks := []uint64{}
+		err = db.ReaderDb.Select(&blocks, `SELECT DISTINCT exec_block_number FROM blocks WHERE exec_block_hash = $1 AND exec_block_number IS NOT NULL`, hash)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			add(types.BlockSearchResult, fmt.Sprintf("%v", block), fmt.Sprintf("Block %v", block))
+		}
+		if included(types.TransactionSearchResult) {
+			tx, err := db.BigtableClient.GetIndexedEth1Transaction(hash)
+			if err == nil && tx != nil {
+				add(types.TransactionSearchResult, hexSearch, fmt.Sprintf("Transaction %#x", tx.Hash))
+			}
+		}
+	} else if len(hexSearch) == 96 && searchLikeRE.MatchString(hexSearch) {
+		indices := []uint64{}
+		err := db.ReaderDb.Select(&indices, `SELECT validatorindex FROM validators WHERE pubkeyhex = $1`, hexSearch)
+		if err != nil {
+			return nil, err
+		}
+		for _, index := range indices {
+			add(types.ValidatorSearchResult, fmt.Sprintf("%v", index), fmt.Sprintf("Validator %v", index))
+		}
+	} else if utils.IsEth1Address(hexSearch) {
+		add(types.AddressSearchResult, hexSearch, fmt.Sprintf("Address %#x", common.FromHex(hexSearch)))
+	} else if thresholdHexLikeRE.MatchString(hexSearch) {
+		// partial pubkeys and addresses are matched by their prefix
+		if included(types.ValidatorSearchResult) {
+			indices := []uint64{}
+			err := db.ReaderDb.Select(&indices, `SELECT validatorindex FROM validators WHERE pubkeyhex LIKE ($1 || '%') ORDER BY validatorindex LIMIT $2`, hexSearch, limit)
+			if err != nil {
+				return nil, err
+			}
+			for _, index := range indices {
+				add(types.ValidatorSearchResult, fmt.Sprintf("%v", index), fmt.Sprintf("Validator %v", index))
+			}
+		}
+		if included(types.AddressSearchResult) && len(hexSearch) < 40 {
+			// an uneven prefix is cut to whole bytes
+			addressPrefix, err := hex.DecodeString(hexSearch[:len(hexSearch)-len(hexSearch)%2])
+			if err != nil {
+				return nil, err
+			}
+			addresses, err := db.BigtableClient.SearchForAddress(addressPrefix, int(limit))
+			if err != nil {
+				return nil, fmt.Errorf("error searching for address prefix %x: %w", addressPrefix, err)
+			}
+			for _, address := range addresses {
+				if !strings.HasPrefix(address.Address, hexSearch) {
+					continue
+				}
+				name := address.Name
+				if name == "" {
+					name = fmt.Sprintf("Address %#x", common.FromHex(address.Address))
+				}
+				add(types.AddressSearchResult, address.Address, name)
+			}
+		}
+	}
+
+	if uint64(len(entries)) < limit {
+		indexed, err := db.Search(search, resultTypes, limit-uint64(len(entries)))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, indexed...)
+	}
+
+	results := make([]*types.ApiSearchResult, 0, len(entries))
+	for _, entry := range entries {
+		results = append(results, &types.ApiSearchResult{
+			Type:  string(entry.Type),
+			Name:  entry.Name,
+			Key:   entry.Key,
+			Url:   searchResultUrl(entry),
+			Count: entry.Count,
+			Score: entry.Score,
+		})
+	}
+	if uint64(len(results)) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchResultUrl returns the page of a search result
+func searchResultUrl(entry *types.SearchIndexEntry) string {
+	switch entry.Type {
+	case types.ValidatorNameSearchResult:
+		if entry.Count == 1 {
+			return "/validator/" + entry.Key
+		}
+		return "/validators?q=" + url.QueryEscape(entry.Name)
+	case types.GraffitiSearchResult:
+		return "/slots?q=" + url.QueryEscape(entry.Name)
+	case types.EnsSearchResult:
+		return "/address/" + url.PathEscape(entry.Name)
+	case types.AddressLabelSearchResult, types.AddressSearchResult:
+		return "/address/0x" + entry.Key
+	case types.TokenSearchResult:
+		return "/token/0x" + entry.Key
+	case types.PoolSearchResult:
+		return "/pools"
+	case types.ValidatorSearchResult:
+		return "/validator/" + entry.Key
+	case types.SlotSearchResult:
+		return "/slot/" + entry.Key
+	case types.EpochSearchResult:
+		return "/epoch/" + entry.Key
+	case types.BlockSearchResult:
+		return "/block/" + entry.Key
+	case types.TransactionSearchResult:
+		return "/tx/0x" + entry.Key
+	}
+	return ""
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/juliangruber/go-intersect"
 	"github.com/lib/pq"
 
@@ -132,6 +133,8 @@ func Slot(w http.ResponseWriter, r *http.Request) {
 		if err == nil {
 			blockPageData.ExecutionData = eth1BlockPageData
 			blockPageData.ExecutionData.IsValidMev = blockPageData.IsValidMev
+			miner := common.FromHex(eth1BlockPageData.MinerAddress)
+			blockPageData.ExecutionData.MinerLabels = getUserAddressLabels(r, miner)[string(miner)]
 		}
 	}
 	data := InitPageData(w, r, "blockchain", fmt.Sprintf("/slot/%v", blockPageData.Slot), fmt.Sprintf("Slot %v", slotOrHash), slotTemplateFiles)
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/utils"
+	"net/http"
+	"strconv"
+)
+
+// ApiStatisticsStatus godoc
+// @Summary Get the progress of the validator statistics export per day
+// @Tags Misc
+// @Description Returns for every day of the range the status, duration and number of attempts of each task of the validator statistics export together with the tasks it depends on.
+// @Description A task that has failed after all retries keeps the status failed and is run again after 10 minutes, the tasks depending on it stay pending until then.
+// @Description The range is limited to 100 days and defaults to the last 7 days.
+// @Produce json
+// @Param from_day query int false "First day of the range"
+// @Param to_day query int false "Last day of the range (default: the latest finalized day)"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiStatisticsDayStatus}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/statistics/status [get]
+func ApiStatisticsStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	toDay := services.LatestFinalizedEpoch() / utils.EpochsPerDay()
+	var err error
+	if q.Get("to_day") != "" {
+		toDay, err = strconv.ParseUint(q.Get("to_day"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid to_day")
+			return
+		}
+	}
+	fromDay := uint64(0)
+	if toDay > 6 {
+		fromDay = toDay - 6
+	}
+	if q.Get("from_day") != "" {
+		fromDay, err = strconv.ParseUint(q.Get("from_day"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid from_day")
+			return
+		}
+	}
+	if fromDay > toDay {
+		sendErrorResponse(w, r.URL.String(), "from_day must not be after to_day")
+		return
+	}
+	if toDay-fromDay >= 100 {
+		sendErrorResponse(w, r.URL.String(), "the range must not exceed 100 days")
+		return
+	}
+
+	data, err := db.GetValidatorStatisticsPipelineStatus(fromDay, toDay)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving validator statistics status of days %v to %v", fromDay, toDay)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve statistics status")
+		return
+	}
+
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	itypes "github.com/gobitfly/eth-rewards/types"
+	"github.com/gorilla/mux"
+)
+
+// ApiValidatorSyncDuties godoc
+// @Summary Get the slot by slot sync committee duties of up to 100 validators in a sync committee period and the rewards earned and lost in the period
+// @Tags Validator
+// @Description Only validators that are members of the sync committee of the period are returned. The status of a duty is participated, missed, orphaned (the block of the slot was orphaned),
+// @Description no_block (no block was proposed in the slot) or scheduled. The missed rewards estimate the rewards that were not earned by missed and orphaned duties.
+// @Produce json
+// @Param indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param period query int false "Sync committee period (default: the current period)"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorSyncPeriod}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/syncduties [get]
+func ApiValidatorSyncDuties(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	validators, err := parseApiValidatorParamToIndices(mux.Vars(r)["indexOrPubkey"], 100)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	latestPeriod := utils.SyncPeriodOfEpoch(services.LatestEpoch())
+	period := latestPeriod
+	if q := r.URL.Query().Get("period"); q != "" {
+		period, err = strconv.ParseUint(q, 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid period")
+			return
+		}
+		// the committee of the next period is known one period in advance
+		if period > latestPeriod+1 {
+			sendErrorResponse(w, r.URL.String(), fmt.Sprintf("the sync committee of period %v is not known yet", period))
+			return
+		}
+	}
+
+	data, err := getValidatorSyncPeriods(validators, period)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving sync committee duties of period %v", period)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve sync committee duties")
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// getValidatorSyncPeriods returns the duties and rewards of the validators that are members of the sync committee of a period
+func getValidatorSyncPeriods(validators []uint64, period uint64) ([]*types.ApiValidatorSyncPeriod, error) {
+	memberships, err := db.GetSyncCommitteeMemberships(validators, period, period)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync committee members: %w", err)
+	}
+	res := make([]*types.ApiValidatorSyncPeriod, 0, len(memberships))
+	if len(memberships) == 0 {
+		return res, nil
+	}
+
+	members := make([]uint64, 0, len(memberships))
+	for _, m := range memberships {
+		members = append(members, m.ValidatorIndex)
+	}
+
+	startEpoch := utils.FirstEpochOfSyncPeriod(period)
+	endEpoch := startEpoch + utils.Config.Chain.Config.EpochsPerSyncCommitteePeriod - 1
+	slotsPerEpoch := utils.Config.Chain.Config.SlotsPerEpoch
+
+	// duties of past epochs are read from bigtable, the remaining slots of the period are scheduled
+	latestEpoch := services.LatestEpoch()
+	dutyEndEpoch := endEpoch
+	if latestEpoch < dutyEndEpoch {
+		dutyEndEpoch = latestEpoch
+	}
+	duties := map[uint64][]*types.ValidatorSyncParticipation{}
+	incomes := map[uint64]map[uint64]*itypes.ValidatorEpochIncome{}
+	slotStatus := map[uint64]uint64{}
+	if startEpoch <= latestEpoch {
+		duties, err = db.BigtableClient.GetValidatorSyncDutiesHistoryOrdered(members, startEpoch, dutyEndEpoch, true)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving sync duties from bigtable: %w", err)
+		}
+		incomes, err = db.BigtableClient.GetValidatorIncomeDetailsHistory(members, startEpoch, dutyEndEpoch)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving income details from bigtable: %w", err)
+		}
+		slotStatus, err = db.GetMissedAndOrphanedSlots(startEpoch*slotsPerEpoch, (dutyEndEpoch+1)*slotsPerEpoch-1)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving missed and orphaned slots: %w", err)
+		}
+	}
+
+	for _, validator := range members {
+		data := &types.ApiValidatorSyncPeriod{
+			ValidatorIndex: validator,
+			Period:         period,
+			StartEpoch:     startEpoch,
+			EndEpoch:       endEpoch,
+			Duties:         make([]*types.ApiSyncDuty, 0, len(duties[validator])),
+		}
+
+		nextSlot := startEpoch * slotsPerEpoch
+		for _, duty := range duties[validator] {
+			status := "missed"
+			switch {
+			case slotStatus[duty.Slot] == 3:
+				status = "orphaned"
+				data.Orphaned++
+			case slotStatus[duty.Slot] == 2:
+				status = "no_block"
+				data.NoBlock++
+			case duty.Status == 1:
+				status = "participated"
+				data.Participated++
+			case time.Since(utils.SlotToTime(duty.Slot)) <= time.Minute:
+				status = "scheduled"
+				data.Scheduled++
+			default:
+				data.Missed++
+			}
+			data.Duties = append(data.Duties, &types.ApiSyncDuty{Slot: duty.Slot, Status: status})
+			nextSlot = duty.Slot + 1
+		}
+		// the slots of the period that have not happened yet are scheduled
+		if headSlot := services.LatestSlot() + 1; nextSlot < headSlot {
+			nextSlot = headSlot
+		}
+		for slot := nextSlot; slot < (endEpoch+1)*slotsPerEpoch; slot++ {
+			data.Duties = append(data.Duties, &types.ApiSyncDuty{Slot: slot, Status: "scheduled"})
+			data.Scheduled++
+		}
+
+		for _, income := range incomes[validator] {
+			data.RewardWei += income.SyncCommitteeReward
+			data.PenaltyWei += income.SyncCommitteePenalty
+		}
+		if data.Participated > 0 {
+			data.MissedRewardWei = data.RewardWei / data.Participated * (data.Missed + data.Orphaned)
+		}
+		res = append(res, data)
+	}
+	return res, nil
+}
+
+// DashboardCalendar returns an iCalendar feed of the upcoming sync committee periods and block proposals of the validators of a dashboard.
+// Calendar clients can subscribe to the feed of a shared dashboard (share query parameter) or of a list of validators.
+func DashboardCalendar(w http.ResponseWriter, r *http.Request) {
+	validators, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+
+	currentPeriod := utils.SyncPeriodOfEpoch(services.LatestEpoch())
+	memberships, err := db.GetSyncCommitteeMemberships(validators, currentPeriod, currentPeriod+1)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving sync committee memberships of dashboard calendar")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	proposals, err := db.GetScheduledProposals(validators, services.LatestSlot()+1)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving scheduled proposals of dashboard calendar")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	domain := utils.Config.Frontend.SiteDomain
+	events := make([]utils.CalendarEvent, 0, len(memberships)+len(proposals))
+	for _, m := range memberships {
+		startEpoch := utils.FirstEpochOfSyncPeriod(m.Period)
+		endEpoch := startEpoch + utils.Config.Chain.Config.EpochsPerSyncCommitteePeriod
+		events = append(events, utils.CalendarEvent{
+			UID:         fmt.Sprintf("sync-committee-%d-%d@%s", m.Period, m.ValidatorIndex, domain),
+			Start:       utils.EpochToTime(startEpoch),
+			End:         utils.EpochToTime(endEpoch),
+			Summary:     fmt.Sprintf("Sync committee duty of validator %d", m.ValidatorIndex),
+			Description: fmt.Sprintf("Validator %d is a member of the sync committee of period %d (epochs %d to %d) and should stay online for the whole period.", m.ValidatorIndex, m.Period, startEpoch, endEpoch-1),
+			URL:         fmt.Sprintf("https://%s/validator/%d#sync", domain, m.ValidatorIndex),
+		})
+	}
+	for _, p := range proposals {
+		start := utils.SlotToTime(p.Slot)
+		events = append(events, utils.CalendarEvent{
+			UID:         fmt.Sprintf("proposal-%d@%s", p.Slot, domain),
+			Start:       start,
+			End:         start.Add(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot)),
+			Summary:     fmt.Sprintf("Block proposal of validator %d", p.Proposer),
+			Description: fmt.Sprintf("Validator %d is scheduled to propose the block of slot %d.", p.Proposer, p.Slot),
+			URL:         fmt.Sprintf("https://%s/slot/%d", domain, p.Slot),
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "inline; filename=validator-duties.ics")
+	_, err = w.Write(utils.ICalendar(domain, fmt.Sprintf("%s validator duties", utils.Config.Frontend.SiteName), events, time.Now()))
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error writing dashboard calendar")
+	}
+}
@@ -492,6 +492,7 @@ func UserUpdateSubscriptions(w http.ResponseWriter, r *http.Request) {
 	pqEventNames := pq.Array([]string{net + ":" + string(types.ValidatorMissedAttestationEventName),
 		net + ":" + string(types.ValidatorMissedProposalEventName),
 		net + ":" + string(types.ValidatorExecutedProposalEventName),
+		net + ":" + string(types.ValidatorUpcomingProposalEventName),
 		net + ":" + string(types.ValidatorGotSlashedEventName),
 		net + ":" + string(types.SyncCommitteeSoon)})
 
@@ -643,6 +644,7 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 		if sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorIsOfflineEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorMissedProposalEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorExecutedProposalEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorUpcomingProposalEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorGotSlashedEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.SyncCommitteeSoon) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorMissedAttestationEventName) ||
@@ -1565,6 +1567,15 @@ func UserValidatorWatchlistAdd(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	proposalUpcoming := FormValueOrJSON(r, "validator_proposal_upcoming")
+	if proposalUpcoming == "on" {
+		err := db.AddSubscription(user.UserID, utils.GetNetwork(), types.ValidatorUpcomingProposalEventName, pubKey, 0)
+		if err != nil {
+			logger.Errorf("error could not ADD subscription for user %v eventName %v eventfilter %v: %v", user.UserID, types.ValidatorUpcomingProposalEventName, pubKey, err)
+			ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
 	syncCommittee := FormValueOrJSON(r, "validator_synccommittee_soon")
 	if syncCommittee == "on" {
 		err := db.AddSubscription(user.UserID, utils.GetNetwork(), types.SyncCommitteeSoon, pubKey, 0)
@@ -1835,7 +1846,6 @@ func MultipleUsersNotificationsSubscribeWeb(w http.ResponseWriter, r *http.Reque
 func internUserNotificationsSubscribe(event, filter string, threshold float64, w http.ResponseWriter, r *http.Request) bool {
 	w.Header().Set("Content-Type", "text/html")
 	user := getUser(r)
-	filter = strings.Replace(filter, "0x", "", -1)
 
 	event = strings.TrimPrefix(event, utils.GetNetwork()+":")
 
@@ -1846,10 +1856,33 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 		return false
 	}
 
+	if utils.IsValidEnsDomain(filter) {
+		filters, err := resolveEnsSubscriptionFilter(eventName, filter, getUserPremium(r).MaxValidators)
+		if err != nil {
+			logger.Warnf("error resolving ens subscription filter %v: %v", filter, err)
+			ErrorOrJSONResponse(w, r, "Could not resolve ens name", http.StatusBadRequest)
+			return false
+		}
+		for _, f := range filters {
+			if !internUserNotificationsSubscribe(event, f, threshold, w, r) {
+				return false
+			}
+		}
+		return true
+	}
+	filter = strings.Replace(filter, "0x", "", -1)
+
 	isPkey := !pkeyRegex.MatchString(filter)
 	filterLen := len(filter)
 
-	if filterLen != 96 && filterLen != 0 && isPkey {
+	if eventName == types.EnsNameExpiringEventName {
+		// ens expiry notifications are subscribed per owner address
+		if filterLen != 40 || !isPkey {
+			ErrorOrJSONResponse(w, r, "Invalid address", http.StatusBadRequest)
+			return false
+		}
+		filter = strings.ToLower(filter)
+	} else if filterLen != 96 && filterLen != 0 && isPkey {
 		logger.Errorf("error invalid pubkey characters or length: %v", err)
 		ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
 		return false
@@ -1962,6 +1995,34 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 	return true
 }
 
+// resolveEnsSubscriptionFilter resolves an ens name used as subscription filter. Events that are filtered by an address (ens and rocketpool node events)
+// are filtered by the address of the name, validator events are filtered by the pubkeys of the validators that were deposited by or withdraw to the address.
+func resolveEnsSubscriptionFilter(eventName types.EventName, name string, maxValidators int) ([]string, error) {
+	if strings.HasPrefix(string(eventName), "ens_") || strings.HasPrefix(string(eventName), "rocketpool_") {
+		ensData, err := GetEnsDomain(name)
+		if err != nil {
+			return nil, err
+		}
+		return []string{strings.ToLower(strings.TrimPrefix(ensData.Address, "0x"))}, nil
+	}
+	if strings.HasPrefix(string(eventName), "monitoring_") || eventName == types.EthClientUpdateEventName {
+		return nil, fmt.Errorf("event %v can not be filtered by an ens name", eventName)
+	}
+
+	validators, err := getEnsValidators(name, maxValidators)
+	if err != nil {
+		return nil, err
+	}
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("no validators found for ens name %v", name)
+	}
+	filters := make([]string, 0, len(validators))
+	for _, v := range validators {
+		filters = append(filters, hex.EncodeToString(v.Pubkey))
+	}
+	return filters, nil
+}
+
 func MultipleUsersNotificationsUnsubscribe(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -2011,7 +2072,6 @@ func internUserNotificationsUnsubscribe(event, filter string, w http.ResponseWri
 	w.Header().Set("Content-Type", "text/html")
 	user := getUser(r)
 
-	filter = strings.Replace(filter, "0x", "", -1)
 	event = strings.TrimPrefix(event, utils.GetNetwork()+":")
 
 	eventName, err := types.EventNameFromString(event)
@@ -2021,10 +2081,33 @@ func internUserNotificationsUnsubscribe(event, filter string, w http.ResponseWri
 		return false
 	}
 
+	if utils.IsValidEnsDomain(filter) {
+		filters, err := resolveEnsSubscriptionFilter(eventName, filter, getUserPremium(r).MaxValidators)
+		if err != nil {
+			logger.Warnf("error resolving ens subscription filter %v: %v", filter, err)
+			ErrorOrJSONResponse(w, r, "Could not resolve ens name", http.StatusBadRequest)
+			return false
+		}
+		for _, f := range filters {
+			if !internUserNotificationsUnsubscribe(event, f, w, r) {
+				return false
+			}
+		}
+		return true
+	}
+	filter = strings.Replace(filter, "0x", "", -1)
+
 	isPkey := !pkeyRegex.MatchString(filter)
 	filterLen := len(filter)
 
-	if len(filter) != 96 && filterLen != 0 && isPkey {
+	if eventName == types.EnsNameExpiringEventName {
+		// ens expiry notifications are subscribed per owner address
+		if filterLen != 40 || !isPkey {
+			ErrorOrJSONResponse(w, r, "Invalid address", http.StatusBadRequest)
+			return false
+		}
+		filter = strings.ToLower(filter)
+	} else if len(filter) != 96 && filterLen != 0 && isPkey {
 		logger.Errorf("error invalid pubkey characters or length: %v", err)
 		ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
 		return false
@@ -2109,7 +2192,14 @@ func UserNotificationsUnsubscribe(w http.ResponseWriter, r *http.Request) {
 	isPkey := !pkeyRegex.MatchString(filter)
 	filterLen := len(filter)
 
-	if len(filter) != 96 && filterLen != 0 && isPkey {
+	if eventName == types.EnsNameExpiringEventName {
+		// ens expiry notifications are subscribed per owner address
+		if filterLen != 40 || !isPkey {
+			ErrorOrJSONResponse(w, r, "Invalid address", http.StatusBadRequest)
+			return
+		}
+		filter = strings.ToLower(filter)
+	} else if len(filter) != 96 && filterLen != 0 && isPkey {
 		logger.Errorf("error invalid pubkey characters or length: %v", err)
 		ErrorOrJSONResponse(w, r, "Internal server error", http.StatusInternalServerError)
 		return
@@ -2474,6 +2564,11 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			EventName:  types.ValidatorExecutedProposalEventName,
 			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorExecutedProposalEventName)),
 		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Proposal Upcoming",
+			EventName:  types.ValidatorUpcomingProposalEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorUpcomingProposalEventName)),
+		})
 		events = append(events, types.EventNameCheckbox{
 			EventLabel: "Withdrawal",
 			EventName:  types.ValidatorReceivedWithdrawalEventName,
@@ -2575,6 +2670,10 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 		EventLabel: "Proposal Submitted",
 		EventName:  types.ValidatorExecutedProposalEventName,
 	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Proposal Upcoming",
+		EventName:  types.ValidatorUpcomingProposalEventName,
+	})
 	events = append(events, types.EventNameCheckbox{
 		EventLabel: "Withdrawal",
 		EventName:  types.ValidatorReceivedWithdrawalEventName,
@@ -2643,6 +2742,7 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	validatorIsOffline := r.FormValue(string(types.ValidatorIsOfflineEventName)) == "on"
 	validatorProposalMissed := r.FormValue(string(types.ValidatorMissedProposalEventName)) == "on"
 	validatorProposalSubmitted := r.FormValue(string(types.ValidatorExecutedProposalEventName)) == "on"
+	validatorProposalUpcoming := r.FormValue(string(types.ValidatorUpcomingProposalEventName)) == "on"
 	validatorReceivedWithdrawal := r.FormValue(string(types.ValidatorReceivedWithdrawalEventName)) == "on"
 	validatorGotSlashed := r.FormValue(string(types.ValidatorGotSlashedEventName)) == "on"
 	validatorSyncCommiteeSoon := r.FormValue(string(types.SyncCommitteeSoon)) == "on"
@@ -2663,6 +2763,7 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	events[string(types.ValidatorIsOfflineEventName)] = validatorIsOffline
 	events[string(types.ValidatorMissedProposalEventName)] = validatorProposalMissed
 	events[string(types.ValidatorExecutedProposalEventName)] = validatorProposalSubmitted
+	events[string(types.ValidatorUpcomingProposalEventName)] = validatorProposalUpcoming
 	events[string(types.ValidatorReceivedWithdrawalEventName)] = validatorReceivedWithdrawal
 	events[string(types.ValidatorGotSlashedEventName)] = validatorGotSlashed
 	events[string(types.SyncCommitteeSoon)] = validatorSyncCommiteeSoon
@@ -2778,6 +2879,7 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	validatorIsOffline := r.FormValue(string(types.ValidatorIsOfflineEventName)) == "on"
 	validatorProposalMissed := r.FormValue(string(types.ValidatorMissedProposalEventName)) == "on"
 	validatorProposalSubmitted := r.FormValue(string(types.ValidatorExecutedProposalEventName)) == "on"
+	validatorProposalUpcoming := r.FormValue(string(types.ValidatorUpcomingProposalEventName)) == "on"
 	validatorReceivedWithdrawal := r.FormValue(string(types.ValidatorReceivedWithdrawalEventName)) == "on"
 	validatorGotSlashed := r.FormValue(string(types.ValidatorGotSlashedEventName)) == "on"
 	validatorSyncCommiteeSoon := r.FormValue(string(types.SyncCommitteeSoon)) == "on"
@@ -2798,6 +2900,7 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	events[string(types.ValidatorIsOfflineEventName)] = validatorIsOffline
 	events[string(types.ValidatorMissedProposalEventName)] = validatorProposalMissed
 	events[string(types.ValidatorExecutedProposalEventName)] = validatorProposalSubmitted
+	events[string(types.ValidatorUpcomingProposalEventName)] = validatorProposalUpcoming
 	events[string(types.ValidatorReceivedWithdrawalEventName)] = validatorReceivedWithdrawal
 	events[string(types.ValidatorGotSlashedEventName)] = validatorGotSlashed
 	events[string(types.SyncCommitteeSoon)] = validatorSyncCommiteeSoon
@@ -0,0 +1,628 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+const maxUserDashboards = 10
+const maxUserDashboardNameLength = 50
+
+// getDashboardValidatorsFromQuery returns the validators of the stored dashboard that is referenced by the dashboard (id of a dashboard
+// of the user) or share (public id of a shared dashboard) query parameter, the group parameter limits the validators to a single group.
+// The returned bool is false if the request does not reference a stored dashboard.
+func getDashboardValidatorsFromQuery(r *http.Request) ([]uint64, bool, error) {
+	if !isDashboardQuery(r) {
+		return nil, false, nil
+	}
+
+	q := r.URL.Query()
+	groupID := uint64(0)
+	if q.Get("group") != "" {
+		var err error
+		groupID, err = strconv.ParseUint(q.Get("group"), 10, 64)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid group: %w", err)
+		}
+	}
+
+	if q.Get("dashboard") != "" {
+		dashboardID, err := strconv.ParseUint(q.Get("dashboard"), 10, 64)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid dashboard: %w", err)
+		}
+		validators, err := getDashboardValidators(r, dashboardID, "", groupID)
+		return validators, true, err
+	}
+	validators, err := getDashboardValidators(r, 0, q.Get("share"), groupID)
+	return validators, true, err
+}
+
+// isDashboardQuery returns true if the request references a stored dashboard by its id or by the public id of a shared dashboard
+func isDashboardQuery(r *http.Request) bool {
+	q := r.URL.Query()
+	return q.Get("dashboard") != "" || q.Get("share") != ""
+}
+
+// getDashboardValidators returns the validators of the dashboard of the requesting user with the given id or of the dashboard that is
+// shared with the given public id
+func getDashboardValidators(r *http.Request, dashboardID uint64, shareID string, groupID uint64) ([]uint64, error) {
+	var dashboard *types.UserDashboard
+	var err error
+	if shareID != "" {
+		dashboard, err = db.GetUserDashboardByPublicID(shareID)
+	} else {
+		userID, ok := getDashboardUserID(r)
+		if !ok {
+			return nil, db.ErrUserDashboardNotFound
+		}
+		dashboard, err = db.GetUserDashboard(userID, dashboardID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dashboard.Network != utils.GetNetwork() {
+		return nil, db.ErrUserDashboardNotFound
+	}
+
+	if groupID != 0 {
+		group := dashboard.Group(groupID)
+		if group == nil {
+			return nil, db.ErrUserDashboardNotFound
+		}
+		return group.Validators, nil
+	}
+	return dashboard.Validators(), nil
+}
+
+// getDashboardUserID returns the id of the user that owns the dashboards of the request, the user is authenticated
+// by the session, by an oauth token or by an api key
+func getDashboardUserID(r *http.Request) (uint64, bool) {
+	user := getUser(r)
+	if user.Authenticated {
+		return user.UserID, true
+	}
+	if claims := getAuthClaims(r); claims != nil {
+		return claims.UserID, true
+	}
+	if apiKey := getApiKey(r); apiKey != "" {
+		u, err := db.GetUserIdByApiKey(apiKey)
+		if err == nil {
+			return u.ID, true
+		}
+	}
+	return 0, false
+}
+
+// UserDashboards godoc
+// @Summary Get all named dashboards of the user
+// @Tags User
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiUserDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards [get]
+func UserDashboards(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboards, err := db.GetUserDashboards(user.UserID, utils.GetNetwork())
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving dashboards of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiUserDashboard, 0, len(dashboards))
+	for _, d := range dashboards {
+		data = append(data, toApiUserDashboard(d, true))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserDashboard godoc
+// @Summary Get a named dashboard of the user
+// @Tags User
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.ApiUserDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId} [get]
+func UserDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+
+	dashboard, err := db.GetUserDashboard(user.UserID, dashboardID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiUserDashboard(dashboard, true)})
+}
+
+// UserDashboardCreate godoc
+// @Summary Create a named dashboard, the dashboard is created with a single default group
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboard body types.ApiUserDashboardRequest true "The name of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.ApiUserDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards [post]
+func UserDashboardCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	req := types.ApiUserDashboardRequest{}
+	name, ok := parseUserDashboardName(w, r, &req)
+	if !ok {
+		return
+	}
+
+	count, err := db.CountUserDashboards(user.UserID, utils.GetNetwork())
+	if err != nil {
+		logger.WithError(err).Errorf("error counting dashboards of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if count >= maxUserDashboards {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("only a maximum of %d dashboards are allowed", maxUserDashboards))
+		return
+	}
+
+	dashboard, err := db.CreateUserDashboard(user.UserID, utils.GetNetwork(), name)
+	if err != nil {
+		logger.WithError(err).Errorf("error creating dashboard of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not create dashboard")
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiUserDashboard(dashboard, true)})
+}
+
+// UserDashboardUpdate godoc
+// @Summary Rename a named dashboard
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param dashboard body types.ApiUserDashboardRequest true "The new name of the dashboard"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/update [post]
+func UserDashboardUpdate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	req := types.ApiUserDashboardRequest{}
+	name, ok := parseUserDashboardName(w, r, &req)
+	if !ok {
+		return
+	}
+
+	err = db.RenameUserDashboard(user.UserID, dashboardID, name)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserDashboardDelete godoc
+// @Summary Delete a named dashboard including all of its groups
+// @Tags User
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/delete [post]
+func UserDashboardDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+
+	err = db.DeleteUserDashboard(user.UserID, dashboardID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserDashboardShare godoc
+// @Summary Enable or disable the read-only public link of a named dashboard. The returned share_id can be used as share parameter of the dashboard endpoints and at /api/v1/dashboard/shared/{shareId}.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param share body types.ApiUserDashboardShareRequest true "Whether the dashboard should be shared"
+// @Success 200 {object} types.ApiResponse{data=types.ApiUserDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/share [post]
+func UserDashboardShare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	req := types.ApiUserDashboardShareRequest{}
+	if !parseUserDashboardBody(w, r, &req) {
+		return
+	}
+
+	_, err = db.SetUserDashboardSharing(user.UserID, dashboardID, req.Enabled)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	dashboard, err := db.GetUserDashboard(user.UserID, dashboardID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiUserDashboard(dashboard, true)})
+}
+
+// UserDashboardGroupCreate godoc
+// @Summary Add a named validator group to a dashboard
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param group body types.ApiUserDashboardGroupRequest true "The name of the group and an optional validator set whose members are part of the group"
+// @Success 200 {object} types.ApiResponse{data=types.UserDashboardGroup}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/groups [post]
+func UserDashboardGroupCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	req := types.ApiUserDashboardGroupRequest{}
+	name, validatorSetID, ok := parseUserDashboardGroupRequest(w, r, user.UserID, &req)
+	if !ok {
+		return
+	}
+
+	group, err := db.CreateUserDashboardGroup(user.UserID, dashboardID, name, validatorSetID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{group})
+}
+
+// UserDashboardGroupUpdate godoc
+// @Summary Rename a validator group of a dashboard and set the validator set whose members are part of the group
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param groupId path int true "Id of the group"
+// @Param group body types.ApiUserDashboardGroupRequest true "The new name of the group and an optional validator set whose members are part of the group"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/groups/{groupId}/update [post]
+func UserDashboardGroupUpdate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	vars := mux.Vars(r)
+
+	dashboardID, err := strconv.ParseUint(vars["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	groupID, err := strconv.ParseUint(vars["groupId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid group id")
+		return
+	}
+	req := types.ApiUserDashboardGroupRequest{}
+	name, validatorSetID, ok := parseUserDashboardGroupRequest(w, r, user.UserID, &req)
+	if !ok {
+		return
+	}
+
+	err = db.UpdateUserDashboardGroup(user.UserID, dashboardID, groupID, name, validatorSetID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserDashboardGroupDelete godoc
+// @Summary Delete a validator group and its validators from a dashboard
+// @Tags User
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param groupId path int true "Id of the group"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/groups/{groupId}/delete [post]
+func UserDashboardGroupDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	vars := mux.Vars(r)
+
+	dashboardID, err := strconv.ParseUint(vars["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	groupID, err := strconv.ParseUint(vars["groupId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid group id")
+		return
+	}
+
+	err = db.DeleteUserDashboardGroup(user.UserID, dashboardID, groupID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserDashboardValidatorsAdd godoc
+// @Summary Add validators to a group of a dashboard, validators that are part of another group of the dashboard are moved to the group
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param validators body types.ApiUserDashboardValidatorsRequest true "Comma separated validator indices or pubkeys and the group they are added to"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/validators [post]
+func UserDashboardValidatorsAdd(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	req := types.ApiUserDashboardValidatorsRequest{}
+	if !parseUserDashboardBody(w, r, &req) {
+		return
+	}
+	indices, err := parseApiValidatorParamToIndices(req.IndicesOrPubKey, maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	dashboard, err := db.GetUserDashboard(user.UserID, dashboardID)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	validators := make(map[uint64]bool)
+	for _, v := range dashboard.Validators() {
+		validators[v] = true
+	}
+	for _, v := range indices {
+		validators[v] = true
+	}
+	if len(validators) > maxValidators {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("only a maximum of %d validators are allowed per dashboard", maxValidators))
+		return
+	}
+
+	err = db.AddValidatorsToUserDashboard(user.UserID, dashboardID, req.GroupID, indices)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserDashboardValidatorsRemove godoc
+// @Summary Remove validators from a dashboard
+// @Tags User
+// @Description Fails with "dashboard not found" if the dashboard does not belong to the user or none of the validators is part of it.
+// @Accept json
+// @Produce json
+// @Param dashboardId path int true "Id of the dashboard"
+// @Param validators body types.ApiUserDashboardValidatorsRequest true "Comma separated validator indices or pubkeys, the group is ignored"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/dashboards/{dashboardId}/validators/remove [post]
+func UserDashboardValidatorsRemove(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	dashboardID, err := strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid dashboard id")
+		return
+	}
+	req := types.ApiUserDashboardValidatorsRequest{}
+	if !parseUserDashboardBody(w, r, &req) {
+		return
+	}
+	indices, err := parseApiValidatorParamToIndices(req.IndicesOrPubKey, getUserPremium(r).MaxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	err = db.RemoveValidatorsFromUserDashboard(user.UserID, dashboardID, indices)
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// ApiSharedDashboard godoc
+// @Summary Get the groups and validators of a dashboard that is shared via a public link
+// @Tags Dashboard
+// @Produce json
+// @Param shareId path string true "The public id of the shared dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.ApiUserDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/dashboard/shared/{shareId} [get]
+func ApiSharedDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard, err := db.GetUserDashboardByPublicID(mux.Vars(r)["shareId"])
+	if err == nil && dashboard.Network != utils.GetNetwork() {
+		err = db.ErrUserDashboardNotFound
+	}
+	if err != nil {
+		handleUserDashboardError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiUserDashboard(dashboard, false)})
+}
+
+func toApiUserDashboard(dashboard *types.UserDashboard, isOwner bool) *types.ApiUserDashboard {
+	res := &types.ApiUserDashboard{
+		ID:        dashboard.ID,
+		Name:      dashboard.Name,
+		CreatedTs: dashboard.CreatedTs.Unix(),
+		Groups:    dashboard.Groups,
+	}
+	if isOwner {
+		res.ShareID = dashboard.PublicID.String
+	} else {
+		// the id of a shared dashboard is only meaningful to its owner
+		res.ID = 0
+	}
+	return res
+}
+
+func parseUserDashboardBody(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1e6))
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not read body")
+		return false
+	}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid request body")
+		return false
+	}
+	return true
+}
+
+func parseUserDashboardName(w http.ResponseWriter, r *http.Request, req *types.ApiUserDashboardRequest) (string, bool) {
+	if !parseUserDashboardBody(w, r, req) {
+		return "", false
+	}
+	return validateUserDashboardName(w, r, req.Name)
+}
+
+func validateUserDashboardName(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxUserDashboardNameLength {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("the name must be between 1 and %d characters long", maxUserDashboardNameLength))
+		return "", false
+	}
+	return name, true
+}
+
+func parseUserDashboardGroupRequest(w http.ResponseWriter, r *http.Request, userID uint64, req *types.ApiUserDashboardGroupRequest) (string, sql.NullInt64, bool) {
+	if !parseUserDashboardBody(w, r, req) {
+		return "", sql.NullInt64{}, false
+	}
+	name, ok := validateUserDashboardName(w, r, req.Name)
+	if !ok {
+		return "", sql.NullInt64{}, false
+	}
+	if req.ValidatorSetID == 0 {
+		return name, sql.NullInt64{}, true
+	}
+	_, err := db.GetValidatorSet(userID, req.ValidatorSetID)
+	if err != nil {
+		handleValidatorSetError(w, r, err)
+		return "", sql.NullInt64{}, false
+	}
+	return name, sql.NullInt64{Int64: int64(req.ValidatorSetID), Valid: true}, true
+}
+
+func handleUserDashboardError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, db.ErrUserDashboardNotFound) {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		sendErrorResponse(w, r.URL.String(), "a group with this name already exists")
+		return
+	}
+	logger.WithError(err).Errorf("error accessing dashboard")
+	sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+}
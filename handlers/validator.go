@@ -84,7 +84,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	futureSyncDutyEpoch := uint64(0)
 
 	stats := services.GetLatestStats()
-	churnRate := stats.ValidatorChurnLimit
+	churnRate := stats.ValidatorActivationChurnLimit
 	if churnRate == nil {
 		churnRate = new(uint64)
 	}
@@ -458,7 +458,15 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			// only calculate the expected next withdrawal if the validator is eligible
 			isFullWithdrawal := validatorPageData.CurrentBalance > 0 && validatorPageData.WithdrawableEpoch <= validatorPageData.Epoch
 			isPartialWithdrawal := validatorPageData.EffectiveBalance == utils.Config.Chain.Config.MaxEffectiveBalance && validatorPageData.CurrentBalance > utils.Config.Chain.Config.MaxEffectiveBalance
-			if stats != nil && stats.LatestValidatorWithdrawalIndex != nil && stats.TotalValidatorCount != nil && validatorPageData.IsWithdrawableAddress && (isFullWithdrawal || isPartialWithdrawal) {
+			if p, ok := services.GetPredictedWithdrawal(validatorPageData.Index); ok && validatorPageData.IsWithdrawableAddress {
+				// the simulated withdrawal sweep is more accurate than the estimation based on the distance to the cursor
+				amount := p.Amount
+				if latestEpoch == lastWithdrawalsEpoch {
+					amount = 0
+				}
+				row := nextWithdrawalRowFromPrediction(p, amount)
+				validatorPageData.NextWithdrawalRow = [][]interface{}{row[1:]}
+			} else if stats != nil && stats.LatestValidatorWithdrawalIndex != nil && stats.TotalValidatorCount != nil && validatorPageData.IsWithdrawableAddress && (isFullWithdrawal || isPartialWithdrawal) {
 				distance, err := GetWithdrawableCountFromCursor(validatorPageData.Epoch, validatorPageData.Index, *stats.LatestValidatorWithdrawalIndex)
 				if err != nil {
 					return fmt.Errorf("error getting withdrawable validator count from cursor: %v", err)
@@ -558,11 +566,16 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 				return fmt.Errorf("failed to retrieve queue ahead of validator %v: %v", validatorPageData.ValidatorIndex, err)
 			}
 			validatorPageData.QueuePosition = queueAhead + 1
-			epochsToWait := queueAhead / *churnRate
-			// calculate dequeue epoch
-			estimatedActivationEpoch := validatorPageData.Epoch + epochsToWait + 1
-			// add activation offset
-			estimatedActivationEpoch += utils.Config.Chain.Config.MaxSeedLookahead + 1
+			var estimatedActivationEpoch uint64
+			if stats.ChurnQueueState != nil {
+				estimatedActivationEpoch = utils.ProjectActivationEpoch(stats.ChurnQueueState, queueAhead)
+			} else {
+				epochsToWait := queueAhead / *churnRate
+				// calculate dequeue epoch
+				estimatedActivationEpoch = validatorPageData.Epoch + epochsToWait + 1
+				// add activation offset
+				estimatedActivationEpoch += utils.Config.Chain.Config.MaxSeedLookahead + 1
+			}
 			validatorPageData.EstimatedActivationEpoch = estimatedActivationEpoch
 			estimatedDequeueTs := utils.EpochToTime(estimatedActivationEpoch)
 			validatorPageData.EstimatedActivationTs = estimatedDequeueTs
@@ -570,6 +583,15 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
+	if stats.ChurnQueueState != nil && validatorPageData.ActivationEpoch <= latestEpoch && validatorPageData.ExitEpoch > 100_000_000 {
+		// estimate when the validator would exit and become withdrawable if it initiated its exit right now
+		exitEpoch := utils.ProjectExitEpochs(stats.ChurnQueueState, 1)[0]
+		validatorPageData.EstimatedExitEpoch = exitEpoch
+		validatorPageData.EstimatedExitTs = utils.EpochToTime(exitEpoch)
+		validatorPageData.EstimatedWithdrawableEpoch = exitEpoch + utils.Config.Chain.Config.MinValidatorWithdrawabilityDelay
+		validatorPageData.EstimatedWithdrawableTs = utils.EpochToTime(validatorPageData.EstimatedWithdrawableEpoch)
+	}
+
 	g.Go(func() error {
 		if validatorPageData.AttestationsCount > 0 {
 			// get attestationStats from validator_stats
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"eth2-exporter/db"
+	"eth2-exporter/price"
 	"eth2-exporter/services"
 	"eth2-exporter/templates"
 	"eth2-exporter/types"
@@ -39,16 +40,14 @@ func ValidatorRewards(w http.ResponseWriter, r *http.Request) {
 
 	var supportedCurrencies []string
 	err = db.ReaderDb.Select(&supportedCurrencies,
-		`select column_name 
-			from information_schema.columns 
-			where table_name = 'price'`)
+		`select distinct lower(currency) from historic_prices order by 1`)
 	if err != nil {
-		logger.Errorf("error getting eth1-deposits-distribution for stake pools: %v", err)
+		logger.Errorf("error getting supported currencies of historic prices: %v", err)
 	}
 
 	var minTime time.Time
 	err = db.ReaderDb.Get(&minTime,
-		`select ts from price order by ts asc limit 1`)
+		`select ts from historic_prices order by ts asc limit 1`)
 	if err != nil {
 		logger.Errorf("error getting min ts: %v", err)
 	}
@@ -86,21 +85,13 @@ func getUserRewardSubscriptions(uid uint64) [][]string {
 	return res
 }
 
+// isValidCurrency returns whether historic prices are exported for a currency, the rewards page uses lower case currencies
 func isValidCurrency(currency string) bool {
-	var count uint64
-	err := db.ReaderDb.Get(&count,
-		`select count(column_name) 
-		from information_schema.columns 
-		where table_name = 'price' AND column_name=$1;`, currency)
-	if err != nil {
-		logger.Errorf("error checking currency: %v", err)
-		return false
-	}
-
-	if count > 0 {
-		return true
+	for _, c := range price.GetCurrencies() {
+		if strings.EqualFold(c, currency) {
+			return true
+		}
 	}
-
 	return false
 }
 
@@ -229,16 +220,33 @@ func RewardNotificationSubscribe(w http.ResponseWriter, r *http.Request) {
 
 	currency := q.Get("currency")
 
-	if validatorArr == "" || !isValidCurrency(currency) {
+	if (validatorArr == "" && q.Get("set") == "") || !isValidCurrency(currency) {
 		logger.WithField("route", r.URL.String()).Error("Bad Query")
 		http.Error(w, "Internal server error, Bad Query", http.StatusInternalServerError)
 		return
 	}
 
+	eventFilter := fmt.Sprintf("validators=%s&days=30&currency=%s", validatorArr, currency)
+	if q.Get("set") != "" {
+		// reports of a rule based validator set cover the members of the set at the time the report is sent
+		setID, err := strconv.ParseUint(q.Get("set"), 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid query", 400)
+			return
+		}
+		_, err = db.GetValidatorSet(user.UserID, setID)
+		if err != nil {
+			logger.WithError(err).WithField("route", r.URL.String()).Warn("error retrieving validator set of rewards report")
+			http.Error(w, "Invalid query", 400)
+			return
+		}
+		eventFilter = fmt.Sprintf("set=%d&days=30&currency=%s", setID, currency)
+	}
+
 	err = db.AddSubscription(user.UserID,
 		utils.Config.Chain.Config.ConfigName,
 		types.TaxReportEventName,
-		fmt.Sprintf("validators=%s&days=30&currency=%s", validatorArr, currency), 0)
+		eventFilter, 0)
 
 	if err != nil {
 		logger.Errorf("error updating user subscriptions: %v", err)
@@ -273,16 +281,21 @@ func RewardNotificationUnsubscribe(w http.ResponseWriter, r *http.Request) {
 
 	currency := q.Get("currency")
 
-	if validatorArr == "" || !isValidCurrency(currency) {
+	if (validatorArr == "" && q.Get("set") == "") || !isValidCurrency(currency) {
 		logger.WithField("route", r.URL.String()).Error("Bad Query")
 		http.Error(w, "Internal server error, Bad Query", http.StatusInternalServerError)
 		return
 	}
 
+	eventFilter := fmt.Sprintf("validators=%s&days=30&currency=%s", validatorArr, currency)
+	if q.Get("set") != "" {
+		eventFilter = fmt.Sprintf("set=%s&days=30&currency=%s", q.Get("set"), currency)
+	}
+
 	err := db.DeleteSubscription(user.UserID,
 		utils.GetNetwork(),
 		types.TaxReportEventName,
-		fmt.Sprintf("validators=%s&days=30&currency=%s", validatorArr, currency))
+		eventFilter)
 
 	if err != nil {
 		logger.Errorf("error deleting entry from user subscriptions: %v", err)
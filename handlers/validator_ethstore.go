@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultEthStoreRangeDays is the number of days the apr of a set is computed over if the range is not given
+const defaultEthStoreRangeDays = 31
+
+var errEthStoreNotAvailable = errors.New("ETH.STORE data is not available yet")
+
+var getLatestEthStoreDay = db.GetLatestEthStoreDay
+
+// ApiValidatorEthStore godoc
+// @Summary Get the ETH.STORE® style apr of a set of validators over a range of days compared day by day against the ETH.STORE®
+// @Tags Validator
+// @Description A set is one of dashboard:{dashboardId}[:{groupId}], share:{shareId}[:{groupId}], validatorset:{setId}, pool:{pool}, address:{withdrawalAddressOrCredentials} or validators:{indicesOrPubkeys}.
+// @Description The apr of a day is the total rewards of the set relative to its effective balances, annualized. The apr over the range relates the rewards of all days
+// @Description to the summed up effective balances of all days, so days with more effective balance weigh more. The ETH.STORE® apr over the range is computed the same way.
+// @Produce json
+// @Param set query string true "Set of validators"
+// @Param from_day query int false "First beaconchain-day of the range (default: 30 days before to_day)"
+// @Param to_day query int false "Last beaconchain-day of the range (default: the latest ETH.STORE® day)"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorEthStoreResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/validators/ethstore [get]
+func ApiValidatorEthStore(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	set := q.Get("set")
+	if set == "" {
+		sendErrorResponse(w, r.URL.String(), "no set provided")
+		return
+	}
+	selector, err := getBenchmarkSelector(r, set)
+	if err != nil {
+		if errors.Is(err, errInvalidBenchmarkSet) || errors.Is(err, db.ErrUserDashboardNotFound) || errors.Is(err, db.ErrValidatorSetNotFound) {
+			sendErrorResponse(w, r.URL.String(), fmt.Sprintf("%v: %v", set, err))
+			return
+		}
+		logger.WithError(err).Errorf("error retrieving validators of ETH.STORE set %v", set)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	fromDay, toDay, err := parseEthStoreRange(q)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	data, err := getValidatorEthStore(selector, set, fromDay, toDay)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving ETH.STORE apr of set %v", set)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	if q.Get("format") == "csv" {
+		writeValidatorEthStoreCsv(w, r, data)
+		return
+	}
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
+
+// DashboardDataEthStore returns the ETH.STORE style apr of the validators of the dashboard over a range of days as json or csv
+func DashboardDataEthStore(w http.ResponseWriter, r *http.Request) {
+	validatorIndices, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+	q := r.URL.Query()
+
+	fromDay, toDay, err := parseEthStoreRange(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := getValidatorEthStore(db.BenchmarkValidators(validatorIndices), "dashboard", fromDay, toDay)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving ETH.STORE apr of dashboard validators")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if q.Get("format") == "csv" {
+		writeValidatorEthStoreCsv(w, r, data)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// parseEthStoreRange returns the range of days of the request, the range is given either as beaconchain-days (from_day, to_day) or as dates (from, to).
+// The range ends at the latest ETH.STORE day at most and spans the last defaultEthStoreRangeDays days by default.
+func parseEthStoreRange(q url.Values) (fromDay, toDay uint64, err error) {
+	latestDay, ok, err := getLatestEthStoreDay()
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving latest ETH.STORE day")
+		return 0, 0, fmt.Errorf("could not retrieve db results")
+	}
+	if !ok {
+		return 0, 0, errEthStoreNotAvailable
+	}
+
+	toDay = latestDay
+	if q.Get("to_day") != "" || q.Get("to") != "" {
+		toDay, err = parseEthStoreDay(q.Get("to_day"), q.Get("to"))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid end of range: %w", err)
+		}
+		if toDay > latestDay {
+			toDay = latestDay
+		}
+	}
+
+	fromDay = 0
+	if toDay+1 > defaultEthStoreRangeDays {
+		fromDay = toDay + 1 - defaultEthStoreRangeDays
+	}
+	if q.Get("from_day") != "" || q.Get("from") != "" {
+		fromDay, err = parseEthStoreDay(q.Get("from_day"), q.Get("from"))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start of range: %w", err)
+		}
+	}
+
+	if fromDay > toDay {
+		return 0, 0, fmt.Errorf("invalid range, the start of the range must not be after its end (day %v)", toDay)
+	}
+	return fromDay, toDay, nil
+}
+
+// parseEthStoreDay parses a beaconchain-day or, if day is empty, a date of the form 2006-01-02
+func parseEthStoreDay(day, date string) (uint64, error) {
+	if day != "" {
+		return strconv.ParseUint(day, 10, 64)
+	}
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0, err
+	}
+	if t.Before(utils.DayToTime(0)) {
+		return 0, nil
+	}
+	return utils.TimeToDay(uint64(t.Unix())), nil
+}
+
+// getValidatorEthStore returns the daily ETH.STORE style performance of the selected validators and their apr over the range
+func getValidatorEthStore(selector db.BenchmarkSelector, set string, fromDay, toDay uint64) (*types.ApiValidatorEthStoreResponse, error) {
+	days, err := db.GetValidatorEthStoreDays(selector, fromDay, toDay)
+	if err != nil {
+		return nil, err
+	}
+	ethStoreApr, err := db.GetEthStoreRangeApr(fromDay, toDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ETH.STORE apr of days %v to %v: %w", fromDay, toDay, err)
+	}
+
+	data := &types.ApiValidatorEthStoreResponse{
+		Set:             set,
+		FromDay:         fromDay,
+		ToDay:           toDay,
+		EthStoreApr:     ethStoreApr,
+		TotalRewardsWei: decimal.Zero,
+		Days:            days,
+	}
+	for _, day := range days {
+		data.TotalRewardsWei = data.TotalRewardsWei.Add(day.TotalRewardsWei)
+	}
+	data.Apr = db.EthStoreRangeApr(days)
+	return data, nil
+}
+
+// writeValidatorEthStoreCsv writes the daily performance of a set as csv download
+func writeValidatorEthStoreCsv(w http.ResponseWriter, r *http.Request, data *types.ApiValidatorEthStoreResponse) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=ethstore_%v_%v.csv", utils.DayToTime(int64(data.FromDay)).Format("20060102"), utils.DayToTime(int64(data.ToDay)).Format("20060102")))
+
+	writer := csv.NewWriter(w)
+	records := [][]string{{"day", "date", "validators", "effective_balances_sum_wei", "consensus_rewards_sum_wei", "tx_fees_sum_wei", "total_rewards_wei", "apr", "ethstore_apr"}}
+	for _, day := range data.Days {
+		records = append(records, []string{
+			fmt.Sprintf("%d", day.Day),
+			utils.DayToTime(int64(day.Day)).Format("2006-01-02"),
+			fmt.Sprintf("%d", day.Validators),
+			day.EffectiveBalancesSumWei.String(),
+			day.ConsensusRewardsSumWei.String(),
+			day.TxFeesSumWei.String(),
+			day.TotalRewardsWei.String(),
+			strconv.FormatFloat(day.Apr, 'f', -1, 64),
+			strconv.FormatFloat(day.EthStoreApr, 'f', -1, 64),
+		})
+	}
+	err := writer.WriteAll(records)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error writing ETH.STORE csv")
+	}
+}
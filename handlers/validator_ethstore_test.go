@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"net/url"
+	"testing"
+)
+
+func TestParseEthStoreRange(t *testing.T) {
+	defer func(config *types.Config) { utils.Config = config }(utils.Config)
+	utils.Config = &types.Config{}
+	utils.Config.Chain.GenesisTimestamp = 1606824023
+
+	defer func(f func() (uint64, bool, error)) { getLatestEthStoreDay = f }(getLatestEthStoreDay)
+	latestDay := uint64(1000)
+	getLatestEthStoreDay = func() (uint64, bool, error) { return latestDay, true, nil }
+
+	tests := []struct {
+		name    string
+		query   string
+		fromDay uint64
+		toDay   uint64
+		err     bool
+	}{
+		{"default range", "", 970, 1000, false},
+		{"end of range", "to_day=500", 470, 500, false},
+		{"end after latest day", "to_day=2000", 970, 1000, false},
+		{"short chain", "to_day=10", 0, 10, false},
+		{"explicit range", "from_day=100&to_day=200", 100, 200, false},
+		{"dates", "from=2020-12-02&to=2020-12-11", 0, 9, false},
+		{"date before genesis", "from=2019-01-01&to_day=5", 0, 5, false},
+		{"start after end", "from_day=300&to_day=200", 0, 0, true},
+		{"invalid day", "to_day=abc", 0, 0, true},
+		{"invalid date", "from=02.12.2020", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fromDay, toDay, err := parseEthStoreRange(q)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected an error, got range %v to %v", fromDay, toDay)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fromDay != tt.fromDay || toDay != tt.toDay {
+				t.Errorf("expected range %v to %v, got %v to %v", tt.fromDay, tt.toDay, fromDay, toDay)
+			}
+		})
+	}
+
+	getLatestEthStoreDay = func() (uint64, bool, error) { return 0, false, nil }
+	if _, _, err := parseEthStoreRange(url.Values{}); !errors.Is(err, errEthStoreNotAvailable) {
+		t.Errorf("expected %v without ETH.STORE days, got %v", errEthStoreNotAvailable, err)
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+const maxValidatorSets = 10
+
+// UserValidatorSets godoc
+// @Summary Get all rule based validator sets of the user
+// @Tags User
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorSet}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/validatorsets [get]
+func UserValidatorSets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	sets, err := db.GetValidatorSets(user.UserID, utils.GetNetwork())
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving validator sets of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiValidatorSet, 0, len(sets))
+	for _, set := range sets {
+		data = append(data, toApiValidatorSet(set))
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserValidatorSet godoc
+// @Summary Get a rule based validator set of the user including its current members
+// @Tags User
+// @Produce json
+// @Param setId path int true "Id of the validator set"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorSet}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/validatorsets/{setId} [get]
+func UserValidatorSet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	setID, err := strconv.ParseUint(mux.Vars(r)["setId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid validator set id")
+		return
+	}
+
+	set, err := db.GetValidatorSet(user.UserID, setID)
+	if err != nil {
+		handleValidatorSetError(w, r, err)
+		return
+	}
+	validators, err := db.GetValidatorSetValidators(set.ID)
+	if err != nil {
+		handleValidatorSetError(w, r, err)
+		return
+	}
+
+	data := toApiValidatorSet(set)
+	data.Validators = validators
+	sendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserValidatorSetCreate godoc
+// @Summary Create a validator set that contains all validators matching a rule: all validators with the given withdrawal credentials or withdrawal address (withdrawal_credentials), all validators deposited from an address (deposit_address) or all validators with a pool tag (pool_tag), optionally limited to the minipools of a rocketpool node. The members of the set are re-evaluated every epoch and new members are subscribed to the given event_names.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param set body types.ApiValidatorSetCreateRequest true "The name and rule of the set and the validator notifications its members are subscribed to"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorSet}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/validatorsets [post]
+func UserValidatorSetCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	req := types.ApiValidatorSetCreateRequest{}
+	if !parseUserDashboardBody(w, r, &req) {
+		return
+	}
+	name, ok := validateUserDashboardName(w, r, req.Name)
+	if !ok {
+		return
+	}
+
+	set := &types.ValidatorSet{
+		UserID:         user.UserID,
+		Network:        utils.GetNetwork(),
+		Name:           name,
+		MaxValidators:  uint64(getUserPremium(r).MaxValidators),
+		EventNames:     []string{},
+		EventThreshold: req.EventThreshold,
+	}
+	err := parseValidatorSetRule(&req, set)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	for _, eventName := range req.EventNames {
+		if !isValidatorWatchlistEvent(eventName) {
+			sendErrorResponse(w, r.URL.String(), fmt.Sprintf("invalid event name: %v", eventName))
+			return
+		}
+		set.EventNames = append(set.EventNames, eventName)
+	}
+
+	count, err := db.CountValidatorSets(user.UserID, utils.GetNetwork())
+	if err != nil {
+		logger.WithError(err).Errorf("error counting validator sets of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if count >= maxValidatorSets {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("only a maximum of %d validator sets are allowed", maxValidatorSets))
+		return
+	}
+
+	err = db.CreateValidatorSet(set)
+	if err != nil {
+		logger.WithError(err).Errorf("error creating validator set of user %v", user.UserID)
+		sendServerErrorResponse(w, r.URL.String(), "could not create validator set")
+		return
+	}
+
+	// evaluate the rule right away, afterwards the set is updated every epoch
+	err = db.UpdateValidatorSet(set, services.LatestEpoch())
+	if err != nil {
+		logger.WithError(err).Errorf("error evaluating validator set %v", set.ID)
+	}
+	sendOKResponse(j, r.URL.String(), []interface{}{toApiValidatorSet(set)})
+}
+
+// UserValidatorSetDelete godoc
+// @Summary Delete a rule based validator set, the subscriptions of its members are kept
+// @Tags User
+// @Produce json
+// @Param setId path int true "Id of the validator set"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 500 {object} types.ApiResponse
+// @Security OAuthAccessCode
+// @Router /api/v1/user/validatorsets/{setId}/delete [post]
+func UserValidatorSetDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+
+	setID, err := strconv.ParseUint(mux.Vars(r)["setId"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid validator set id")
+		return
+	}
+
+	err = db.DeleteValidatorSet(user.UserID, setID)
+	if err != nil {
+		handleValidatorSetError(w, r, err)
+		return
+	}
+	sendOKResponse(j, r.URL.String(), nil)
+}
+
+// parseValidatorSetRule validates the rule of the request and stores it normalized in set
+func parseValidatorSetRule(req *types.ApiValidatorSetCreateRequest, set *types.ValidatorSet) error {
+	set.RuleType = types.ValidatorSetRuleType(req.RuleType)
+	switch set.RuleType {
+	case types.WithdrawalCredentialsValidatorSetRule:
+		set.RuleEnsName = validatorSetRuleEnsName(req.RuleValue)
+		value := strings.ToLower(ReplaceEnsNameWithAddress(strings.TrimSpace(req.RuleValue)))
+		if utils.IsValidEth1Address(value) {
+			credentials, err := utils.AddressToWithdrawalCredentials(common.FromHex(value))
+			if err != nil {
+				return err
+			}
+			set.RuleValue = hex.EncodeToString(credentials)
+		} else if utils.IsValidWithdrawalCredentials(value) {
+			set.RuleValue = strings.TrimPrefix(value, "0x")
+		} else {
+			return fmt.Errorf("invalid withdrawal credentials or address")
+		}
+	case types.DepositAddressValidatorSetRule:
+		set.RuleEnsName = validatorSetRuleEnsName(req.RuleValue)
+		value := strings.ToLower(ReplaceEnsNameWithAddress(strings.TrimSpace(req.RuleValue)))
+		if !utils.IsValidEth1Address(value) {
+			return fmt.Errorf("invalid deposit address")
+		}
+		set.RuleValue = strings.TrimPrefix(value, "0x")
+	case types.PoolTagValidatorSetRule:
+		value := strings.TrimSpace(req.RuleValue)
+		if value == "" || len(value) > 100 {
+			return fmt.Errorf("invalid pool tag")
+		}
+		set.RuleValue = value
+		if req.RuleNodeAddress != "" {
+			set.RuleEnsName = validatorSetRuleEnsName(req.RuleNodeAddress)
+			nodeAddress := strings.ToLower(ReplaceEnsNameWithAddress(strings.TrimSpace(req.RuleNodeAddress)))
+			if !utils.IsValidEth1Address(nodeAddress) {
+				return fmt.Errorf("invalid node address")
+			}
+			set.RuleNodeAddress = strings.TrimPrefix(nodeAddress, "0x")
+		}
+	default:
+		return fmt.Errorf("invalid rule type, must be one of %v, %v or %v", types.WithdrawalCredentialsValidatorSetRule, types.DepositAddressValidatorSetRule, types.PoolTagValidatorSetRule)
+	}
+	return nil
+}
+
+// validatorSetRuleEnsName returns the ens name if the address of a rule is given as ens name, the address is re-resolved when the set is evaluated
+func validatorSetRuleEnsName(value string) string {
+	value = strings.TrimSpace(value)
+	if utils.IsValidEnsDomain(value) {
+		return value
+	}
+	return ""
+}
+
+func isValidatorWatchlistEvent(eventName string) bool {
+	for _, e := range types.AddWatchlistEvents {
+		if string(e.Event) == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+func toApiValidatorSet(set *types.ValidatorSet) *types.ApiValidatorSet {
+	res := &types.ApiValidatorSet{
+		ID:             set.ID,
+		Name:           set.Name,
+		RuleType:       string(set.RuleType),
+		RuleValue:      set.RuleValue,
+		RuleEnsName:    set.RuleEnsName,
+		EventNames:     set.EventNames,
+		EventThreshold: set.EventThreshold,
+		ValidatorCount: set.ValidatorCount,
+		CreatedTs:      set.CreatedTs.Unix(),
+	}
+	if set.RuleType != types.PoolTagValidatorSetRule {
+		res.RuleValue = "0x" + set.RuleValue
+	}
+	if set.RuleNodeAddress != "" {
+		res.RuleNodeAddress = "0x" + set.RuleNodeAddress
+	}
+	if set.LastEvaluatedEpoch.Valid {
+		epoch := uint64(set.LastEvaluatedEpoch.Int64)
+		res.LastEvaluatedEpoch = &epoch
+	}
+	return res
+}
+
+func handleValidatorSetError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, db.ErrValidatorSetNotFound) {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	logger.WithError(err).Errorf("error accessing validator set")
+	sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+}
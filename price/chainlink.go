@@ -0,0 +1,109 @@
+package price
+
+import (
+	"eth2-exporter/price/chainlink_feed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// ChainlinkSource reads prices from chainlink price feed contracts. The price of the native currency in USD is read from the USD feed,
+// the price in another currency is derived from the USD price and the <currency>/USD feed.
+type ChainlinkSource struct {
+	feeds    map[string]*chainlink_feed.Feed
+	decimals map[string]int32
+}
+
+// NewChainlinkSource connects to the feed contracts, feeds maps currencies to feed addresses and must contain USD
+func NewChainlinkSource(eth1Endpoint string, feeds map[string]string) (*ChainlinkSource, error) {
+	if _, ok := feeds["USD"]; !ok {
+		return nil, fmt.Errorf("missing USD feed of chainlink price source")
+	}
+
+	client, err := ethclient.Dial(eth1Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing pricing eth1 endpoint: %w", err)
+	}
+
+	source := &ChainlinkSource{
+		feeds:    make(map[string]*chainlink_feed.Feed, len(feeds)),
+		decimals: make(map[string]int32, len(feeds)),
+	}
+	for currency, address := range feeds {
+		currency = strings.ToUpper(currency)
+		feed, err := chainlink_feed.NewFeed(common.HexToAddress(address), client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize chainlink %v/USD feed contract: %w", currency, err)
+		}
+		decimals, err := feed.Decimals(nil)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving decimals of chainlink %v/USD feed: %w", currency, err)
+		}
+		source.feeds[currency] = feed
+		source.decimals[currency] = int32(decimals)
+	}
+	return source, nil
+}
+
+func (s *ChainlinkSource) Name() string {
+	return "chainlink"
+}
+
+func (s *ChainlinkSource) Prices(currencies []string) (map[string]Quote, error) {
+	usd, err := s.latest("USD")
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]Quote, len(currencies))
+	for _, currency := range currencies {
+		if currency == "USD" {
+			prices[currency] = usd
+			continue
+		}
+		if _, ok := s.feeds[currency]; !ok {
+			continue
+		}
+		rate, err := s.latest(currency)
+		if err != nil {
+			return nil, err
+		}
+		if rate.Price == 0 {
+			continue
+		}
+		// fiat feeds are only updated once a day if the rate does not move, so the age of the derived price is the age of the USD price
+		prices[currency] = Quote{Price: usd.Price / rate.Price, UpdatedAt: usd.UpdatedAt}
+	}
+	return prices, nil
+}
+
+// HistoricPrices is not supported as chainlink rounds can not be looked up by time
+func (s *ChainlinkSource) HistoricPrices(ts time.Time, currencies []string) (map[string]float64, error) {
+	return nil, ErrHistoricPricesNotSupported
+}
+
+// HourlyPrices is not supported as chainlink rounds can not be looked up by time
+func (s *ChainlinkSource) HourlyPrices(ts time.Time, currencies []string) (map[time.Time]map[string]float64, error) {
+	return nil, ErrHistoricPricesNotSupported
+}
+
+// HourlyPriceWindow is 0 as hourly prices are not supported
+func (s *ChainlinkSource) HourlyPriceWindow() time.Duration {
+	return 0
+}
+
+// latest returns the latest answer of the feed of a currency
+func (s *ChainlinkSource) latest(currency string) (Quote, error) {
+	res, err := s.feeds[currency].LatestRoundData(nil)
+	if err != nil {
+		return Quote{}, fmt.Errorf("failed to fetch latest chainlink %v/USD price feed data: %w", currency, err)
+	}
+	return Quote{
+		Price:     decimal.NewFromBigInt(res.Answer, -s.decimals[currency]).InexactFloat64(),
+		UpdatedAt: time.Unix(res.UpdatedAt.Int64(), 0),
+	}, nil
+}
@@ -0,0 +1,102 @@
+package price
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CoingeckoSource reads prices from the coingecko api
+type CoingeckoSource struct {
+	endpoint string
+	coin     string
+}
+
+// NewCoingeckoSource creates a coingecko source for a coin id, the endpoint defaults to the public coingecko api
+func NewCoingeckoSource(endpoint, coin string) *CoingeckoSource {
+	if endpoint == "" {
+		endpoint = "https://api.coingecko.com/api/v3"
+	}
+	if coin == "" {
+		coin = "ethereum"
+	}
+	return &CoingeckoSource{endpoint: strings.TrimSuffix(endpoint, "/"), coin: coin}
+}
+
+func (s *CoingeckoSource) Name() string {
+	return "coingecko"
+}
+
+func (s *CoingeckoSource) Prices(currencies []string) (map[string]Quote, error) {
+	res := map[string]map[string]float64{}
+	err := getJSON(fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s&include_last_updated_at=true", s.endpoint, s.coin, strings.Join(lowerCurrencies(currencies), ",")), &res)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving coingecko prices: %w", err)
+	}
+	coin, ok := res[s.coin]
+	if !ok {
+		return nil, fmt.Errorf("coingecko prices of %v not found", s.coin)
+	}
+
+	updatedAt := time.Unix(int64(coin["last_updated_at"]), 0)
+	prices := make(map[string]Quote, len(currencies))
+	for _, currency := range currencies {
+		if price, ok := coin[strings.ToLower(currency)]; ok && price > 0 {
+			prices[currency] = Quote{Price: price, UpdatedAt: updatedAt}
+		}
+	}
+	return prices, nil
+}
+
+func (s *CoingeckoSource) HistoricPrices(ts time.Time, currencies []string) (map[string]float64, error) {
+	res := struct {
+		MarketData struct {
+			CurrentPrice map[string]float64 `json:"current_price"`
+		} `json:"market_data"`
+	}{}
+	err := getJSON(fmt.Sprintf("%s/coins/%s/history?date=%s", s.endpoint, s.coin, dayStart(ts).Format("02-01-2006")), &res)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving coingecko prices of %v: %w", ts.Format("2006-01-02"), err)
+	}
+
+	prices := make(map[string]float64, len(currencies))
+	for _, currency := range currencies {
+		if price, ok := res.MarketData.CurrentPrice[strings.ToLower(currency)]; ok && price > 0 {
+			prices[currency] = price
+		}
+	}
+	return prices, nil
+}
+
+// HourlyPrices returns for every hour the price that is closest to the start of the hour, coingecko serves hourly data for ranges of one day
+func (s *CoingeckoSource) HourlyPrices(ts time.Time, currencies []string) (map[time.Time]map[string]float64, error) {
+	day := dayStart(ts)
+	prices := make(map[time.Time]map[string]float64, 24)
+	for _, currency := range currencies {
+		res := struct {
+			// Prices are [unix ms, price] pairs
+			Prices [][2]float64 `json:"prices"`
+		}{}
+		err := getJSON(fmt.Sprintf("%s/coins/%s/market_chart/range?vs_currency=%s&from=%d&to=%d", s.endpoint, s.coin, strings.ToLower(currency), day.Add(-time.Hour).Unix(), day.Add(time.Hour*24).Unix()), &res)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving coingecko %v prices of %v: %w", currency, day.Format("2006-01-02"), err)
+		}
+
+		points := make([]hourlyPoint, 0, len(res.Prices))
+		for _, p := range res.Prices {
+			points = append(points, hourlyPoint{ts: time.UnixMilli(int64(p[0])), price: p[1]})
+		}
+		for hour, price := range closestToHours(day, points) {
+			if prices[hour] == nil {
+				prices[hour] = map[string]float64{}
+			}
+			prices[hour][currency] = price
+		}
+	}
+	return prices, nil
+}
+
+// HourlyPriceWindow is 90 days, coingecko only serves hourly data for days within the last 90 days and daily data for older days
+func (s *CoingeckoSource) HourlyPriceWindow() time.Duration {
+	return time.Hour * 24 * 90
+}
@@ -0,0 +1,152 @@
+package price
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KrakenSource reads prices from an exchange api in the style of the kraken public market data api.
+// The price in a currency is the last trade price of the <asset><currency> pair, currencies without a pair are omitted.
+type KrakenSource struct {
+	endpoint string
+	asset    string
+}
+
+// NewKrakenSource creates a kraken source for an asset, the endpoint defaults to the public kraken api
+func NewKrakenSource(endpoint, asset string) *KrakenSource {
+	if endpoint == "" {
+		endpoint = "https://api.kraken.com/0/public"
+	}
+	if asset == "" {
+		asset = "ETH"
+	}
+	return &KrakenSource{endpoint: strings.TrimSuffix(endpoint, "/"), asset: strings.ToUpper(asset)}
+}
+
+func (s *KrakenSource) Name() string {
+	return "kraken"
+}
+
+func (s *KrakenSource) Prices(currencies []string) (map[string]Quote, error) {
+	prices := make(map[string]Quote, len(currencies))
+	for _, currency := range currencies {
+		res := struct {
+			Error  []string `json:"error"`
+			Result map[string]struct {
+				// C is the last trade as [price, volume]
+				C []string `json:"c"`
+			} `json:"result"`
+		}{}
+		err := getJSON(fmt.Sprintf("%s/Ticker?pair=%s%s", s.endpoint, s.asset, currency), &res)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving kraken %v%v ticker: %w", s.asset, currency, err)
+		}
+		if len(res.Error) > 0 {
+			// unknown pairs are reported as error
+			continue
+		}
+		// the result is keyed by the name of the pair which may differ from the requested name, e.g. XETHZUSD for ETHUSD
+		for _, ticker := range res.Result {
+			if len(ticker.C) == 0 {
+				continue
+			}
+			price, err := strconv.ParseFloat(ticker.C[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid kraken %v%v price %q: %w", s.asset, currency, ticker.C[0], err)
+			}
+			prices[currency] = Quote{Price: price, UpdatedAt: time.Now()}
+		}
+	}
+	return prices, nil
+}
+
+// HistoricPrices returns the open prices of the daily candles, kraken only serves the latest 720 candles so older days are omitted
+func (s *KrakenSource) HistoricPrices(ts time.Time, currencies []string) (map[string]float64, error) {
+	day := dayStart(ts)
+	prices := make(map[string]float64, len(currencies))
+	for _, currency := range currencies {
+		points, err := s.candles(currency, 1440, day)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range points {
+			if p.ts.Equal(day) {
+				prices[currency] = p.price
+			}
+		}
+	}
+	return prices, nil
+}
+
+// candles returns the open prices of the candles of an interval in minutes starting at since, unknown pairs return no candles
+func (s *KrakenSource) candles(currency string, interval int, since time.Time) ([]hourlyPoint, error) {
+	res := struct {
+		Error []string `json:"error"`
+		// Result maps the pair to candles of [time, open, high, low, close, vwap, volume, count] and last to the time of the latest candle
+		Result map[string]interface{} `json:"result"`
+	}{}
+	err := getJSON(fmt.Sprintf("%s/OHLC?pair=%s%s&interval=%d&since=%d", s.endpoint, s.asset, currency, interval, since.Unix()-1), &res)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving kraken %v%v candles: %w", s.asset, currency, err)
+	}
+	if len(res.Error) > 0 {
+		return nil, nil
+	}
+
+	points := []hourlyPoint{}
+	for pair, candles := range res.Result {
+		if pair == "last" {
+			continue
+		}
+		list, ok := candles.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, candle := range list {
+			fields, ok := candle.([]interface{})
+			if !ok || len(fields) < 2 {
+				continue
+			}
+			t, ok := fields[0].(float64)
+			if !ok {
+				continue
+			}
+			open, ok := fields[1].(string)
+			if !ok {
+				continue
+			}
+			price, err := strconv.ParseFloat(open, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid kraken %v%v price %q: %w", s.asset, currency, open, err)
+			}
+			points = append(points, hourlyPoint{ts: time.Unix(int64(t), 0).UTC(), price: price})
+		}
+	}
+	return points, nil
+}
+
+// HourlyPrices returns the open prices of the hourly candles, kraken only serves the latest 720 candles so older days are omitted
+func (s *KrakenSource) HourlyPrices(ts time.Time, currencies []string) (map[time.Time]map[string]float64, error) {
+	day := dayStart(ts)
+	prices := make(map[time.Time]map[string]float64, 24)
+	for _, currency := range currencies {
+		points, err := s.candles(currency, 60, day)
+		if err != nil {
+			return nil, err
+		}
+		for hour, price := range closestToHours(day, points) {
+			if prices[hour] == nil {
+				prices[hour] = map[string]float64{}
+			}
+			prices[hour][currency] = price
+		}
+	}
+	return prices, nil
+}
+
+// HourlyPriceWindow is 720 hours, kraken only serves the latest 720 candles
+func (s *KrakenSource) HourlyPriceWindow() time.Duration {
+	return time.Hour * 720
+}
@@ -1,257 +1,206 @@
 package price
 
 import (
-	"eth2-exporter/price/chainlink_feed"
+	"eth2-exporter/types"
 	"fmt"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 )
 
 var logger = logrus.New().WithField("module", "price")
 
-type EthPrice struct {
-	Ethereum struct {
-		Cad float64 `json:"cad"`
-		Cny float64 `json:"cny"`
-		Eur float64 `json:"eur"`
-		Jpy float64 `json:"jpy"`
-		Usd float64 `json:"usd"`
-		Gbp float64 `json:"gbp"`
-		Aud float64 `json:"aud"`
-	} `json:"ethereum"`
-}
+var availableCurrencies = []string{"ETH"}
+var currencies = []string{}
+var maxAge time.Duration
 
-var availableCurrencies = []string{"ETH", "USD", "EUR", "GBP", "CNY", "CAD", "AUD", "JPY"}
-var ethPrice = new(EthPrice)
+// ethPrices holds the aggregated current prices by currency
+var ethPrices = map[string]Quote{}
 var ethPriceMux = &sync.RWMutex{}
 
-var ethUSDFeed *chainlink_feed.Feed
-var eurUSDFeed *chainlink_feed.Feed
-var cadUSDFeed *chainlink_feed.Feed
-var cnyUSDFeed *chainlink_feed.Feed
-var jpyUSDFeed *chainlink_feed.Feed
-var gbpUSDFeed *chainlink_feed.Feed
-var audUSDFeed *chainlink_feed.Feed
-
-func Init(chainId uint64, eth1Endpoint string) {
-
-	eClient, err := ethclient.Dial(eth1Endpoint)
-	if err != nil {
-		logger.Errorf("error dialing pricing eth1 endpoint: %v", err)
-		return
-	}
-
-	ethUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eth/usd feed contract: %v", err)
-		return
-	}
-
-	eurUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0xb49f677943bc038e9857d61e7d053caa2c1734c1"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eur/usd feed contract: %v", err)
-		return
-	}
-
-	cadUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0xa34317db73e77d453b1b8d04550c44d10e981c8e"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eur/usd feed contract: %v", err)
-		return
-	}
-
-	cnyUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0xef8a4af35cd47424672e3c590abd37fbb7a7759a"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eur/usd feed contract: %v", err)
-		return
-	}
-
-	jpyUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0xbce206cae7f0ec07b545edde332a47c2f75bbeb3"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eur/usd feed contract: %v", err)
-		return
-	}
-
-	gbpUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0x5c0ab2d9b5a7ed9f470386e82bb36a3613cdd4b5"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eur/usd feed contract: %v", err)
-		return
-	}
+var sources = []PriceSource{}
+var initSourcesOnce sync.Once
 
-	audUSDFeed, err = chainlink_feed.NewFeed(common.HexToAddress("0x77f9710e7d0a19669a13c055f62cd80d313df022"), eClient)
-	if err != nil {
-		logger.Errorf("failed to initialized chainlink eur/usd feed contract: %v", err)
-		return
-	}
+// InitSources creates the configured price sources, processes that only backfill historic prices do not need to call Init
+func InitSources(cfg *types.Config) {
+	initSourcesOnce.Do(func() {
+		currencies = cfg.Price.Currencies
+		availableCurrencies = append([]string{"ETH"}, currencies...)
+		maxAge = cfg.Price.MaxAge
 
-	go updateEthPrice(chainId, eth1Endpoint)
+		sourceConfigs := cfg.Price.Sources
+		if len(sourceConfigs) == 0 {
+			sourceConfigs = defaultSources(cfg.Chain.Config.DepositChainID, cfg.Chain.Name, cfg.Eth1ErigonEndpoint)
+		}
+		for _, sourceConfig := range sourceConfigs {
+			source, err := newSource(sourceConfig)
+			if err != nil {
+				logger.Errorf("error initializing %v price source: %v", sourceConfig.Type, err)
+				continue
+			}
+			sources = append(sources, source)
+		}
+		logger.Infof("initialized %v price sources for currencies %v", len(sources), currencies)
+	})
 }
 
-func updateEthPrice(chainId uint64, eth1Endpoint string) {
+// Init creates the configured price sources and starts updating the current prices
+func Init(cfg *types.Config) {
+	InitSources(cfg)
+	go updateEthPrice()
+}
 
+func updateEthPrice() {
 	for {
-		fetchChainlinkFeed(chainId)
+		fetchPrices()
 		time.Sleep(time.Minute)
 	}
 }
 
-func fetchChainlinkFeed(chainId uint64) {
-	if chainId != 1 {
-		ethPrice = &EthPrice{
-			Ethereum: struct {
-				Cad float64 "json:\"cad\""
-				Cny float64 "json:\"cny\""
-				Eur float64 "json:\"eur\""
-				Jpy float64 "json:\"jpy\""
-				Usd float64 "json:\"usd\""
-				Gbp float64 "json:\"gbp\""
-				Aud float64 "json:\"aud\""
-			}{
-				Cad: 0,
-				Cny: 0,
-				Eur: 0,
-				Jpy: 0,
-				Usd: 0,
-				Gbp: 0,
-				Aud: 0,
-			},
+// fetchPrices updates the price of every currency to the median of the prices of all sources that are not stale.
+// If all prices of a currency are stale the previous price is kept.
+func fetchPrices() {
+	quotes := make([]map[string]Quote, len(sources))
+	wg := &sync.WaitGroup{}
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source PriceSource) {
+			defer wg.Done()
+			prices, err := source.Prices(currencies)
+			if err != nil {
+				logger.Errorf("error fetching prices from %v: %v", source.Name(), err)
+				return
+			}
+			quotes[i] = prices
+		}(i, source)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	prices := make(map[string]Quote, len(currencies))
+	for _, currency := range currencies {
+		values := []float64{}
+		for i, sourceQuotes := range quotes {
+			quote, ok := sourceQuotes[currency]
+			if !ok {
+				continue
+			}
+			if now.Sub(quote.UpdatedAt) > maxAge {
+				logger.Warnf("ignoring stale %v price of %v, last updated at %v", currency, sources[i].Name(), quote.UpdatedAt)
+				continue
+			}
+			values = append(values, quote.Price)
+		}
+		if len(values) > 0 {
+			prices[currency] = Quote{Price: median(values), UpdatedAt: now}
 		}
-		return
 	}
 
-	var ethUSDPrice float64
-	var eurUSDPrice float64
-	var cadUSDPrice float64
-	var cnyUSDPrice float64
-	var jpyUSDPrice float64
-	var gbpUSDPrice float64
-	var audUSDPrice float64
-
-	g := &errgroup.Group{}
+	ethPriceMux.Lock()
+	defer ethPriceMux.Unlock()
+	for _, currency := range currencies {
+		if quote, ok := prices[currency]; ok {
+			ethPrices[currency] = quote
+		} else if previous, ok := ethPrices[currency]; ok && now.Sub(previous.UpdatedAt) > maxAge {
+			logger.Warnf("%v price is stale, last updated at %v", currency, previous.UpdatedAt)
+		}
+	}
+}
 
-	g.Go(func() error {
-		var err error
-		ethUSDPrice, err = getPriceFromFeed(ethUSDFeed)
-		if err != nil {
-			return fmt.Errorf("error fetching price from EUR/USD feed: %v", err)
+// GetHistoricPrices returns the median of the prices of all sources at the start of the utc day of ts for the configured currencies,
+// currencies without a price are omitted
+func GetHistoricPrices(ts time.Time) (map[string]float64, error) {
+	values := make(map[string][]float64, len(currencies))
+	for _, source := range sources {
+		prices, err := source.HistoricPrices(ts, currencies)
+		if err == ErrHistoricPricesNotSupported {
+			continue
 		}
-		return nil
-	})
-	g.Go(func() error {
-		var err error
-		eurUSDPrice, err = getPriceFromFeed(eurUSDFeed)
 		if err != nil {
-			return fmt.Errorf("error fetching price from EUR/USD feed: %v", err)
+			logger.Errorf("error fetching historic prices of %v from %v: %v", ts.Format("2006-01-02"), source.Name(), err)
+			continue
 		}
-		return nil
-	})
-	g.Go(func() error {
-		var err error
-		cadUSDPrice, err = getPriceFromFeed(cadUSDFeed)
-		if err != nil {
-			return fmt.Errorf("error fetching price from CAD/USD feed: %v", err)
+		for currency, price := range prices {
+			values[currency] = append(values[currency], price)
 		}
-		return nil
-	})
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no price source has historic prices of %v", ts.Format("2006-01-02"))
+	}
 
-	g.Go(func() error {
-		var err error
-		cnyUSDPrice, err = getPriceFromFeed(cnyUSDFeed)
-		if err != nil {
-			return fmt.Errorf("error fetching price from CNY/USD feed: %v", err)
-		}
-		return nil
-	})
-	g.Go(func() error {
-		var err error
-		jpyUSDPrice, err = getPriceFromFeed(jpyUSDFeed)
-		if err != nil {
-			return fmt.Errorf("error fetching price from JPY/USD feed: %v", err)
+	prices := make(map[string]float64, len(values))
+	for currency, currencyValues := range values {
+		prices[currency] = median(currencyValues)
+	}
+	return prices, nil
+}
+
+// GetHourlyPrices returns the median of the prices of all sources at the start of every hour of the utc day of ts for the configured currencies,
+// hours and currencies without a price are omitted
+func GetHourlyPrices(ts time.Time) (map[time.Time]map[string]float64, error) {
+	values := map[time.Time]map[string][]float64{}
+	supported := false
+	for _, source := range sources {
+		prices, err := source.HourlyPrices(ts, currencies)
+		if err == ErrHistoricPricesNotSupported {
+			continue
 		}
-		return nil
-	})
-	g.Go(func() error {
-		var err error
-		gbpUSDPrice, err = getPriceFromFeed(gbpUSDFeed)
+		supported = true
 		if err != nil {
-			return fmt.Errorf("error fetching price from GPY/USD feed: %v", err)
+			logger.Errorf("error fetching hourly prices of %v from %v: %v", ts.Format("2006-01-02"), source.Name(), err)
+			continue
 		}
-		return nil
-	})
-	g.Go(func() error {
-		var err error
-		audUSDPrice, err = getPriceFromFeed(audUSDFeed)
-		if err != nil {
-			return fmt.Errorf("error fetching price from AUD/USD feed: %v", err)
+		for hour, hourPrices := range prices {
+			if values[hour] == nil {
+				values[hour] = map[string][]float64{}
+			}
+			for currency, price := range hourPrices {
+				values[hour][currency] = append(values[hour][currency], price)
+			}
 		}
-		return nil
-	})
-
-	err := g.Wait()
-
-	if err != nil {
-		logger.Error(err)
-		return
+	}
+	if !supported {
+		return nil, ErrHistoricPricesNotSupported
 	}
 
-	ethPrice = &EthPrice{
-		Ethereum: struct {
-			Cad float64 "json:\"cad\""
-			Cny float64 "json:\"cny\""
-			Eur float64 "json:\"eur\""
-			Jpy float64 "json:\"jpy\""
-			Usd float64 "json:\"usd\""
-			Gbp float64 "json:\"gbp\""
-			Aud float64 "json:\"aud\""
-		}{
-			Cad: ethUSDPrice / cadUSDPrice,
-			Cny: ethUSDPrice / cnyUSDPrice,
-			Eur: ethUSDPrice / eurUSDPrice,
-			Jpy: ethUSDPrice / jpyUSDPrice,
-			Usd: ethUSDPrice,
-			Gbp: ethUSDPrice / gbpUSDPrice,
-			Aud: ethUSDPrice / audUSDPrice,
-		},
+	prices := make(map[time.Time]map[string]float64, len(values))
+	for hour, hourValues := range values {
+		prices[hour] = make(map[string]float64, len(hourValues))
+		for currency, currencyValues := range hourValues {
+			prices[hour][currency] = median(currencyValues)
+		}
 	}
+	return prices, nil
 }
 
-func getPriceFromFeed(feed *chainlink_feed.Feed) (float64, error) {
-	decimals := decimal.NewFromInt(100000000)
-
-	res, err := feed.LatestRoundData(nil)
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch latest chainlink eth/usd price feed data: %v", err)
+// GetHourlyPriceWindow returns how far back from now hourly prices are served by any of the sources, it is 0 if no source supports hourly prices
+func GetHourlyPriceWindow() time.Duration {
+	window := time.Duration(0)
+	for _, source := range sources {
+		if w := source.HourlyPriceWindow(); w > window {
+			window = w
+		}
 	}
-	return decimal.NewFromBigInt(res.Answer, 0).Div(decimals).InexactFloat64(), nil
+	return window
+}
+
+// GetCurrencies returns the configured fiat currencies
+func GetCurrencies() []string {
+	return currencies
 }
 
+// GetEthPrice returns the price of the native currency in a currency, the price is 1 for ETH and unknown currencies and 0 if there is no price yet
 func GetEthPrice(currency string) float64 {
 	ethPriceMux.RLock()
 	defer ethPriceMux.RUnlock()
 
-	switch currency {
-	case "EUR":
-		return ethPrice.Ethereum.Eur
-	case "USD":
-		return ethPrice.Ethereum.Usd
-	case "CNY":
-		return ethPrice.Ethereum.Cny
-	case "CAD":
-		return ethPrice.Ethereum.Cad
-	case "AUD":
-		return ethPrice.Ethereum.Aud
-	case "JPY":
-		return ethPrice.Ethereum.Jpy
-	case "GBP":
-		return ethPrice.Ethereum.Gbp
-	default:
-		return 1
+	for _, c := range currencies {
+		if c == currency {
+			return ethPrices[currency].Price
+		}
 	}
+	return 1
 }
 
 func GetAvailableCurrencies() []string {
@@ -278,6 +227,26 @@ func GetCurrencyLabel(currency string) string {
 		return "Australian Dollar"
 	case "JPY":
 		return "Japanese Yen"
+	case "CHF":
+		return "Swiss Franc"
+	case "INR":
+		return "Indian Rupee"
+	case "KRW":
+		return "South Korean Won"
+	case "BRL":
+		return "Brazilian Real"
+	case "SGD":
+		return "Singapore Dollar"
+	case "HKD":
+		return "Hong Kong Dollar"
+	case "NZD":
+		return "New Zealand Dollar"
+	case "SEK":
+		return "Swedish Krona"
+	case "NOK":
+		return "Norwegian Krone"
+	case "PLN":
+		return "Polish Zloty"
 	default:
 		return ""
 	}
@@ -302,6 +271,24 @@ func GetSymbol(currency string) string {
 		return "¥"
 	case "GBP":
 		return "£"
+	case "CHF":
+		return "Fr."
+	case "INR":
+		return "₹"
+	case "KRW":
+		return "₩"
+	case "BRL":
+		return "R$"
+	case "SGD":
+		return "S$"
+	case "HKD":
+		return "HK$"
+	case "NZD":
+		return "NZ$"
+	case "SEK", "NOK":
+		return "kr"
+	case "PLN":
+		return "zł"
 	default:
 		return ""
 	}
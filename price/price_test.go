@@ -0,0 +1,152 @@
+package price
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		median float64
+	}{
+		{"no values", nil, 0},
+		{"single value", []float64{1800}, 1800},
+		{"odd count", []float64{1900, 1700, 1800}, 1800},
+		{"even count", []float64{1900, 1700, 1800, 2000}, 1850},
+		{"two values", []float64{1700, 1800}, 1750},
+		{"outlier", []float64{1800, 1810, 1790, 1805, 100000}, 1805},
+		{"duplicates", []float64{1800, 1800, 1700, 1900}, 1800},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if m := median(tt.values); m != tt.median {
+				t.Errorf("expected median %v, got %v", tt.median, m)
+			}
+		})
+	}
+}
+
+func TestClosestToHours(t *testing.T) {
+	day := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Hour*time.Duration(hour) + time.Minute*time.Duration(minute))
+	}
+
+	tests := []struct {
+		name   string
+		points []hourlyPoint
+		prices map[time.Time]float64
+	}{
+		{"no points", nil, map[time.Time]float64{}},
+		{"exactly at the hour", []hourlyPoint{{at(5, 0), 1800}}, map[time.Time]float64{at(5, 0): 1800}},
+		{"closest point wins", []hourlyPoint{{at(5, 20), 1800}, {at(4, 55), 1810}, {at(5, 10), 1820}}, map[time.Time]float64{at(5, 0): 1810}},
+		{"earlier point wins a tie", []hourlyPoint{{at(5, 10), 1800}, {at(4, 50), 1810}}, map[time.Time]float64{at(5, 0): 1800}},
+		{"rounded to the next hour", []hourlyPoint{{at(5, 31), 1800}}, map[time.Time]float64{at(6, 0): 1800}},
+		{"first hour from the previous day", []hourlyPoint{{day.Add(-time.Minute * 10), 1800}}, map[time.Time]float64{at(0, 0): 1800}},
+		{"outside of the day", []hourlyPoint{{day.Add(-time.Minute * 40), 1800}, {at(23, 45), 1810}}, map[time.Time]float64{}},
+		{"invalid prices", []hourlyPoint{{at(5, 0), 0}, {at(6, 0), -1}}, map[time.Time]float64{}},
+		{"other time zone", []hourlyPoint{{at(5, 0).In(time.FixedZone("UTC+2", 7200)), 1800}}, map[time.Time]float64{at(5, 0): 1800}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := closestToHours(day, tt.points)
+			if len(prices) != len(tt.prices) {
+				t.Fatalf("expected prices %v, got %v", tt.prices, prices)
+			}
+			for hour, price := range tt.prices {
+				if prices[hour] != price {
+					t.Errorf("expected price %v at %v, got %v", price, hour, prices[hour])
+				}
+			}
+		})
+	}
+}
+
+type testPriceSource struct {
+	quotes map[string]Quote
+	err    error
+}
+
+func (source *testPriceSource) Name() string {
+	return "test"
+}
+
+func (source *testPriceSource) Prices(currencies []string) (map[string]Quote, error) {
+	return source.quotes, source.err
+}
+
+func (source *testPriceSource) HistoricPrices(ts time.Time, currencies []string) (map[string]float64, error) {
+	return nil, ErrHistoricPricesNotSupported
+}
+
+func (source *testPriceSource) HourlyPrices(ts time.Time, currencies []string) (map[time.Time]map[string]float64, error) {
+	return nil, ErrHistoricPricesNotSupported
+}
+
+func (source *testPriceSource) HourlyPriceWindow() time.Duration {
+	return 0
+}
+
+func TestFetchPrices(t *testing.T) {
+	defer func(s []PriceSource, c []string, a time.Duration) { sources, currencies, maxAge = s, c, a }(sources, currencies, maxAge)
+	currencies = []string{"USD"}
+	maxAge = time.Hour
+
+	now := time.Now()
+	fresh := func(price float64) *testPriceSource {
+		return &testPriceSource{quotes: map[string]Quote{"USD": {Price: price, UpdatedAt: now.Add(-time.Minute)}}}
+	}
+	stale := func(price float64) *testPriceSource {
+		return &testPriceSource{quotes: map[string]Quote{"USD": {Price: price, UpdatedAt: now.Add(-time.Hour * 2)}}}
+	}
+	failing := &testPriceSource{err: errors.New("rate limited")}
+	missing := &testPriceSource{quotes: map[string]Quote{"EUR": {Price: 1700, UpdatedAt: now}}}
+
+	tests := []struct {
+		name     string
+		sources  []PriceSource
+		previous *Quote
+		price    float64
+		updated  bool
+	}{
+		{"single source", []PriceSource{fresh(1800)}, nil, 1800, true},
+		{"odd source count", []PriceSource{fresh(1800), fresh(1900), fresh(1700)}, nil, 1800, true},
+		{"even source count", []PriceSource{fresh(1800), fresh(1900), fresh(1700), fresh(2000)}, nil, 1850, true},
+		{"stale sources are ignored", []PriceSource{fresh(1800), stale(5000), stale(6000), fresh(1900)}, nil, 1850, true},
+		{"failing and incomplete sources are ignored", []PriceSource{failing, missing, fresh(1800)}, nil, 1800, true},
+		{"all sources stale", []PriceSource{stale(1800), stale(1900)}, &Quote{Price: 1500, UpdatedAt: now.Add(-time.Hour * 3)}, 1500, false},
+		{"no price", []PriceSource{failing}, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources = tt.sources
+			ethPriceMux.Lock()
+			ethPrices = map[string]Quote{}
+			if tt.previous != nil {
+				ethPrices["USD"] = *tt.previous
+			}
+			ethPriceMux.Unlock()
+
+			fetchPrices()
+
+			ethPriceMux.RLock()
+			quote, ok := ethPrices["USD"]
+			ethPriceMux.RUnlock()
+			if tt.previous == nil && !tt.updated {
+				if ok {
+					t.Errorf("expected no price, got %v", quote.Price)
+				}
+				return
+			}
+			if quote.Price != tt.price {
+				t.Errorf("expected price %v, got %v", tt.price, quote.Price)
+			}
+			if updated := quote.UpdatedAt.After(now); updated != tt.updated {
+				t.Errorf("expected the price to be updated %v, got last update at %v", tt.updated, quote.UpdatedAt)
+			}
+		})
+	}
+}
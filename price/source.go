@@ -0,0 +1,153 @@
+package price
+
+import (
+	"encoding/json"
+	"errors"
+	"eth2-exporter/types"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrHistoricPricesNotSupported is returned by sources that do not provide historic or hourly prices
+var ErrHistoricPricesNotSupported = errors.New("historic prices are not supported by the price source")
+
+// Quote is a price of the native currency and the time the price was last updated by its source
+type Quote struct {
+	Price     float64
+	UpdatedAt time.Time
+}
+
+// PriceSource provides prices of the native currency of the chain in fiat currencies, currencies are upper case iso codes.
+// Currencies that are not supported by a source are omitted from its results.
+type PriceSource interface {
+	Name() string
+	// Prices returns the current prices in the given currencies
+	Prices(currencies []string) (map[string]Quote, error)
+	// HistoricPrices returns the prices at the start of the utc day of ts in the given currencies
+	HistoricPrices(ts time.Time, currencies []string) (map[string]float64, error)
+	// HourlyPrices returns the prices at the start of every hour of the utc day of ts in the given currencies, hours without a price are omitted
+	HourlyPrices(ts time.Time, currencies []string) (map[time.Time]map[string]float64, error)
+	// HourlyPriceWindow returns how far back from now the source serves hourly prices, it is 0 if hourly prices are not supported
+	HourlyPriceWindow() time.Duration
+}
+
+var httpClient = &http.Client{Timeout: time.Second * 10}
+
+// newSource creates a price source from its config
+func newSource(cfg types.PriceSourceConfig) (PriceSource, error) {
+	switch cfg.Type {
+	case "chainlink":
+		return NewChainlinkSource(cfg.Endpoint, cfg.Feeds)
+	case "coingecko":
+		return NewCoingeckoSource(cfg.Endpoint, cfg.Asset), nil
+	case "kraken":
+		return NewKrakenSource(cfg.Endpoint, cfg.Asset), nil
+	case "static":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("missing file of static price source")
+		}
+		return NewStaticSource(cfg.File), nil
+	default:
+		return nil, fmt.Errorf("unknown price source type %q, must be one of chainlink, coingecko, kraken or static", cfg.Type)
+	}
+}
+
+// defaultSources returns the chainlink feeds on mainnet and coingecko for the native currency of the chain
+func defaultSources(chainId uint64, chainName, eth1Endpoint string) []types.PriceSourceConfig {
+	sources := []types.PriceSourceConfig{}
+	if chainId == 1 {
+		sources = append(sources, types.PriceSourceConfig{
+			Type:     "chainlink",
+			Endpoint: eth1Endpoint,
+			Feeds: map[string]string{
+				"USD": "0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419",
+				"EUR": "0xb49f677943bc038e9857d61e7d053caa2c1734c1",
+				"CAD": "0xa34317db73e77d453b1b8d04550c44d10e981c8e",
+				"CNY": "0xef8a4af35cd47424672e3c590abd37fbb7a7759a",
+				"JPY": "0xbce206cae7f0ec07b545edde332a47c2f75bbeb3",
+				"GBP": "0x5c0ab2d9b5a7ed9f470386e82bb36a3613cdd4b5",
+				"AUD": "0x77f9710e7d0a19669a13c055f62cd80d313df022",
+			},
+		})
+	}
+	asset := "ethereum"
+	if chainName == "gnosis" {
+		asset = "gnosis"
+	}
+	return append(sources, types.PriceSourceConfig{Type: "coingecko", Asset: asset})
+}
+
+// median returns the median of the values, values is sorted in place
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	m := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[m-1] + values[m]) / 2
+	}
+	return values[m]
+}
+
+// getJSON decodes the json response of a get request into v
+func getJSON(url string, v interface{}) error {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %v from %v", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// dayStart returns the start of the utc day of ts
+func dayStart(ts time.Time) time.Time {
+	return ts.UTC().Truncate(time.Hour * 24)
+}
+
+// lowerCurrencies returns the currencies in lower case as used by most apis
+func lowerCurrencies(currencies []string) []string {
+	res := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		res = append(res, strings.ToLower(currency))
+	}
+	return res
+}
+
+type hourlyPoint struct {
+	ts    time.Time
+	price float64
+}
+
+// closestToHours returns for every hour of the day the price of the point that is closest to the start of the hour,
+// points more than 30 minutes away from the start of an hour are ignored
+func closestToHours(day time.Time, points []hourlyPoint) map[time.Time]float64 {
+	prices := make(map[time.Time]float64, 24)
+	distances := make(map[time.Time]time.Duration, 24)
+	for _, p := range points {
+		if p.price <= 0 {
+			continue
+		}
+		hour := p.ts.UTC().Add(time.Minute * 30).Truncate(time.Hour)
+		if hour.Before(day) || !hour.Before(day.Add(time.Hour*24)) {
+			continue
+		}
+		distance := p.ts.Sub(hour)
+		if distance < 0 {
+			distance = -distance
+		}
+		if d, ok := distances[hour]; ok && d <= distance {
+			continue
+		}
+		distances[hour] = distance
+		prices[hour] = p.price
+	}
+	return prices
+}
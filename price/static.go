@@ -0,0 +1,104 @@
+package price
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// StaticSource reads prices from a json file, e.g. for testnets and deployments without internet access. The file has the format
+//
+//	{"prices": {"USD": 1800}, "history": {"2023-01-31": {"USD": 1580, "EUR": 1450}}}
+//
+// The file is read on every request so it can be updated without a restart. Static prices are never stale and the historic price of a day
+// without an entry is the price of the latest earlier day.
+type StaticSource struct {
+	file string
+}
+
+type staticPrices struct {
+	Prices  map[string]float64            `json:"prices"`
+	History map[string]map[string]float64 `json:"history"`
+}
+
+func NewStaticSource(file string) *StaticSource {
+	return &StaticSource{file: file}
+}
+
+func (s *StaticSource) Name() string {
+	return "static"
+}
+
+func (s *StaticSource) Prices(currencies []string) (map[string]Quote, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]Quote, len(currencies))
+	for _, currency := range currencies {
+		if price, ok := data.Prices[currency]; ok && price > 0 {
+			prices[currency] = Quote{Price: price, UpdatedAt: time.Now()}
+		}
+	}
+	return prices, nil
+}
+
+func (s *StaticSource) HistoricPrices(ts time.Time, currencies []string) (map[string]float64, error) {
+	data, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	// the dates are formatted as yyyy-mm-dd so they can be compared as strings
+	date := dayStart(ts).Format("2006-01-02")
+	prices := make(map[string]float64, len(currencies))
+	for _, currency := range currencies {
+		latest := ""
+		for day, dayPrices := range data.History {
+			if day <= date && day > latest && dayPrices[currency] > 0 {
+				latest = day
+			}
+		}
+		if latest != "" {
+			prices[currency] = data.History[latest][currency]
+		}
+	}
+	return prices, nil
+}
+
+// HourlyPrices is not supported as static files only contain daily prices
+func (s *StaticSource) HourlyPrices(ts time.Time, currencies []string) (map[time.Time]map[string]float64, error) {
+	return nil, ErrHistoricPricesNotSupported
+}
+
+// HourlyPriceWindow is 0 as hourly prices are not supported
+func (s *StaticSource) HourlyPriceWindow() time.Duration {
+	return 0
+}
+
+func (s *StaticSource) read() (*staticPrices, error) {
+	f, err := os.Open(s.file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening static price file: %w", err)
+	}
+	defer f.Close()
+
+	data := &staticPrices{}
+	err = json.NewDecoder(f).Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding static price file %v: %w", s.file, err)
+	}
+	// currencies are matched case insensitive
+	for currency, price := range data.Prices {
+		data.Prices[strings.ToUpper(currency)] = price
+	}
+	for _, dayPrices := range data.History {
+		for currency, price := range dayPrices {
+			dayPrices[strings.ToUpper(currency)] = price
+		}
+	}
+	return data, nil
+}
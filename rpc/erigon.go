@@ -205,11 +205,14 @@ func (client *ErigonClient) GetBlock(number int64) (*types.Eth1Block, *types.Get
 			logger.Infof("retrieved %v calls via geth", len(gethTraceData))
 
 			for _, trace := range gethTraceData {
-				if trace.Error == "" {
-					c.Transactions[trace.TransactionPosition].Status = 1
-				} else {
-					c.Transactions[trace.TransactionPosition].Status = 0
-					c.Transactions[trace.TransactionPosition].ErrorMsg = trace.Error
+				// only the top level call determines the status of the transaction
+				if len(trace.TraceAddress) == 0 {
+					if trace.Error == "" {
+						c.Transactions[trace.TransactionPosition].Status = 1
+					} else {
+						c.Transactions[trace.TransactionPosition].Status = 0
+						c.Transactions[trace.TransactionPosition].ErrorMsg = trace.Error
+					}
 				}
 
 				if trace.Type == "CREATE2" {
@@ -217,8 +220,9 @@ func (client *ErigonClient) GetBlock(number int64) (*types.Eth1Block, *types.Get
 				}
 
 				tracePb := &types.Eth1InternalTransaction{
-					Type: strings.ToLower(trace.Type),
-					Path: "0",
+					Type:     strings.ToLower(trace.Type),
+					Path:     fmt.Sprint(trace.TraceAddress),
+					ErrorMsg: trace.Error,
 				}
 
 				tracePb.From = trace.From.Bytes()
@@ -254,16 +258,20 @@ func (client *ErigonClient) GetBlock(number int64) (*types.Eth1Block, *types.Get
 				return fmt.Errorf("error transaction position %v out of range", trace.TransactionPosition)
 			}
 
-			if trace.Error == "" {
-				c.Transactions[trace.TransactionPosition].Status = 1
-			} else {
-				c.Transactions[trace.TransactionPosition].Status = 0
-				c.Transactions[trace.TransactionPosition].ErrorMsg = trace.Error
+			// only the top level call determines the status of the transaction
+			if len(trace.TraceAddress) == 0 {
+				if trace.Error == "" {
+					c.Transactions[trace.TransactionPosition].Status = 1
+				} else {
+					c.Transactions[trace.TransactionPosition].Status = 0
+					c.Transactions[trace.TransactionPosition].ErrorMsg = trace.Error
+				}
 			}
 
 			tracePb := &types.Eth1InternalTransaction{
-				Type: trace.Type,
-				Path: fmt.Sprint(trace.TraceAddress),
+				Type:     trace.Type,
+				Path:     fmt.Sprint(trace.TraceAddress),
+				ErrorMsg: trace.Error,
 			}
 
 			if tracePb.Type == "call" {
@@ -381,6 +389,8 @@ type GethTraceCallResult struct {
 	Error               string
 	Type                string
 	Calls               []*GethTraceCallResult
+	// TraceAddress is the position of the call in the call tree in the format of parity style traces, e.g. [0 1] for the second call of the first call
+	TraceAddress []int `json:"-"`
 }
 
 type GethTraceCallData struct {
@@ -405,8 +415,9 @@ func extractCalls(r *GethTraceCallResult, d *[]*GethTraceCallResult) {
 	if r.Calls == nil {
 		return
 	}
-	for _, c := range r.Calls {
+	for i, c := range r.Calls {
 		c.TransactionPosition = r.TransactionPosition
+		c.TraceAddress = append(append(make([]int, 0, len(r.TraceAddress)+1), r.TraceAddress...), i)
 		extractCalls(c, d)
 	}
 }
@@ -483,6 +494,15 @@ func (client *ErigonClient) TraceParityTx(txHash string) ([]*ParityTraceResult,
 }
 
 func (client *ErigonClient) GetBalances(pairs []*types.Eth1AddressBalance, addressIndex, tokenIndex int) ([]*types.Eth1AddressBalance, error) {
+	return client.getBalances(pairs, "latest")
+}
+
+// GetBalancesAtBlock retrieves the native and ERC-20 balances of the pairs at blockNumber, which requires an archive node for blocks that are not recent
+func (client *ErigonClient) GetBalancesAtBlock(pairs []*types.Eth1AddressBalance, blockNumber uint64) ([]*types.Eth1AddressBalance, error) {
+	return client.getBalances(pairs, hexutil.EncodeUint64(blockNumber))
+}
+
+func (client *ErigonClient) getBalances(pairs []*types.Eth1AddressBalance, block string) ([]*types.Eth1AddressBalance, error) {
 	batchElements := make([]geth_rpc.BatchElem, 0, len(pairs))
 
 	ret := make([]*types.Eth1AddressBalance, len(pairs))
@@ -505,7 +525,7 @@ func (client *ErigonClient) GetBalances(pairs []*types.Eth1AddressBalance, addre
 		if len(pair.Token) < 20 {
 			batchElements = append(batchElements, geth_rpc.BatchElem{
 				Method: "eth_getBalance",
-				Args:   []interface{}{common.BytesToAddress(pair.Address), "latest"},
+				Args:   []interface{}{common.BytesToAddress(pair.Address), block},
 				Result: &result,
 			})
 		} else {
@@ -518,7 +538,7 @@ func (client *ErigonClient) GetBalances(pairs []*types.Eth1AddressBalance, addre
 
 			batchElements = append(batchElements, geth_rpc.BatchElem{
 				Method: "eth_call",
-				Args:   []interface{}{toCallArg(msg), "latest"},
+				Args:   []interface{}{toCallArg(msg), block},
 				Result: &result,
 			})
 		}
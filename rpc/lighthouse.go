@@ -268,7 +268,8 @@ func (lc *LighthouseClient) GetEpochAssignments(epoch uint64) (*types.EpochAssig
 		}
 	}
 
-	if len(assignments.AttestorAssignments) > 0 && len(assignments.ProposerAssignments) > 0 {
+	// the duties of an epoch that has not started yet can still change, only cache them once the epoch has begun
+	if len(assignments.AttestorAssignments) > 0 && len(assignments.ProposerAssignments) > 0 && !utils.EpochToTime(epoch).After(time.Now()) {
 		lc.assignmentsCache.Add(epoch, assignments)
 	}
 
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"eth2-exporter/cache"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BenchmarkWindows are the number of days over which the performance of validator sets can be benchmarked
+var BenchmarkWindows = []uint64{1, 7, 31}
+
+// benchmarkDays is the number of days of network performance that are kept as benchmark, it must be at least the largest window
+const benchmarkDays = 31
+
+// validatorBenchmarkUpdater aggregates the daily network performance and the apr distribution of all validators whenever a new ETH.STORE day is available
+func validatorBenchmarkUpdater(wg *sync.WaitGroup) {
+	firstRun := true
+	cacheKey := fmt.Sprintf("%d:frontend:validatorBenchmark", utils.Config.Chain.Config.DepositChainID)
+
+	// continue with the benchmark of another instance to avoid aggregating all days again
+	var data *types.ValidatorBenchmarkData
+	if cached, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Second*60, &types.ValidatorBenchmarkData{}); err == nil {
+		data = cached.(*types.ValidatorBenchmarkData)
+	}
+
+	for {
+		next, err := getValidatorBenchmarkData(data)
+		if err != nil {
+			logger.Errorf("error retrieving validator benchmark data: %v", err)
+			time.Sleep(time.Second * 10)
+			continue
+		}
+
+		if next != data {
+			err = cache.TieredCache.Set(cacheKey, next, time.Hour*24)
+			if err != nil {
+				logger.Errorf("error caching validator benchmark data: %v", err)
+			}
+			data = next
+		}
+		if firstRun {
+			firstRun = false
+			wg.Done()
+			logger.Info("initialized validator benchmark updater")
+		}
+		ReportStatus("validatorBenchmarkUpdater", "Running", nil)
+		time.Sleep(time.Minute * 10)
+	}
+}
+
+// getValidatorBenchmarkData returns the benchmark up to the latest ETH.STORE day, complete days of the previous benchmark are not aggregated again.
+// The previous benchmark is returned as is if there is no new day.
+func getValidatorBenchmarkData(previous *types.ValidatorBenchmarkData) (*types.ValidatorBenchmarkData, error) {
+	lastDay, ok, err := db.GetLatestEthStoreDay()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving latest ETH.STORE day: %w", err)
+	}
+	if !ok {
+		return &types.ValidatorBenchmarkData{Days: []*types.ValidatorBenchmarkDay{}, AprPercentiles: map[uint64][]float64{}, Apr: map[uint64]float64{}, EthStoreApr: map[uint64]float64{}}, nil
+	}
+	if previous != nil && previous.Apr != nil && len(previous.Days) > 0 && previous.Days[len(previous.Days)-1].Day == lastDay && previous.Days[len(previous.Days)-1].Validators > 0 {
+		return previous, nil
+	}
+
+	start := time.Now()
+	firstDay := uint64(0)
+	if lastDay >= benchmarkDays {
+		firstDay = lastDay - benchmarkDays + 1
+	}
+
+	// keep the leading days of the previous benchmark that are complete, all following days are aggregated again
+	days := make([]*types.ValidatorBenchmarkDay, 0, benchmarkDays)
+	fromDay := firstDay
+	if previous != nil {
+		for _, day := range previous.Days {
+			if day.Day != fromDay || day.Validators == 0 || day.EthStoreApr == 0 {
+				continue
+			}
+			days = append(days, day)
+			fromDay++
+		}
+	}
+
+	if fromDay <= lastDay {
+		newDays, err := db.GetValidatorBenchmarkDays(db.BenchmarkSelector{}, fromDay, lastDay)
+		if err != nil {
+			return nil, err
+		}
+		ethStoreAprs, err := db.GetEthStoreAprs(fromDay, lastDay)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving ETH.STORE days %v to %v: %w", fromDay, lastDay, err)
+		}
+		for _, day := range newDays {
+			day.EthStoreApr = ethStoreAprs[day.Day]
+			days = append(days, day)
+		}
+	}
+
+	data := &types.ValidatorBenchmarkData{
+		Days:           days,
+		AprPercentiles: make(map[uint64][]float64, len(BenchmarkWindows)),
+		Apr:            make(map[uint64]float64, len(BenchmarkWindows)),
+		EthStoreApr:    make(map[uint64]float64, len(BenchmarkWindows)),
+	}
+	for _, window := range BenchmarkWindows {
+		windowStart := firstDay
+		if lastDay+1 >= window {
+			windowStart = lastDay + 1 - window
+		}
+		percentiles, err := db.GetValidatorAprPercentiles(windowStart, lastDay)
+		if err != nil {
+			return nil, err
+		}
+		data.AprPercentiles[window] = percentiles
+
+		apr, err := db.GetValidatorBenchmarkApr(db.BenchmarkSelector{}, windowStart, lastDay)
+		if err != nil {
+			return nil, err
+		}
+		data.Apr[window] = apr
+		ethStoreApr, err := db.GetEthStoreRangeApr(windowStart, lastDay)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving ETH.STORE apr of days %v to %v: %w", windowStart, lastDay, err)
+		}
+		data.EthStoreApr[window] = ethStoreApr
+	}
+
+	logger.WithFields(logrus.Fields{"day": lastDay, "duration": time.Since(start)}).Infof("updated validator benchmark")
+	return data, nil
+}
+
+// LatestValidatorBenchmark returns the latest daily network performance and apr distribution that validator sets are benchmarked against
+func LatestValidatorBenchmark() *types.ValidatorBenchmarkData {
+	wanted := &types.ValidatorBenchmarkData{}
+	cacheKey := fmt.Sprintf("%d:frontend:validatorBenchmark", utils.Config.Chain.Config.DepositChainID)
+	if wanted, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Second*60, wanted); err == nil {
+		return wanted.(*types.ValidatorBenchmarkData)
+	} else {
+		logger.Errorf("error retrieving validator benchmark data from cache: %v", err)
+	}
+	return &types.ValidatorBenchmarkData{}
+}
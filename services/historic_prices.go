@@ -1,14 +1,15 @@
 package services
 
 import (
-	"encoding/json"
+	"errors"
 	"eth2-exporter/db"
 	"eth2-exporter/metrics"
-	"eth2-exporter/types"
+	"eth2-exporter/price"
 	"eth2-exporter/utils"
 	"fmt"
-	"net/http"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 func StartHistoricPriceService() {
@@ -18,52 +19,91 @@ func StartHistoricPriceService() {
 	}
 }
 
+// StartHourlyPriceService backfills the hourly prices, it runs separately from the historic price service so the backfill does not delay the daily prices
+func StartHourlyPriceService() {
+	attempts := make(map[string]time.Time)
+	for {
+		err := updateHourlyPrices(attempts)
+		if err != nil {
+			utils.LogError(err, "error updating hourly prices", 0)
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
+// WriteHistoricPricesForDay stores the prices of the configured price sources at the start of the day of ts
 func WriteHistoricPricesForDay(ts time.Time) error {
 	tsFormatted := ts.Format("01-02-2006")
 
-	historicPrice, err := fetchHistoricPrice(ts)
+	prices, err := price.GetHistoricPrices(ts)
 	if err != nil {
 		return fmt.Errorf("error retrieving historic eth prices for %v: %w", tsFormatted, err)
 	}
 
-	if historicPrice.MarketData.CurrentPrice.Eth == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Eur == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Usd == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Rub == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Cny == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Cad == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Jpy == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Gbp == 0.0 ||
-		historicPrice.MarketData.CurrentPrice.Aud == 0.0 {
-		return fmt.Errorf("incomplete historic eth prices for %v", tsFormatted)
+	currencies := make(pq.StringArray, 0, len(prices))
+	values := make(pq.Float64Array, 0, len(prices))
+	for _, currency := range price.GetCurrencies() {
+		value, ok := prices[currency]
+		if !ok {
+			logger.Warnf("missing historic %v price for %v", currency, tsFormatted)
+			continue
+		}
+		currencies = append(currencies, currency)
+		values = append(values, value)
 	}
 
 	_, err = db.WriterDb.Exec(`
-		INSERT INTO price (ts, eur, usd, rub, cny, cad, jpy, gbp, aud)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		ON CONFLICT (ts) DO UPDATE SET
-			eur = excluded.eur,
-			usd = excluded.usd,
-			rub = excluded.rub,
-			cny = excluded.cny,
-			cad = excluded.cad,
-			jpy = excluded.jpy,
-			gbp = excluded.gbp,
-			aud = excluded.aud`,
-		ts,
-		historicPrice.MarketData.CurrentPrice.Eur,
-		historicPrice.MarketData.CurrentPrice.Usd,
-		historicPrice.MarketData.CurrentPrice.Rub,
-		historicPrice.MarketData.CurrentPrice.Cny,
-		historicPrice.MarketData.CurrentPrice.Cad,
-		historicPrice.MarketData.CurrentPrice.Jpy,
-		historicPrice.MarketData.CurrentPrice.Gbp,
-		historicPrice.MarketData.CurrentPrice.Aud,
-	)
-
+		INSERT INTO historic_prices (ts, currency, price)
+		SELECT $1, currency, price
+		FROM UNNEST($2::text[], $3::numeric[]) AS t(currency, price)
+		ON CONFLICT (ts, currency) DO UPDATE SET
+			price = excluded.price`,
+		ts, currencies, values)
 	if err != nil {
 		return fmt.Errorf("error saving historic eth prices for %v: %w", tsFormatted, err)
 	}
+	if len(currencies) < len(price.GetCurrencies()) {
+		return fmt.Errorf("incomplete historic eth prices for %v", tsFormatted)
+	}
+	return nil
+}
+
+// WriteHourlyPricesForDay stores the prices of the configured price sources at the start of every hour of the day of ts
+func WriteHourlyPricesForDay(ts time.Time) error {
+	tsFormatted := ts.Format("01-02-2006")
+
+	prices, err := price.GetHourlyPrices(ts)
+	if err != nil {
+		return fmt.Errorf("error retrieving hourly eth prices for %v: %w", tsFormatted, err)
+	}
+	if len(prices) == 0 {
+		return fmt.Errorf("no hourly eth prices for %v", tsFormatted)
+	}
+
+	hours := make(pq.StringArray, 0, len(prices)*len(price.GetCurrencies()))
+	currencies := make(pq.StringArray, 0, cap(hours))
+	values := make(pq.Float64Array, 0, cap(hours))
+	for hour, hourPrices := range prices {
+		for currency, value := range hourPrices {
+			hours = append(hours, hour.UTC().Format("2006-01-02 15:04:05"))
+			currencies = append(currencies, currency)
+			values = append(values, value)
+		}
+	}
+
+	_, err = db.WriterDb.Exec(`
+		INSERT INTO historic_prices (ts, currency, price)
+		SELECT ts, currency, price
+		FROM UNNEST($1::timestamp[], $2::text[], $3::numeric[]) AS t(ts, currency, price)
+		ON CONFLICT (ts, currency) DO UPDATE SET
+			price = excluded.price`,
+		hours, currencies, values)
+	if err != nil {
+		return fmt.Errorf("error saving hourly eth prices for %v: %w", tsFormatted, err)
+	}
+	if len(values) < 24*len(price.GetCurrencies()) {
+		return fmt.Errorf("incomplete hourly eth prices for %v", tsFormatted)
+	}
 	return nil
 }
 
@@ -74,7 +114,8 @@ func updateHistoricPrices() error {
 	}()
 	var dates []time.Time
 
-	err := db.WriterDb.Select(&dates, "SELECT ts FROM price")
+	// days that miss the price of a configured currency at the start of the day are fetched again
+	err := db.WriterDb.Select(&dates, "SELECT ts FROM historic_prices WHERE currency = ANY($1) AND ts = DATE_TRUNC('day', ts) GROUP BY ts HAVING COUNT(*) = $2", pq.StringArray(price.GetCurrencies()), len(price.GetCurrencies()))
 
 	if err != nil {
 		return err
@@ -100,25 +141,71 @@ func updateHistoricPrices() error {
 		}
 		currentDay = currentDay.Add(utils.Day)
 	}
+
 	return nil
 }
 
-func fetchHistoricPrice(ts time.Time) (*types.HistoricEthPrice, error) {
-	logger.Infof("fetching historic prices for day %v", ts)
-	client := &http.Client{Timeout: time.Second * 10}
-
-	chain := "ethereum"
+// updateHourlyPrices backfills the hourly prices of all complete days within the window in which the price sources serve hourly prices.
+// Days that stay incomplete are only attempted again a day after their last attempt, attempts maps the days to the time of their last attempt.
+func updateHourlyPrices(attempts map[string]time.Time) error {
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("service_hourly_prices").Observe(time.Since(start).Seconds())
+	}()
 
-	if utils.Config.Chain.Name == "gnosis" {
-		chain = "gnosis"
+	window := price.GetHourlyPriceWindow()
+	if window == 0 {
+		logger.Infof("no price source supports hourly prices, skipping the hourly price backfill")
+		return nil
 	}
-	resp, err := client.Get(fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s/history?date=%s", chain, ts.Truncate(time.Hour*24).Format("02-01-2006")))
 
+	var days []time.Time
+	// days that miss the price of an hour of a configured currency are fetched again, the price at the start of the day is stored by WriteHistoricPricesForDay
+	err := db.WriterDb.Select(&days, `
+		SELECT DATE_TRUNC('day', ts)
+		FROM historic_prices
+		WHERE currency = ANY($1)
+		GROUP BY 1
+		HAVING COUNT(*) = 24 * $2`, pq.StringArray(price.GetCurrencies()), len(price.GetCurrencies()))
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	daysMap := make(map[string]bool)
+	for _, day := range days {
+		daysMap[day.Format("01-02-2006")] = true
 	}
 
-	priceData := &types.HistoricEthPrice{}
-	err = json.NewDecoder(resp.Body).Decode(&priceData)
-	return priceData, err
+	today := time.Now().UTC().Truncate(utils.Day)
+	// the first day of the window is skipped as its first hours are already outside of the window
+	currentDay := time.Now().UTC().Add(-window).Truncate(utils.Day).Add(utils.Day)
+	if genesisDay := time.Unix(int64(utils.Config.Chain.GenesisTimestamp), 0).UTC().Truncate(utils.Day); currentDay.Before(genesisDay) {
+		currentDay = genesisDay
+	}
+	for currentDay.Before(today) {
+		key := currentDay.Format("01-02-2006")
+		if !daysMap[key] && time.Since(attempts[key]) > utils.Day {
+			attempts[key] = time.Now()
+			err = WriteHourlyPricesForDay(currentDay)
+			if errors.Is(err, price.ErrHistoricPricesNotSupported) {
+				logger.Infof("no price source supports hourly prices, skipping the hourly price backfill")
+				return nil
+			}
+			if err != nil {
+				utils.LogError(err, "error writing hourly prices", 0)
+			}
+
+			// Wait to not overload the API, hourly prices are requested per currency
+			time.Sleep(5 * time.Second * time.Duration(len(price.GetCurrencies())))
+		}
+		currentDay = currentDay.Add(utils.Day)
+	}
+
+	// days that left the window are never attempted again
+	for key := range attempts {
+		if day, err := time.Parse("01-02-2006", key); err == nil && day.Before(today.Add(-window)) {
+			delete(attempts, key)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// how often the last seen timestamp of all pending transactions is refreshed in the db
+const mempoolTrackerPersistInterval = time.Minute
+
+// how long resolved (included, replaced or dropped) transactions are kept in the db
+const mempoolTrackerRetention = time.Hour * 24 * 7
+
+// mempoolTracker records the lifecycle of every transaction seen in the mempool snapshots
+// polled by the mempoolUpdater: when it was first seen and whether it was included, replaced or dropped
+type mempoolTracker struct {
+	client      *ethclient.Client
+	tracked     map[common.Hash]*types.MempoolTxLifecycle
+	initialized bool
+	lastPersist time.Time
+	lastPrune   time.Time
+}
+
+type mempoolSenderNonce struct {
+	sender common.Address
+	nonce  uint64
+}
+
+func newMempoolTracker(client *ethclient.Client) *mempoolTracker {
+	return &mempoolTracker{
+		client:  client,
+		tracked: make(map[common.Hash]*types.MempoolTxLifecycle),
+	}
+}
+
+// run updates the tracker with every mempool snapshot it receives until the channel is closed
+func (t *mempoolTracker) run(snapshots <-chan *types.RawMempoolResponse) {
+	for mempool := range snapshots {
+		err := t.update(mempool)
+		if err != nil {
+			logger.Errorf("error tracking mempool transactions: %v", err)
+		}
+	}
+}
+
+// update compares the given mempool snapshot with the previously tracked transactions
+// and persists newly seen as well as resolved transactions
+func (t *mempoolTracker) update(mempool *types.RawMempoolResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	now := time.Now()
+
+	if !t.initialized {
+		// resume tracking of transactions that were still pending when the tracker was last stopped
+		pending, err := db.GetPendingMempoolTransactions()
+		if err != nil {
+			return fmt.Errorf("error retrieving pending mempool transactions: %w", err)
+		}
+		for _, l := range pending {
+			t.tracked[common.BytesToHash(l.Hash)] = l
+		}
+		t.initialized = true
+		logger.Infof("resumed tracking of %v pending mempool transactions", len(pending))
+	}
+
+	head, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving head block number: %w", err)
+	}
+
+	persistAll := now.Sub(t.lastPersist) > mempoolTrackerPersistInterval
+	toSave := make([]*types.MempoolTxLifecycle, 0)
+	current := make(map[mempoolSenderNonce]common.Hash, len(mempool.TxsByHash))
+
+	for hash, tx := range mempool.TxsByHash {
+		if tx.From == nil || tx.Nonce == nil {
+			continue
+		}
+		current[mempoolSenderNonce{sender: *tx.From, nonce: tx.Nonce.ToInt().Uint64()}] = hash
+
+		l, known := t.tracked[hash]
+		if !known {
+			l = &types.MempoolTxLifecycle{
+				Hash:           hash.Bytes(),
+				Sender:         tx.From.Bytes(),
+				Nonce:          tx.Nonce.ToInt().Uint64(),
+				Status:         types.MempoolTxStatusPending,
+				FirstSeen:      now,
+				FirstSeenBlock: head,
+			}
+			t.tracked[hash] = l
+		}
+		l.LastSeen = now
+		l.GasPrice = mempoolFeeToDecimal(tx.GasPrice)
+		l.GasFeeCap = mempoolFeeToDecimal(tx.GasFeeCap)
+		l.GasTipCap = mempoolFeeToDecimal(tx.GasTipCap)
+
+		if !known || persistAll {
+			toSave = append(toSave, l)
+		}
+	}
+
+	err = db.SaveMempoolTransactions(toSave)
+	if err != nil {
+		return fmt.Errorf("error saving mempool transactions: %w", err)
+	}
+	if persistAll {
+		t.lastPersist = now
+	}
+
+	// first resolve all transactions that left the mempool because they were included in a block
+	gone := make([]common.Hash, 0)
+	included := make(map[mempoolSenderNonce]common.Hash)
+	blockTimes := make(map[uint64]time.Time)
+	for hash, l := range t.tracked {
+		if _, ok := mempool.TxsByHash[hash]; ok {
+			continue
+		}
+
+		receipt, err := t.client.TransactionReceipt(ctx, hash)
+		if err != nil {
+			if errors.Is(err, ethereum.NotFound) {
+				gone = append(gone, hash)
+				continue
+			}
+			return fmt.Errorf("error retrieving receipt of tx %v: %w", hash, err)
+		}
+
+		blockNumber := receipt.BlockNumber.Uint64()
+		blockTime, ok := blockTimes[blockNumber]
+		if !ok {
+			header, err := t.client.HeaderByNumber(ctx, receipt.BlockNumber)
+			if err != nil {
+				return fmt.Errorf("error retrieving header of block %v: %w", blockNumber, err)
+			}
+			blockTime = time.Unix(int64(header.Time), 0)
+			blockTimes[blockNumber] = blockTime
+		}
+
+		err = db.MarkMempoolTransactionIncluded(hash.Bytes(), blockNumber, blockTime)
+		if err != nil {
+			return fmt.Errorf("error marking mempool tx %v as included: %w", hash, err)
+		}
+		included[mempoolSenderNonce{sender: common.BytesToAddress(l.Sender), nonce: l.Nonce}] = hash
+		delete(t.tracked, hash)
+	}
+
+	// the remaining transactions were either replaced by a tx with the same nonce or dropped from the pool
+	accountNonces := make(map[common.Address]uint64)
+	for _, hash := range gone {
+		l := t.tracked[hash]
+		key := mempoolSenderNonce{sender: common.BytesToAddress(l.Sender), nonce: l.Nonce}
+
+		replacement, replaced := current[key]
+		if !replaced {
+			replacement, replaced = included[key]
+		}
+		if replaced {
+			err = db.MarkMempoolTransactionReplaced(hash.Bytes(), replacement.Bytes(), now)
+		} else {
+			accountNonce, ok := accountNonces[key.sender]
+			if !ok {
+				accountNonce, err = t.client.NonceAt(ctx, key.sender, nil)
+				if err != nil {
+					return fmt.Errorf("error retrieving nonce of account %v: %w", key.sender, err)
+				}
+				accountNonces[key.sender] = accountNonce
+			}
+
+			if accountNonce > l.Nonce {
+				// the nonce has been used by a transaction we have never seen in the mempool
+				err = db.MarkMempoolTransactionReplaced(hash.Bytes(), nil, now)
+			} else {
+				err = db.MarkMempoolTransactionDropped(hash.Bytes(), now)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("error resolving mempool tx %v: %w", hash, err)
+		}
+		delete(t.tracked, hash)
+	}
+
+	if now.Sub(t.lastPrune) > time.Hour {
+		deleted, err := db.DeleteMempoolTransactionsBefore(now.Add(-mempoolTrackerRetention))
+		if err != nil {
+			return fmt.Errorf("error pruning mempool transactions: %w", err)
+		}
+		t.lastPrune = now
+		logger.WithFields(logrus.Fields{"deleted": deleted}).Infof("pruned resolved mempool transactions")
+	}
+
+	return nil
+}
+
+func mempoolFeeToDecimal(fee *hexutil.Big) decimal.Decimal {
+	if fee == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(fee.ToInt(), 0)
+}
@@ -37,6 +37,33 @@ import (
 	"golang.org/x/text/language"
 )
 
+// upcomingProposalNotificationCollector collects & queues the notifications of upcoming block proposals once the head enters a new epoch.
+// It runs independently of the finalized epochs of the notificationCollector so that upcoming proposals are also notified during non-finality.
+func upcomingProposalNotificationCollector() {
+	lastCollectedEpoch := uint64(0)
+	for ; ; time.Sleep(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot)) {
+		headSlot := LatestSlot()
+		headEpoch := utils.EpochOfSlot(headSlot)
+		if headSlot == 0 || headEpoch == lastCollectedEpoch {
+			continue
+		}
+
+		start := time.Now()
+		notificationsByUserID := map[uint64]map[types.EventName][]types.Notification{}
+		err := collectUpcomingProposalNotifications(notificationsByUserID, types.ValidatorUpcomingProposalEventName, headSlot)
+		if err != nil {
+			metrics.Errors.WithLabelValues("notifications_collect_upcoming_block_proposal").Inc()
+			logger.Errorf("error collecting validator_proposal_upcoming notifications: %v", err)
+			continue
+		}
+		queueNotifications(notificationsByUserID, db.FrontendWriterDB)
+		lastCollectedEpoch = headEpoch
+
+		logger.WithField("epoch", headEpoch).WithField("duration", time.Since(start)).Info("upcoming proposal notifications completed")
+		metrics.TaskDuration.WithLabelValues("service_notifications_upcoming_proposals").Observe(time.Since(start).Seconds())
+	}
+}
+
 // the notificationCollector is responsible for collecting & queuing notifications
 // it is epoch based and will only collect notification for a given epoch once
 // notifications are collected in ascending epoch order
@@ -90,6 +117,12 @@ func notificationCollector() {
 			start := time.Now()
 			logger.Infof("collecting notifications for epoch %v", epoch)
 
+			// re-evaluate the rule based validator sets so that new members are subscribed before the notifications are collected
+			err = db.UpdateValidatorSets(epoch)
+			if err != nil {
+				logger.Errorf("error updating validator sets: %v", err)
+			}
+
 			// Network DB Notifications (network related)
 			notifications, err := collectNotifications(epoch)
 
@@ -325,6 +358,13 @@ func collectNotifications(epoch uint64) (map[uint64]map[types.EventName][]types.
 	}
 	logger.Infof("collecting sync committee took: %v", time.Since(start))
 
+	err = collectEnsExpiryNotifications(notificationsByUserID, types.EnsNameExpiringEventName, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_ens_name_expiring").Inc()
+		return nil, fmt.Errorf("error collecting ens name expiring notifications: %v", err)
+	}
+	logger.Infof("collecting ens name expiring notifications took: %v", time.Since(start))
+
 	return notificationsByUserID, nil
 }
 
@@ -415,18 +455,20 @@ func queueNotifications(notificationsByUserID map[uint64]map[types.EventName][]t
 		logger.WithError(err).Error("error queuing webhook notifications")
 	}
 
+	// a subscription is marked with the latest epoch it was queued for
+	lastEpochBySub := map[uint64]uint64{}
 	for _, events := range notificationsByUserID {
 		for _, notifications := range events {
 			for _, n := range notifications {
-				e := n.GetEpoch()
-				if _, exists := subByEpoch[e]; !exists {
-					subByEpoch[e] = []uint64{n.GetSubscriptionID()}
-				} else {
-					subByEpoch[e] = append(subByEpoch[e], n.GetSubscriptionID())
+				if e, exists := lastEpochBySub[n.GetSubscriptionID()]; !exists || n.GetEpoch() > e {
+					lastEpochBySub[n.GetSubscriptionID()] = n.GetEpoch()
 				}
 			}
 		}
 	}
+	for subID, e := range lastEpochBySub {
+		subByEpoch[e] = append(subByEpoch[e], subID)
+	}
 	for epoch, subIDs := range subByEpoch {
 		// update that we've queued the subscription (last sent rather means last queued)
 		err := db.UpdateSubscriptionsLastSent(subIDs, time.Now(), epoch, useDB)
@@ -1273,11 +1315,74 @@ func collectBlockProposalNotifications(notificationsByUserID map[uint64]map[type
 	return nil
 }
 
+// collectUpcomingProposalNotifications notifies the subscribers of validators that are scheduled to propose a block
+// after headSlot in the remainder of the head epoch or in the next epoch, at most once per epoch and subscription
+func collectUpcomingProposalNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, eventName types.EventName, headSlot uint64) error {
+	_, subMap, err := db.GetSubsForEventFilter(eventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for upcoming proposals %w", err)
+	}
+	if len(subMap) == 0 {
+		return nil
+	}
+
+	lastEpoch := utils.EpochOfSlot(headSlot) + 1
+	proposals, err := db.GetAllScheduledProposals(headSlot + 1)
+	if err != nil {
+		return fmt.Errorf("error retrieving scheduled proposals from slot %v: %w", headSlot+1, err)
+	}
+
+	for _, proposal := range proposals {
+		epoch := utils.EpochOfSlot(proposal.Slot)
+		if epoch > lastEpoch {
+			continue
+		}
+		pubkey, err := GetGetPubkeyForIndex(proposal.Proposer)
+		if err != nil {
+			logger.Errorf("error retrieving pubkey for validator %v: %v", proposal.Proposer, err)
+			continue
+		}
+		subscribers, ok := subMap[hex.EncodeToString(pubkey)]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId or subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil && *sub.LastEpoch >= epoch {
+				continue
+			}
+			logger.Infof("creating %v notification for validator %v in slot %v", eventName, proposal.Proposer, proposal.Slot)
+			n := &validatorProposalNotification{
+				SubscriptionID: *sub.ID,
+				ValidatorIndex: proposal.Proposer,
+				Epoch:          epoch,
+				Slot:           proposal.Slot,
+				Status:         0,
+				EventName:      eventName,
+				EventFilter:    hex.EncodeToString(pubkey),
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
 type validatorProposalNotification struct {
 	SubscriptionID     uint64
 	ValidatorIndex     uint64
 	ValidatorPublicKey string
 	Epoch              uint64
+	Slot               uint64
 	Status             uint64 // * Can be 0 = scheduled, 1 executed, 2 missed */
 	EventName          types.EventName
 	EventFilter        string
@@ -1317,6 +1422,9 @@ func (n *validatorProposalNotification) GetInfo(includeUrl bool) string {
 	switch n.Status {
 	case 0:
 		generalPart = fmt.Sprintf(`New scheduled block proposal for Validator %v.`, n.ValidatorIndex)
+		if n.Slot != 0 {
+			generalPart = fmt.Sprintf(`Validator %v is scheduled to propose the block of slot %v in %s.`, n.ValidatorIndex, n.Slot, time.Until(utils.SlotToTime(n.Slot)).Round(time.Second))
+		}
 	case 1:
 		generalPart = fmt.Sprintf(`Validator %v proposed a new block with %v ETH execution reward.`, n.ValidatorIndex, n.Reward)
 	case 2:
@@ -1350,6 +1458,9 @@ func (n *validatorProposalNotification) GetInfoMarkdown() string {
 	switch n.Status {
 	case 0:
 		generalPart = fmt.Sprintf(`New scheduled block proposal for Validator [%[1]v](https://%[2]v/%[1]v).`, n.ValidatorIndex, utils.Config.Frontend.SiteDomain+"/validator")
+		if n.Slot != 0 {
+			generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[2]v/%[1]v) is scheduled to propose the block of slot [%[3]v](https://%[4]v/%[3]v) in %[5]s.`, n.ValidatorIndex, utils.Config.Frontend.SiteDomain+"/validator", n.Slot, utils.Config.Frontend.SiteDomain+"/slot", time.Until(utils.SlotToTime(n.Slot)).Round(time.Second))
+		}
 	case 1:
 		generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[2]v/%[1]v) proposed a new block with %[3]v ETH execution reward.`, n.ValidatorIndex, utils.Config.Frontend.SiteDomain+"/validator", n.Reward)
 	case 2:
@@ -2532,7 +2643,19 @@ func (n *taxReportNotification) GetEmailAttachment() *types.EmailAttachment {
 
 	validators := []uint64{}
 	valSlice := strings.Split(q.Get("validators"), ",")
-	if len(valSlice) > 0 {
+	if q.Get("set") != "" {
+		// the report covers the members of a rule based validator set at the time the report is sent
+		setID, err := strconv.ParseUint(q.Get("set"), 10, 64)
+		if err != nil {
+			logger.Warn("Failed to parse validator set of rewards report eventfilter")
+			return nil
+		}
+		validators, err = db.GetValidatorSetValidators(setID)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to get validators of validator set %v for rewards report", setID)
+			return nil
+		}
+	} else if len(valSlice) > 0 {
 		for _, val := range valSlice {
 			v, err := strconv.ParseUint(val, 10, 64)
 			if err != nil {
@@ -3149,6 +3272,152 @@ func collectSyncCommittee(notificationsByUserID map[uint64]map[types.EventName][
 	return nil
 }
 
+// ensExpiryNotificationPeriod is how long before their expiry subscribers are notified about expiring ens names
+const ensExpiryNotificationPeriod = time.Hour * 24 * 30
+
+type ensExpiryNotification struct {
+	SubscriptionID  uint64
+	UserID          uint64
+	Epoch           uint64
+	EventFilter     string
+	Names           []*types.EnsRecords
+	UnsubscribeHash sql.NullString
+}
+
+func (n *ensExpiryNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *ensExpiryNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *ensExpiryNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *ensExpiryNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *ensExpiryNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *ensExpiryNotification) GetEventName() types.EventName {
+	return types.EnsNameExpiringEventName
+}
+
+func (n *ensExpiryNotification) GetInfo(includeUrl bool) string {
+	infos := make([]string, 0, len(n.Names))
+	for _, name := range n.Names {
+		info := ""
+		if name.Status == types.EnsNameStatusGracePeriod {
+			info = fmt.Sprintf(`The ENS name %v expired on %v and can be renewed until %v.`, name.Name, name.ValidTo.Format("2006-01-02"), name.ValidTo.Add(utils.EnsGracePeriod).Format("2006-01-02"))
+		} else {
+			info = fmt.Sprintf(`The ENS name %v expires on %v.`, name.Name, name.ValidTo.Format("2006-01-02"))
+		}
+		if includeUrl {
+			info += fmt.Sprintf(` https://%s/ens/%s`, utils.Config.Frontend.SiteDomain, name.Name)
+		}
+		infos = append(infos, info)
+	}
+	return strings.Join(infos, "\n")
+}
+
+func (n *ensExpiryNotification) GetTitle() string {
+	return "ENS Name Expiring"
+}
+
+func (n *ensExpiryNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *ensExpiryNotification) GetInfoMarkdown() string {
+	infos := make([]string, 0, len(n.Names))
+	for _, name := range n.Names {
+		link := fmt.Sprintf(`[%v](https://%s/ens/%s)`, name.Name, utils.Config.Frontend.SiteDomain, name.Name)
+		if name.Status == types.EnsNameStatusGracePeriod {
+			infos = append(infos, fmt.Sprintf(`The ENS name %v expired on %v and can be renewed until %v.`, link, name.ValidTo.Format("2006-01-02"), name.ValidTo.Add(utils.EnsGracePeriod).Format("2006-01-02")))
+		} else {
+			infos = append(infos, fmt.Sprintf(`The ENS name %v expires on %v.`, link, name.ValidTo.Format("2006-01-02")))
+		}
+	}
+	return strings.Join(infos, "\n")
+}
+
+// collectEnsExpiryNotifications notifies the subscribers of an address about the ens names registered to the address that expire soon or are within their grace period,
+// the notification is repeated weekly until the names are renewed
+func collectEnsExpiryNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, eventName types.EventName, epoch uint64) error {
+	var dbResult []struct {
+		SubscriptionID  uint64         `db:"id"`
+		UserID          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+
+	err := db.FrontendWriterDB.Select(&dbResult, `
+		SELECT us.id, us.user_id, us.event_filter, ENCODE(us.unsubscribe_hash, 'hex') as unsubscribe_hash
+		FROM users_subscriptions AS us
+		WHERE us.event_name=$1 AND (us.last_sent_ts <= NOW() - INTERVAL '7 days' OR us.last_sent_ts IS NULL);
+		`,
+		utils.GetNetwork()+":"+string(eventName))
+	if err != nil {
+		return err
+	}
+	if len(dbResult) == 0 {
+		return nil
+	}
+
+	owners := make([][]byte, 0, len(dbResult))
+	for _, r := range dbResult {
+		owner, err := hex.DecodeString(strings.TrimPrefix(r.EventFilter, "0x"))
+		if err != nil || len(owner) != 20 {
+			logger.Warnf("invalid event filter %v of ens expiry subscription %v", r.EventFilter, r.SubscriptionID)
+			continue
+		}
+		owners = append(owners, owner)
+	}
+
+	names, err := db.GetExpiringEnsNames(owners, time.Now().Add(ensExpiryNotificationPeriod))
+	if err != nil {
+		return err
+	}
+	namesByOwner := make(map[string][]*types.EnsRecords)
+	for _, name := range names {
+		owner := fmt.Sprintf("%x", name.Owner)
+		namesByOwner[owner] = append(namesByOwner[owner], name)
+	}
+
+	for _, r := range dbResult {
+		ownerNames := namesByOwner[strings.ToLower(strings.TrimPrefix(r.EventFilter, "0x"))]
+		if len(ownerNames) == 0 {
+			continue
+		}
+		n := &ensExpiryNotification{
+			SubscriptionID:  r.SubscriptionID,
+			UserID:          r.UserID,
+			Epoch:           epoch,
+			EventFilter:     r.EventFilter,
+			Names:           ownerNames,
+			UnsubscribeHash: r.UnsubscribeHash,
+		}
+		if _, exists := notificationsByUserID[r.UserID]; !exists {
+			notificationsByUserID[r.UserID] = map[types.EventName][]types.Notification{}
+		}
+		if _, exists := notificationsByUserID[r.UserID][n.GetEventName()]; !exists {
+			notificationsByUserID[r.UserID][n.GetEventName()] = []types.Notification{}
+		}
+		notificationsByUserID[r.UserID][n.GetEventName()] = append(notificationsByUserID[r.UserID][n.GetEventName()], n)
+		metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+	}
+
+	return nil
+}
+
 type WebhookQueue struct {
 	NotificationID uint64         `db:"id"`
 	Url            string         `db:"url"`
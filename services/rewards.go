@@ -26,13 +26,14 @@ type rewardHistory struct {
 func GetValidatorHist(validatorArr []uint64, currency string, start uint64, end uint64) rewardHistory {
 	var err error
 
-	var pricesDb []types.Price
-	// we get prices with a 1 day buffer to so we have no problems in different time zones
-	var oneDay = 24 * 60 * 60
-	err = db.WriterDb.Select(&pricesDb,
-		`select ts, eur, usd, gbp, cad, jpy, cny, rub, aud from price where ts >= TO_TIMESTAMP($1) and ts <= TO_TIMESTAMP($2) order by ts desc`, start-uint64(oneDay), end+uint64(oneDay))
+	currency = strings.ToUpper(currency)
+	supported := false
+	err = db.WriterDb.Get(&supported, `select exists (select 1 from historic_prices where currency = $1)`, currency)
 	if err != nil {
-		logger.Errorf("error getting prices: %v", err)
+		logger.Errorf("error checking price currency %v: %v", currency, err)
+	}
+	if !supported {
+		currency = "USD"
 	}
 
 	lowerBound := utils.TimeToDay(start)
@@ -44,38 +45,11 @@ func GetValidatorHist(validatorArr []uint64, currency string, start uint64, end
 		lowerBound++
 	}
 
-	income, err := db.GetValidatorIncomeHistory(validatorArr, lowerBound, upperBound, LatestFinalizedEpoch())
+	income, err := db.GetValidatorIncomeHistory(validatorArr, lowerBound, upperBound, LatestFinalizedEpoch(), currency)
 	if err != nil {
 		logger.Errorf("error getting income history for validator hist: %v", err)
 	}
 
-	prices := map[string]float64{}
-	for _, item := range pricesDb {
-		date := fmt.Sprintf("%v", item.TS)
-		date = strings.Split(date, " ")[0]
-		switch currency {
-		case "eur":
-			prices[date] = item.EUR
-		case "usd":
-			prices[date] = item.USD
-		case "gbp":
-			prices[date] = item.GBP
-		case "cad":
-			prices[date] = item.CAD
-		case "cny":
-			prices[date] = item.CNY
-		case "jpy":
-			prices[date] = item.JPY
-		case "rub":
-			prices[date] = item.RUB
-		case "aud":
-			prices[date] = item.AUD
-		default:
-			prices[date] = item.USD
-			currency = "usd"
-		}
-	}
-
 	data := make([][]string, len(income))
 	tETH := 0.0
 	tCur := 0.0
@@ -83,16 +57,21 @@ func GetValidatorHist(validatorArr []uint64, currency string, start uint64, end
 	for i, item := range income {
 		key := fmt.Sprintf("%v", utils.DayToTime(item.Day))
 		key = strings.Split(key, " ")[0]
+		// the income of the day only contains the consensus rewards, the execution rewards of proposed blocks are listed separately
+		// and are valued at the price of the hour of the block
 		iETH := float64(item.ClRewards) / 1e9
-		tETH += iETH
-		iCur := iETH * prices[key]
-		tCur += iCur
+		elETH := float64(item.ElRewards) / 1e9
+		tETH += iETH + elETH
+		iCur := item.ClRewardsValue
+		tCur += iCur + item.ElRewardsValue
 		data[i] = []string{
 			key,
-			addCommas(float64(item.EndBalance.Int64)/1e9, "%.5f"),                           // end of day balance
-			addCommas(iETH, "%.5f"),                                                         // income of day ETH
-			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(prices[key], "%.2f")), //price will default to 0 if key does not exist
-			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(iCur, "%.2f")),        // income of day Currency
+			addCommas(float64(item.EndBalance.Int64)/1e9, "%.5f"),                                   // end of day balance
+			addCommas(iETH, "%.5f"),                                                                 // income of day ETH
+			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(item.Price, "%.2f")),          // average price of the day, 0 if there is no price
+			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(iCur, "%.2f")),                // income of day Currency
+			addCommas(elETH, "%.5f"),                                                                // execution rewards of day ETH
+			fmt.Sprintf("%s %s", strings.ToUpper(currency), addCommas(item.ElRewardsValue, "%.2f")), // execution rewards of day Currency
 		}
 	}
 
@@ -159,8 +138,8 @@ func GeneratePdfReport(hist rewardHistory, currency string) []byte {
 
 	// generating the table
 	const (
-		colCount = 5
-		colWd    = 40.0
+		colCount = 7
+		colWd    = 28.0
 		marginH  = 5.0
 		lineHt   = 5.5
 		maxHt    = 5
@@ -171,7 +150,7 @@ func GeneratePdfReport(hist rewardHistory, currency string) []byte {
 	// pdf.Ln(-1)
 	pdf.CellFormat(0, maxHt, fmt.Sprintf("Income For Timeframe %s | %s", hist.TotalETH, hist.TotalCurrency), "", 0, "CM", true, 0, "")
 
-	header := [colCount]string{"Date", "Balance", "Income", "ETH Value", fmt.Sprintf("Income (%v)", currency)}
+	header := [colCount]string{"Date", "Balance", "Income", "ETH Value", fmt.Sprintf("Income (%v)", currency), "EL Rewards", fmt.Sprintf("EL Rewards (%v)", currency)}
 
 	// pdf.SetMargins(marginH, marginH, marginH)
 	pdf.Ln(10)
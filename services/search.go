@@ -0,0 +1,61 @@
+package services
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"sync"
+	"time"
+)
+
+// searchIndexUpdater periodically rebuilds all types of the search index
+func searchIndexUpdater(wg *sync.WaitGroup) {
+	firstRun := true
+
+	for {
+		err := updateSearchIndex()
+		if err != nil {
+			logger.Errorf("error updating search index: %v", err)
+		}
+		if firstRun {
+			firstRun = false
+			wg.Done()
+			logger.Info("initialized search index updater")
+		}
+		ReportStatus("searchIndexUpdater", "Running", nil)
+		time.Sleep(time.Hour)
+	}
+}
+
+// updateSearchIndex rebuilds every type of the search index, a failing type does not stop the other types from being rebuilt
+func updateSearchIndex() error {
+	var lastErr error
+	for _, resultType := range []types.SearchResultType{types.ValidatorNameSearchResult, types.GraffitiSearchResult, types.EnsSearchResult, types.PoolSearchResult} {
+		start := time.Now()
+		err := db.RebuildSearchIndex(resultType)
+		if err != nil {
+			logger.Errorf("error rebuilding search index %v: %v", resultType, err)
+			lastErr = err
+			continue
+		}
+		logger.Infof("rebuilt search index %v in %v", resultType, time.Since(start))
+	}
+
+	sources := map[types.SearchResultType]func() ([]*types.SearchIndexEntry, error){
+		types.AddressLabelSearchResult: db.GetCommunityAddressLabelSearchEntries,
+		types.TokenSearchResult:        db.GetTokenSearchEntries,
+	}
+	for resultType, source := range sources {
+		start := time.Now()
+		entries, err := source()
+		if err == nil {
+			err = db.ReplaceSearchIndex(resultType, entries)
+		}
+		if err != nil {
+			logger.Errorf("error rebuilding search index %v: %v", resultType, err)
+			lastErr = err
+			continue
+		}
+		logger.Infof("rebuilt search index %v with %v entries in %v", resultType, len(entries), time.Since(start))
+	}
+	return lastErr
+}
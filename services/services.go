@@ -25,6 +25,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	geth_types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	geth_rpc "github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -78,6 +79,15 @@ func Init() {
 	ready.Add(1)
 	go ethStoreStatisticsDataUpdater(ready)
 
+	ready.Add(1)
+	go withdrawalSweepUpdater(ready)
+
+	ready.Add(1)
+	go validatorBenchmarkUpdater(ready)
+
+	ready.Add(1)
+	go searchIndexUpdater(ready)
+
 	ready.Add(1)
 	go startMonitoringService(ready)
 
@@ -96,6 +106,7 @@ func InitNotificationCollector(pubkeyCachePath string) {
 	}
 
 	go notificationCollector()
+	go upcomingProposalNotificationCollector()
 }
 
 func getRelaysPageData() (*types.RelaysResp, error) {
@@ -1208,6 +1219,7 @@ func GetLatestStats() *types.Stats {
 		ActiveValidatorCount:           new(uint64),
 		PendingValidatorCount:          new(uint64),
 		ValidatorChurnLimit:            new(uint64),
+		ValidatorActivationChurnLimit:  new(uint64),
 		LatestValidatorWithdrawalIndex: new(uint64),
 	}
 }
@@ -1399,6 +1411,8 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 	errorCount := 0
 
 	var client *geth_rpc.Client
+	// the tracker persists the snapshots in its own goroutine so that it neither delays the mempool cache nor the startup
+	var trackerSnapshots chan *types.RawMempoolResponse
 
 	for {
 		var err error
@@ -1410,6 +1424,10 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 				time.Sleep(time.Second * 30)
 				continue
 			}
+			if utils.Config.Frontend.MempoolTracker.Enabled {
+				trackerSnapshots = make(chan *types.RawMempoolResponse, 1)
+				go newMempoolTracker(ethclient.NewClient(client)).run(trackerSnapshots)
+			}
 		}
 
 		var mempoolTx types.RawMempoolResponse
@@ -1468,6 +1486,13 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 			wg.Done()
 			firstRun = false
 		}
+		if trackerSnapshots != nil {
+			select {
+			case trackerSnapshots <- &mempoolTx:
+			default:
+				logger.Warnf("skipping mempool snapshot, the mempool tracker is still processing the previous one")
+			}
+		}
 		ReportStatus("mempoolUpdater", "Running", nil)
 		time.Sleep(time.Second * 5)
 	}
@@ -88,6 +88,15 @@ func calculateStats() (*types.Stats, error) {
 
 	stats.ValidatorChurnLimit = &validatorChurnLimit
 
+	validatorActivationChurnLimit := utils.GetValidatorActivationChurnLimit(activeValidatorCount, LatestEpoch())
+	stats.ValidatorActivationChurnLimit = &validatorActivationChurnLimit
+
+	churnQueueState, err := db.GetChurnQueueState(LatestEpoch())
+	if err != nil {
+		logger.WithError(err).Error("error getting churn queue state")
+	}
+	stats.ChurnQueueState = churnQueueState
+
 	LatestValidatorWithdrawalIndex, err := db.GetMostRecentWithdrawalValidator()
 	if err != nil {
 		logger.WithError(err).Error("error getting most recent withdrawal validator index")
@@ -198,18 +207,7 @@ func eth1UniqueValidatorsCount() (*uint64, error) {
 	return &count, nil
 }
 
-// GetValidatorChurnLimit returns the rate at which validators can enter or leave the system
+// GetValidatorChurnLimit returns the rate at which validators can leave the system, the activation churn is capped since deneb
 func getValidatorChurnLimit(validatorCount uint64) (uint64, error) {
-	min := utils.Config.Chain.Config.MinPerEpochChurnLimit
-
-	adaptable := uint64(0)
-	if validatorCount > 0 {
-		adaptable = validatorCount / utils.Config.Chain.Config.ChurnLimitQuotient
-	}
-
-	if min > adaptable {
-		return min, nil
-	}
-
-	return adaptable, nil
+	return utils.GetValidatorChurnLimit(validatorCount), nil
 }
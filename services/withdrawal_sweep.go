@@ -0,0 +1,151 @@
+package services
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var withdrawalPredictions atomic.Value // map[uint64]*types.PredictedWithdrawal
+
+// withdrawalSweepUpdater simulates the withdrawal sweep once per epoch to predict the next withdrawal of every validator
+func withdrawalSweepUpdater(wg *sync.WaitGroup) {
+	firstRun := true
+	lastEpoch := uint64(0)
+
+	for {
+		epoch := LatestEpoch()
+		if epoch < utils.Config.Chain.Config.CappellaForkEpoch || (!firstRun && epoch == lastEpoch) {
+			if firstRun {
+				firstRun = false
+				wg.Done()
+			}
+			time.Sleep(time.Second * 12)
+			continue
+		}
+
+		predictions, err := predictWithdrawals(epoch)
+		if err != nil {
+			logger.Errorf("error predicting withdrawals: %v", err)
+			time.Sleep(time.Second * 10)
+			continue
+		}
+		withdrawalPredictions.Store(predictions)
+		lastEpoch = epoch
+
+		if firstRun {
+			firstRun = false
+			wg.Done()
+			logger.Info("initialized withdrawal sweep updater")
+		}
+		ReportStatus("withdrawalSweepUpdater", "Running", nil)
+		time.Sleep(time.Second * 12)
+	}
+}
+
+// GetPredictedWithdrawal returns the predicted next withdrawal of a validator, it returns false if the validator is not expected to be withdrawn
+// during the next sweep or if no prediction is available yet
+func GetPredictedWithdrawal(validatorIndex uint64) (*types.PredictedWithdrawal, bool) {
+	predictions, ok := withdrawalPredictions.Load().(map[uint64]*types.PredictedWithdrawal)
+	if !ok {
+		return nil, false
+	}
+	p, ok := predictions[validatorIndex]
+	return p, ok
+}
+
+// WithdrawalPredictionsAvailable returns true once the first simulation of the withdrawal sweep has finished
+func WithdrawalPredictionsAvailable() bool {
+	_, ok := withdrawalPredictions.Load().(map[uint64]*types.PredictedWithdrawal)
+	return ok
+}
+
+func predictWithdrawals(epoch uint64) (map[uint64]*types.PredictedWithdrawal, error) {
+	start := time.Now()
+
+	validators, validatorCount, err := db.GetWithdrawalSweepValidators()
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := db.GetMostRecentWithdrawalValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	if validatorCount == 0 {
+		return map[uint64]*types.PredictedWithdrawal{}, nil
+	}
+
+	predictions := simulateWithdrawalSweep(validators, validatorCount, (cursor+1)%validatorCount, LatestSlot()+1)
+
+	logger.WithFields(logrus.Fields{"epoch": epoch, "validators": len(validators), "predictions": len(predictions), "duration": time.Since(start)}).Infof("predicted withdrawals")
+	return predictions, nil
+}
+
+// simulateWithdrawalSweep runs the withdrawal sweep of get_expected_withdrawals for one full cycle over all validators, starting at the
+// validator index nextValidatorIndex in slot startSlot. Validators must only contain validators with execution withdrawal credentials, sorted by index.
+// Every slot is expected to contain a payload and balances are assumed to stay constant, so the result is only an estimation.
+func simulateWithdrawalSweep(validators []*types.WithdrawalSweepValidator, validatorCount, nextValidatorIndex, startSlot uint64) map[uint64]*types.PredictedWithdrawal {
+	predictions := make(map[uint64]*types.PredictedWithdrawal)
+	if len(validators) == 0 || validatorCount == 0 {
+		return predictions
+	}
+
+	maxSweep := utils.Config.Chain.Config.MaxValidatorsPerWithdrawalSweep
+	if maxSweep > validatorCount {
+		maxSweep = validatorCount
+	}
+	maxWithdrawals := utils.Config.Chain.Config.MaxWithdrawalsPerPayload
+	maxEffectiveBalance := utils.Config.Chain.Config.MaxEffectiveBalance
+
+	slot := startSlot
+	// positions are counted relative to nextValidatorIndex, the sweep of the payload in slot covers [payloadStart, payloadStart+maxSweep)
+	payloadStart := uint64(0)
+	payloadWithdrawals := uint64(0)
+
+	first := sort.Search(len(validators), func(i int) bool { return validators[i].Index >= nextValidatorIndex })
+	for i := 0; i < len(validators); i++ {
+		v := validators[(first+i)%len(validators)]
+		pos := (v.Index + validatorCount - nextValidatorIndex) % validatorCount
+
+		if pos >= payloadStart+maxSweep {
+			// the validator is out of reach for the current payload, skip all payloads whose sweep ends before it
+			slot += (pos - payloadStart) / maxSweep
+			payloadStart += (pos - payloadStart) / maxSweep * maxSweep
+			payloadWithdrawals = 0
+		}
+
+		epoch := slot / utils.Config.Chain.Config.SlotsPerEpoch
+		p := &types.PredictedWithdrawal{ValidatorIndex: v.Index}
+		if v.WithdrawableEpoch <= epoch && v.Balance > 0 {
+			p.IsFull = true
+			p.Amount = v.Balance
+		} else if v.EffectiveBalance == maxEffectiveBalance && v.Balance > maxEffectiveBalance {
+			p.Amount = v.Balance - maxEffectiveBalance
+		} else {
+			continue
+		}
+		p.Slot = slot
+		p.Time = utils.SlotToTime(slot)
+		if len(v.WithdrawalCredentials) == 32 {
+			p.Address = v.WithdrawalCredentials[12:]
+		}
+		predictions[v.Index] = p
+
+		payloadWithdrawals++
+		if payloadWithdrawals == maxWithdrawals {
+			// a full payload moves the cursor right behind the last withdrawn validator
+			slot++
+			payloadStart = pos + 1
+			payloadWithdrawals = 0
+		}
+	}
+
+	return predictions
+}
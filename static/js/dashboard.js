@@ -1029,6 +1029,12 @@ $(document).ready(function () {
     //   alert(`You can not add more than ${VALLIMIT} validators to your dashboard`)
     //   return
     // }
+    if (typeof DASHBOARD_VALIDATORS !== "undefined") {
+      // the validators of a stored dashboard are provided by the server
+      state.validators = DASHBOARD_VALIDATORS.map((v) => v.toString())
+      state.validators.sort(sortValidators)
+      return
+    }
     var usp = new URLSearchParams(window.location.search)
     var validatorsStr = usp.get("validators")
     if (!validatorsStr) {
@@ -1180,12 +1186,15 @@ $(document).ready(function () {
       // alert(`Too many validators, you can not add more than ${VALLIMIT} validators to your dashboard!`)
       return
     }
-    localStorage.setItem("dashboard_validators", JSON.stringify(state.validators))
+    var storedDashboard = typeof DASHBOARD_VALIDATORS !== "undefined"
+    if (!storedDashboard) {
+      localStorage.setItem("dashboard_validators", JSON.stringify(state.validators))
+    }
     window.dispatchEvent(new CustomEvent("dashboard_validators_set"))
 
-    if (state.validators.length) {
+    var qryStr = "?validators=" + state.validators.join(",")
+    if (state.validators.length && !storedDashboard) {
       // console.log('length', state.validators)
-      var qryStr = "?validators=" + state.validators.join(",")
       var newUrl = window.location.pathname + qryStr
       window.history.replaceState(null, "Dashboard", newUrl)
     }
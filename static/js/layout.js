@@ -175,7 +175,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.pubkey
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/validators/%QUERY",
@@ -204,7 +204,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.slot
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/slots/%QUERY",
@@ -217,7 +217,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.block
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/blocks/%QUERY",
@@ -230,7 +230,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.txhash
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/transactions/%QUERY",
@@ -243,7 +243,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.graffiti
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/graffiti/%QUERY",
@@ -256,7 +256,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.epoch
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/epochs/%QUERY",
@@ -269,7 +269,7 @@ $(document).ready(function () {
     datumTokenizer: Bloodhound.tokenizers.whitespace,
     queryTokenizer: Bloodhound.tokenizers.whitespace,
     identify: function (obj) {
-      return obj.account
+      return obj.type + obj.key
     },
     remote: {
       url: "/search/eth1_addresses/%QUERY",
@@ -291,6 +291,12 @@ $(document).ready(function () {
     },
   })
 
+  // results of the search service have html-escaped names, the count is only shown for names of several validators, blocks or holders
+  function searchResultSuggestion(data) {
+    let count = data.count > 1 ? `<div style="max-width:fit-content;white-space:nowrap;">${data.count}</div>` : ""
+    return `<div class="text-monospace" style="display:flex"><div class="text-truncate" style="flex:1 1 auto;">${data.name}</div>${count}</div>`
+  }
+
   // before adding datasets make sure requestNum is set to the correct value
   $(".typeahead").typeahead(
     {
@@ -303,12 +309,10 @@ $(document).ready(function () {
       limit: 5,
       name: "validators",
       source: bhValidators,
-      display: "pubkey",
+      display: "name",
       templates: {
         header: '<h3 class="h5">Validators</h3>',
-        suggestion: function (data) {
-          return `<div class="text-monospace text-truncate">${data.index}: ${data.pubkey}</div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     },
     {
@@ -329,69 +333,50 @@ $(document).ready(function () {
       limit: 5,
       name: "blocks",
       source: bhBlocks,
-      display: "hash",
+      display: "name",
       templates: {
         header: '<h3 class="h5">Blocks</h3>',
-        suggestion: function (data) {
-          return `<div class="text-monospace text-truncate">${data.block}: ${data.hash}</div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     },
     {
       limit: 5,
       name: "slots",
       source: bhSlots,
-      display: "blockroot",
+      display: "name",
       templates: {
         header: '<h3 class="h5">Slots</h3>',
-        suggestion: function (data) {
-          return `<div class="text-monospace text-truncate">${data.slot}: 0x${data.blockroot}</div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     },
     {
       limit: 5,
       name: "transactions",
       source: bhTransactions,
-      display: "txhash",
+      display: "name",
       templates: {
         header: '<h3 class="h5">Transactions</h3>',
-        suggestion: function (data) {
-          return `<div class="text-monospace text-truncate">0x${data.txhash}</div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     },
     {
       limit: 5,
       name: "epochs",
       source: bhEpochs,
-      display: "epoch",
+      display: "name",
       templates: {
         header: '<h3 class="h5">Epochs</h3>',
-        suggestion: function (data) {
-          return `<div>${data.epoch}</div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     },
     {
       limit: 5,
       name: "addresses",
       source: bhEth1Accounts,
-      display: (data) => data.address || data.name,
+      display: "name",
       templates: {
         header: '<h3 class="h5">Address</h3>',
-        suggestion: function (data) {
-          if (data.name) {
-            return `
-              <div class="d-flex justify-content-between">
-                <div class="text-monospace text-truncate">${data.name}</div>
-                <div class="text-monospace ml-1 d-flex">
-                  ${shortenAddress(data.addres)}
-                </div>
-              </div>`
-          }
-          return `<div class="text-monospace text-truncate">0x${data.address}</div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     },
     {
@@ -410,12 +395,10 @@ $(document).ready(function () {
       limit: 5,
       name: "graffiti",
       source: bhGraffiti,
-      display: "graffiti",
+      display: "name",
       templates: {
         header: '<h3 class="h5">Blocks by Graffiti</h3>',
-        suggestion: function (data) {
-          return `<div class="text-monospace" style="display:flex"><div class="text-truncate" style="flex:1 1 auto;">${data.graffiti}</div><div style="max-width:fit-content;white-space:nowrap;">${data.count}</div></div>`
-        },
+        suggestion: searchResultSuggestion,
       },
     }
   )
@@ -443,26 +426,10 @@ $(document).ready(function () {
   })
 
   $(".typeahead").on("typeahead:select", function (ev, sug) {
-    if (sug.txhash !== undefined) {
-      window.location = "/tx/" + sug.txhash
-    } else if (sug.block !== undefined) {
-      window.location = "/block/" + sug.block
-    } else if (sug.slot !== undefined) {
-      window.location = "/slot/" + sug.slot
-    } else if (sug.index !== undefined) {
-      if (sug.index === "deposited") window.location = "/validator/" + sug.pubkey
-      else window.location = "/validator/" + sug.index
-    } else if (sug.epoch !== undefined) {
-      window.location = "/epoch/" + sug.epoch
-    } else if (sug.address !== undefined) {
-      window.location = "/address/" + sug.address
+    if (sug.url !== undefined) {
+      window.location = sug.url
     } else if (sug.eth1_address !== undefined) {
       window.location = "/validators/deposits?q=" + sug.eth1_address
-    } else if (sug.graffiti !== undefined) {
-      // sug.graffiti is html-escaped to prevent xss, we need to unescape it
-      var el = document.createElement("textarea")
-      el.innerHTML = sug.graffiti
-      window.location = "/slots?q=" + encodeURIComponent(el.value)
     } else {
       console.log("invalid typeahead-selection", sug)
     }
@@ -1,6 +1,6 @@
 var csrfToken = ""
 
-const VALIDATOR_EVENTS = ["validator_attestation_missed", "validator_proposal_missed", "validator_proposal_submitted", "validator_got_slashed", "validator_synccommittee_soon", "validator_is_offline", "validator_withdrawal"]
+const VALIDATOR_EVENTS = ["validator_attestation_missed", "validator_proposal_missed", "validator_proposal_submitted", "validator_proposal_upcoming", "validator_got_slashed", "validator_synccommittee_soon", "validator_is_offline", "validator_withdrawal"]
 
 // const MONITORING_EVENTS = ['monitoring_machine_offline', 'monitoring_hdd_almostfull', 'monitoring_cpu_load']
 
@@ -12,7 +12,7 @@ function create_typeahead(input_container) {
       return obj.index
     },
     remote: {
-      url: "/search/validators/%QUERY",
+      url: "/search/indexed_validators/%QUERY",
       wildcard: "%QUERY",
     },
   })
@@ -644,6 +644,9 @@ function loadValidatorsData(data) {
                   case "validator_proposal_missed":
                     badgeColor = "badge-light"
                     break
+                  case "validator_proposal_upcoming":
+                    badgeColor = "badge-light"
+                    break
                   case "validator_got_slashed":
                     badgeColor = "badge-light"
                     break
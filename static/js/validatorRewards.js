@@ -238,6 +238,22 @@ function showTable(data) {
           //    return `${currency} ${addCommas(parseFloat(data).toFixed(DECIMAL_POINTS_CURRENCY))}`
           return data
         },
+      },
+      {
+        targets: 5,
+        data: "5",
+        orderable: true,
+        render: function (data, type, row, meta) {
+          return data
+        },
+      },
+      {
+        targets: 6,
+        data: "6",
+        orderable: false,
+        render: function (data, type, row, meta) {
+          return data
+        },
         // }, {
         //     targets: 5,
         //     data: '5',
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 )
 
 type ApiResponse struct {
@@ -95,6 +96,142 @@ type UsersNotificationsRequest struct {
 
 type DashboardRequest struct {
 	IndicesOrPubKey string `json:"indicesOrPubkey"`
+	// DashboardID or ShareID select the validators of a stored dashboard instead of IndicesOrPubKey, GroupID optionally limits them to a single group
+	DashboardID uint64 `json:"dashboardId"`
+	ShareID     string `json:"shareId"`
+	GroupID     uint64 `json:"groupId"`
+}
+
+type ApiUserDashboard struct {
+	ID        uint64                `json:"id"`
+	Name      string                `json:"name"`
+	ShareID   string                `json:"share_id,omitempty"`
+	CreatedTs int64                 `json:"created_ts"`
+	Groups    []*UserDashboardGroup `json:"groups"`
+}
+
+type ApiUserDashboardRequest struct {
+	Name string `json:"name"`
+}
+
+type ApiUserDashboardGroupRequest struct {
+	Name string `json:"name"`
+	// ValidatorSetID optionally adds all members of a rule based validator set to the group
+	ValidatorSetID uint64 `json:"validator_set_id"`
+}
+
+type ApiValidatorSet struct {
+	ID                 uint64   `json:"id"`
+	Name               string   `json:"name"`
+	RuleType           string   `json:"rule_type"`
+	RuleValue          string   `json:"rule_value"`
+	RuleNodeAddress    string   `json:"rule_node_address,omitempty"`
+	RuleEnsName        string   `json:"rule_ens_name,omitempty"`
+	EventNames         []string `json:"event_names"`
+	EventThreshold     float64  `json:"event_threshold"`
+	ValidatorCount     uint64   `json:"validator_count"`
+	LastEvaluatedEpoch *uint64  `json:"last_evaluated_epoch"`
+	CreatedTs          int64    `json:"created_ts"`
+	Validators         []uint64 `json:"validators,omitempty"`
+}
+
+type ApiValidatorSetCreateRequest struct {
+	Name string `json:"name"`
+	// RuleType is one of withdrawal_credentials, deposit_address or pool_tag
+	RuleType string `json:"rule_type"`
+	// RuleValue is the withdrawal credentials or withdrawal address, the deposit address or the pool tag (e.g. rocketpool)
+	RuleValue string `json:"rule_value"`
+	// RuleNodeAddress optionally limits a rocketpool pool tag rule to the minipools of a node
+	RuleNodeAddress string `json:"rule_node_address"`
+	// EventNames are the validator notifications every member of the set is subscribed to
+	EventNames     []string `json:"event_names"`
+	EventThreshold float64  `json:"event_threshold"`
+}
+
+type ApiUserDashboardValidatorsRequest struct {
+	// GroupID is the group the validators are added to, the first group of the dashboard is used if it is 0
+	GroupID         uint64 `json:"group_id"`
+	IndicesOrPubKey string `json:"indicesOrPubkey"`
+}
+
+type ApiUserDashboardShareRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type ApiValidatorBenchmarkResponse struct {
+	// Days is the number of days the sets are compared over
+	Days uint64 `json:"days"`
+	// Network is the performance of all validators, its EthStoreApr is the average ETH.STORE apr over the days
+	Network *ApiValidatorBenchmarkSet   `json:"network"`
+	Sets    []*ApiValidatorBenchmarkSet `json:"sets"`
+}
+
+type ApiValidatorBenchmarkSet struct {
+	Set string `json:"set"`
+	// Validators is the number of active validators of the set on the last day
+	Validators               uint64  `json:"validators"`
+	Apr                      float64 `json:"apr"`
+	EthStoreApr              float64 `json:"ethstore_apr,omitempty"`
+	AprPercentileRank        float64 `json:"apr_percentile_rank"`
+	AttestationParticipation float64 `json:"attestation_participation"`
+	MissedAttestations       uint64  `json:"missed_attestations"`
+	ProposedBlocks           uint64  `json:"proposed_blocks"`
+	MissedBlocks             uint64  `json:"missed_blocks"`
+	SyncParticipation        float64 `json:"sync_participation"`
+	MissedSync               uint64  `json:"missed_sync"`
+	// DailyPerformance contains the performance of the set on each day
+	DailyPerformance []*ValidatorBenchmarkDay `json:"daily_performance"`
+}
+
+// ApiValidatorSyncPeriod contains the sync committee duties of a validator in a sync committee period and the rewards of the duties
+type ApiValidatorSyncPeriod struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	Period         uint64 `json:"period"`
+	StartEpoch     uint64 `json:"start_epoch"`
+	EndEpoch       uint64 `json:"end_epoch"`
+	Participated   uint64 `json:"participated"`
+	Missed         uint64 `json:"missed"`
+	// Orphaned is the number of duties of slots whose block was orphaned
+	Orphaned uint64 `json:"orphaned"`
+	// NoBlock is the number of duties of slots in which no block was proposed
+	NoBlock   uint64 `json:"no_block"`
+	Scheduled uint64 `json:"scheduled"`
+	RewardWei uint64 `json:"reward_wei"`
+	// PenaltyWei is the penalty of the missed duties
+	PenaltyWei uint64 `json:"penalty_wei"`
+	// MissedRewardWei estimates the rewards that were not earned by missed and orphaned duties at the average reward of a participated duty of the period
+	MissedRewardWei uint64 `json:"missed_reward_wei"`
+	// Duties contains the duty of every slot of the period in which the validator was a member of the sync committee
+	Duties []*ApiSyncDuty `json:"duties"`
+}
+
+type ApiSyncDuty struct {
+	Slot uint64 `json:"slot"`
+	// Status is one of participated, missed, orphaned, no_block or scheduled
+	Status string `json:"status"`
+}
+
+// ApiUpcomingProposal is a block proposal of a validator in the current or the next epoch that has not happened yet
+type ApiUpcomingProposal struct {
+	Slot           uint64 `json:"slot"`
+	Epoch          uint64 `json:"epoch"`
+	ValidatorIndex uint64 `json:"validatorindex"`
+	// Timestamp is the unix timestamp of the start of the slot
+	Timestamp int64 `json:"timestamp"`
+	// EtaSeconds is the number of seconds until the start of the slot
+	EtaSeconds int64 `json:"eta_seconds"`
+}
+
+type ApiValidatorEthStoreResponse struct {
+	Set     string `json:"set"`
+	FromDay uint64 `json:"from_day"`
+	ToDay   uint64 `json:"to_day"`
+	// Apr is the apr of the set over all days, the rewards of all days are related to the summed up effective balances of all days
+	Apr float64 `json:"apr"`
+	// EthStoreApr is the apr of the ETH.STORE over all days, computed like Apr
+	EthStoreApr     float64                 `json:"ethstore_apr"`
+	TotalRewardsWei decimal.Decimal         `json:"total_rewards_wei"`
+	Days            []*ValidatorEthStoreDay `json:"days"`
 }
 
 type DiscordEmbed struct {
@@ -516,6 +653,36 @@ type ApiValidatorQueueResponse struct {
 	ValidatorsCount     uint64 `json:"validators_count"`
 }
 
+type ApiValidatorQueueSimulationResponse struct {
+	State                *ChurnQueueState `json:"state"`
+	ChurnLimit           uint64           `json:"churn_limit"`
+	ActivationChurnLimit uint64           `json:"activation_churn_limit"`
+	// projection for the given number of validators exiting now
+	Exits                 uint64 `json:"exits"`
+	FirstExitEpoch        uint64 `json:"first_exit_epoch,omitempty"`
+	LastExitEpoch         uint64 `json:"last_exit_epoch,omitempty"`
+	LastExitTs            int64  `json:"last_exit_ts,omitempty"`
+	LastWithdrawableEpoch uint64 `json:"last_withdrawable_epoch,omitempty"`
+	LastWithdrawableTs    int64  `json:"last_withdrawable_ts,omitempty"`
+	// projection for the given number of validators joining the end of the activation queue now
+	Activations         uint64 `json:"activations"`
+	LastActivationEpoch uint64 `json:"last_activation_epoch,omitempty"`
+	LastActivationTs    int64  `json:"last_activation_ts,omitempty"`
+}
+
+type ApiValidatorQueueEstimateResponse struct {
+	ValidatorIndex    uint64 `json:"validatorindex"`
+	Status            string `json:"status"`
+	QueuePosition     uint64 `json:"queue_position,omitempty"`
+	ActivationEpoch   uint64 `json:"activation_epoch,omitempty"`
+	ActivationTs      int64  `json:"activation_ts,omitempty"`
+	ExitEpoch         uint64 `json:"exit_epoch,omitempty"`
+	ExitTs            int64  `json:"exit_ts,omitempty"`
+	WithdrawableEpoch uint64 `json:"withdrawable_epoch,omitempty"`
+	WithdrawableTs    int64  `json:"withdrawable_ts,omitempty"`
+	IsEstimate        bool   `json:"is_estimate"`
+}
+
 type APIValidatorResponse struct {
 	ActivationEligibilityEpoch uint64 `json:"activation_eligibility_epoch"`
 	ActivationEpoch            uint64 `json:"activation_epoch"`
@@ -574,6 +741,36 @@ type ApiValidatorIncomeHistoryResponse struct {
 	Week           uint64                     `json:"week"`
 	WeekStart      time.Time                  `json:"week_start"`
 	WeekEnd        time.Time                  `json:"week_end"`
+	Hour           *time.Time                 `json:"hour,omitempty"`
+}
+
+// ApiAttestationRewardAnalysis compares the attestation rewards of a validator in an epoch with the ideal rewards of a validator
+// with the same effective balance and attributes the shortfall to its causes, all amounts are in gwei
+type ApiAttestationRewardAnalysis struct {
+	Epoch            uint64                        `json:"epoch"`
+	ValidatorIndex   uint64                        `json:"validatorindex"`
+	EffectiveBalance uint64                        `json:"effective_balance"`
+	Included         bool                          `json:"included"`
+	InclusionDelay   int64                         `json:"inclusion_delay"`
+	Ideal            ApiAttestationRewards         `json:"ideal"`
+	Actual           ApiAttestationRewards         `json:"actual"`
+	Shortfall        ApiAttestationRewardShortfall `json:"shortfall"`
+}
+
+type ApiAttestationRewards struct {
+	Head   int64 `json:"head"`
+	Source int64 `json:"source"`
+	Target int64 `json:"target"`
+	Total  int64 `json:"total"`
+}
+
+type ApiAttestationRewardShortfall struct {
+	WrongHead      int64 `json:"wrong_head"`
+	WrongTarget    int64 `json:"wrong_target"`
+	LateSource     int64 `json:"late_source"`
+	InclusionDelay int64 `json:"inclusion_delay"`
+	Missed         int64 `json:"missed"`
+	Total          int64 `json:"total"`
 }
 
 type ApiValidatorIncomeHistory struct {
@@ -614,6 +811,16 @@ type ApiValidatorWithdrawalResponse struct {
 	Amount         uint64 `json:"amount"`
 }
 
+type ApiValidatorNextWithdrawalResponse struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	Epoch          uint64 `json:"epoch"`
+	Slot           uint64 `json:"slot"`
+	Timestamp      int64  `json:"timestamp"`
+	Address        string `json:"address"`
+	Amount         uint64 `json:"amount"`
+	IsFull         bool   `json:"is_full_withdrawal"`
+}
+
 type ApiValidatorBlsChangeResponse struct {
 	Epoch                    uint64 `db:"epoch" json:"epoch,omitempty"`
 	Slot                     uint64 `db:"slot" json:"slot,omitempty"`
@@ -714,4 +921,193 @@ type ApiValidatorProposalsResponse struct {
 type EnsDomainResponse struct {
 	Address string `json:"address"`
 	Domain  string `json:"domain"`
+	// Records are the resolver records and the expiry of the domain, they are nil if the domain is not indexed
+	Records *EnsRecords `json:"records,omitempty"`
+}
+
+type EnsNameStatus string
+
+const (
+	EnsNameStatusActive      EnsNameStatus = "active"
+	EnsNameStatusGracePeriod EnsNameStatus = "grace_period"
+	EnsNameStatusExpired     EnsNameStatus = "expired"
+)
+
+// EnsRecords are the indexed resolver records of an ens name, text records that are not set are empty
+type EnsRecords struct {
+	Name        string `db:"ens_name" json:"name"`
+	Address     []byte `db:"address" json:"-"`
+	Owner       []byte `db:"owner" json:"-"`
+	Url         string `db:"url" json:"url"`
+	Twitter     string `db:"twitter" json:"twitter"`
+	Github      string `db:"github" json:"github"`
+	Description string `db:"description" json:"description"`
+	Avatar      string `db:"avatar" json:"avatar"`
+	// AvatarUrl is the resolved image url of the avatar record, e.g. the image of the nft an avatar record points to
+	AvatarUrl   string        `db:"avatar_url" json:"avatar_url"`
+	Contenthash string        `db:"contenthash" json:"contenthash"`
+	ValidTo     time.Time     `db:"valid_to" json:"valid_to"`
+	Status      EnsNameStatus `db:"-" json:"status"`
+}
+
+type ApiEth1DecodedTxResponse struct {
+	Hash          string                    `json:"hash"`
+	From          string                    `json:"from"`
+	To            string                    `json:"to,omitempty"`
+	Method        string                    `json:"method"`
+	Call          *ApiEth1DecodedCall       `json:"call"`
+	Events        []*ApiEth1DecodedEvent    `json:"events"`
+	InternalCalls []*ApiEth1DecodedInternal `json:"internal_calls"`
+}
+
+type ApiEth1DecodedCall struct {
+	Name      string               `json:"name"`
+	Signature string               `json:"signature"`
+	Args      []*ApiEth1DecodedArg `json:"args"`
+}
+
+type ApiEth1DecodedArg struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ApiEth1DecodedEvent struct {
+	Address string               `json:"address"`
+	Name    string               `json:"name"`
+	Topics  []string             `json:"topics"`
+	Data    string               `json:"data"`
+	Args    []*ApiEth1DecodedArg `json:"args"`
+}
+
+type ApiEth1DecodedInternal struct {
+	TraceAddress []int64             `json:"trace_address"`
+	CallType     string              `json:"call_type"`
+	From         string              `json:"from"`
+	To           string              `json:"to"`
+	Value        string              `json:"value"`
+	Input        string              `json:"input"`
+	Method       string              `json:"method"`
+	Error        string              `json:"error,omitempty"`
+	Call         *ApiEth1DecodedCall `json:"call"`
+}
+
+type ApiContractAbiRequest struct {
+	// Abi is either the json abi of the contract or the metadata json of the solidity compiler as published by Sourcify
+	Abi json.RawMessage `json:"abi"`
+}
+
+type ApiContractAbiResponse struct {
+	Address        string `json:"address"`
+	Name           string `json:"name"`
+	Source         string `json:"source"`
+	Implementation string `json:"implementation,omitempty"`
+	Functions      int    `json:"functions"`
+	Events         int    `json:"events"`
+}
+
+type ApiEth1IndexedEventsResponse struct {
+	Indexer       string                 `json:"indexer"`
+	Contract      string                 `json:"contract"`
+	Events        []*ApiEth1IndexedEvent `json:"events"`
+	NextPageToken string                 `json:"next_page_token,omitempty"`
+}
+
+type ApiEth1IndexedEvent struct {
+	Name        string               `json:"name"`
+	BlockNumber uint64               `json:"block_number"`
+	Timestamp   int64                `json:"timestamp"`
+	TxHash      string               `json:"tx_hash"`
+	LogIndex    uint64               `json:"log_index"`
+	Args        []*ApiEth1DecodedArg `json:"args"`
+}
+
+type ApiTokenHoldersResponse struct {
+	Token string `json:"token"`
+	// Block is the block of the snapshot, it is omitted for the latest balances
+	Block   *uint64           `json:"block,omitempty"`
+	Holders uint64            `json:"holders"`
+	Top     []*ApiTokenHolder `json:"top_holders"`
+}
+
+type ApiTokenHolder struct {
+	Rank        uint64  `json:"rank"`
+	Address     string  `json:"address"`
+	Balance     string  `json:"balance"`
+	Share       float64 `json:"share"`
+	BlockNumber uint64  `json:"block_number"`
+}
+
+type ApiTokenHolderCountResponse struct {
+	Day     string `json:"day"`
+	Holders uint64 `json:"holders"`
+}
+
+type ApiAddressLabelSet struct {
+	ID             uint64  `json:"id"`
+	Name           string  `json:"name"`
+	Source         string  `json:"source"`
+	OrganisationID *uint64 `json:"organisation_id,omitempty"`
+	LabelCount     uint64  `json:"label_count"`
+	CreatedTs      int64   `json:"created_ts"`
+}
+
+type ApiAddressLabelSetCreateRequest struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	// OrganisationID shares the set with all members of the organisation, the set is private to the user if it is 0
+	OrganisationID uint64 `json:"organisation_id"`
+}
+
+type ApiAddressLabel struct {
+	Address    string   `json:"address"`
+	Name       string   `json:"name"`
+	Category   string   `json:"category,omitempty"`
+	Tags       []string `json:"tags"`
+	Confidence float64  `json:"confidence"`
+	Source     string   `json:"source,omitempty"`
+	Private    bool     `json:"private"`
+}
+
+type ApiOrganisation struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Owner     bool   `json:"owner"`
+	Members   uint64 `json:"members"`
+	CreatedTs int64  `json:"created_ts"`
+}
+
+type ApiOrganisationMemberRequest struct {
+	Email string `json:"email"`
+}
+
+type ApiSearchResult struct {
+	// Type is one of validator_name, graffiti, ens, address_label, token, pool, validator, slot, epoch, block, transaction or address
+	Type  string  `json:"type"`
+	Name  string  `json:"name"`
+	Key   string  `json:"key"`
+	Url   string  `json:"url"`
+	Count uint64  `json:"count"`
+	Score float64 `json:"score"`
+}
+
+// ApiStatisticsDayStatus is the progress of the validator statistics export of a day
+type ApiStatisticsDayStatus struct {
+	Day       uint64                     `json:"day"`
+	Completed bool                       `json:"completed"`
+	Tasks     []*ApiStatisticsTaskStatus `json:"tasks"`
+}
+
+// ApiStatisticsTaskStatus is the status of a task of the validator statistics export of a day
+type ApiStatisticsTaskStatus struct {
+	Task                 string   `json:"task"`
+	DependsOn            []string `json:"depends_on"`
+	DependsOnPreviousDay []string `json:"depends_on_previous_day"`
+	Exported             bool     `json:"exported"`
+	// Status is one of pending, running, completed or failed
+	Status     string `json:"status"`
+	Attempts   uint64 `json:"attempts"`
+	DurationMs uint64 `json:"duration_ms"`
+	StartedTs  int64  `json:"started_ts"`
+	FinishedTs int64  `json:"finished_ts"`
 }
@@ -39,6 +39,7 @@ type ChainConfig struct {
 	EjectionBalance                  uint64 `yaml:"EJECTION_BALANCE"`
 	MinPerEpochChurnLimit            uint64 `yaml:"MIN_PER_EPOCH_CHURN_LIMIT"`
 	ChurnLimitQuotient               uint64 `yaml:"CHURN_LIMIT_QUOTIENT"`
+	MaxPerEpochActivationChurnLimit  uint64 `yaml:"MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT"`
 	ProposerScoreBoost               uint64 `yaml:"PROPOSER_SCORE_BOOST"`
 	DepositChainID                   uint64 `yaml:"DEPOSIT_CHAIN_ID"`
 	DepositNetworkID                 uint64 `yaml:"DEPOSIT_NETWORK_ID"`
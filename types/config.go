@@ -71,6 +71,8 @@ type Config struct {
 		EnsTransformer struct {
 			ValidRegistrarContracts []string `yaml:"validRegistrarContracts" envconfig:"ENS_VALID_REGISTRAR_CONTRACTS"`
 		} `yaml:"ensTransformer"`
+		// EventIndexers index the logs of arbitrary contracts, each indexer is served by the /api/v1/execution/events/{indexerName} endpoint
+		EventIndexers []EventIndexerConfig `yaml:"eventIndexers"`
 	} `yaml:"indexer"`
 	Frontend struct {
 		Debug                          bool   `yaml:"debug" envconfig:"FRONTEND_DEBUG"`
@@ -158,6 +160,14 @@ type Config struct {
 		Validator struct {
 			ShowProposerRewards bool `yaml:"showProposerRewards" envconfig:"FRONTEND_SHOW_PROPOSER_REWARDS"`
 		} `yaml:"validator"`
+		MempoolTracker struct {
+			Enabled bool `yaml:"enabled" envconfig:"FRONTEND_MEMPOOL_TRACKER_ENABLED"`
+		} `yaml:"mempoolTracker"`
+		NftMetadata struct {
+			// IpfsGateway is used to resolve ipfs:// and ipns:// metadata uris, e.g. https://ipfs.io/
+			IpfsGateway string        `yaml:"ipfsGateway" envconfig:"FRONTEND_NFT_METADATA_IPFS_GATEWAY"`
+			Timeout     time.Duration `yaml:"timeout" envconfig:"FRONTEND_NFT_METADATA_TIMEOUT"`
+		} `yaml:"nftMetadata"`
 		HttpReadTimeout  time.Duration `yaml:"httpReadTimeout" envconfig:"FRONTEND_HTTP_READ_TIMEOUT"`
 		HttpWriteTimeout time.Duration `yaml:"httpWriteTimeout" envconfig:"FRONTEND_HTTP_WRITE_TIMEOUT"`
 		HttpIdleTimeout  time.Duration `yaml:"httpIdleTimeout" envconfig:"FRONTEND_HTTP_IDLE_TIMEOUT"`
@@ -188,6 +198,14 @@ type Config struct {
 	MevBoostRelayExporter struct {
 		Enabled bool `yaml:"enabled" envconfig:"MEVBOOSTRELAY_EXPORTER_ENABLED"`
 	} `yaml:"mevBoostRelayExporter"`
+	RollingValidatorStats struct {
+		// Enabled makes the rewards exporter store the income details of every epoch in postgres, downsampled to hourly aggregates
+		Enabled bool `yaml:"enabled" envconfig:"ROLLING_VALIDATOR_STATS_ENABLED"`
+		// EpochWindow is how long the income details of single epochs are kept (default 24h)
+		EpochWindow time.Duration `yaml:"epochWindow" envconfig:"ROLLING_VALIDATOR_STATS_EPOCH_WINDOW"`
+		// HourlyWindow is how long the hourly aggregates are kept (default 720h)
+		HourlyWindow time.Duration `yaml:"hourlyWindow" envconfig:"ROLLING_VALIDATOR_STATS_HOURLY_WINDOW"`
+	} `yaml:"rollingValidatorStats"`
 	Pprof struct {
 		Enabled bool   `yaml:"enabled" envconfig:"PPROF_ENABLED"`
 		Port    string `yaml:"port" envconfig:"PPROF_PORT"`
@@ -195,7 +213,10 @@ type Config struct {
 	NodeJobsProcessor struct {
 		ElEndpoint string `yaml:"elEndpoint" envconfig:"NODE_JOBS_PROCESSOR_EL_ENDPOINT"`
 		ClEndpoint string `yaml:"clEndpoint" envconfig:"NODE_JOBS_PROCESSOR_CL_ENDPOINT"`
+		// hex encoded 32 byte key used to encrypt pre-signed scheduled exits at rest
+		ExitEncryptionKey string `yaml:"exitEncryptionKey" envconfig:"NODE_JOBS_PROCESSOR_EXIT_ENCRYPTION_KEY"`
 	} `yaml:"nodeJobsProcessor"`
+	Price                           PriceConfig                      `yaml:"price"`
 	ServiceMonitoringConfigurations []ServiceMonitoringConfiguration `yaml:"serviceMonitoringConfigurations" envconfig:"SERVICE_MONITORING_CONFIGURATIONS"`
 }
 
@@ -213,3 +234,35 @@ type ServiceMonitoringConfiguration struct {
 	Name     string        `yaml:"name" envconfig:"NAME"`
 	Duration time.Duration `yaml:"duration" envconfig:"DURATION"`
 }
+
+type PriceConfig struct {
+	// Currencies are the fiat currencies prices are tracked in
+	Currencies []string `yaml:"currencies" envconfig:"PRICE_CURRENCIES"`
+	// MaxAge is the age after which the price of a source is stale and no longer used
+	MaxAge time.Duration `yaml:"maxAge" envconfig:"PRICE_MAX_AGE"`
+	// Sources are queried for the price of the native currency, the median of their prices is used.
+	// Defaults to the chainlink feeds on mainnet and coingecko.
+	Sources []PriceSourceConfig `yaml:"sources"`
+}
+
+type PriceSourceConfig struct {
+	// Type is one of chainlink, coingecko, kraken or static
+	Type string `yaml:"type"`
+	// Endpoint is the eth1 rpc endpoint of chainlink sources and the api base url of coingecko and kraken sources
+	Endpoint string `yaml:"endpoint"`
+	// Feeds maps currencies to the addresses of the chainlink <currency>/USD feeds, USD maps to the feed of the native currency
+	Feeds map[string]string `yaml:"feeds"`
+	// Asset is the coingecko coin id or the kraken asset of the native currency
+	Asset string `yaml:"asset"`
+	// File is the path of the json file of static sources, see price.StaticSource
+	File string `yaml:"file"`
+}
+
+type EventIndexerConfig struct {
+	// Name identifies the indexer in bigtable rows and the api, it may only contain letters, digits, - and _
+	Name string `yaml:"name"`
+	// Address of the contract whose logs are indexed
+	Address string `yaml:"address"`
+	// Abi is the json abi of the indexed events, all non-anonymous events of the abi are indexed
+	Abi string `yaml:"abi"`
+}
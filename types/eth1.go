@@ -16,3 +16,60 @@ type BulkMutations struct {
 	Keys []string
 	Muts []*gcp_bigtable.Mutation
 }
+
+// Eth1IndexedEvent is a log that was indexed by a config driven event indexer, it is decoded with the abi of the indexer when it is read
+type Eth1IndexedEvent struct {
+	BlockNumber uint64
+	Time        int64
+	TxHash      []byte
+	TxIndex     uint64
+	LogIndex    uint64
+	Topics      [][]byte
+	Data        []byte
+}
+
+// Eth1BalanceDelta is the net change of the native balance of an address within a block
+type Eth1BalanceDelta struct {
+	BlockNumber uint64
+	Time        int64
+	// Delta is the signed change in wei as decimal string
+	Delta string
+}
+
+// Eth1BalanceHistoryPoint is the native balance of an address after its last balance change of a utc day
+type Eth1BalanceHistoryPoint struct {
+	Day         int64  `json:"day"`
+	BlockNumber uint64 `json:"block"`
+	Balance     string `json:"balance"`
+}
+
+const (
+	NftStandardERC721  = "ERC721"
+	NftStandardERC1155 = "ERC1155"
+)
+
+// NftTransfer is a transfer of a single token id of an ERC-721 or ERC-1155 collection
+type NftTransfer struct {
+	TokenId     []byte
+	TxHash      []byte
+	BlockNumber uint64
+	Time        time.Time
+	From        []byte
+	To          []byte
+	// Value is always 1 for ERC-721 transfers
+	Value []byte
+}
+
+// NftMetadata is the metadata json an NFT points to by its tokenURI (ERC-721) or uri (ERC-1155)
+type NftMetadata struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Image       string                 `json:"image"`
+	ExternalUrl string                 `json:"external_url"`
+	Attributes  []NftMetadataAttribute `json:"attributes"`
+}
+
+type NftMetadataAttribute struct {
+	TraitType string      `json:"trait_type"`
+	Value     interface{} `json:"value"`
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -24,6 +25,7 @@ const (
 	ValidatorBalanceDecreasedEventName               EventName = "validator_balance_decreased"
 	ValidatorMissedProposalEventName                 EventName = "validator_proposal_missed"
 	ValidatorExecutedProposalEventName               EventName = "validator_proposal_submitted"
+	ValidatorUpcomingProposalEventName               EventName = "validator_proposal_upcoming"
 	ValidatorMissedAttestationEventName              EventName = "validator_attestation_missed"
 	ValidatorGotSlashedEventName                     EventName = "validator_got_slashed"
 	ValidatorDidSlashEventName                       EventName = "validator_did_slash"
@@ -49,6 +51,7 @@ const (
 	RocketpoolCollateralMinReached                   EventName = "rocketpool_colleteral_min"
 	RocketpoolCollateralMaxReached                   EventName = "rocketpool_colleteral_max"
 	SyncCommitteeSoon                                EventName = "validator_synccommittee_soon"
+	EnsNameExpiringEventName                         EventName = "ens_name_expiring"
 )
 
 var UserIndexEvents = []EventName{
@@ -67,6 +70,7 @@ var EventLabel map[EventName]string = map[EventName]string{
 	ValidatorBalanceDecreasedEventName:               "Your validator(s) balance decreased",
 	ValidatorMissedProposalEventName:                 "Your validator(s) missed a proposal",
 	ValidatorExecutedProposalEventName:               "Your validator(s) submitted a proposal",
+	ValidatorUpcomingProposalEventName:               "Your validator(s) will propose a block soon",
 	ValidatorMissedAttestationEventName:              "Your validator(s) missed an attestation",
 	ValidatorGotSlashedEventName:                     "Your validator(s) got slashed",
 	ValidatorDidSlashEventName:                       "Your validator(s) slashed another validator",
@@ -92,6 +96,7 @@ var EventLabel map[EventName]string = map[EventName]string{
 	RocketpoolCollateralMinReached:                   "You reached the rocketpool min collateral",
 	RocketpoolCollateralMaxReached:                   "You reached the rocketpool max collateral",
 	SyncCommitteeSoon:                                "Your validator(s) will soon be part of the sync committee",
+	EnsNameExpiringEventName:                         "Your ENS name(s) will expire soon",
 }
 
 func IsUserIndexed(event EventName) bool {
@@ -106,6 +111,7 @@ func IsUserIndexed(event EventName) bool {
 var EventNames = []EventName{
 	ValidatorBalanceDecreasedEventName,
 	ValidatorExecutedProposalEventName,
+	ValidatorUpcomingProposalEventName,
 	ValidatorMissedProposalEventName,
 	ValidatorMissedAttestationEventName,
 	ValidatorGotSlashedEventName,
@@ -132,6 +138,7 @@ var EventNames = []EventName{
 	RocketpoolCollateralMinReached,
 	RocketpoolCollateralMaxReached,
 	SyncCommitteeSoon,
+	EnsNameExpiringEventName,
 }
 
 type EventNameDesc struct {
@@ -165,6 +172,11 @@ var AddWatchlistEvents = []EventNameDesc{
 		Desc:  "Proposals submitted",
 		Event: ValidatorExecutedProposalEventName,
 	},
+	{
+		Desc:  "Upcoming proposals",
+		Event: ValidatorUpcomingProposalEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" title="Will trigger one epoch (6.4 minutes) before the proposal" class="fas fa-question-circle"></i>`),
+	},
 	{
 		Desc:  "Validator got slashed",
 		Event: ValidatorGotSlashedEventName,
@@ -559,6 +571,78 @@ type MempoolTxPageData struct {
 	RawMempoolTransaction
 	TargetIsContract   bool
 	IsContractCreation bool
+	Lifecycle          *MempoolTxLifecycle
+	Replacements       []*MempoolTxLifecycle
+}
+
+type MempoolTxStatus string
+
+const (
+	MempoolTxStatusPending  MempoolTxStatus = "pending"
+	MempoolTxStatusReplaced MempoolTxStatus = "replaced"
+	MempoolTxStatusDropped  MempoolTxStatus = "dropped"
+	MempoolTxStatusIncluded MempoolTxStatus = "included"
+)
+
+// MempoolTxLifecycle tracks a single transaction from the moment it was first seen in the mempool
+// until it was either included in a block, replaced by another transaction with the same nonce or dropped
+type MempoolTxLifecycle struct {
+	Hash           []byte          `db:"hash" json:"-"`
+	Sender         []byte          `db:"sender" json:"-"`
+	Nonce          uint64          `db:"nonce" json:"nonce"`
+	GasPrice       decimal.Decimal `db:"gas_price" json:"gas_price"`
+	GasFeeCap      decimal.Decimal `db:"gas_fee_cap" json:"gas_fee_cap"`
+	GasTipCap      decimal.Decimal `db:"gas_tip_cap" json:"gas_tip_cap"`
+	Status         MempoolTxStatus `db:"status" json:"status"`
+	FirstSeen      time.Time       `db:"first_seen" json:"first_seen"`
+	FirstSeenBlock uint64          `db:"first_seen_block" json:"first_seen_block"`
+	LastSeen       time.Time       `db:"last_seen" json:"last_seen"`
+	ReplacedBy     []byte          `db:"replaced_by" json:"-"`
+	IncludedBlock  sql.NullInt64   `db:"included_block" json:"-"`
+	IncludedTime   sql.NullTime    `db:"included_time" json:"-"`
+	DroppedTime    sql.NullTime    `db:"dropped_time" json:"-"`
+}
+
+// InclusionLatencyBlocks returns the number of blocks the transaction spent in the mempool before it was included
+func (l *MempoolTxLifecycle) InclusionLatencyBlocks() uint64 {
+	if !l.IncludedBlock.Valid || uint64(l.IncludedBlock.Int64) < l.FirstSeenBlock {
+		return 0
+	}
+	return uint64(l.IncludedBlock.Int64) - l.FirstSeenBlock
+}
+
+// InclusionLatency returns the time the transaction spent in the mempool before it was included
+func (l *MempoolTxLifecycle) InclusionLatency() time.Duration {
+	if !l.IncludedTime.Valid || l.IncludedTime.Time.Before(l.FirstSeen) {
+		return 0
+	}
+	return l.IncludedTime.Time.Sub(l.FirstSeen)
+}
+
+type ApiMempoolTxLifecycleResponse struct {
+	Hash                    string          `json:"hash"`
+	Sender                  string          `json:"sender"`
+	Nonce                   uint64          `json:"nonce"`
+	Status                  MempoolTxStatus `json:"status"`
+	GasPrice                string          `json:"gas_price"`
+	GasFeeCap               string          `json:"max_fee_per_gas"`
+	GasTipCap               string          `json:"max_priority_fee_per_gas"`
+	FirstSeen               time.Time       `json:"first_seen"`
+	FirstSeenBlock          uint64          `json:"first_seen_block"`
+	LastSeen                time.Time       `json:"last_seen"`
+	ReplacedBy              string          `json:"replaced_by,omitempty"`
+	IncludedBlock           *uint64         `json:"included_block,omitempty"`
+	InclusionLatencyBlocks  *uint64         `json:"inclusion_latency_blocks,omitempty"`
+	InclusionLatencySeconds *float64        `json:"inclusion_latency_seconds,omitempty"`
+	Diagnosis               []string        `json:"diagnosis,omitempty"`
+}
+
+type ApiMempoolSenderResponse struct {
+	Sender         string                           `json:"sender"`
+	AccountNonce   uint64                           `json:"account_nonce"`
+	BaseFeePerGas  string                           `json:"base_fee_per_gas"`
+	Pending        []*ApiMempoolTxLifecycleResponse `json:"pending"`
+	RecentlyClosed []*ApiMempoolTxLifecycleResponse `json:"recently_closed"`
 }
 
 type SyncCommitteesStats struct {
@@ -568,6 +652,50 @@ type SyncCommitteesStats struct {
 	ScheduledSlots    uint64 `json:"scheduledSlots"`
 }
 
+// SyncCommitteeMembership is the membership of a validator in the sync committee of a period
+type SyncCommitteeMembership struct {
+	Period         uint64 `db:"period"`
+	ValidatorIndex uint64 `db:"validatorindex"`
+}
+
+// ScheduledProposal is a block proposal of a validator that has not happened yet
+type ScheduledProposal struct {
+	Slot     uint64 `db:"slot"`
+	Proposer uint64 `db:"proposer"`
+}
+
+// IdealAttestationReward holds the attestation rewards a validator with the effective balance would have received in the epoch with perfect attestations
+type IdealAttestationReward struct {
+	Epoch            uint64 `db:"epoch"`
+	EffectiveBalance uint64 `db:"effective_balance"`
+	Head             int64  `db:"head"`
+	Source           int64  `db:"source"`
+	Target           int64  `db:"target"`
+}
+
+// RollingValidatorIncome holds the income details of a validator (or the sum of a set of validators) for an epoch or an hour of the rolling validator stats
+type RollingValidatorIncome struct {
+	ValidatorIndex                     uint64    `db:"validatorindex"`
+	Epoch                              uint64    `db:"epoch"`
+	Ts                                 time.Time `db:"ts"`
+	Epochs                             uint64    `db:"epochs"`
+	AttestationSourceReward            int64     `db:"attestation_source_reward"`
+	AttestationSourcePenalty           int64     `db:"attestation_source_penalty"`
+	AttestationTargetReward            int64     `db:"attestation_target_reward"`
+	AttestationTargetPenalty           int64     `db:"attestation_target_penalty"`
+	AttestationHeadReward              int64     `db:"attestation_head_reward"`
+	FinalityDelayPenalty               int64     `db:"finality_delay_penalty"`
+	ProposerSlashingInclusionReward    int64     `db:"proposer_slashing_inclusion_reward"`
+	ProposerAttestationInclusionReward int64     `db:"proposer_attestation_inclusion_reward"`
+	ProposerSyncInclusionReward        int64     `db:"proposer_sync_inclusion_reward"`
+	SyncCommitteeReward                int64     `db:"sync_committee_reward"`
+	SyncCommitteePenalty               int64     `db:"sync_committee_penalty"`
+	SlashingReward                     int64     `db:"slashing_reward"`
+	SlashingPenalty                    int64     `db:"slashing_penalty"`
+	ProposalsMissed                    uint64    `db:"proposals_missed"`
+	AttestationsMissed                 uint64    `db:"attestations_missed"`
+}
+
 type SignatureType string
 
 const (
@@ -594,3 +722,250 @@ type SearchValidatorsByEth1Result []struct {
 	ValidatorIndices pq.Int64Array `db:"validatorindices" json:"validator_indices"`
 	Count            uint64        `db:"count" json:"-"`
 }
+
+// ChurnQueueState is the state of the activation and exit queue at Epoch that is used to project activation and exit epochs
+type ChurnQueueState struct {
+	Epoch                uint64 `json:"epoch" db:"epoch"`
+	ActiveValidatorCount uint64 `json:"active_validator_count" db:"active_validator_count"`
+	// the latest exit epoch assigned to any validator and the number of validators exiting in it
+	ExitQueueEpoch uint64 `json:"exit_queue_epoch" db:"exit_queue_epoch"`
+	ExitQueueChurn uint64 `json:"exit_queue_churn" db:"exit_queue_churn"`
+	// the number of validators waiting for activation
+	ActivationQueueLength uint64 `json:"activation_queue_length" db:"activation_queue_length"`
+}
+
+// UserDashboard is a named, server side stored dashboard of a user, the validators of the dashboard are organized in groups
+type UserDashboard struct {
+	ID        uint64                `json:"id" db:"id"`
+	UserID    uint64                `json:"-" db:"user_id"`
+	Network   string                `json:"-" db:"network"`
+	Name      string                `json:"name" db:"name"`
+	PublicID  sql.NullString        `json:"-" db:"public_id"`
+	CreatedTs time.Time             `json:"-" db:"created_ts"`
+	Groups    []*UserDashboardGroup `json:"groups" db:"-"`
+}
+
+// Validators returns the indices of all validators of the dashboard
+func (d *UserDashboard) Validators() []uint64 {
+	validators := []uint64{}
+	seen := make(map[uint64]bool)
+	for _, g := range d.Groups {
+		for _, v := range g.Validators {
+			// members of validator sets can be part of several groups
+			if !seen[v] {
+				seen[v] = true
+				validators = append(validators, v)
+			}
+		}
+	}
+	return validators
+}
+
+// Group returns the group with the given id or nil if the dashboard has no such group
+func (d *UserDashboard) Group(groupID uint64) *UserDashboardGroup {
+	for _, g := range d.Groups {
+		if g.ID == groupID {
+			return g
+		}
+	}
+	return nil
+}
+
+type UserDashboardGroup struct {
+	ID          uint64 `json:"id" db:"id"`
+	DashboardID uint64 `json:"-" db:"dashboard_id"`
+	Name        string `json:"name" db:"name"`
+	// ValidatorSetID is set if the group also contains all members of a rule based validator set
+	ValidatorSetID *uint64  `json:"validator_set_id,omitempty" db:"validator_set_id"`
+	Validators     []uint64 `json:"validators" db:"-"`
+}
+
+type ValidatorSetRuleType string
+
+const (
+	WithdrawalCredentialsValidatorSetRule ValidatorSetRuleType = "withdrawal_credentials"
+	DepositAddressValidatorSetRule        ValidatorSetRuleType = "deposit_address"
+	PoolTagValidatorSetRule               ValidatorSetRuleType = "pool_tag"
+)
+
+// ValidatorSet is a set of validators of a user that is defined by a rule, the members of the set are re-evaluated every epoch.
+// Members that join the set are subscribed to the EventNames of the set.
+type ValidatorSet struct {
+	ID       uint64               `db:"id"`
+	UserID   uint64               `db:"user_id"`
+	Network  string               `db:"network"`
+	Name     string               `db:"name"`
+	RuleType ValidatorSetRuleType `db:"rule_type"`
+	// RuleValue is the hex encoded withdrawal credentials or deposit address or the pool tag
+	RuleValue string `db:"rule_value"`
+	// RuleNodeAddress optionally limits a pool tag rule to the rocketpool minipools of a node
+	RuleNodeAddress string `db:"rule_node_address"`
+	// RuleEnsName is the ens name the address of the rule (deposit, withdrawal or node address) was given as, the address is re-resolved on every evaluation
+	RuleEnsName        string         `db:"rule_ens_name"`
+	MaxValidators      uint64         `db:"max_validators"`
+	EventNames         pq.StringArray `db:"event_names"`
+	EventThreshold     float64        `db:"event_threshold"`
+	LastEvaluatedEpoch sql.NullInt64  `db:"last_evaluated_epoch"`
+	CreatedTs          time.Time      `db:"created_ts"`
+	ValidatorCount     uint64         `db:"validator_count"`
+}
+
+type ValidatorSetMember struct {
+	Index  uint64 `db:"validatorindex"`
+	Pubkey []byte `db:"pubkey"`
+}
+
+// ValidatorBenchmarkDay contains the aggregated daily performance of a set of validators
+type ValidatorBenchmarkDay struct {
+	Day uint64 `json:"day" db:"day"`
+	// Validators is the number of validators of the set that were active on the day
+	Validators uint64 `json:"validators" db:"validators"`
+	// Apr is the ETH.STORE style apr of the set: its total rewards relative to its effective balances, annualized
+	Apr float64 `json:"apr" db:"apr"`
+	// EthStoreApr is the apr of the ETH.STORE day, only set for the network
+	EthStoreApr              float64 `json:"ethstore_apr,omitempty" db:"ethstore_apr"`
+	MissedAttestations       uint64  `json:"missed_attestations" db:"missed_attestations"`
+	AttestationParticipation float64 `json:"attestation_participation" db:"-"`
+	ProposedBlocks           uint64  `json:"proposed_blocks" db:"proposed_blocks"`
+	MissedBlocks             uint64  `json:"missed_blocks" db:"missed_blocks"`
+	ParticipatedSync         uint64  `json:"participated_sync" db:"participated_sync"`
+	MissedSync               uint64  `json:"missed_sync" db:"missed_sync"`
+	SyncParticipation        float64 `json:"sync_participation" db:"-"`
+}
+
+// ValidatorEthStoreDay contains the ETH.STORE style performance of a set of validators on a day next to the ETH.STORE of the day
+type ValidatorEthStoreDay struct {
+	Day uint64 `json:"day" db:"day"`
+	// Validators is the number of validators of the set that were active on the day
+	Validators              uint64          `json:"validators" db:"validators"`
+	EffectiveBalancesSumWei decimal.Decimal `json:"effective_balances_sum_wei" db:"effective_balances_sum_wei"`
+	ConsensusRewardsSumWei  decimal.Decimal `json:"consensus_rewards_sum_wei" db:"consensus_rewards_sum_wei"`
+	TxFeesSumWei            decimal.Decimal `json:"tx_fees_sum_wei" db:"tx_fees_sum_wei"`
+	TotalRewardsWei         decimal.Decimal `json:"total_rewards_wei" db:"total_rewards_wei"`
+	// Apr is the total rewards of the set relative to its effective balances, annualized
+	Apr         float64 `json:"apr" db:"apr"`
+	EthStoreApr float64 `json:"ethstore_apr" db:"ethstore_apr"`
+}
+
+// ValidatorBenchmarkData is the network wide benchmark the performance of validator sets is compared against
+type ValidatorBenchmarkData struct {
+	Days []*ValidatorBenchmarkDay `json:"days"`
+	// AprPercentiles contains the 0th to 100th percentile of the apr of all validators for each benchmark window in days
+	AprPercentiles map[uint64][]float64 `json:"apr_percentiles"`
+	// Apr and EthStoreApr contain the apr of the network and of ETH.STORE for each benchmark window, weighted by effective balance
+	Apr         map[uint64]float64 `json:"apr"`
+	EthStoreApr map[uint64]float64 `json:"ethstore_apr"`
+}
+
+type ValidatorBenchmarkPageData struct {
+	Sets       []string
+	Days       uint64
+	Windows    []uint64
+	Disclaimer string
+}
+
+// TokenHolder is the balance of an address of an ERC-20 token after a transfer of the token at a block
+type TokenHolder struct {
+	Address     []byte          `db:"address"`
+	Balance     decimal.Decimal `db:"balance"`
+	BlockNumber uint64          `db:"block_number"`
+}
+
+// TokenHolderUpdate marks the balance of an address of an ERC-20 token for an update of the holder index after it transferred the token at a block
+type TokenHolderUpdate struct {
+	Token       []byte
+	Address     []byte
+	BlockNumber uint64
+	BlockTime   time.Time
+}
+
+type TokenHolderCount struct {
+	Day     time.Time `db:"day"`
+	Holders uint64    `db:"holders"`
+}
+
+type NftOwner struct {
+	Owner       []byte          `db:"owner"`
+	Amount      decimal.Decimal `db:"amount"`
+	BlockNumber uint64          `db:"block_number"`
+}
+
+type NftCollectionStats struct {
+	Supply  decimal.Decimal `db:"supply"`
+	Tokens  uint64          `db:"tokens"`
+	Holders uint64          `db:"holders"`
+}
+
+type AddressLabelCategory string
+
+const (
+	ExchangeAddressLabel    AddressLabelCategory = "exchange"
+	BridgeAddressLabel      AddressLabelCategory = "bridge"
+	MevBotAddressLabel      AddressLabelCategory = "mev_bot"
+	StakingPoolAddressLabel AddressLabelCategory = "staking_pool"
+)
+
+var AddressLabelCategories = []AddressLabelCategory{ExchangeAddressLabel, BridgeAddressLabel, MevBotAddressLabel, StakingPoolAddressLabel}
+
+// AddressLabelSet is a named list of address labels. Sets without user and organisation are community sets that are visible to everyone,
+// the sets of a user or an organisation are only visible to the user or the members of the organisation.
+type AddressLabelSet struct {
+	ID             uint64        `db:"id"`
+	Name           string        `db:"name"`
+	Source         string        `db:"source"`
+	UserID         sql.NullInt64 `db:"user_id"`
+	OrganisationID sql.NullInt64 `db:"organisation_id"`
+	CreatedTs      time.Time     `db:"created_ts"`
+	LabelCount     uint64        `db:"label_count"`
+}
+
+type AddressLabel struct {
+	SetID    uint64               `db:"set_id"`
+	Address  []byte               `db:"address"`
+	Name     string               `db:"name"`
+	Category AddressLabelCategory `db:"category"`
+	Tags     pq.StringArray       `db:"tags"`
+	// Confidence is the confidence of the source in the label between 0 and 1
+	Confidence float64 `db:"confidence"`
+	// Source and Private are taken from the set of the label
+	Source  string `db:"source"`
+	Private bool   `db:"private"`
+}
+
+type Organisation struct {
+	ID        uint64    `db:"id"`
+	Name      string    `db:"name"`
+	OwnerID   uint64    `db:"owner_id"`
+	CreatedTs time.Time `db:"created_ts"`
+	Members   uint64    `db:"members"`
+}
+
+type SearchResultType string
+
+const (
+	// result types that are kept in the search index
+	ValidatorNameSearchResult SearchResultType = "validator_name"
+	GraffitiSearchResult      SearchResultType = "graffiti"
+	EnsSearchResult           SearchResultType = "ens"
+	AddressLabelSearchResult  SearchResultType = "address_label"
+	TokenSearchResult         SearchResultType = "token"
+	PoolSearchResult          SearchResultType = "pool"
+
+	// result types that are looked up directly by number or hash
+	ValidatorSearchResult   SearchResultType = "validator"
+	SlotSearchResult        SearchResultType = "slot"
+	EpochSearchResult       SearchResultType = "epoch"
+	BlockSearchResult       SearchResultType = "block"
+	TransactionSearchResult SearchResultType = "transaction"
+	AddressSearchResult     SearchResultType = "address"
+)
+
+// SearchIndexEntry is a searchable name of an object, Key identifies the object within its type and Count is the number
+// of underlying objects (e.g. the validators with a name or the blocks with a graffiti) which is used to rank the entry
+type SearchIndexEntry struct {
+	Type  SearchResultType `db:"type"`
+	Key   string           `db:"key"`
+	Name  string           `db:"name"`
+	Count uint64           `db:"count"`
+	Score float64          `db:"score"`
+}
@@ -22,11 +22,47 @@ type NodeJobType string
 
 const BLSToExecutionChangesNodeJobType NodeJobType = "BLS_TO_EXECUTION_CHANGES"
 const VoluntaryExitsNodeJobType NodeJobType = "VOLUNTARY_EXITS"
+const ScheduledExitsNodeJobType NodeJobType = "SCHEDULED_EXITS"
+const DepositDataValidationNodeJobType NodeJobType = "DEPOSIT_DATA_VALIDATION"
 const UnknownNodeJobType NodeJobType = "UNKNOWN"
 
 var NodeJobTypes = []NodeJobType{
 	BLSToExecutionChangesNodeJobType,
 	VoluntaryExitsNodeJobType,
+	ScheduledExitsNodeJobType,
+	DepositDataValidationNodeJobType,
+}
+
+type ScheduledExitTrigger string
+
+const ScheduledExitTriggerSlashed ScheduledExitTrigger = "slashed" // broadcast as soon as a validator with the same withdrawal credentials has been slashed
+const ScheduledExitTriggerOffline ScheduledExitTrigger = "offline" // broadcast as soon as the validator has been offline for OfflineEpochs epochs
+
+// ScheduledExitNodeJobData holds a pre-signed voluntary exit that will be broadcast at BroadcastEpoch or when Trigger fires.
+// The signed exit is only kept in plain text until the job is created, afterwards it is stored encrypted in EncryptedExit.
+type ScheduledExitNodeJobData struct {
+	ValidatorIndex uint64                      `json:"validator_index"`
+	Exit           *phase0.SignedVoluntaryExit `json:"exit,omitempty"`
+	EncryptedExit  []byte                      `json:"encrypted_exit,omitempty"`
+	BroadcastEpoch uint64                      `json:"broadcast_epoch,omitempty"`
+	Trigger        ScheduledExitTrigger        `json:"trigger,omitempty"`
+	OfflineEpochs  uint64                      `json:"offline_epochs,omitempty"`
+}
+
+// DepositData is a single entry of a deposit_data-*.json file as generated by the staking-deposit-cli
+type DepositData struct {
+	Pubkey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositMessageRoot    string `json:"deposit_message_root"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+	ForkVersion           string `json:"fork_version"`
+	NetworkName           string `json:"network_name,omitempty"`
+	DepositCliVersion     string `json:"deposit_cli_version,omitempty"`
+	// set by the deposit-data-validation job
+	Valid  *bool    `json:"valid,omitempty"`
+	Errors []string `json:"errors,omitempty"`
 }
 
 func NewNodeJob(data []byte) (*NodeJob, error) {
@@ -40,14 +76,54 @@ func NewNodeJob(data []byte) (*NodeJob, error) {
 }
 
 type NodeJob struct {
-	ID                  string        `db:"id"`
-	CreatedTime         time.Time     `db:"created_time"`
-	SubmittedToNodeTime sql.NullTime  `db:"submitted_to_node_time"`
-	CompletedTime       sql.NullTime  `db:"completed_time"`
-	Type                NodeJobType   `db:"type"`
-	Status              NodeJobStatus `db:"status"`
-	RawData             []byte        `db:"data"`
-	Data                interface{}   `db:"-"`
+	ID                  string         `db:"id"`
+	CreatedTime         time.Time      `db:"created_time"`
+	SubmittedToNodeTime sql.NullTime   `db:"submitted_to_node_time"`
+	CompletedTime       sql.NullTime   `db:"completed_time"`
+	Type                NodeJobType    `db:"type"`
+	Status              NodeJobStatus  `db:"status"`
+	RawData             []byte         `db:"data"`
+	Data                interface{}    `db:"-"`
+	OwnerID             sql.NullInt64  `db:"owner_id"`
+	CallbackURL         sql.NullString `db:"callback_url"`
+	CallbackSecret      sql.NullString `db:"callback_secret"`
+}
+
+// NodeJobCallback is a queued notification about a status transition of a NodeJob that will be POSTed to NodeJob.CallbackURL
+type NodeJobCallback struct {
+	ID              uint64        `db:"id"`
+	NodeJobID       string        `db:"node_job_id"`
+	Status          NodeJobStatus `db:"status"`
+	CreatedTime     time.Time     `db:"created_time"`
+	Attempts        uint64        `db:"attempts"`
+	LastAttemptTime sql.NullTime  `db:"last_attempt_time"`
+	DeliveredTime   sql.NullTime  `db:"delivered_time"`
+}
+
+// NodeJobCallbackPayload is the body of a callback request, it is signed with the callback secret of the job
+type NodeJobCallbackPayload struct {
+	ID             string        `json:"id"`
+	Type           NodeJobType   `json:"type"`
+	Status         NodeJobStatus `json:"status"`
+	TransitionTime int64         `json:"transition_time"`
+}
+
+// ApiNodeJob is the representation of a NodeJob in the /api/v1/nodejobs endpoints
+type ApiNodeJob struct {
+	ID                  string          `json:"id"`
+	Type                NodeJobType     `json:"type"`
+	Status              NodeJobStatus   `json:"status"`
+	CreatedTime         int64           `json:"created_time"`
+	SubmittedToNodeTime *int64          `json:"submitted_to_node_time"`
+	CompletedTime       *int64          `json:"completed_time"`
+	CallbackURL         string          `json:"callback_url,omitempty"`
+	CallbackSecret      string          `json:"callback_secret,omitempty"` // only returned once when the job is created
+	Data                json.RawMessage `json:"data"`
+}
+
+type ApiNodeJobCreateRequest struct {
+	Data        json.RawMessage `json:"data"`
+	CallbackURL string          `json:"callback_url"`
 }
 
 type CreateNodeJobUserError struct {
@@ -99,6 +175,47 @@ func (nj *NodeJob) ParseData() error {
 			return nj.SanitizeRawData()
 		}
 	}
+	{
+		var d *ScheduledExitNodeJobData
+		err := json.Unmarshal(nj.RawData, &d)
+		if err == nil && d != nil && (d.Exit != nil || len(d.EncryptedExit) > 0) && (d.BroadcastEpoch != 0 || d.Trigger != "") {
+			if nj.Type != "" && nj.Type != UnknownNodeJobType && nj.Type != ScheduledExitsNodeJobType {
+				return fmt.Errorf("nodejob.RawData mismatches nodejob.Type (%v)", nj.Type)
+			}
+			switch d.Trigger {
+			case "", ScheduledExitTriggerSlashed:
+			case ScheduledExitTriggerOffline:
+				if d.OfflineEpochs == 0 {
+					return CreateNodeJobUserError{Message: "offline_epochs must be set for the offline trigger"}
+				}
+			default:
+				return CreateNodeJobUserError{Message: fmt.Sprintf("unknown trigger: %v", d.Trigger)}
+			}
+			if d.Exit != nil {
+				d.ValidatorIndex = uint64(d.Exit.Message.ValidatorIndex)
+			}
+			nj.Type = ScheduledExitsNodeJobType
+			nj.Data = d
+			return nj.SanitizeRawData()
+		}
+	}
+	{
+		d := []*DepositData{}
+		err := json.Unmarshal(nj.RawData, &d)
+		if err == nil && len(d) > 0 {
+			for _, dd := range d {
+				if dd == nil || dd.Pubkey == "" || dd.Signature == "" {
+					return CreateNodeJobUserError{Message: "can not unmarshal data: invalid deposit data"}
+				}
+			}
+			if nj.Type != "" && nj.Type != UnknownNodeJobType && nj.Type != DepositDataValidationNodeJobType {
+				return fmt.Errorf("nodejob.RawData mismatches nodejob.Type (%v)", nj.Type)
+			}
+			nj.Type = DepositDataValidationNodeJobType
+			nj.Data = d
+			return nj.SanitizeRawData()
+		}
+	}
 	return CreateNodeJobUserError{Message: "can not unmarshal data: invalid json"}
 }
 
@@ -120,3 +237,13 @@ func (nj NodeJob) GetVoluntaryExitsNodeJobData() (*phase0.SignedVoluntaryExit, b
 	d, ok := nj.Data.(*phase0.SignedVoluntaryExit)
 	return d, ok
 }
+
+func (nj NodeJob) GetScheduledExitNodeJobData() (*ScheduledExitNodeJobData, bool) {
+	d, ok := nj.Data.(*ScheduledExitNodeJobData)
+	return d, ok
+}
+
+func (nj NodeJob) GetDepositDataValidationNodeJobData() ([]*DepositData, bool) {
+	d, ok := nj.Data.([]*DepositData)
+	return d, ok
+}
@@ -155,6 +155,8 @@ type Stats struct {
 	ActiveValidatorCount           *uint64 `db:"count"`
 	PendingValidatorCount          *uint64 `db:"count"`
 	ValidatorChurnLimit            *uint64
+	ValidatorActivationChurnLimit  *uint64
+	ChurnQueueState                *ChurnQueueState
 	LatestValidatorWithdrawalIndex *uint64 `db:"index"`
 	WithdrawableValidatorCount     *uint64 `db:"count"`
 	// WithdrawableAmount             *uint64 `db:"amount"`
@@ -410,6 +412,10 @@ type ValidatorPageData struct {
 	QueuePosition                            uint64
 	EstimatedActivationTs                    time.Time
 	EstimatedActivationEpoch                 uint64
+	EstimatedExitEpoch                       uint64
+	EstimatedExitTs                          time.Time
+	EstimatedWithdrawableEpoch               uint64
+	EstimatedWithdrawableTs                  time.Time
 	InclusionDelay                           int64
 	CurrentAttestationStreak                 uint64
 	LongestAttestationStreak                 uint64
@@ -520,6 +526,13 @@ type ValidatorIncomeHistory struct {
 	StartBalance     sql.NullInt64 `db:"start_balance"`
 	DepositAmount    sql.NullInt64 `db:"deposits_amount"`
 	WithdrawalAmount sql.NullInt64 `db:"withdrawals_amount"`
+
+	// the following fields are only set if the income is valued in a currency, see db.GetValidatorIncomeHistory
+	Price            float64 // average of the hourly prices of the day
+	ClRewardsValue   float64 // cl rewards valued at the average price of the day as they accrue evenly over the day
+	ElRewards        int64   // execution rewards of the proposed blocks of the day in gwei
+	ElRewardsValue   float64 // execution rewards valued at the price of the hour of each block
+	WithdrawalsValue float64 // withdrawals valued at the price of the hour of each withdrawal
 }
 
 type ValidatorBalanceHistoryChartData struct {
@@ -874,34 +887,6 @@ type EpochPageMinMaxSlot struct {
 	MaxEpoch uint64
 }
 
-// SearchAheadEpochsResult is a struct to hold the search ahead epochs results
-type SearchAheadEpochsResult []struct {
-	Epoch string `db:"epoch" json:"epoch,omitempty"`
-}
-
-// SearchAheadSlotsResult is a struct to hold the search ahead slots results
-type SearchAheadSlotsResult []struct {
-	Slot string `db:"slot" json:"slot,omitempty"`
-	Root string `db:"blockroot" json:"blockroot,omitempty"`
-}
-
-// SearchAheadBlockssResult is a struct to hold the search ahead block results
-type SearchAheadBlocksResult []struct {
-	Block uint64 `json:"block,omitempty"`
-	Hash  string `json:"hash,omitempty"`
-}
-
-type SearchAheadTransactionsResult []struct {
-	Slot   string `db:"slot" json:"slot,omitempty"`
-	TxHash string `db:"txhash" json:"txhash,omitempty"`
-}
-
-// SearchAheadGraffitiResult is a struct to hold the search ahead blocks results with a given graffiti
-type SearchAheadGraffitiResult []struct {
-	Graffiti string `db:"graffiti" json:"graffiti,omitempty"`
-	Count    string `db:"count" json:"count,omitempty"`
-}
-
 // SearchAheadEth1Result is a struct to hold the search ahead eth1 results
 type SearchAheadEth1Result []struct {
 	Publickey   string `db:"publickey" json:"publickey,omitempty"`
@@ -991,6 +976,43 @@ type DashboardData struct {
 	Csrf                string `json:"csrf"`
 	ValidatorLimit      int    `json:"valLimit"`
 	CappellaHasHappened bool
+	// StoredDashboard is set if the page shows the validators of a named dashboard or of a shared dashboard link
+	StoredDashboard           bool
+	StoredDashboardValidators []uint64
+	// IncomeDetailsEnabled is set if the rolling validator stats are available to chart the income details
+	IncomeDetailsEnabled bool
+}
+
+// DashboardMissedRewards holds the ideal and actual attestation rewards of the dashboard validators in an epoch range and the
+// attestation rewards that were left on the table by cause, all amounts are in gwei
+type DashboardMissedRewards struct {
+	FromEpoch  uint64                             `json:"from_epoch"`
+	ToEpoch    uint64                             `json:"to_epoch"`
+	Epochs     uint64                             `json:"epochs"`
+	Ideal      ApiAttestationRewards              `json:"ideal"`
+	Actual     ApiAttestationRewards              `json:"actual"`
+	Shortfall  ApiAttestationRewardShortfall      `json:"shortfall"`
+	Validators []*DashboardMissedRewardsValidator `json:"validators"`
+}
+
+// DashboardMissedRewardsValidator holds the attestation rewards a validator of the dashboard left on the table
+type DashboardMissedRewardsValidator struct {
+	ValidatorIndex uint64                        `json:"validatorindex"`
+	Shortfall      ApiAttestationRewardShortfall `json:"shortfall"`
+}
+
+// DashboardIncomeDetails holds the summed income components of the dashboard validators per epoch or per hour, all amounts are in gwei
+type DashboardIncomeDetails struct {
+	Granularity        string   `json:"granularity"`
+	Timestamps         []int64  `json:"timestamps"`
+	Source             []int64  `json:"source"`
+	Target             []int64  `json:"target"`
+	Head               []int64  `json:"head"`
+	Sync               []int64  `json:"sync"`
+	Proposer           []int64  `json:"proposer"`
+	Other              []int64  `json:"other"`
+	AttestationsMissed []uint64 `json:"attestations_missed"`
+	ProposalsMissed    []uint64 `json:"proposals_missed"`
 }
 
 // DashboardValidatorBalanceHistory is a struct to hold data for the balance-history on the dashboard-page
@@ -1343,15 +1365,8 @@ type GoogleRecaptchaResponse struct {
 }
 
 type Price struct {
-	TS  time.Time `db:"ts"`
-	EUR float64   `db:"eur"`
-	USD float64   `db:"usd"`
-	GBP float64   `db:"gbp"`
-	CAD float64   `db:"cad"`
-	JPY float64   `db:"jpy"`
-	CNY float64   `db:"cny"`
-	RUB float64   `db:"rub"`
-	AUD float64   `db:"aud"`
+	TS    time.Time `db:"ts"`
+	Price float64   `db:"price"`
 }
 
 type ApiStatistics struct {
@@ -1585,6 +1600,14 @@ type Eth1AddressPageData struct {
 	WithdrawalsTable   *DataTableResponse
 	EtherValue         template.HTML
 	Tabs               []Eth1AddressPageTabs
+	// Contract is the metadata of the contract at the address, it is nil for contracts without a known ABI
+	Contract *ContractMetadata
+	Labels   []*AddressLabel
+	// Ens are the records of the primary ens name of the address, it is nil for addresses without a primary name
+	Ens *EnsRecords
+	// EnsExpirySubscribed is whether the user is notified about expiring ens names registered to the address
+	EnsExpirySubscribed bool
+	CsrfField           template.HTML
 }
 
 type Eth1AddressPageTabs struct {
@@ -1639,6 +1662,46 @@ type ContractMetadata struct {
 	Name    string
 	ABI     *abi.ABI `msgpack:"-" json:"-"`
 	ABIJson []byte
+	// Source is where the ABI was retrieved from: etherscan, or abi and sourcify for ABIs that were submitted and verified locally
+	Source string
+	// SubmittedBy is the id of the user that submitted a locally verified ABI
+	SubmittedBy uint64
+}
+
+// IsVerified returns whether the ABI of the contract was retrieved from a verified source, metadata stored before the source
+// was recorded was always retrieved from etherscan
+func (meta *ContractMetadata) IsVerified() bool {
+	return meta.Source == ContractMetadataSourceEtherscan || (meta.Source == "" && len(meta.ABIJson) > 0)
+}
+
+const (
+	ContractMetadataSourceEtherscan = "etherscan"
+	ContractMetadataSourceAbi       = "abi"
+	ContractMetadataSourceSourcify  = "sourcify"
+)
+
+type NftCollectionPageData struct {
+	Token     string
+	Standard  string
+	Name      string
+	Symbol    string
+	Stats     *NftCollectionStats
+	Transfers []*NftTransfer
+}
+
+type NftTokenPageData struct {
+	Token          string
+	TokenId        string
+	Standard       string
+	CollectionName string
+	Owners         []*NftOwner
+	// Transfers is the ownership chain of the token id, the latest transfer comes first
+	Transfers     []*NftTransfer
+	Mint          *NftTransfer
+	MetadataUri   string
+	Metadata      *NftMetadata
+	Image         string
+	MetadataError string
 }
 
 type Eth1TokenPageData struct {
@@ -1680,6 +1743,8 @@ type Eth1TxData struct {
 	InternalTxns []Transfer
 	FromName     string
 	ToName       string
+	FromLabels   []*AddressLabel
+	ToLabels     []*AddressLabel
 	Gas          struct {
 		BlockBaseFee   []byte
 		MaxFee         []byte
@@ -1709,11 +1774,15 @@ type Eth1TxData struct {
 	IsContractCreation          bool
 	CallData                    string
 	Method                      string
+	DecodedCallData             *Eth1DecodedCall
 	Events                      []*Eth1EventData
+	InternalCalls               []*Eth1InternalCall
 	Transfers                   []*Transfer
 	DepositContractInteractions []DepositContractInteraction
 	CurrentEtherPrice           template.HTML
 	HistoricalEtherPrice        template.HTML
+	MempoolLifecycle            *MempoolTxLifecycle
+	MempoolReplacements         []*MempoolTxLifecycle
 }
 
 type Eth1EventData struct {
@@ -1731,6 +1800,35 @@ type Eth1DecodedEventData struct {
 	Address common.Address
 }
 
+// Eth1DecodedCall is the calldata of a contract call that was decoded with the ABI of the contract
+type Eth1DecodedCall struct {
+	Name string
+	// Signature is the full signature of the method including the names of its arguments
+	Signature string
+	Args      []*Eth1DecodedCallArg
+}
+
+type Eth1DecodedCallArg struct {
+	Name    string
+	Type    string
+	Value   string
+	Raw     string
+	Address common.Address
+}
+
+// Eth1InternalCall is a call of a contract within a transaction, TraceAddress is the position of the call in the call tree
+type Eth1InternalCall struct {
+	TraceAddress []int64
+	CallType     string
+	From         common.Address
+	To           common.Address
+	Value        []byte
+	Input        []byte
+	Error        string
+	Method       string
+	DecodedCall  *Eth1DecodedCall
+}
+
 type SourcifyContractMetadata struct {
 	Compiler struct {
 		Version string `json:"version"`
@@ -1810,6 +1908,7 @@ type Eth1BlockPageData struct {
 	ParentHash            string
 	MinerAddress          string
 	MinerFormatted        template.HTML
+	MinerLabels           []*AddressLabel
 	Reward                *big.Int
 	MevReward             *big.Int
 	MevBribe              *big.Int
@@ -2062,6 +2161,10 @@ type BroadcastStatusPageData struct {
 	JobTitle     string
 	JobJson      string
 	Validators   *[]NodeJobValidatorInfo
+	// only set for scheduled exit jobs
+	ScheduledExit *ScheduledExitNodeJobData
+	// only set for deposit data validation jobs
+	Deposits []*DepositData
 }
 
 type ValidatorIncomePerformance struct {
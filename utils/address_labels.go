@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"eth2-exporter/types"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	maxAddressLabelNameLength = 100
+	maxAddressLabelTags       = 10
+	maxAddressLabelTagLength  = 30
+)
+
+type addressLabelRow struct {
+	Address    string   `json:"address"`
+	Name       string   `json:"name"`
+	Category   string   `json:"category"`
+	Tags       []string `json:"tags"`
+	Confidence *float64 `json:"confidence"`
+}
+
+// ParseAddressLabels parses a list of address labels in the csv or json format.
+// Csv lists have the header address,name,category,tags,confidence where tags are separated by semicolons,
+// json lists are an array of objects with the same keys where tags is an array of strings.
+// Only the address and name are required, the confidence defaults to 1.
+func ParseAddressLabels(r io.Reader, format string) ([]*types.AddressLabel, error) {
+	rows := []*addressLabelRow{}
+
+	switch format {
+	case "json":
+		err := json.NewDecoder(r).Decode(&rows)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding address labels: %w", err)
+		}
+	case "csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding address labels: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, errors.New("missing csv header")
+		}
+		columns := map[string]int{}
+		for i, column := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(column))] = i
+		}
+		if _, ok := columns["address"]; !ok {
+			return nil, errors.New("missing address column in csv header")
+		}
+		if _, ok := columns["name"]; !ok {
+			return nil, errors.New("missing name column in csv header")
+		}
+		field := func(record []string, column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		for line, record := range records[1:] {
+			row := &addressLabelRow{
+				Address:  field(record, "address"),
+				Name:     field(record, "name"),
+				Category: field(record, "category"),
+			}
+			if tags := field(record, "tags"); tags != "" {
+				row.Tags = strings.Split(tags, ";")
+			}
+			if confidence := field(record, "confidence"); confidence != "" {
+				value, err := strconv.ParseFloat(confidence, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid confidence %q in line %v", confidence, line+2)
+				}
+				row.Confidence = &value
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported address label format %v, must be csv or json", format)
+	}
+
+	labels := make([]*types.AddressLabel, 0, len(rows))
+	seen := map[string]bool{}
+	for i, row := range rows {
+		label, err := newAddressLabel(row.Address, row.Name, row.Category, row.Tags, row.Confidence)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address label %v: %w", i+1, err)
+		}
+		if seen[string(label.Address)] {
+			return nil, fmt.Errorf("invalid address label %v: duplicate address %v", i+1, row.Address)
+		}
+		seen[string(label.Address)] = true
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// newAddressLabel validates and normalizes the fields of an address label
+func newAddressLabel(address, name, category string, tags []string, confidence *float64) (*types.AddressLabel, error) {
+	if !IsValidEth1Address(address) {
+		return nil, fmt.Errorf("invalid address %q", address)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAddressLabelNameLength {
+		return nil, fmt.Errorf("the name must be between 1 and %d characters long", maxAddressLabelNameLength)
+	}
+
+	label := &types.AddressLabel{
+		Address:    common.HexToAddress(address).Bytes(),
+		Name:       name,
+		Tags:       []string{},
+		Confidence: 1,
+	}
+
+	if category != "" {
+		label.Category = types.AddressLabelCategory(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "_"))
+		valid := false
+		for _, c := range types.AddressLabelCategories {
+			valid = valid || c == label.Category
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid category %q, must be one of %v", category, types.AddressLabelCategories)
+		}
+	}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxAddressLabelTagLength || strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("invalid tag %q, tags must be at most %d characters long and must not contain commas", tag, maxAddressLabelTagLength)
+		}
+		label.Tags = append(label.Tags, tag)
+	}
+	if len(label.Tags) > maxAddressLabelTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxAddressLabelTags)
+	}
+
+	if confidence != nil {
+		if *confidence < 0 || *confidence > 1 {
+			return nil, fmt.Errorf("invalid confidence %v, must be between 0 and 1", *confidence)
+		}
+		label.Confidence = *confidence
+	}
+	return label, nil
+}
+
+// FormatAddressLabels returns the labels of an address as badges, private labels are highlighted and the source and confidence are shown as tooltip
+func FormatAddressLabels(labels []*types.AddressLabel) template.HTML {
+	var sb strings.Builder
+	for _, label := range labels {
+		class := "badge-secondary"
+		if label.Private {
+			class = "badge-info"
+		}
+		title := fmt.Sprintf("Confidence %.0f%%", label.Confidence*100)
+		if label.Source != "" {
+			title = fmt.Sprintf("Source: %s, %s", label.Source, title)
+		}
+		if label.Category != "" {
+			title = fmt.Sprintf("%s, %s", strings.ReplaceAll(string(label.Category), "_", " "), title)
+		}
+		if len(label.Tags) > 0 {
+			title = fmt.Sprintf("%s, tags: %s", title, strings.Join(label.Tags, ", "))
+		}
+		fmt.Fprintf(&sb, `<span class="badge %s text-white mr-1" data-toggle="tooltip" title="%s">%s</span>`, class, html.EscapeString(title), html.EscapeString(label.Name))
+	}
+	return template.HTML(sb.String())
+}
@@ -0,0 +1,60 @@
+package utils
+
+import "eth2-exporter/types"
+
+// AttributeAttestationRewardShortfall computes the totals of the ideal and actual attestation rewards of the analysis and attributes the
+// difference per component to its cause. A missed attestation loses everything, a source penalty of an included attestation means the
+// source vote was included too late and a target penalty means a wrong target vote, which also loses the head reward. A lost head reward
+// of an attestation with a correct target is caused by the inclusion delay if it was not included in the next slot and by a wrong head vote otherwise.
+func AttributeAttestationRewardShortfall(a *types.ApiAttestationRewardAnalysis) {
+	a.Ideal.Total = a.Ideal.Head + a.Ideal.Source + a.Ideal.Target
+	a.Actual.Total = a.Actual.Head + a.Actual.Source + a.Actual.Target
+
+	head := shortfall(a.Ideal.Head, a.Actual.Head)
+	source := shortfall(a.Ideal.Source, a.Actual.Source)
+	target := shortfall(a.Ideal.Target, a.Actual.Target)
+
+	s := types.ApiAttestationRewardShortfall{}
+	switch {
+	case !a.Included:
+		s.Missed = head + source + target
+	case a.Actual.Target < 0:
+		s.WrongTarget = head + target
+		s.LateSource = source
+	default:
+		s.LateSource = source
+		s.WrongTarget = target
+		if a.InclusionDelay > 0 {
+			s.InclusionDelay = head
+		} else {
+			s.WrongHead = head
+		}
+	}
+	s.Total = s.WrongHead + s.WrongTarget + s.LateSource + s.InclusionDelay + s.Missed
+	a.Shortfall = s
+}
+
+// AddAttestationRewardShortfall adds the shortfall b to a
+func AddAttestationRewardShortfall(a *types.ApiAttestationRewardShortfall, b types.ApiAttestationRewardShortfall) {
+	a.WrongHead += b.WrongHead
+	a.WrongTarget += b.WrongTarget
+	a.LateSource += b.LateSource
+	a.InclusionDelay += b.InclusionDelay
+	a.Missed += b.Missed
+	a.Total += b.Total
+}
+
+// AddAttestationRewards adds the attestation rewards b to a
+func AddAttestationRewards(a *types.ApiAttestationRewards, b types.ApiAttestationRewards) {
+	a.Head += b.Head
+	a.Source += b.Source
+	a.Target += b.Target
+	a.Total += b.Total
+}
+
+func shortfall(ideal, actual int64) int64 {
+	if actual >= ideal {
+		return 0
+	}
+	return ideal - actual
+}
@@ -1,11 +1,65 @@
 package utils
 
 import (
+	"eth2-exporter/types"
+	"math/big"
 	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 var ENS_ETH_REGEXP = regexp.MustCompile(`^.{3,}\.eth$`)
 
+// ENS_AVATAR_NFT_REGEXP matches avatar records that point to an nft, e.g. eip155:1/erc721:0xb47e3cd837ddf8e4c57f05d70ab865de6e193bbb/0
+var ENS_AVATAR_NFT_REGEXP = regexp.MustCompile(`^eip155:[0-9]+/(erc721|erc1155):(0x[0-9a-fA-F]{40})/([0-9]+)$`)
+
+// EnsGracePeriod is the time after the expiry of a .eth name during which only its registrant can renew it
+const EnsGracePeriod = time.Hour * 24 * 90
+
+// EnsTextRecordKeys are the resolver text records that are indexed for ens names
+var EnsTextRecordKeys = []string{"url", "com.twitter", "com.github", "description", "avatar"}
+
 func IsValidEnsDomain(text string) bool {
 	return ENS_ETH_REGEXP.MatchString(text)
 }
+
+// EnsNameStatus returns whether a name that expires at validTo is active, in its grace period or expired at now
+func EnsNameStatus(validTo, now time.Time) types.EnsNameStatus {
+	if now.Before(validTo) {
+		return types.EnsNameStatusActive
+	}
+	if now.Before(validTo.Add(EnsGracePeriod)) {
+		return types.EnsNameStatusGracePeriod
+	}
+	return types.EnsNameStatusExpired
+}
+
+// ParseEnsAvatarNft returns the nft standard, the contract and the token id of an avatar record that points to an nft
+func ParseEnsAvatarNft(avatar string) (standard string, token common.Address, tokenId *big.Int, ok bool) {
+	matches := ENS_AVATAR_NFT_REGEXP.FindStringSubmatch(strings.TrimSpace(avatar))
+	if matches == nil {
+		return "", common.Address{}, nil, false
+	}
+	tokenId, ok = new(big.Int).SetString(matches[3], 10)
+	if !ok {
+		return "", common.Address{}, nil, false
+	}
+	standard = types.NftStandardERC721
+	if matches[1] == "erc1155" {
+		standard = types.NftStandardERC1155
+	}
+	return standard, common.HexToAddress(matches[2]), tokenId, true
+}
+
+// EnsAvatarUrl returns the image url of an avatar record that is an http(s), ipfs or ipns uri, other records return an empty string.
+// Avatars that point to an nft have to be resolved via the metadata of the nft, see ParseEnsAvatarNft.
+func EnsAvatarUrl(avatar, ipfsGateway string) string {
+	avatar = strings.TrimSpace(avatar)
+	resolved := ResolveNftUri(avatar, ipfsGateway)
+	if strings.HasPrefix(resolved, "https://") || strings.HasPrefix(resolved, "http://") {
+		return resolved
+	}
+	return ""
+}
@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/hex"
+	"eth2-exporter/types"
 	"fmt"
+	"strings"
 
 	"github.com/attestantio/go-eth2-client/spec/capella"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/prysmaticlabs/go-ssz"
+	prysm_deposit "github.com/prysmaticlabs/prysm/v3/contracts/deposit"
+	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
 	"github.com/sirupsen/logrus"
 	e2types "github.com/wealdtech/go-eth2-types/v2"
 )
@@ -128,6 +134,158 @@ func VerifyVoluntaryExitSignature(op *phase0.SignedVoluntaryExit, forkVersion, p
 	return nil
 }
 
+// ValidateDepositData checks a deposit_data entry as generated by the staking-deposit-cli against the deposit domain of the configured chain.
+// It returns a list of all problems found, an empty list means the deposit will be accepted by the beacon chain.
+func ValidateDepositData(d *types.DepositData) []string {
+	problems := []string{}
+
+	if !strings.EqualFold(strings.TrimPrefix(d.ForkVersion, "0x"), strings.TrimPrefix(Config.Chain.Config.GenesisForkVersion, "0x")) {
+		problems = append(problems, fmt.Sprintf("fork_version %v does not match the genesis fork version %v of %v", d.ForkVersion, Config.Chain.Config.GenesisForkVersion, Config.Chain.Name))
+	}
+
+	pubkey, err := hex.DecodeString(strings.TrimPrefix(d.Pubkey, "0x"))
+	if err != nil || len(pubkey) != 48 {
+		problems = append(problems, "invalid pubkey")
+	}
+	withdrawalCredentials, err := hex.DecodeString(strings.TrimPrefix(d.WithdrawalCredentials, "0x"))
+	if err != nil || len(withdrawalCredentials) != 32 {
+		problems = append(problems, "invalid withdrawal_credentials")
+	} else if withdrawalCredentials[0] != 0x00 && withdrawalCredentials[0] != 0x01 {
+		problems = append(problems, fmt.Sprintf("unknown withdrawal_credentials prefix %#x", withdrawalCredentials[0]))
+	} else if withdrawalCredentials[0] == 0x01 && !bytes.Equal(withdrawalCredentials[1:12], make([]byte, 11)) {
+		problems = append(problems, "0x01 withdrawal_credentials must be padded with zeros")
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(d.Signature, "0x"))
+	if err != nil || len(signature) != 96 {
+		problems = append(problems, "invalid signature")
+	}
+	if d.Amount < Config.Chain.Config.MinDepositAmount {
+		problems = append(problems, fmt.Sprintf("amount %v is below the minimum deposit amount of %v Gwei", d.Amount, Config.Chain.Config.MinDepositAmount))
+	}
+	if d.Amount > Config.Chain.Config.MaxEffectiveBalance {
+		problems = append(problems, fmt.Sprintf("amount %v exceeds the maximum effective balance of %v Gwei", d.Amount, Config.Chain.Config.MaxEffectiveBalance))
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	depositMessageRoot, err := (&ethpb.DepositMessage{PublicKey: pubkey, WithdrawalCredentials: withdrawalCredentials, Amount: d.Amount}).HashTreeRoot()
+	if err != nil {
+		return append(problems, fmt.Sprintf("can not compute deposit_message_root: %v", err))
+	}
+	if !strings.EqualFold(strings.TrimPrefix(d.DepositMessageRoot, "0x"), hex.EncodeToString(depositMessageRoot[:])) {
+		problems = append(problems, "deposit_message_root does not match the deposit message")
+	}
+
+	depositData := &ethpb.Deposit_Data{PublicKey: pubkey, WithdrawalCredentials: withdrawalCredentials, Amount: d.Amount, Signature: signature}
+	depositDataRoot, err := depositData.HashTreeRoot()
+	if err != nil {
+		return append(problems, fmt.Sprintf("can not compute deposit_data_root: %v", err))
+	}
+	if !strings.EqualFold(strings.TrimPrefix(d.DepositDataRoot, "0x"), hex.EncodeToString(depositDataRoot[:])) {
+		problems = append(problems, "deposit_data_root does not match the deposit data")
+	}
+
+	domain, err := GetSigningDomain()
+	if err != nil {
+		return append(problems, fmt.Sprintf("can not compute deposit domain: %v", err))
+	}
+	err = prysm_deposit.VerifyDepositSignature(depositData, domain)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("signature does not verify against the deposit domain: %v", err))
+	}
+
+	return problems
+}
+
 func FixAddressCasing(add string) string {
 	return common.HexToAddress(add).Hex()
 }
+
+// GetValidatorChurnLimit returns the number of validators that can enter or leave the active set per epoch (get_validator_churn_limit)
+func GetValidatorChurnLimit(activeValidatorCount uint64) uint64 {
+	churnLimit := Config.Chain.Config.MinPerEpochChurnLimit
+	if Config.Chain.Config.ChurnLimitQuotient > 0 && activeValidatorCount/Config.Chain.Config.ChurnLimitQuotient > churnLimit {
+		churnLimit = activeValidatorCount / Config.Chain.Config.ChurnLimitQuotient
+	}
+	return churnLimit
+}
+
+// GetValidatorActivationChurnLimit returns the number of validators that can be activated per epoch, since deneb this is capped at MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT
+func GetValidatorActivationChurnLimit(activeValidatorCount, epoch uint64) uint64 {
+	churnLimit := GetValidatorChurnLimit(activeValidatorCount)
+	if epoch >= Config.Chain.Config.DenebForkEpoch && Config.Chain.Config.MaxPerEpochActivationChurnLimit > 0 && churnLimit > Config.Chain.Config.MaxPerEpochActivationChurnLimit {
+		churnLimit = Config.Chain.Config.MaxPerEpochActivationChurnLimit
+	}
+	return churnLimit
+}
+
+// ComputeActivationExitEpoch returns the epoch during which validator activations and exits initiated in epoch take effect
+func ComputeActivationExitEpoch(epoch uint64) uint64 {
+	return epoch + 1 + Config.Chain.Config.MaxSeedLookahead
+}
+
+// ProjectExitEpochs returns the exit epochs of count validators that initiate their exit in the current epoch of the given queue state, in the
+// order they are processed, following initiate_validator_exit. The churn limit is assumed to stay constant.
+func ProjectExitEpochs(state *types.ChurnQueueState, count uint64) []uint64 {
+	churnLimit := GetValidatorChurnLimit(state.ActiveValidatorCount)
+	exitQueueEpoch := ComputeActivationExitEpoch(state.Epoch)
+	exitQueueChurn := uint64(0)
+	if state.ExitQueueEpoch >= exitQueueEpoch {
+		exitQueueEpoch = state.ExitQueueEpoch
+		exitQueueChurn = state.ExitQueueChurn
+	}
+
+	exitEpochs := make([]uint64, 0, count)
+	for i := uint64(0); i < count; i++ {
+		if exitQueueChurn >= churnLimit {
+			exitQueueEpoch++
+			exitQueueChurn = 0
+		}
+		exitEpochs = append(exitEpochs, exitQueueEpoch)
+		exitQueueChurn++
+	}
+	return exitEpochs
+}
+
+// ProjectExitEpochRange returns the exit epochs of the first and the last of count validators that initiate their exit in the current epoch of the
+// given queue state, it is equivalent to the first and last element of ProjectExitEpochs without projecting the validators in between
+func ProjectExitEpochRange(state *types.ChurnQueueState, count uint64) (first, last uint64) {
+	churnLimit := GetValidatorChurnLimit(state.ActiveValidatorCount)
+	if churnLimit == 0 {
+		churnLimit = 1
+	}
+	exitQueueEpoch := ComputeActivationExitEpoch(state.Epoch)
+	exitQueueChurn := uint64(0)
+	if state.ExitQueueEpoch >= exitQueueEpoch {
+		exitQueueEpoch = state.ExitQueueEpoch
+		exitQueueChurn = state.ExitQueueChurn
+	}
+	if exitQueueChurn >= churnLimit {
+		exitQueueEpoch++
+		exitQueueChurn = 0
+	}
+	if count == 0 {
+		return exitQueueEpoch, exitQueueEpoch
+	}
+	return exitQueueEpoch, exitQueueEpoch + (exitQueueChurn+count-1)/churnLimit
+}
+
+// ProjectActivationEpoch returns the epoch in which a validator with queuePosition validators ahead of it in the activation queue will be activated
+func ProjectActivationEpoch(state *types.ChurnQueueState, queuePosition uint64) uint64 {
+	epoch := state.Epoch
+	remaining := queuePosition
+	for {
+		churnLimit := GetValidatorActivationChurnLimit(state.ActiveValidatorCount, epoch)
+		if churnLimit == 0 {
+			churnLimit = 1
+		}
+		if remaining < churnLimit {
+			break
+		}
+		remaining -= churnLimit
+		epoch++
+	}
+	// the validator is dequeued in epoch and activated after the seed lookahead
+	return ComputeActivationExitEpoch(epoch)
+}
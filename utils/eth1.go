@@ -44,6 +44,105 @@ func Eth1TotalReward(block *types.Eth1BlockIndexed) *big.Int {
 	return totalReward.Add(totalReward, uncleReward)
 }
 
+// Eth1NativeBalanceDeltas returns the net change of the native balance of every address whose balance is changed by a block:
+// value transfers of transactions and internal transactions, gas fees paid by senders, priority fees, block and uncle rewards
+// received by miners and withdrawals. Value transfers of failed transactions and of reverted internal calls are skipped, the latter
+// requires the error of the internal calls which is only stored for blocks that were exported after it was added.
+// Addresses whose balance changes add up to zero are omitted.
+func Eth1NativeBalanceDeltas(block *types.Eth1Block) map[common.Address]*big.Int {
+	deltas := make(map[common.Address]*big.Int)
+	add := func(address []byte, value *big.Int) {
+		if len(address) == 0 || value.Sign() == 0 {
+			return
+		}
+		a := common.BytesToAddress(address)
+		if deltas[a] == nil {
+			deltas[a] = new(big.Int)
+		}
+		deltas[a].Add(deltas[a], value)
+	}
+
+	baseFee := new(big.Int).SetBytes(block.GetBaseFee())
+	for _, tx := range block.GetTransactions() {
+		gasUsed := new(big.Int).SetUint64(tx.GetGasUsed())
+		gasPrice := new(big.Int).SetBytes(tx.GetGasPrice())
+		minerGasPrice := gasPrice
+		if len(block.GetBaseFee()) > 0 {
+			gasPrice = new(big.Int).Add(new(big.Int).SetBytes(tx.GetMaxPriorityFeePerGas()), baseFee)
+			if maxFee := new(big.Int).SetBytes(tx.GetMaxFeePerGas()); gasPrice.Cmp(maxFee) > 0 {
+				gasPrice = maxFee
+			}
+			minerGasPrice = new(big.Int).Sub(gasPrice, baseFee)
+		}
+		add(tx.GetFrom(), new(big.Int).Neg(new(big.Int).Mul(gasPrice, gasUsed)))
+		add(block.GetCoinbase(), new(big.Int).Mul(minerGasPrice, gasUsed))
+
+		if tx.GetStatus() != 1 {
+			continue
+		}
+		if len(tx.GetItx()) == 0 {
+			to := tx.GetTo()
+			if len(to) == 0 {
+				to = tx.GetContractAddress()
+			}
+			value := new(big.Int).SetBytes(tx.GetValue())
+			add(tx.GetFrom(), new(big.Int).Neg(value))
+			add(to, value)
+			continue
+		}
+
+		// the top level call is part of the internal transactions, calls below a reverted call are reverted as well,
+		// their trace address starts with the trace address of the reverted call, e.g. [0 1] is below [0]
+		reverted := []string{}
+		for _, itx := range tx.GetItx() {
+			isReverted := itx.GetErrorMsg() != ""
+			if strings.HasPrefix(itx.GetPath(), "[") {
+				path := strings.Trim(itx.GetPath(), "[]")
+				for _, r := range reverted {
+					if r == "" || strings.HasPrefix(path, r+" ") {
+						isReverted = true
+						break
+					}
+				}
+				if itx.GetErrorMsg() != "" {
+					reverted = append(reverted, path)
+				}
+			}
+			if isReverted || itx.GetType() == "delegatecall" || itx.GetType() == "staticcall" {
+				continue
+			}
+			value := new(big.Int).SetBytes(itx.GetValue())
+			add(itx.GetFrom(), new(big.Int).Neg(value))
+			add(itx.GetTo(), value)
+		}
+	}
+
+	blockReward := Eth1BlockReward(block.GetNumber(), block.GetDifficulty())
+	if blockReward.Sign() > 0 {
+		add(block.GetCoinbase(), blockReward)
+		for _, uncle := range block.GetUncles() {
+			// the miner of an uncle receives (uncleNumber + 8 - blockNumber) / 8 of the block reward, the miner of the block 1/32 per included uncle
+			uncleReward := new(big.Int).SetUint64(uncle.GetNumber() + 8 - block.GetNumber())
+			uncleReward.Mul(uncleReward, blockReward)
+			uncleReward.Div(uncleReward, big.NewInt(8))
+			add(uncle.GetCoinbase(), uncleReward)
+			add(block.GetCoinbase(), new(big.Int).Div(blockReward, big.NewInt(32)))
+		}
+	}
+
+	for _, withdrawal := range block.GetWithdrawals() {
+		// withdrawal amounts are denominated in gwei
+		add(withdrawal.GetAddress(), new(big.Int).Mul(new(big.Int).SetBytes(withdrawal.GetAmount()), big.NewInt(1e9)))
+	}
+
+	for address, delta := range deltas {
+		if delta.Sign() == 0 {
+			delete(deltas, address)
+		}
+	}
+	return deltas
+}
+
 func StripPrefix(hexStr string) string {
 	return strings.Replace(hexStr, "0x", "", 1)
 }
@@ -3,10 +3,15 @@ package utils
 import (
 	"encoding/json"
 	"eth2-exporter/types"
+	"fmt"
+	"math"
 	"testing"
 
 	capella "github.com/attestantio/go-eth2-client/spec/capella"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	prysm_deposit "github.com/prysmaticlabs/prysm/v3/contracts/deposit"
+	"github.com/prysmaticlabs/prysm/v3/crypto/bls"
+	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
 )
 
 /*
@@ -93,3 +98,125 @@ func TestVerifyVoluntaryExitSignature(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateDepositData(t *testing.T) {
+	Config = &types.Config{}
+	ReadConfig(Config, "")
+	Config.Chain.Config.GenesisForkVersion = "0x00000000"
+	Config.Chain.Config.MinDepositAmount = 1e9
+	Config.Chain.Config.MaxEffectiveBalance = 32e9
+
+	key, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dd, depositDataRoot, err := prysm_deposit.DepositInput(key, key, 32e9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depositMessageRoot, err := (&ethpb.DepositMessage{PublicKey: dd.PublicKey, WithdrawalCredentials: dd.WithdrawalCredentials, Amount: dd.Amount}).HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := types.DepositData{
+		Pubkey:                fmt.Sprintf("%x", dd.PublicKey),
+		WithdrawalCredentials: fmt.Sprintf("%x", dd.WithdrawalCredentials),
+		Amount:                dd.Amount,
+		Signature:             fmt.Sprintf("%x", dd.Signature),
+		DepositMessageRoot:    fmt.Sprintf("%x", depositMessageRoot),
+		DepositDataRoot:       fmt.Sprintf("%x", depositDataRoot),
+		ForkVersion:           "00000000",
+	}
+
+	wrongForkVersion := valid
+	wrongForkVersion.ForkVersion = "00001020"
+	wrongAmount := valid
+	wrongAmount.Amount = 16e9
+	wrongRoot := valid
+	wrongRoot.DepositDataRoot = valid.DepositMessageRoot
+
+	tests := []struct {
+		Name  string
+		Data  types.DepositData
+		Valid bool
+	}{
+		{"valid", valid, true},
+		{"wrong fork version", wrongForkVersion, false},
+		{"wrong amount", wrongAmount, false},
+		{"wrong deposit data root", wrongRoot, false},
+	}
+	for _, tt := range tests {
+		problems := ValidateDepositData(&tt.Data)
+		if (len(problems) == 0) != tt.Valid {
+			t.Errorf("%v: expected valid = %v, got problems: %v", tt.Name, tt.Valid, problems)
+		}
+	}
+}
+
+func TestProjectExitEpochs(t *testing.T) {
+	Config = &types.Config{}
+	Config.Chain.Config.MinPerEpochChurnLimit = 4
+	Config.Chain.Config.ChurnLimitQuotient = 65536
+	Config.Chain.Config.MaxSeedLookahead = 4
+
+	// 655360 active validators result in a churn limit of 10
+	state := &types.ChurnQueueState{Epoch: 100, ActiveValidatorCount: 655360, ExitQueueEpoch: 110, ExitQueueChurn: 8}
+	exitEpochs := ProjectExitEpochs(state, 13)
+	if exitEpochs[0] != 110 || exitEpochs[1] != 110 || exitEpochs[2] != 111 || exitEpochs[11] != 111 || exitEpochs[12] != 112 {
+		t.Errorf("unexpected exit epochs: %v", exitEpochs)
+	}
+
+	// an empty exit queue starts at the activation exit epoch
+	state = &types.ChurnQueueState{Epoch: 100, ActiveValidatorCount: 1000}
+	exitEpochs = ProjectExitEpochs(state, 5)
+	if exitEpochs[0] != 105 || exitEpochs[3] != 105 || exitEpochs[4] != 106 {
+		t.Errorf("unexpected exit epochs: %v", exitEpochs)
+	}
+}
+
+func TestProjectExitEpochRange(t *testing.T) {
+	Config = &types.Config{}
+	Config.Chain.Config.MinPerEpochChurnLimit = 4
+	Config.Chain.Config.ChurnLimitQuotient = 65536
+	Config.Chain.Config.MaxSeedLookahead = 4
+
+	states := []*types.ChurnQueueState{
+		{Epoch: 100, ActiveValidatorCount: 655360, ExitQueueEpoch: 110, ExitQueueChurn: 8},
+		{Epoch: 100, ActiveValidatorCount: 655360, ExitQueueEpoch: 110, ExitQueueChurn: 10},
+		{Epoch: 100, ActiveValidatorCount: 655360, ExitQueueEpoch: 102, ExitQueueChurn: 3},
+		{Epoch: 100, ActiveValidatorCount: 1000},
+	}
+	for _, state := range states {
+		for _, count := range []uint64{1, 2, 3, 10, 11, 12, 25} {
+			exitEpochs := ProjectExitEpochs(state, count)
+			first, last := ProjectExitEpochRange(state, count)
+			if first != exitEpochs[0] || last != exitEpochs[len(exitEpochs)-1] {
+				t.Errorf("state %+v, count %v: expected exit epochs %v - %v, got %v - %v", *state, count, exitEpochs[0], exitEpochs[len(exitEpochs)-1], first, last)
+			}
+		}
+	}
+}
+
+func TestGetValidatorActivationChurnLimit(t *testing.T) {
+	Config = &types.Config{}
+	Config.Chain.Config.MinPerEpochChurnLimit = 4
+	Config.Chain.Config.ChurnLimitQuotient = 65536
+	Config.Chain.Config.MaxPerEpochActivationChurnLimit = 8
+
+	// an unscheduled deneb fork uses the max epoch, the activation churn is not capped
+	Config.Chain.Config.DenebForkEpoch = math.MaxUint64
+	if churnLimit := GetValidatorActivationChurnLimit(655360, 100); churnLimit != 10 {
+		t.Errorf("unexpected activation churn limit without deneb: %v", churnLimit)
+	}
+
+	Config.Chain.Config.DenebForkEpoch = 200
+	if churnLimit := GetValidatorActivationChurnLimit(655360, 100); churnLimit != 10 {
+		t.Errorf("unexpected activation churn limit before deneb: %v", churnLimit)
+	}
+	if churnLimit := GetValidatorActivationChurnLimit(655360, 200); churnLimit != 8 {
+		t.Errorf("unexpected activation churn limit after deneb: %v", churnLimit)
+	}
+	if churnLimit := GetValidatorChurnLimit(655360); churnLimit != 10 {
+		t.Errorf("unexpected exit churn limit after deneb: %v", churnLimit)
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// CalendarEvent is an event of an iCalendar feed
+type CalendarEvent struct {
+	// UID identifies the event across updates of the feed
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	URL         string
+}
+
+const icalTimeFormat = "20060102T150405Z"
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// ICalendar returns an iCalendar (RFC 5545) feed named name that contains the events, the feed is published by producer and now is used as time stamp of the events
+func ICalendar(producer, name string, events []CalendarEvent, now time.Time) []byte {
+	buf := &bytes.Buffer{}
+	writeICalLine(buf, "BEGIN:VCALENDAR")
+	writeICalLine(buf, "VERSION:2.0")
+	writeICalLine(buf, "PRODID:-//"+icalEscaper.Replace(producer)+"//EN")
+	writeICalLine(buf, "CALSCALE:GREGORIAN")
+	writeICalLine(buf, "METHOD:PUBLISH")
+	writeICalLine(buf, "X-WR-CALNAME:"+icalEscaper.Replace(name))
+	for _, event := range events {
+		writeICalLine(buf, "BEGIN:VEVENT")
+		writeICalLine(buf, "UID:"+icalEscaper.Replace(event.UID))
+		writeICalLine(buf, "DTSTAMP:"+now.UTC().Format(icalTimeFormat))
+		writeICalLine(buf, "DTSTART:"+event.Start.UTC().Format(icalTimeFormat))
+		writeICalLine(buf, "DTEND:"+event.End.UTC().Format(icalTimeFormat))
+		writeICalLine(buf, "SUMMARY:"+icalEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			writeICalLine(buf, "DESCRIPTION:"+icalEscaper.Replace(event.Description))
+		}
+		if event.URL != "" {
+			writeICalLine(buf, "URL:"+event.URL)
+		}
+		writeICalLine(buf, "END:VEVENT")
+	}
+	writeICalLine(buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writeICalLine writes a content line terminated by CRLF, lines longer than 75 octets are folded without splitting utf-8 characters
+func writeICalLine(buf *bytes.Buffer, line string) {
+	// continuation lines start with a space which counts towards their length
+	limit := 75
+	for len(line) > limit {
+		i := limit
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+		buf.WriteString(line[:i])
+		buf.WriteString("\r\n ")
+		line = line[i:]
+		limit = 74
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
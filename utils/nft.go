@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"eth2-exporter/types"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// NftMetadataFetcher retrieves the metadata json an nft points to by its metadata uri
+type NftMetadataFetcher interface {
+	FetchNftMetadata(ctx context.Context, uri string) (*types.NftMetadata, error)
+}
+
+// HttpNftMetadataFetcher fetches nft metadata from http(s) uris, ipfs:// and ipns:// uris are fetched through an ipfs gateway and data: uris are decoded without a request
+type HttpNftMetadataFetcher struct {
+	Client      *http.Client
+	IpfsGateway string
+}
+
+// NewHttpNftMetadataFetcher returns a fetcher whose client refuses to connect to private and loopback addresses, as metadata uris are set by arbitrary contracts
+func NewHttpNftMetadataFetcher(ipfsGateway string, timeout time.Duration) *HttpNftMetadataFetcher {
+	return &HttpNftMetadataFetcher{
+		Client:      NewPublicHttpClient(timeout),
+		IpfsGateway: ipfsGateway,
+	}
+}
+
+// NewPublicHttpClient returns a http client that refuses to connect to private, loopback and other non global unicast addresses.
+// It is meant for requests to urls that are set by users or contracts.
+func NewPublicHttpClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return fmt.Errorf("refusing to connect to non public address %v", address)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+}
+
+func (fetcher *HttpNftMetadataFetcher) FetchNftMetadata(ctx context.Context, uri string) (*types.NftMetadata, error) {
+	var body []byte
+	var err error
+	if strings.HasPrefix(uri, "data:") {
+		body, err = decodeDataUri(uri)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		resolved := ResolveNftUri(uri, fetcher.IpfsGateway)
+		if !strings.HasPrefix(resolved, "https://") && !strings.HasPrefix(resolved, "http://") {
+			return nil, fmt.Errorf("unsupported nft metadata uri %v", uri)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, resolved, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := fetcher.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error fetching nft metadata from %v: unexpected status code %v", resolved, res.StatusCode)
+		}
+		body, err = io.ReadAll(io.LimitReader(res.Body, 1<<20))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata := &types.NftMetadata{}
+	err = json.Unmarshal(body, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding nft metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// ResolveNftUri returns the gateway url of ipfs:// and ipns:// uris, other uris are returned unchanged
+func ResolveNftUri(uri string, ipfsGateway string) string {
+	gateway := strings.TrimSuffix(ipfsGateway, "/")
+	for _, scheme := range []string{"ipfs", "ipns"} {
+		if strings.HasPrefix(uri, scheme+"://") {
+			path := strings.TrimPrefix(strings.TrimPrefix(uri, scheme+"://"), scheme+"/")
+			return fmt.Sprintf("%s/%s/%s", gateway, scheme, path)
+		}
+	}
+	return uri
+}
+
+// NftTokenUri substitutes the {id} placeholder of an ERC-1155 uri with the lowercase, zero padded hex encoding of the token id
+func NftTokenUri(uri string, tokenId *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", tokenId))
+}
+
+// decodeDataUri returns the data of a data:[<mediatype>][;base64],<data> uri
+func decodeDataUri(uri string) ([]byte, error) {
+	header, data, found := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !found {
+		return nil, fmt.Errorf("invalid data uri")
+	}
+	if strings.HasSuffix(header, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	decoded, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decoded), nil
+}
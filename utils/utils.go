@@ -3,6 +3,9 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
 	securerand "crypto/rand"
 	"crypto/sha256"
 	"database/sql"
@@ -127,6 +130,7 @@ func GetTemplateFuncs() template.FuncMap {
 		"formatValidatorName":                     FormatValidatorName,
 		"formatAttestationInclusionEffectiveness": FormatAttestationInclusionEffectiveness,
 		"formatValidatorTags":                     FormatValidatorTags,
+		"formatAddressLabels":                     FormatAddressLabels,
 		"formatValidatorTag":                      FormatValidatorTag,
 		"formatRPL":                               FormatRPL,
 		"formatETH":                               FormatETH,
@@ -482,6 +486,34 @@ func ReadConfig(cfg *types.Config, path string) error {
 		cfg.Chain.DomainVoluntaryExit = "0x04000000"
 	}
 
+	if cfg.Frontend.NftMetadata.IpfsGateway == "" {
+		cfg.Frontend.NftMetadata.IpfsGateway = "https://ipfs.io/"
+	}
+	if cfg.Frontend.NftMetadata.Timeout == 0 {
+		cfg.Frontend.NftMetadata.Timeout = time.Second * 5
+	}
+
+	if len(cfg.Price.Currencies) == 0 {
+		cfg.Price.Currencies = []string{"USD", "EUR", "GBP", "CNY", "RUB", "CAD", "AUD", "JPY"}
+	}
+	for i, currency := range cfg.Price.Currencies {
+		cfg.Price.Currencies[i] = strings.ToUpper(strings.TrimSpace(currency))
+	}
+	if cfg.Price.MaxAge == 0 {
+		cfg.Price.MaxAge = time.Hour
+	}
+
+	// the hourly aggregates are computed from the epoch rows, so they have to be kept for at least two hours
+	if cfg.RollingValidatorStats.EpochWindow == 0 {
+		cfg.RollingValidatorStats.EpochWindow = time.Hour * 24
+	} else if cfg.RollingValidatorStats.EpochWindow < time.Hour*2 {
+		logrus.Warnf("rolling validator stats epoch window %v is below the minimum, using %v", cfg.RollingValidatorStats.EpochWindow, time.Hour*2)
+		cfg.RollingValidatorStats.EpochWindow = time.Hour * 2
+	}
+	if cfg.RollingValidatorStats.HourlyWindow == 0 {
+		cfg.RollingValidatorStats.HourlyWindow = time.Hour * 24 * 30
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"genesisTimestamp":       cfg.Chain.GenesisTimestamp,
 		"genesisValidatorsRoot":  cfg.Chain.GenesisValidatorsRoot,
@@ -969,6 +1001,50 @@ func getABIFromEtherscan(address []byte) (*types.ContractMetadata, error) {
 	meta.ABIJson = []byte(data.Result[0].Abi)
 	meta.ABI = &contractAbi
 	meta.Name = data.Result[0].ContractName
+	meta.Source = types.ContractMetadataSourceEtherscan
+	return meta, nil
+}
+
+// ParseContractMetadata parses a submitted contract ABI, data is either the JSON ABI or the metadata JSON of a contract as
+// published by the Solidity compiler and Sourcify. The name of the contract is taken from the compilation target of the metadata.
+func ParseContractMetadata(data []byte) (*types.ContractMetadata, error) {
+	data = bytes.TrimSpace(data)
+	meta := &types.ContractMetadata{
+		ABIJson: data,
+		Source:  types.ContractMetadataSourceAbi,
+	}
+
+	if bytes.HasPrefix(data, []byte("{")) {
+		metadata := &struct {
+			Output struct {
+				Abi json.RawMessage `json:"abi"`
+			} `json:"output"`
+			Settings struct {
+				CompilationTarget map[string]string `json:"compilationTarget"`
+			} `json:"settings"`
+		}{}
+		err := json.Unmarshal(data, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract metadata: %w", err)
+		}
+		if len(metadata.Output.Abi) == 0 {
+			return nil, fmt.Errorf("contract metadata does not contain an abi")
+		}
+		meta.ABIJson = metadata.Output.Abi
+		meta.Source = types.ContractMetadataSourceSourcify
+		for _, name := range metadata.Settings.CompilationTarget {
+			meta.Name = name
+		}
+	}
+
+	contractAbi, err := abi.JSON(bytes.NewReader(meta.ABIJson))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi: %w", err)
+	}
+	if len(contractAbi.Methods) == 0 && len(contractAbi.Events) == 0 {
+		return nil, fmt.Errorf("abi does not contain any function or event")
+	}
+	meta.ABI = &contractAbi
 	return meta, nil
 }
 
@@ -1390,3 +1466,65 @@ func SortedUniqueUint64(arr []uint64) []uint64 {
 
 	return result
 }
+
+// PercentileRank returns the percentile rank (0 to 100) of value within a distribution that is described by its evenly spaced, ascending
+// percentiles, e.g. the 0th to 100th percentile. Values between two percentiles are interpolated linearly.
+func PercentileRank(percentiles []float64, value float64) float64 {
+	if len(percentiles) < 2 || value < percentiles[0] {
+		return 0
+	}
+	last := len(percentiles) - 1
+	if value >= percentiles[last] {
+		return 100
+	}
+
+	// i is the last percentile that is less than or equal to value
+	i := sort.Search(len(percentiles), func(i int) bool { return percentiles[i] > value }) - 1
+	rank := float64(i)
+	if percentiles[i+1] > percentiles[i] {
+		rank += (value - percentiles[i]) / (percentiles[i+1] - percentiles[i])
+	}
+	return rank / float64(last) * 100
+}
+
+// AesGcmEncrypt encrypts the plaintext with the given 16, 24 or 32 byte key, the random nonce is prepended to the returned ciphertext
+func AesGcmEncrypt(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = securerand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// AesGcmDecrypt decrypts a ciphertext created by AesGcmEncrypt
+func AesGcmDecrypt(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// HmacSha256Hex returns the hex encoded HMAC-SHA256 of msg
+func HmacSha256Hex(key, msg []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,7 +1,14 @@
 package utils
 
 import (
+	"context"
+	"eth2-exporter/types"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestIsValidUrl(t *testing.T) {
@@ -26,3 +33,281 @@ func TestIsValidUrl(t *testing.T) {
 		}
 	}
 }
+
+func TestPercentileRank(t *testing.T) {
+	percentiles := []float64{1, 2, 3, 3, 5}
+	tests := []struct {
+		value float64
+		rank  float64
+	}{
+		{0, 0},
+		{1, 0},
+		{1.5, 12.5},
+		{2, 25},
+		{3, 75},
+		{4, 87.5},
+		{5, 100},
+		{6, 100},
+	}
+	for _, tt := range tests {
+		rank := PercentileRank(percentiles, tt.value)
+		if rank != tt.rank {
+			t.Errorf("wrong percentile rank for value %v: got %v, want %v", tt.value, rank, tt.rank)
+		}
+	}
+	if rank := PercentileRank(nil, 1); rank != 0 {
+		t.Errorf("wrong percentile rank for empty distribution: got %v, want 0", rank)
+	}
+}
+
+func TestParseContractMetadata(t *testing.T) {
+	abiJson := `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"}]`
+
+	meta, err := ParseContractMetadata([]byte(abiJson))
+	if err != nil {
+		t.Fatalf("error parsing abi: %v", err)
+	}
+	if meta.Source != "abi" || meta.ABI.Methods["transfer"].Sig != "transfer(address,uint256)" {
+		t.Errorf("wrong metadata for abi: source %v, methods %v", meta.Source, meta.ABI.Methods)
+	}
+
+	meta, err = ParseContractMetadata([]byte(`{"output":{"abi":` + abiJson + `},"settings":{"compilationTarget":{"contracts/Token.sol":"Token"}}}`))
+	if err != nil {
+		t.Fatalf("error parsing contract metadata: %v", err)
+	}
+	if meta.Source != "sourcify" || meta.Name != "Token" || len(meta.ABI.Methods) != 1 {
+		t.Errorf("wrong metadata for contract metadata: source %v, name %v, methods %v", meta.Source, meta.Name, meta.ABI.Methods)
+	}
+
+	for _, invalid := range []string{``, `[]`, `{"output":{}}`, `[{"type":"function","name":`} {
+		if _, err := ParseContractMetadata([]byte(invalid)); err == nil {
+			t.Errorf("expected error parsing %q", invalid)
+		}
+	}
+}
+
+func TestHttpNftMetadataFetcher(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/QmTest/1.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"name":"Token #1","image":"ipfs://QmImage/1.png","attributes":[{"trait_type":"Color","value":"blue"}]}`))
+	}))
+	defer gateway.Close()
+
+	fetcher := &HttpNftMetadataFetcher{Client: gateway.Client(), IpfsGateway: gateway.URL}
+	tests := []struct {
+		uri  string
+		name string
+		err  bool
+	}{
+		{"ipfs://QmTest/1.json", "Token #1", false},
+		{"ipfs://ipfs/QmTest/1.json", "Token #1", false},
+		{gateway.URL + "/ipfs/QmTest/1.json", "Token #1", false},
+		{gateway.URL + "/ipfs/QmTest/2.json", "", true},
+		{"data:application/json;base64,eyJuYW1lIjoiT24gQ2hhaW4ifQ==", "On Chain", false},
+		{`data:application/json;utf8,{"name":"Plain%20Text"}`, "Plain Text", false},
+		{"ftp://example.com/1.json", "", true},
+	}
+	for _, tt := range tests {
+		metadata, err := fetcher.FetchNftMetadata(context.Background(), tt.uri)
+		if tt.err {
+			if err == nil {
+				t.Errorf("expected an error fetching nft metadata from %v", tt.uri)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("error fetching nft metadata from %v: %v", tt.uri, err)
+			continue
+		}
+		if metadata.Name != tt.name {
+			t.Errorf("wrong nft metadata name for %v: got %v, want %v", tt.uri, metadata.Name, tt.name)
+		}
+	}
+
+	if uri := ResolveNftUri("ipfs://QmImage/1.png", "https://ipfs.io/"); uri != "https://ipfs.io/ipfs/QmImage/1.png" {
+		t.Errorf("wrong resolved nft uri: %v", uri)
+	}
+	if uri := NftTokenUri("https://example.com/{id}.json", big.NewInt(314)); uri != "https://example.com/000000000000000000000000000000000000000000000000000000000000013a.json" {
+		t.Errorf("wrong nft token uri: %v", uri)
+	}
+}
+
+func TestEth1NativeBalanceDeltas(t *testing.T) {
+	sender := []byte{1}
+	receiver := []byte{2}
+	coinbase := []byte{3}
+	withdrawer := []byte{4}
+	wei := func(v int64) []byte { return big.NewInt(v).Bytes() }
+
+	block := &types.Eth1Block{
+		Coinbase: coinbase,
+		BaseFee:  wei(10),
+		Transactions: []*types.Eth1Transaction{
+			// plain transfer, pays (2 + 10) * 21000 for gas
+			{From: sender, To: receiver, Value: wei(1000), GasUsed: 21000, MaxPriorityFeePerGas: wei(2), MaxFeePerGas: wei(100), Status: 1},
+			// failed transaction, only pays gas capped by the max fee (11 * 100)
+			{From: sender, To: receiver, Value: wei(1000), GasUsed: 100, MaxPriorityFeePerGas: wei(5), MaxFeePerGas: wei(11), Status: 0},
+			// the value of the reverted internal call and of its child is not transferred
+			{From: sender, To: receiver, GasUsed: 0, Status: 1, Itx: []*types.Eth1InternalTransaction{
+				{Type: "call", From: sender, To: receiver, Value: wei(50), Path: "[]"},
+				{Type: "call", From: receiver, To: withdrawer, Value: wei(7), Path: "[0]", ErrorMsg: "execution reverted"},
+				{Type: "call", From: withdrawer, To: coinbase, Value: wei(3), Path: "[0 0]"},
+				{Type: "delegatecall", From: receiver, To: withdrawer, Value: wei(50), Path: "[1]"},
+			}},
+			// successful transaction whose last internal call reverted, the other internal calls are transferred
+			{From: sender, To: receiver, GasUsed: 0, Status: 1, Itx: []*types.Eth1InternalTransaction{
+				{Type: "call", From: sender, To: receiver, Value: wei(30), Path: "[]"},
+				{Type: "call", From: receiver, To: coinbase, Value: wei(5), Path: "[0]"},
+				{Type: "call", From: receiver, To: withdrawer, Value: wei(4), Path: "[1]", ErrorMsg: "execution reverted"},
+			}},
+			// failed transaction of a block that was exported before internal calls carried their error, the internal calls are not counted
+			{From: sender, To: receiver, GasUsed: 0, Status: 0, Itx: []*types.Eth1InternalTransaction{
+				{Type: "call", From: sender, To: receiver, Value: wei(20), Path: "[]"},
+			}},
+		},
+		Withdrawals: []*types.Eth1Withdrawal{{Address: withdrawer, Amount: wei(2)}},
+	}
+
+	expected := map[string]int64{
+		"0x0000000000000000000000000000000000000001": -1000 - 12*21000 - 11*100 - 50 - 30,
+		"0x0000000000000000000000000000000000000002": 1000 + 50 + 30 - 5,
+		"0x0000000000000000000000000000000000000003": 2*21000 + 1*100 + 5,
+		"0x0000000000000000000000000000000000000004": 2e9,
+	}
+	deltas := Eth1NativeBalanceDeltas(block)
+	if len(deltas) != len(expected) {
+		t.Errorf("expected %v balance deltas, got %v", len(expected), len(deltas))
+	}
+	for address, delta := range deltas {
+		if delta.Cmp(big.NewInt(expected[address.Hex()])) != 0 {
+			t.Errorf("expected balance delta %v for %v, got %v", expected[address.Hex()], address.Hex(), delta)
+		}
+	}
+}
+
+func TestParseAddressLabels(t *testing.T) {
+	csvLabels := "address,name,category,tags,confidence\n" +
+		"0x00000000219ab540356cBB839Cbe05303d7705Fa,Deposit Contract,,beacon;staking,\n" +
+		"0x28c6c06298d514db089934071355e5743bf21d60,Binance 14,Exchange,hot wallet,0.9\n"
+	labels, err := ParseAddressLabels(strings.NewReader(csvLabels), "csv")
+	if err != nil {
+		t.Fatalf("error parsing csv labels: %v", err)
+	}
+	if len(labels) != 2 || labels[0].Name != "Deposit Contract" || len(labels[0].Tags) != 2 || labels[0].Confidence != 1 {
+		t.Errorf("unexpected csv labels %+v", labels)
+	}
+	if labels[1].Category != types.ExchangeAddressLabel || labels[1].Confidence != 0.9 {
+		t.Errorf("unexpected csv label %+v", labels[1])
+	}
+
+	jsonLabels := `[{"address":"0x28c6c06298d514db089934071355e5743bf21d60","name":"Binance 14","category":"mev bot","tags":["Hot Wallet"]}]`
+	labels, err = ParseAddressLabels(strings.NewReader(jsonLabels), "json")
+	if err != nil {
+		t.Fatalf("error parsing json labels: %v", err)
+	}
+	if len(labels) != 1 || labels[0].Category != types.MevBotAddressLabel || labels[0].Tags[0] != "hot wallet" {
+		t.Errorf("unexpected json labels %+v", labels)
+	}
+
+	invalid := []string{
+		"name\nDeposit Contract\n",
+		"address,name\n0x1234,Deposit Contract\n",
+		"address,name,category\n0x00000000219ab540356cBB839Cbe05303d7705Fa,Deposit Contract,unknown\n",
+		"address,name,confidence\n0x00000000219ab540356cBB839Cbe05303d7705Fa,Deposit Contract,2\n",
+		"address,name\n0x00000000219ab540356cBB839Cbe05303d7705Fa,A\n0x00000000219ab540356cbb839cbe05303d7705fa,B\n",
+	}
+	for _, list := range invalid {
+		if _, err := ParseAddressLabels(strings.NewReader(list), "csv"); err == nil {
+			t.Errorf("expected an error for %q", list)
+		}
+	}
+}
+
+func TestEnsAvatar(t *testing.T) {
+	standard, token, tokenId, ok := ParseEnsAvatarNft("eip155:1/erc1155:0x495f947276749Ce646f68AC8c248420045cb7b5e/8112316025873927737505937898915153732580103913704334048512380490797008551937")
+	if !ok || standard != types.NftStandardERC1155 || token.Hex() != "0x495f947276749Ce646f68AC8c248420045cb7b5e" || tokenId.String() != "8112316025873927737505937898915153732580103913704334048512380490797008551937" {
+		t.Errorf("unexpected nft avatar %v %v %v %v", standard, token, tokenId, ok)
+	}
+	if _, _, _, ok := ParseEnsAvatarNft("https://example.com/avatar.png"); ok {
+		t.Errorf("expected a url avatar not to be parsed as nft")
+	}
+
+	tests := map[string]string{
+		"https://example.com/avatar.png":  "https://example.com/avatar.png",
+		"ipfs://QmYwAPJzv5CZsnAzt8auVZRn": "https://ipfs.io/ipfs/QmYwAPJzv5CZsnAzt8auVZRn",
+		"data:image/png;base64,iVBORw0K":  "",
+		"javascript:alert(1)":             "",
+	}
+	for avatar, expected := range tests {
+		if url := EnsAvatarUrl(avatar, "https://ipfs.io/"); url != expected {
+			t.Errorf("unexpected url of avatar %v: %v, expected %v", avatar, url, expected)
+		}
+	}
+}
+
+func TestEnsNameStatus(t *testing.T) {
+	validTo := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	if status := EnsNameStatus(validTo, validTo.Add(-time.Hour)); status != types.EnsNameStatusActive {
+		t.Errorf("unexpected status %v before expiry", status)
+	}
+	if status := EnsNameStatus(validTo, validTo.Add(EnsGracePeriod-time.Hour)); status != types.EnsNameStatusGracePeriod {
+		t.Errorf("unexpected status %v within grace period", status)
+	}
+	if status := EnsNameStatus(validTo, validTo.Add(EnsGracePeriod)); status != types.EnsNameStatusExpired {
+		t.Errorf("unexpected status %v after grace period", status)
+	}
+}
+
+func TestICalendar(t *testing.T) {
+	start := time.Date(2023, 9, 12, 10, 0, 0, 0, time.UTC)
+	feed := string(ICalendar("explorer", "Duties; validator 1", []CalendarEvent{{
+		UID:         "sync-1@explorer",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Summary:     "Sync committee, period 1",
+		Description: strings.Repeat("ä", 60),
+	}}, start))
+
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %q is longer than 75 octets", line)
+		}
+	}
+	for _, expected := range []string{"X-WR-CALNAME:Duties\\; validator 1\r\n", "DTSTART:20230912T100000Z\r\n", "DTEND:20230912T110000Z\r\n", "SUMMARY:Sync committee\\, period 1\r\n"} {
+		if !strings.Contains(feed, expected) {
+			t.Errorf("feed does not contain %q", expected)
+		}
+	}
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("ä", 60)+"\r\n") {
+		t.Errorf("folded description is not restored by unfolding")
+	}
+}
+
+func TestAttributeAttestationRewardShortfall(t *testing.T) {
+	ideal := types.ApiAttestationRewards{Head: 3000, Source: 5000, Target: 9000}
+	tests := []struct {
+		name      string
+		analysis  types.ApiAttestationRewardAnalysis
+		shortfall types.ApiAttestationRewardShortfall
+	}{
+		{"perfect", types.ApiAttestationRewardAnalysis{Included: true, Ideal: ideal, Actual: ideal}, types.ApiAttestationRewardShortfall{}},
+		{"missed", types.ApiAttestationRewardAnalysis{Ideal: ideal, Actual: types.ApiAttestationRewards{Source: -5000, Target: -9000}}, types.ApiAttestationRewardShortfall{Missed: 31000, Total: 31000}},
+		{"wrong head", types.ApiAttestationRewardAnalysis{Included: true, Ideal: ideal, Actual: types.ApiAttestationRewards{Source: 5000, Target: 9000}}, types.ApiAttestationRewardShortfall{WrongHead: 3000, Total: 3000}},
+		{"inclusion delay", types.ApiAttestationRewardAnalysis{Included: true, InclusionDelay: 2, Ideal: ideal, Actual: types.ApiAttestationRewards{Source: 5000, Target: 9000}}, types.ApiAttestationRewardShortfall{InclusionDelay: 3000, Total: 3000}},
+		{"late source", types.ApiAttestationRewardAnalysis{Included: true, InclusionDelay: 7, Ideal: ideal, Actual: types.ApiAttestationRewards{Source: -5000, Target: 9000}}, types.ApiAttestationRewardShortfall{LateSource: 10000, InclusionDelay: 3000, Total: 13000}},
+		{"wrong target", types.ApiAttestationRewardAnalysis{Included: true, Ideal: ideal, Actual: types.ApiAttestationRewards{Source: 5000, Target: -9000}}, types.ApiAttestationRewardShortfall{WrongTarget: 21000, Total: 21000}},
+	}
+	for _, test := range tests {
+		AttributeAttestationRewardShortfall(&test.analysis)
+		if test.analysis.Shortfall != test.shortfall {
+			t.Errorf("unexpected shortfall of %v: %+v, expected %+v", test.name, test.analysis.Shortfall, test.shortfall)
+		}
+		if test.analysis.Ideal.Total-test.analysis.Actual.Total != test.analysis.Shortfall.Total {
+			t.Errorf("shortfall of %v does not match the difference of ideal and actual rewards", test.name)
+		}
+	}
+}